@@ -0,0 +1,108 @@
+// japwords-jmdict-import imports JMdict file to index that can be used
+// as offline lemma dictionary by japwords-server.
+package main
+
+import (
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Darkclainer/japwords/pkg/config"
+	"github.com/Darkclainer/japwords/pkg/jmdict"
+)
+
+const (
+	formatAuto = "auto"
+	formatXML  = "xml"
+	formatJSON = "json"
+)
+
+type FlagOpts struct {
+	Input  string
+	Output string
+	Format string
+}
+
+func main() {
+	flagOpts := parseFlags()
+	start := time.Now()
+	imported, err := importFile(flagOpts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error! Import failed: %s\n", err)
+		os.Exit(2)
+	}
+	fmt.Printf("Imported %d entries to %s in %s\n", imported, flagOpts.Output, time.Since(start).Round(time.Millisecond))
+}
+
+func parseFlags() *FlagOpts {
+	cliName := "japwords-jmdict-import"
+	fset := flag.NewFlagSet(cliName, flag.ExitOnError)
+	fset.SetOutput(os.Stderr)
+	fset.Usage = func() {
+		fmt.Fprintf(fset.Output(), "Usage:\n  %s -i JMdict_e.gz [flags]\n", cliName)
+		fmt.Fprint(fset.Output(), "\nflags:\n")
+		fset.PrintDefaults()
+	}
+
+	var flagOpts FlagOpts
+	fset.StringVar(&flagOpts.Input, "i", "", "path to JMdict XML or jmdict-simplified JSON file (can be gzipped)")
+	fset.StringVar(&flagOpts.Output, "o", config.DefaultDataPath("jmdict.db"), "path to created index")
+	fset.StringVar(&flagOpts.Format, "format", formatAuto, "format of input file: auto, xml or json")
+	err := fset.Parse(os.Args[1:])
+	if err != nil {
+		// because we use flag.ExitOnError
+		panic("unreachable")
+	}
+	if flagOpts.Input == "" {
+		fset.Usage()
+		os.Exit(2)
+	}
+	return &flagOpts
+}
+
+func importFile(opts *FlagOpts) (int, error) {
+	format := opts.Format
+	if format == formatAuto {
+		format = detectFormat(opts.Input)
+	}
+	var parse func(io.Reader, jmdict.EntryHandler) error
+	switch format {
+	case formatXML:
+		parse = jmdict.ParseXML
+	case formatJSON:
+		parse = jmdict.ParseJSON
+	default:
+		return 0, fmt.Errorf("unknown format %q", format)
+	}
+	return jmdict.Import(opts.Output, func(handler jmdict.EntryHandler) error {
+		file, err := os.Open(opts.Input)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		var src io.Reader = file
+		if strings.HasSuffix(opts.Input, ".gz") {
+			gzipReader, err := gzip.NewReader(file)
+			if err != nil {
+				return err
+			}
+			defer gzipReader.Close()
+			src = gzipReader
+		}
+		return parse(src, handler)
+	})
+}
+
+func detectFormat(path string) string {
+	ext := filepath.Ext(strings.TrimSuffix(path, ".gz"))
+	if ext == ".json" {
+		return formatJSON
+	}
+	// original JMdict files are usually distributed without extension
+	return formatXML
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/Darkclainer/japwords/pkg/config"
+	"github.com/Darkclainer/japwords/pkg/examples"
+	"github.com/Darkclainer/japwords/pkg/kanji"
+	"github.com/Darkclainer/japwords/pkg/lemmalookup"
+)
+
+type command struct {
+	Description string
+	// Run runs command with arguments that follow its name and returns exit code
+	Run func(args []string) int
+}
+
+var commands = map[string]*command{
+	"doctor": {
+		Description: "diagnose problems with Anki and dictionaries",
+		Run:         runDoctor,
+	},
+	"import": {
+		Description: "add notes for words from word list",
+		Run:         runImport,
+	},
+	"lookup": {
+		Description: "print lemmas for query",
+		Run:         runLookup,
+	},
+}
+
+// commandNames returns sorted names of commands.
+func commandNames() []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// openCommandConfig opens config for command, it's like prepareConfig but prints to stderr,
+// so output of command is not mixed with it.
+func openCommandConfig(path string, provided bool) (*config.Manager, error) {
+	if !provided {
+		path = config.DefaultConfigPath()
+	}
+	if err := config.EnsureConfigFile(path); err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(os.Stderr, "Using config file from: %s\n", path)
+	return config.New(path)
+}
+
+// newProjector returns projector with sources that are enabled in config, so commands project
+// lemmas the same way as server.
+func newProjector(examplesIndex *examples.Index, kanjiDict *kanji.Dict) *lemmalookup.Projector {
+	projector := &lemmalookup.Projector{}
+	// nil pointers must not become non nil interfaces
+	if examplesIndex != nil {
+		projector.Examples = examplesIndex
+	}
+	if kanjiDict != nil {
+		projector.Kanji = kanjiDict
+	}
+	return projector
+}
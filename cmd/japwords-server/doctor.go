@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Darkclainer/japwords/pkg/anki"
+	"github.com/Darkclainer/japwords/pkg/config"
+	"github.com/Darkclainer/japwords/pkg/dictconfig"
+	"github.com/Darkclainer/japwords/pkg/doctor"
+	"github.com/Darkclainer/japwords/pkg/fetcher"
+)
+
+const (
+	// doctorTimeout limits all checks together, so doctor doesn't hang if something is unreachable
+	doctorTimeout = 30 * time.Second
+	// doctorFixTimeout limits every fix and checks after fixes, time of user answers is not included
+	doctorFixTimeout = 30 * time.Second
+)
+
+type doctorFlagOpts struct {
+	ConfigPath    string
+	ConfigPathSet bool
+	// Fix applies all available fixes without asking
+	Fix bool
+}
+
+func parseDoctorFlags(args []string) *doctorFlagOpts {
+	fset := newFlagSet(cliName+" doctor", printDoctorUsage)
+	var flagOpts doctorFlagOpts
+	fset.StringVar(&flagOpts.ConfigPath, "c", "config.yaml", "path to config")
+	fset.BoolVar(&flagOpts.Fix, "fix", false, "apply all available fixes without asking")
+	err := fset.Parse(args)
+	if err != nil {
+		// because we use flag.ExitOnError
+		panic("unreachable")
+	}
+	fset.Visit(func(f *flag.Flag) {
+		if f.Name == "c" {
+			flagOpts.ConfigPathSet = true
+		}
+	})
+	return &flagOpts
+}
+
+func printDoctorUsage(f *flag.FlagSet, name string) {
+	fmt.Fprintf(f.Output(), "Usage:\n  %s [flags]\n", name)
+	fmt.Fprint(f.Output(), `
+Checks that notes can be added to Anki, templates in config are valid and online
+dictionaries are reachable. Exits with non-zero status if any check failed.
+`)
+
+	// print flags
+	fmt.Fprint(f.Output(), "\nflags:\n")
+	f.PrintDefaults()
+}
+
+func runDoctor(args []string) int {
+	flagOpts := parseDoctorFlags(args)
+	configMgr, err := openCommandConfig(flagOpts.ConfigPath, flagOpts.ConfigPathSet)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error! Failed to read config: %s\n", err)
+		return 2
+	}
+	uc := configMgr.Current()
+	signalCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(signalCtx, doctorTimeout)
+	defer cancel()
+
+	// config is returned even if it's invalid, so other checks can still run
+	ankiConfig, ankiConfigErr := anki.NewConfig(uc)
+	ankiClient := anki.NewAnki(anki.DefaultStatefullClientConstructor)
+	var ankiSection *doctor.Section
+	if err := ankiClient.ReloadConfig(ankiConfig); err != nil {
+		ankiSection = &doctor.Section{
+			Title: "Anki",
+			Checks: []*doctor.Check{
+				doctor.CheckAnkiConfig(ankiConfigErr),
+				{
+					Name:    "AnkiConnect is available",
+					Problem: fmt.Sprintf("Address of AnkiConnect %q (anki.addr in config) is invalid: %s", ankiConfig.Addr, err),
+				},
+			},
+		}
+	} else {
+		defer ankiClient.Stop()
+		ankiSection = doctor.CheckAnki(ctx, ankiClient, ankiConfig, ankiConfigErr)
+	}
+	sections := []*doctor.Section{
+		ankiSection,
+		doctor.CheckTemplates(&uc.Anki),
+		checkDictionaryURLs(ctx, uc),
+	}
+	for i, section := range sections {
+		if i != 0 {
+			fmt.Println()
+		}
+		_ = doctor.WriteSection(os.Stdout, section)
+	}
+
+	// checks can take all the time of ctx, and user can think about fixes as long as they want
+	if applyFixes(signalCtx, ankiSection, flagOpts.Fix, os.Stdin) {
+		// fixes can uncover other problems, for example fields of created note type
+		recheckCtx, cancel := context.WithTimeout(signalCtx, doctorFixTimeout)
+		defer cancel()
+		sections[0] = doctor.CheckAnki(recheckCtx, ankiClient, ankiConfig, ankiConfigErr)
+		sections[0].Title = "Anki (after fixes)"
+		fmt.Println()
+		_ = doctor.WriteSection(os.Stdout, sections[0])
+	}
+
+	failed := 0
+	for _, section := range sections {
+		failed += len(section.Failed())
+	}
+	if failed != 0 {
+		fmt.Printf("\n%d check(s) failed\n", failed)
+		return 1
+	}
+	fmt.Println("\nEverything is fine")
+	return 0
+}
+
+// checkDictionaryURLs checks online dictionaries with the same headers and timeouts as server uses.
+func checkDictionaryURLs(ctx context.Context, uc *config.UserConfig) *doctor.Section {
+	failedSection := func(err error) *doctor.Section {
+		return &doctor.Section{
+			Title: "Online dictionaries",
+			Checks: []*doctor.Check{{
+				Name:    "Request settings are valid",
+				Problem: fmt.Sprintf("dictionary.headers or dictionary.requests in config are invalid: %s", err),
+			}},
+		}
+	}
+	fetcherConfig, err := dictconfig.FetcherConfig(&uc.Dictionary)
+	if err != nil {
+		return failedSection(err)
+	}
+	// single failed request is enough for diagnostics
+	fetcherConfig.Retries = 0
+	client, err := fetcher.New(fetcherConfig)
+	if err != nil {
+		return failedSection(err)
+	}
+	return doctor.CheckURLs(ctx, client, &uc.Dictionary)
+}
+
+// applyFixes applies fixes of failed checks, user is asked about every fix unless all is true.
+// Every fix gets its own timeout derived from ctx. It returns true if any fix was applied.
+func applyFixes(ctx context.Context, section *doctor.Section, all bool, input io.Reader) bool {
+	reader := bufio.NewReader(input)
+	applied := false
+	for _, check := range section.Failed() {
+		if check.Fix == nil {
+			continue
+		}
+		if !all {
+			fmt.Printf("\nDo you want to %s? [y/N] ", check.Fix.Description)
+			answer, _ := reader.ReadString('\n')
+			answer = strings.ToLower(strings.TrimSpace(answer))
+			if answer != "y" && answer != "yes" {
+				continue
+			}
+		}
+		if err := applyFix(ctx, check.Fix); err != nil {
+			fmt.Printf("Failed to %s: %s\n", check.Fix.Description, err)
+			continue
+		}
+		fmt.Printf("Done: %s\n", check.Fix.Description)
+		applied = true
+	}
+	return applied
+}
+
+func applyFix(ctx context.Context, fix *doctor.Fix) error {
+	ctx, cancel := context.WithTimeout(ctx, doctorFixTimeout)
+	defer cancel()
+	return fix.Apply(ctx)
+}
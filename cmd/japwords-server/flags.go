@@ -10,14 +10,20 @@ type FlagOpts struct {
 	ConfigPath string
 	// ConfigPathSet indicates that path for config was provided by user
 	ConfigPathSet bool
+	// HTTPMode is mode of recording and replaying requests to online dictionaries
+	HTTPMode string
+	// FixtureDir is directory where requests are recorded to and replayed from
+	FixtureDir string
 }
 
 func ParseFlags() *FlagOpts {
-	fset := newFlagSet()
+	fset := newFlagSet(cliName, printUsage)
 
 	// sloppy, but ok
 	var flagOpts FlagOpts
 	fset.StringVar(&flagOpts.ConfigPath, "c", "config.yaml", "path to config")
+	fset.StringVar(&flagOpts.HTTPMode, "http-mode", "off", "record or replay requests to online dictionaries: off, record, replay")
+	fset.StringVar(&flagOpts.FixtureDir, "fixtures", "fixtures", "directory for recorded requests")
 	err := fset.Parse(os.Args[1:])
 	if err != nil {
 		// because we use flag.ExitOnError
@@ -33,16 +39,23 @@ func ParseFlags() *FlagOpts {
 	return &flagOpts
 }
 
-func newFlagSet() *flag.FlagSet {
-	cliName := "japwords"
-	fset := flag.NewFlagSet(cliName, flag.ExitOnError)
+const cliName = "japwords"
+
+func newFlagSet(name string, usage func(f *flag.FlagSet, name string)) *flag.FlagSet {
+	fset := flag.NewFlagSet(name, flag.ExitOnError)
 	fset.SetOutput(os.Stderr)
-	fset.Usage = func() { printUsage(fset, cliName) }
+	fset.Usage = func() { usage(fset, name) }
 	return fset
 }
 
 func printUsage(f *flag.FlagSet, name string) {
-	fmt.Fprintf(f.Output(), "Usage:\n  %s [flags]\n", name)
+	fmt.Fprintf(f.Output(), "Usage:\n  %s [flags]\n  %s <command> [flags] [args]\n", name, name)
+
+	// print commands
+	fmt.Fprint(f.Output(), "\ncommands:\n")
+	for _, commandName := range commandNames() {
+		fmt.Fprintf(f.Output(), "  %-10s %s\n", commandName, commands[commandName].Description)
+	}
 
 	// print flags
 	fmt.Fprint(f.Output(), "\nflags:\n")
@@ -7,10 +7,14 @@ import (
 
 	"github.com/Darkclainer/japwords/pkg/anki"
 	"github.com/Darkclainer/japwords/pkg/config"
+	"github.com/Darkclainer/japwords/pkg/media"
 )
 
-func NewAnki(configManager *config.Manager, LC fx.Lifecycle) (*anki.Anki, *anki.ConfigReloader, error) {
+func NewAnki(configManager *config.Manager, mediaStore *media.Store, LC fx.Lifecycle) (*anki.Anki, *anki.ConfigReloader, error) {
 	client := anki.NewAnki(anki.DefaultStatefullClientConstructor)
+	if mediaStore != nil {
+		client.SetAudioFetcher(mediaStore)
+	}
 	reloader, err := anki.NewConfigReloader(client, configManager)
 	if err != nil {
 		return nil, nil, err
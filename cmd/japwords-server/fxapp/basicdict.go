@@ -1,11 +1,30 @@
 package fxapp
 
 import (
+	"go.uber.org/zap"
+
 	"github.com/Darkclainer/japwords/pkg/basicdict"
+	"github.com/Darkclainer/japwords/pkg/fetcher"
+	"github.com/Darkclainer/japwords/pkg/httpfixture"
 	"github.com/Darkclainer/japwords/pkg/jisho"
 	"github.com/Darkclainer/japwords/pkg/wadoku"
 )
 
+// NewDictFetcher returns fetcher for online dictionaries, that records or replays requests
+// if it was requested by command line flags.
+func NewDictFetcher(opts *Options, f *fetcher.Fetcher, logger *zap.Logger) (basicdict.Fetcher, error) {
+	switch opts.HTTPMode {
+	case httpfixture.ModeRecord:
+		logger.Info("recording requests to online dictionaries", zap.String("dir", opts.FixtureDir))
+		return httpfixture.NewRecorder(f, opts.FixtureDir)
+	case httpfixture.ModeReplay:
+		logger.Info("replaying requests to online dictionaries", zap.String("dir", opts.FixtureDir))
+		return httpfixture.NewReplayer(opts.FixtureDir)
+	default:
+		return f, nil
+	}
+}
+
 func NewBasicDict(fetcher basicdict.Fetcher) (jisho.BasicDict, wadoku.BasicDict) {
 	bd := basicdict.New(fetcher)
 	return bd, bd
@@ -0,0 +1,80 @@
+package fxapp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/fx"
+
+	"github.com/Darkclainer/japwords/pkg/cachedict"
+	"github.com/Darkclainer/japwords/pkg/config"
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+type CacheConfig struct {
+	Path       string
+	TTL        time.Duration
+	MaxEntries int
+}
+
+func (c *CacheConfig) Equal(o any) bool {
+	oc, ok := o.(*CacheConfig)
+	if !ok {
+		return false
+	}
+	return *c == *oc
+}
+
+type CacheStoreIn struct {
+	fx.In
+
+	LC        fx.Lifecycle
+	ConfigMgr *config.Manager
+}
+
+// NewCacheStore returns persistent cache for online dictionaries or nil if it's disabled in config.
+func NewCacheStore(in CacheStoreIn) (*cachedict.Store, error) {
+	part, _, err := in.ConfigMgr.Register(config.ConsumerFunc(func(uc *config.UserConfig) (config.Part, error) {
+		cacheConfig := &CacheConfig{
+			MaxEntries: uc.Dictionary.Cache.MaxEntries,
+		}
+		if uc.Dictionary.Cache.Path != "" {
+			cacheConfig.Path = in.ConfigMgr.ResolvePath(uc.Dictionary.Cache.Path)
+		}
+		ttl, err := parseOptionalDuration(uc.Dictionary.Cache.TTL)
+		if err != nil {
+			return nil, fmt.Errorf("cache ttl is invalid: %w", err)
+		}
+		cacheConfig.TTL = ttl
+		if cacheConfig.TTL < 0 || cacheConfig.MaxEntries < 0 {
+			return nil, fmt.Errorf("cache ttl and max entries should not be negative")
+		}
+		return cacheConfig, nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+	cacheConfig := part.(*CacheConfig)
+	if cacheConfig.Path == "" {
+		return nil, nil
+	}
+	store, err := cachedict.OpenStore(cacheConfig.Path, cachedict.StoreOptions{
+		TTL:        cacheConfig.TTL,
+		MaxEntries: cacheConfig.MaxEntries,
+	})
+	if err != nil {
+		return nil, err
+	}
+	in.LC.Append(fx.Hook{
+		OnStop: func(_ context.Context) error {
+			return store.Close()
+		},
+	})
+	return store, nil
+}
+
+// NewCacheGroup returns all dictionary caches, so they can be cleared together.
+func NewCacheGroup(jisho *cachedict.CacheDict[[]*lemma.Lemma], wadoku *cachedict.CacheDict[[]*lemma.PitchedLemma]) *cachedict.Group {
+	return cachedict.NewGroup(jisho, wadoku)
+}
@@ -0,0 +1,45 @@
+package fxapp
+
+import (
+	"go.uber.org/fx"
+
+	"github.com/Darkclainer/japwords/pkg/config"
+	"github.com/Darkclainer/japwords/pkg/examples"
+)
+
+type ExamplesConfig struct {
+	Path string
+}
+
+func (c *ExamplesConfig) Equal(o any) bool {
+	oc, ok := o.(*ExamplesConfig)
+	if !ok {
+		return false
+	}
+	return c.Path == oc.Path
+}
+
+type ExamplesIn struct {
+	fx.In
+
+	ConfigMgr *config.Manager
+}
+
+// NewExamples returns index of example sentences or nil if examples are disabled in config.
+func NewExamples(in ExamplesIn) (*examples.Index, error) {
+	part, _, err := in.ConfigMgr.Register(config.ConsumerFunc(func(uc *config.UserConfig) (config.Part, error) {
+		examplesConfig := &ExamplesConfig{}
+		if uc.Dictionary.Examples.Path != "" {
+			examplesConfig.Path = in.ConfigMgr.ResolvePath(uc.Dictionary.Examples.Path)
+		}
+		return examplesConfig, nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+	examplesConfig := part.(*ExamplesConfig)
+	if examplesConfig.Path == "" {
+		return nil, nil
+	}
+	return examples.Open(examplesConfig.Path)
+}
@@ -1,24 +1,29 @@
 package fxapp
 
 import (
+	"time"
+
 	"github.com/Darkclainer/japwords/pkg/config"
+	"github.com/Darkclainer/japwords/pkg/dictconfig"
 	"github.com/Darkclainer/japwords/pkg/fetcher"
 )
 
 func NewFetcher(configMgr *config.Manager) (*fetcher.Fetcher, error) {
-	part, _, err := configMgr.Register(config.ConsumerFunc(func(uc *config.UserConfig) (config.Part, error) {
-		var conf fetcher.Config
-		conf.Headers = uc.Dictionary.Headers
-		if conf.Headers == nil {
-			conf.Headers = make(map[string]string)
-		}
-		conf.Headers["User-Agent"] = uc.Dictionary.UserAgent
-		return &conf, nil
-	}))
+	// actual config is set by reloader
+	fetcherClient, err := fetcher.New(&fetcher.Config{})
 	if err != nil {
 		return nil, err
 	}
-	fetcherConf := part.(*fetcher.Config)
-	fetcherClient, err := fetcher.New(fetcherConf)
-	return fetcherClient, err
+	if _, _, err := configMgr.Register(dictconfig.NewFetcherReloader(fetcherClient)); err != nil {
+		return nil, err
+	}
+	return fetcherClient, nil
+}
+
+// parseOptionalDuration parses duration in go format, empty string is zero duration.
+func parseOptionalDuration(src string) (time.Duration, error) {
+	if src == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(src)
 }
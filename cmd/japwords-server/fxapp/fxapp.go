@@ -4,60 +4,121 @@ import (
 	"go.uber.org/fx"
 
 	"github.com/Darkclainer/japwords/graphql/gqlresolver"
-	"github.com/Darkclainer/japwords/pkg/basicdict"
+	"github.com/Darkclainer/japwords/pkg/cachedict"
 	"github.com/Darkclainer/japwords/pkg/config"
+	"github.com/Darkclainer/japwords/pkg/dictconfig"
+	"github.com/Darkclainer/japwords/pkg/httpfixture"
 	"github.com/Darkclainer/japwords/pkg/httpserver"
 	"github.com/Darkclainer/japwords/pkg/logger"
-	"github.com/Darkclainer/japwords/pkg/multidict"
+	"github.com/Darkclainer/japwords/pkg/media"
 	"github.com/Darkclainer/japwords/ui"
 )
 
-func NewApp(configMgr *config.Manager) (*fx.App, error) {
-	opts := []fx.Option{
+// Options are set from command line and can't be changed in runtime.
+type Options struct {
+	HTTPMode   httpfixture.Mode
+	FixtureDir string
+}
+
+func NewApp(configMgr *config.Manager, appOpts *Options) (*fx.App, error) {
+	opts := append(
+		coreOptions(configMgr, appOpts),
+		// http/graphql staff
+		fx.Provide(
+			NewHttpServerConfig,
+			httpserver.New,
+		),
+		fx.Provide(
+			gqlresolver.New,
+		),
+		fx.Invoke(InvokeApp),
+	)
+	return fx.New(opts...), nil
+}
+
+// NewCommandApp returns application for command line commands, it has dictionaries and Anki,
+// but doesn't serve anything. Targets are populated like with fx.Populate, app should be started
+// before they are used.
+func NewCommandApp(configMgr *config.Manager, appOpts *Options, targets ...any) (*fx.App, error) {
+	opts := append(
+		dictOptions(configMgr, appOpts),
+		fx.Provide(
+			noCacheStore,
+			noMedia,
+			NewAnki,
+		),
+		fx.Populate(targets...),
+		fx.NopLogger,
+	)
+	app := fx.New(opts...)
+	if err := app.Err(); err != nil {
+		return nil, err
+	}
+	return app, nil
+}
+
+// coreOptions provides everything except http server.
+func coreOptions(configMgr *config.Manager, appOpts *Options) []fx.Option {
+	return append(
+		dictOptions(configMgr, appOpts),
+		fx.Provide(NewCacheStore),
+		fx.Provide(NewMedia),
+		fx.Provide(NewHistory),
+		fx.Provide(NewSuggestIndex),
+		fx.Provide(NewAnki),
+		fx.Provide(NewOutbox),
+	)
+}
+
+// dictOptions provides dictionaries, persistent cache of online dictionaries should be provided separately.
+func dictOptions(configMgr *config.Manager, appOpts *Options) []fx.Option {
+	return []fx.Option{
 		// util staff
-		fx.Supply(configMgr),
+		fx.Supply(configMgr, appOpts),
 		fx.Provide(
 			logger.New,
 		),
 		// dictionary things
 		fx.Provide(
-			fx.Annotate(
-				NewFetcher,
-				fx.As(new(basicdict.Fetcher)),
-			),
+			NewFetcher,
+			NewDictFetcher,
 		),
 		fx.Provide(
 			NewBasicDict,
 		),
 		fx.Provide(
-			fx.Annotate(
-				NewJisho,
-				fx.As(new(multidict.LemmaDict)),
-			),
-			fx.Annotate(
-				NewWadoku,
-				fx.As(new(multidict.PitchDict)),
-			),
+			NewJisho,
+			NewLemmaDicts,
+			NewWadoku,
+			NewPitchDicts,
+			NewCacheGroup,
 		),
 		fx.Provide(NewMultidict),
-		fx.Provide(NewAnki),
-		// http/graphql staff
-		fx.Provide(
-			NewHttpServerConfig,
-			httpserver.New,
-		),
-		fx.Provide(
-			gqlresolver.New,
-		),
-		fx.Invoke(InvokeApp),
+		fx.Provide(dictconfig.NewUpdater),
+		fx.Provide(NewExamples),
+		fx.Provide(NewKanji),
 	}
-	return fx.New(opts...), nil
+}
+
+// noCacheStore disables persistent cache for commands, because server that can be running
+// at the same time holds lock of cache file.
+func noCacheStore() *cachedict.Store {
+	return nil
+}
+
+// noMedia disables downloading of audio for commands, Anki downloads it itself.
+func noMedia() *media.Store {
+	return nil
 }
 
 func InvokeApp(
 	server *httpserver.Server,
 	resolver *gqlresolver.Resolver,
+	mediaStore *media.Store,
 ) {
 	server.RegisterHandler("/api/query", resolver.Handler())
+	if mediaStore != nil {
+		server.RegisterHandler(gqlresolver.MediaPrefix, mediaStore.Handler(gqlresolver.MediaPrefix))
+	}
 	server.RegisterHandler("/", ui.Handler("/"))
 }
@@ -0,0 +1,74 @@
+package fxapp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/fx"
+
+	"github.com/Darkclainer/japwords/pkg/config"
+	"github.com/Darkclainer/japwords/pkg/history"
+)
+
+type HistoryConfig struct {
+	Path       string
+	Retention  time.Duration
+	MaxEntries int
+}
+
+func (c *HistoryConfig) Equal(o any) bool {
+	oc, ok := o.(*HistoryConfig)
+	if !ok {
+		return false
+	}
+	return *c == *oc
+}
+
+type HistoryIn struct {
+	fx.In
+
+	LC        fx.Lifecycle
+	ConfigMgr *config.Manager
+}
+
+// NewHistory returns history of queries or nil if it's disabled in config.
+func NewHistory(in HistoryIn) (*history.Store, error) {
+	part, _, err := in.ConfigMgr.Register(config.ConsumerFunc(func(uc *config.UserConfig) (config.Part, error) {
+		historyConfig := &HistoryConfig{
+			MaxEntries: uc.History.MaxEntries,
+		}
+		if uc.History.Path != "" {
+			historyConfig.Path = in.ConfigMgr.ResolvePath(uc.History.Path)
+		}
+		retention, err := parseOptionalDuration(uc.History.Retention)
+		if err != nil {
+			return nil, fmt.Errorf("history retention is invalid: %w", err)
+		}
+		historyConfig.Retention = retention
+		if historyConfig.Retention < 0 || historyConfig.MaxEntries < 0 {
+			return nil, fmt.Errorf("history retention and max entries should not be negative")
+		}
+		return historyConfig, nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+	historyConfig := part.(*HistoryConfig)
+	if historyConfig.Path == "" {
+		return nil, nil
+	}
+	store, err := history.Open(historyConfig.Path, history.Options{
+		Retention:  historyConfig.Retention,
+		MaxEntries: historyConfig.MaxEntries,
+	})
+	if err != nil {
+		return nil, err
+	}
+	in.LC.Append(fx.Hook{
+		OnStop: func(_ context.Context) error {
+			return store.Close()
+		},
+	})
+	return store, nil
+}
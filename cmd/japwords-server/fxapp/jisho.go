@@ -3,32 +3,19 @@ package fxapp
 import (
 	"github.com/Darkclainer/japwords/pkg/cachedict"
 	"github.com/Darkclainer/japwords/pkg/config"
+	"github.com/Darkclainer/japwords/pkg/dictconfig"
 	"github.com/Darkclainer/japwords/pkg/jisho"
 	"github.com/Darkclainer/japwords/pkg/lemma"
 )
 
-type JishoConfig struct {
-	URL string
-}
-
-func (c *JishoConfig) Equal(o any) bool {
-	oc, ok := o.(*JishoConfig)
-	if !ok {
-		return false
-	}
-	return c.URL == oc.URL
-}
-
-func NewJisho(jishoClient jisho.BasicDict, configMgr *config.Manager) (*cachedict.CacheDict[[]*lemma.Lemma], error) {
-	part, _, err := configMgr.Register(config.ConsumerFunc(func(uc *config.UserConfig) (config.Part, error) {
-		return &JishoConfig{
-			URL: uc.Dictionary.Jisho.URL,
-		}, nil
-	}))
-	if err != nil {
+func NewJisho(jishoClient jisho.BasicDict, configMgr *config.Manager, store *cachedict.Store) (*cachedict.CacheDict[[]*lemma.Lemma], error) {
+	// base url is set by reloader
+	dict := jisho.New(jishoClient, "")
+	if _, _, err := configMgr.Register(dictconfig.NewJishoReloader(dict)); err != nil {
 		return nil, err
 	}
-	jishoConfig := part.(*JishoConfig)
-	dict := jisho.New(jishoClient, jishoConfig.URL)
-	return cachedict.New[[]*lemma.Lemma](dict)
+	return cachedict.New[[]*lemma.Lemma](dict, cachedict.Options{
+		Name:  "jisho",
+		Store: store,
+	})
 }
@@ -0,0 +1,45 @@
+package fxapp
+
+import (
+	"go.uber.org/fx"
+
+	"github.com/Darkclainer/japwords/pkg/config"
+	"github.com/Darkclainer/japwords/pkg/kanji"
+)
+
+type KanjiConfig struct {
+	Path string
+}
+
+func (c *KanjiConfig) Equal(o any) bool {
+	oc, ok := o.(*KanjiConfig)
+	if !ok {
+		return false
+	}
+	return c.Path == oc.Path
+}
+
+type KanjiIn struct {
+	fx.In
+
+	ConfigMgr *config.Manager
+}
+
+// NewKanji returns kanji dictionary or nil if kanji information is disabled in config.
+func NewKanji(in KanjiIn) (*kanji.Dict, error) {
+	part, _, err := in.ConfigMgr.Register(config.ConsumerFunc(func(uc *config.UserConfig) (config.Part, error) {
+		kanjiConfig := &KanjiConfig{}
+		if uc.Dictionary.Kanji.Path != "" {
+			kanjiConfig.Path = in.ConfigMgr.ResolvePath(uc.Dictionary.Kanji.Path)
+		}
+		return kanjiConfig, nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+	kanjiConfig := part.(*KanjiConfig)
+	if kanjiConfig.Path == "" {
+		return nil, nil
+	}
+	return kanji.Open(kanjiConfig.Path)
+}
@@ -0,0 +1,95 @@
+package fxapp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"go.uber.org/fx"
+
+	"github.com/Darkclainer/japwords/pkg/cachedict"
+	"github.com/Darkclainer/japwords/pkg/config"
+	"github.com/Darkclainer/japwords/pkg/jmdict"
+	"github.com/Darkclainer/japwords/pkg/lemma"
+	"github.com/Darkclainer/japwords/pkg/multidict"
+)
+
+const (
+	lemmaDictJisho  = "jisho"
+	lemmaDictJMdict = "jmdict"
+)
+
+type LemmaDictConfig struct {
+	Names      []string
+	JMdictPath string
+}
+
+func (c *LemmaDictConfig) Equal(o any) bool {
+	oc, ok := o.(*LemmaDictConfig)
+	if !ok {
+		return false
+	}
+	return slices.Equal(c.Names, oc.Names) && c.JMdictPath == oc.JMdictPath
+}
+
+type LemmaDictIn struct {
+	fx.In
+
+	LC        fx.Lifecycle
+	ConfigMgr *config.Manager
+	Jisho     *cachedict.CacheDict[[]*lemma.Lemma]
+}
+
+// NewLemmaDicts returns lemma dictionaries selected in config in the same order.
+func NewLemmaDicts(in LemmaDictIn) ([]multidict.LemmaSource, error) {
+	part, _, err := in.ConfigMgr.Register(config.ConsumerFunc(func(uc *config.UserConfig) (config.Part, error) {
+		return &LemmaDictConfig{
+			Names:      uc.Dictionary.LemmaDicts,
+			JMdictPath: in.ConfigMgr.ResolvePath(uc.Dictionary.JMdict.Path),
+		}, nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+	lemmaDictConfig := part.(*LemmaDictConfig)
+	names := lemmaDictConfig.Names
+	if len(names) == 0 {
+		names = []string{lemmaDictJisho}
+	}
+	var sources []multidict.LemmaSource
+	for _, name := range names {
+		if slices.ContainsFunc(sources, func(s multidict.LemmaSource) bool { return s.Name == name }) {
+			return nil, fmt.Errorf("lemma dictionary %q specified several times", name)
+		}
+		var dict multidict.LemmaDict
+		switch name {
+		case lemmaDictJisho:
+			dict = in.Jisho
+		case lemmaDictJMdict:
+			dict, err = newJMdict(in.LC, lemmaDictConfig.JMdictPath)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unknown lemma dictionary %q", name)
+		}
+		sources = append(sources, multidict.LemmaSource{
+			Name: name,
+			Dict: dict,
+		})
+	}
+	return sources, nil
+}
+
+func newJMdict(lc fx.Lifecycle, path string) (*jmdict.JMdict, error) {
+	dict, err := jmdict.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w (use japwords-jmdict-import to create it)", err)
+	}
+	lc.Append(fx.Hook{
+		OnStop: func(_ context.Context) error {
+			return dict.Close()
+		},
+	})
+	return dict, nil
+}
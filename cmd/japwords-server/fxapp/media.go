@@ -0,0 +1,58 @@
+package fxapp
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/Darkclainer/japwords/pkg/basicdict"
+	"github.com/Darkclainer/japwords/pkg/config"
+	"github.com/Darkclainer/japwords/pkg/media"
+)
+
+type MediaConfig struct {
+	Dir          string
+	MaxSize      int64
+	MaxTotalSize int64
+}
+
+func (c *MediaConfig) Equal(o any) bool {
+	oc, ok := o.(*MediaConfig)
+	if !ok {
+		return false
+	}
+	return *c == *oc
+}
+
+// NewMedia returns store for downloaded audio or nil if it's disabled in config.
+func NewMedia(configMgr *config.Manager, f basicdict.Fetcher, logger *zap.Logger) (*media.Store, error) {
+	part, _, err := configMgr.Register(config.ConsumerFunc(func(uc *config.UserConfig) (config.Part, error) {
+		mediaConfig := &MediaConfig{
+			MaxSize:      uc.Media.MaxSize,
+			MaxTotalSize: uc.Media.MaxTotalSize,
+		}
+		if uc.Media.Path != "" {
+			mediaConfig.Dir = configMgr.ResolvePath(uc.Media.Path)
+		}
+		if mediaConfig.MaxSize < 0 {
+			return nil, fmt.Errorf("media max size should not be negative")
+		}
+		if mediaConfig.MaxTotalSize < 0 {
+			return nil, fmt.Errorf("media max total size should not be negative")
+		}
+		return mediaConfig, nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+	mediaConfig := part.(*MediaConfig)
+	if mediaConfig.Dir == "" {
+		return nil, nil
+	}
+	return media.New(f, &media.Options{
+		Dir:          mediaConfig.Dir,
+		MaxSize:      mediaConfig.MaxSize,
+		MaxTotalSize: mediaConfig.MaxTotalSize,
+		Logger:       logger,
+	})
+}
@@ -6,49 +6,31 @@ import (
 	"go.uber.org/fx"
 
 	"github.com/Darkclainer/japwords/pkg/config"
+	"github.com/Darkclainer/japwords/pkg/dictconfig"
 	"github.com/Darkclainer/japwords/pkg/multidict"
 )
 
-type MultiDictConfig struct {
-	Workers int
-}
-
-func (c *MultiDictConfig) Equal(o any) bool {
-	oc, ok := o.(*MultiDictConfig)
-	if !ok {
-		return false
-	}
-	return c.Workers == oc.Workers
-}
-
 type MultiDictIn struct {
 	fx.In
 
-	LC        fx.Lifecycle
-	ConfigMgr *config.Manager
-	LemmaDict multidict.LemmaDict
-	PitchDict multidict.PitchDict
+	LC         fx.Lifecycle
+	ConfigMgr  *config.Manager
+	LemmaDicts []multidict.LemmaSource
+	PitchDicts []multidict.PitchSource
 }
 
 func NewMultidict(in MultiDictIn) (*multidict.MultiDict, error) {
-	part, _, err := in.ConfigMgr.Register(config.ConsumerFunc(func(uc *config.UserConfig) (config.Part, error) {
-		return &MultiDictConfig{
-			Workers: uc.Dictionary.Workers,
-		}, nil
-	}))
-	if err != nil {
-		return nil, err
-	}
-	mdConfig := part.(*MultiDictConfig)
-
+	// workers and merge policy are set by reloader
 	dict, err := multidict.New(&multidict.Options{
-		Workers:   mdConfig.Workers,
-		LemmaDict: in.LemmaDict,
-		PitchDict: in.PitchDict,
+		LemmaDicts: in.LemmaDicts,
+		PitchDicts: in.PitchDicts,
 	})
 	if err != nil {
 		return nil, err
 	}
+	if _, _, err := in.ConfigMgr.Register(dictconfig.NewMultiDictReloader(dict)); err != nil {
+		return nil, err
+	}
 	in.LC.Append(fx.Hook{
 		OnStart: func(_ context.Context) error {
 			dict.Init()
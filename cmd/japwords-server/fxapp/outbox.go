@@ -0,0 +1,101 @@
+package fxapp
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/Darkclainer/japwords/pkg/anki"
+	"github.com/Darkclainer/japwords/pkg/config"
+	"github.com/Darkclainer/japwords/pkg/history"
+	"github.com/Darkclainer/japwords/pkg/outbox"
+)
+
+// outboxFlushTimeout limits single flush of outbox, remaining notes are added when Anki reconnects next time
+const outboxFlushTimeout = 5 * time.Minute
+
+type OutboxConfig struct {
+	Path string
+}
+
+func (c *OutboxConfig) Equal(o any) bool {
+	oc, ok := o.(*OutboxConfig)
+	if !ok {
+		return false
+	}
+	return *c == *oc
+}
+
+type OutboxIn struct {
+	fx.In
+
+	LC        fx.Lifecycle
+	ConfigMgr *config.Manager
+	Anki      *anki.Anki
+	History   *history.Store
+	Logger    *zap.Logger
+}
+
+// NewOutbox returns outbox for notes that are added while Anki is unreachable or nil if it's disabled in config.
+// Queued notes are added every time Anki becomes available.
+func NewOutbox(in OutboxIn) (*outbox.Outbox, error) {
+	part, _, err := in.ConfigMgr.Register(config.ConsumerFunc(func(uc *config.UserConfig) (config.Part, error) {
+		outboxConfig := &OutboxConfig{}
+		if uc.Outbox.Path != "" {
+			outboxConfig.Path = in.ConfigMgr.ResolvePath(uc.Outbox.Path)
+		}
+		return outboxConfig, nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+	outboxConfig := part.(*OutboxConfig)
+	if outboxConfig.Path == "" {
+		return nil, nil
+	}
+	ob, err := outbox.Open(outboxConfig.Path, outbox.Options{
+		Anki: in.Anki,
+		OnAdded: func(entry *outbox.Entry, noteID anki.NoteID) {
+			if in.History == nil || entry.HistoryID == 0 {
+				return
+			}
+			err := in.History.MarkNoteAdded(entry.HistoryID)
+			if err != nil && !errors.Is(err, history.ErrNotFound) {
+				in.Logger.Warn("history entry is not marked", zap.Uint64("id", entry.HistoryID), zap.Error(err))
+			}
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	// stopCtx cancels flush that is in progress, so application can be stopped
+	stopCtx, stop := context.WithCancel(context.Background())
+	in.LC.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			in.Anki.SetConnectedHandler(func() {
+				ctx, cancel := context.WithTimeout(stopCtx, outboxFlushTimeout)
+				defer cancel()
+				result, err := ob.Flush(ctx)
+				if result.Added+result.Duplicates+result.Failed == 0 && err == nil {
+					return
+				}
+				in.Logger.Info("outbox is flushed",
+					zap.Int("added", result.Added),
+					zap.Int("duplicates", result.Duplicates),
+					zap.Int("failed", result.Failed),
+					zap.Error(err),
+				)
+			})
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			in.Anki.SetConnectedHandler(nil)
+			stop()
+			return ob.Close()
+		},
+	})
+	return ob, nil
+}
@@ -0,0 +1,80 @@
+package fxapp
+
+import (
+	"fmt"
+	"slices"
+
+	"go.uber.org/fx"
+
+	"github.com/Darkclainer/japwords/pkg/accentdict"
+	"github.com/Darkclainer/japwords/pkg/cachedict"
+	"github.com/Darkclainer/japwords/pkg/config"
+	"github.com/Darkclainer/japwords/pkg/lemma"
+	"github.com/Darkclainer/japwords/pkg/multidict"
+)
+
+const (
+	pitchDictWadoku  = "wadoku"
+	pitchDictAccents = "accents"
+)
+
+type PitchDictConfig struct {
+	Names       []string
+	AccentsPath string
+}
+
+func (c *PitchDictConfig) Equal(o any) bool {
+	oc, ok := o.(*PitchDictConfig)
+	if !ok {
+		return false
+	}
+	return slices.Equal(c.Names, oc.Names) && c.AccentsPath == oc.AccentsPath
+}
+
+type PitchDictIn struct {
+	fx.In
+
+	ConfigMgr *config.Manager
+	Wadoku    *cachedict.CacheDict[[]*lemma.PitchedLemma]
+}
+
+// NewPitchDicts returns pitch dictionaries selected in config in the same order.
+func NewPitchDicts(in PitchDictIn) ([]multidict.PitchSource, error) {
+	part, _, err := in.ConfigMgr.Register(config.ConsumerFunc(func(uc *config.UserConfig) (config.Part, error) {
+		return &PitchDictConfig{
+			Names:       uc.Dictionary.PitchDicts,
+			AccentsPath: in.ConfigMgr.ResolvePath(uc.Dictionary.Accents.Path),
+		}, nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+	pitchDictConfig := part.(*PitchDictConfig)
+	names := pitchDictConfig.Names
+	if len(names) == 0 {
+		names = []string{pitchDictWadoku}
+	}
+	var sources []multidict.PitchSource
+	for _, name := range names {
+		if slices.ContainsFunc(sources, func(s multidict.PitchSource) bool { return s.Name == name }) {
+			return nil, fmt.Errorf("pitch dictionary %q specified several times", name)
+		}
+		var dict multidict.PitchDict
+		switch name {
+		case pitchDictWadoku:
+			dict = in.Wadoku
+		case pitchDictAccents:
+			dict, err = accentdict.Open(pitchDictConfig.AccentsPath)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unknown pitch dictionary %q", name)
+		}
+		sources = append(sources, multidict.PitchSource{
+			Name: name,
+			Dict: dict,
+		})
+	}
+	return sources, nil
+}
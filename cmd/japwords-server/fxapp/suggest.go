@@ -0,0 +1,112 @@
+package fxapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/Darkclainer/japwords/pkg/cachedict"
+	"github.com/Darkclainer/japwords/pkg/history"
+	"github.com/Darkclainer/japwords/pkg/jmdict"
+	"github.com/Darkclainer/japwords/pkg/kana"
+	"github.com/Darkclainer/japwords/pkg/lemma"
+	"github.com/Darkclainer/japwords/pkg/multidict"
+	"github.com/Darkclainer/japwords/pkg/suggest"
+)
+
+type SuggestIn struct {
+	fx.In
+
+	LC         fx.Lifecycle
+	Logger     *zap.Logger
+	LemmaDicts []multidict.LemmaSource
+	// CacheStore and History are nil if they are disabled
+	CacheStore *cachedict.Store
+	History    *history.Store
+}
+
+// NewSuggestIndex returns index for query suggestions. Index is filled in background from history,
+// cache of online dictionaries and offline dictionaries, so it can be incomplete right after start.
+func NewSuggestIndex(in SuggestIn) *suggest.Index {
+	index := suggest.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	// wait OnStart goroutine inside OnStop
+	var wg sync.WaitGroup
+	in.LC.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				err := loadSuggestions(ctx, index, in)
+				if err != nil && !errors.Is(err, context.Canceled) {
+					in.Logger.Warn("suggestions are loaded partially", zap.Error(err))
+					return
+				}
+				in.Logger.Debug("suggestions are loaded", zap.Int("words", index.Len()))
+			}()
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			cancel()
+			wg.Wait()
+			return nil
+		},
+	})
+	return index
+}
+
+func loadSuggestions(ctx context.Context, index *suggest.Index, in SuggestIn) error {
+	if in.History != nil {
+		entries, _, err := in.History.List(0, 0, nil)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			// queries without result are probably typos
+			if entry.ResultCount != 0 {
+				index.AddUsage(kana.NormalizeQuery(entry.Query), entry.Time)
+			}
+		}
+	}
+	if in.CacheStore != nil {
+		var words []suggest.Word
+		err := in.CacheStore.ForEach(lemmaDictJisho, func(_ string, value []byte) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			var lemmas []*lemma.Lemma
+			if err := json.Unmarshal(value, &lemmas); err != nil {
+				// cache can contain values of older versions, they are just skipped
+				return nil
+			}
+			for _, l := range lemmas {
+				words = append(words, suggest.LemmaWords(l)...)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		index.AddWords(words...)
+	}
+	for _, source := range in.LemmaDicts {
+		dict, ok := source.Dict.(*jmdict.JMdict)
+		if !ok {
+			continue
+		}
+		var words []suggest.Word
+		err := dict.ForEachLemma(ctx, func(l *lemma.Lemma) error {
+			words = append(words, suggest.LemmaWords(l)...)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		index.AddWords(words...)
+	}
+	return nil
+}
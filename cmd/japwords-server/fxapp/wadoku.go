@@ -3,32 +3,19 @@ package fxapp
 import (
 	"github.com/Darkclainer/japwords/pkg/cachedict"
 	"github.com/Darkclainer/japwords/pkg/config"
+	"github.com/Darkclainer/japwords/pkg/dictconfig"
 	"github.com/Darkclainer/japwords/pkg/lemma"
 	"github.com/Darkclainer/japwords/pkg/wadoku"
 )
 
-type WadokuConfig struct {
-	URL string
-}
-
-func (c *WadokuConfig) Equal(o any) bool {
-	oc, ok := o.(*WadokuConfig)
-	if !ok {
-		return false
-	}
-	return c.URL == oc.URL
-}
-
-func NewWadoku(wadokuClient wadoku.BasicDict, configMgr *config.Manager) (*cachedict.CacheDict[[]*lemma.PitchedLemma], error) {
-	part, _, err := configMgr.Register(config.ConsumerFunc(func(uc *config.UserConfig) (config.Part, error) {
-		return &WadokuConfig{
-			URL: uc.Dictionary.Wadoku.URL,
-		}, nil
-	}))
-	if err != nil {
+func NewWadoku(wadokuClient wadoku.BasicDict, configMgr *config.Manager, store *cachedict.Store) (*cachedict.CacheDict[[]*lemma.PitchedLemma], error) {
+	// base url is set by reloader
+	dict := wadoku.New(wadokuClient, "")
+	if _, _, err := configMgr.Register(dictconfig.NewWadokuReloader(dict)); err != nil {
 		return nil, err
 	}
-	wadokuConfig := part.(*WadokuConfig)
-	dict := wadoku.New(wadokuClient, wadokuConfig.URL)
-	return cachedict.New[[]*lemma.PitchedLemma](dict)
+	return cachedict.New[[]*lemma.PitchedLemma](dict, cachedict.Options{
+		Name:  "wadoku",
+		Store: store,
+	})
 }
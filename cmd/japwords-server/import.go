@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Darkclainer/japwords/cmd/japwords-server/fxapp"
+	"github.com/Darkclainer/japwords/pkg/anki"
+	"github.com/Darkclainer/japwords/pkg/examples"
+	"github.com/Darkclainer/japwords/pkg/httpfixture"
+	"github.com/Darkclainer/japwords/pkg/kanji"
+	"github.com/Darkclainer/japwords/pkg/multidict"
+	"github.com/Darkclainer/japwords/pkg/wordimport"
+)
+
+type importFlagOpts struct {
+	ConfigPath    string
+	ConfigPathSet bool
+	Format        string
+	Senses        string
+	ProgressPath  string
+	ReportPath    string
+	InputPath     string
+}
+
+func parseImportFlags(args []string) (*importFlagOpts, error) {
+	fset := newFlagSet(cliName+" import", printImportUsage)
+	var flagOpts importFlagOpts
+	fset.StringVar(&flagOpts.ConfigPath, "c", "config.yaml", "path to config")
+	fset.StringVar(&flagOpts.Format, "format", "auto", "format of word list: auto, text, csv")
+	fset.StringVar(&flagOpts.Senses, "senses", string(wordimport.SensePolicyFirst), "what senses become notes: first, all")
+	fset.StringVar(&flagOpts.ProgressPath, "progress", "", "file where progress is saved (default <input>.progress)")
+	fset.StringVar(&flagOpts.ReportPath, "report", "", "file where report is written (default stdout)")
+	err := fset.Parse(args)
+	if err != nil {
+		// because we use flag.ExitOnError
+		panic("unreachable")
+	}
+	fset.Visit(func(f *flag.Flag) {
+		if f.Name == "c" {
+			flagOpts.ConfigPathSet = true
+		}
+	})
+	if fset.NArg() != 1 {
+		fset.Usage()
+		return nil, fmt.Errorf("expected exactly one word list, got %d", fset.NArg())
+	}
+	flagOpts.InputPath = fset.Arg(0)
+	if flagOpts.ProgressPath == "" {
+		flagOpts.ProgressPath = flagOpts.InputPath + ".progress"
+	}
+	return &flagOpts, nil
+}
+
+func printImportUsage(f *flag.FlagSet, name string) {
+	fmt.Fprintf(f.Output(), "Usage:\n  %s [flags] <word list>\n", name)
+	fmt.Fprint(f.Output(), `
+Looks up every word of word list and adds notes for it to Anki. Words that already
+have notes are skipped. Interrupted import is resumed from progress file.
+`)
+
+	// print flags
+	fmt.Fprint(f.Output(), "\nflags:\n")
+	f.PrintDefaults()
+}
+
+func runImport(args []string) int {
+	flagOpts, err := parseImportFlags(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error! %s\n", err)
+		return 2
+	}
+	format := wordimport.DetectFormat(flagOpts.InputPath)
+	if flagOpts.Format != "auto" {
+		format, err = wordimport.ParseFormat(flagOpts.Format)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error! %s\n", err)
+			return 2
+		}
+	}
+	sensePolicy, err := wordimport.ParseSensePolicy(flagOpts.Senses)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error! %s\n", err)
+		return 2
+	}
+	words, err := readWordList(flagOpts.InputPath, format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error! Failed to read word list: %s\n", err)
+		return 2
+	}
+	configMgr, err := openCommandConfig(flagOpts.ConfigPath, flagOpts.ConfigPathSet)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error! Failed to read config: %s\n", err)
+		return 2
+	}
+
+	var (
+		multiDict     *multidict.MultiDict
+		ankiClient    *anki.Anki
+		examplesIndex *examples.Index
+		kanjiDict     *kanji.Dict
+	)
+	app, err := fxapp.NewCommandApp(
+		configMgr,
+		&fxapp.Options{HTTPMode: httpfixture.ModeOff},
+		&multiDict, &ankiClient, &examplesIndex, &kanjiDict,
+	)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error! Failed to create application: %s\n", err)
+		return 3
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := app.Start(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Error! Failed to start application: %s\n", err)
+		return 3
+	}
+	defer func() {
+		_ = app.Stop(context.Background())
+	}()
+
+	progress, err := wordimport.OpenProgress(flagOpts.ProgressPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error! Failed to open progress file: %s\n", err)
+		return 2
+	}
+	importer := wordimport.New(&wordimport.Options{
+		Dict:        multiDict,
+		Anki:        ankiClient,
+		SensePolicy: sensePolicy,
+		Projector:   newProjector(examplesIndex, kanjiDict),
+	})
+	results, err := importer.Run(ctx, words, progress, func(result *wordimport.Result) {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", result.Word, result.Status)
+	})
+	if err != nil {
+		_ = progress.Close()
+		fmt.Fprintf(os.Stderr, "Error! Import stopped after %d of %d words: %s\n", len(results), len(words), err)
+		fmt.Fprintf(os.Stderr, "Run the same command again to resume.\n")
+		return 1
+	}
+	if err := writeImportReport(flagOpts.ReportPath, results); err != nil {
+		_ = progress.Close()
+		fmt.Fprintf(os.Stderr, "Error! Failed to write report: %s\n", err)
+		return 1
+	}
+	allDone := true
+	for _, result := range results {
+		allDone = allDone && result.Done()
+	}
+	if !allDone {
+		// failed words are tried again on the next run
+		_ = progress.Close()
+		return 1
+	}
+	if err := progress.Remove(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error! Failed to remove progress file: %s\n", err)
+	}
+	return 0
+}
+
+func readWordList(path string, format wordimport.Format) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return wordimport.ReadWords(file, format)
+}
+
+func writeImportReport(path string, results []*wordimport.Result) error {
+	var w io.Writer = os.Stdout
+	if path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+	return wordimport.WriteReport(w, results)
+}
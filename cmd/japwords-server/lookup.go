@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/Darkclainer/japwords/cmd/japwords-server/fxapp"
+	"github.com/Darkclainer/japwords/pkg/anki"
+	"github.com/Darkclainer/japwords/pkg/examples"
+	"github.com/Darkclainer/japwords/pkg/httpfixture"
+	"github.com/Darkclainer/japwords/pkg/kanji"
+	"github.com/Darkclainer/japwords/pkg/lemmalookup"
+	"github.com/Darkclainer/japwords/pkg/lemmaprint"
+	"github.com/Darkclainer/japwords/pkg/multidict"
+)
+
+type lookupFlagOpts struct {
+	ConfigPath    string
+	ConfigPathSet bool
+	Format        string
+	Query         string
+}
+
+func parseLookupFlags(args []string) (*lookupFlagOpts, error) {
+	fset := newFlagSet(cliName+" lookup", printLookupUsage)
+	var flagOpts lookupFlagOpts
+	fset.StringVar(&flagOpts.ConfigPath, "c", "config.yaml", "path to config")
+	fset.StringVar(&flagOpts.Format, "format", string(lemmaprint.FormatTable), "output format: table, json, yaml")
+	err := fset.Parse(args)
+	if err != nil {
+		// because we use flag.ExitOnError
+		panic("unreachable")
+	}
+	fset.Visit(func(f *flag.Flag) {
+		if f.Name == "c" {
+			flagOpts.ConfigPathSet = true
+		}
+	})
+	flagOpts.Query = strings.TrimSpace(strings.Join(fset.Args(), " "))
+	if flagOpts.Query == "" {
+		fset.Usage()
+		return nil, fmt.Errorf("query is not specified")
+	}
+	return &flagOpts, nil
+}
+
+func printLookupUsage(f *flag.FlagSet, name string) {
+	fmt.Fprintf(f.Output(), "Usage:\n  %s [flags] <query>\n", name)
+	fmt.Fprint(f.Output(), `
+Looks up query in dictionaries and prints lemmas with furigana, pitch, definitions
+and status of their notes in Anki.
+`)
+
+	// print flags
+	fmt.Fprint(f.Output(), "\nflags:\n")
+	f.PrintDefaults()
+}
+
+func runLookup(args []string) int {
+	flagOpts, err := parseLookupFlags(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error! %s\n", err)
+		return 2
+	}
+	format, err := lemmaprint.ParseFormat(flagOpts.Format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error! %s\n", err)
+		return 2
+	}
+	configMgr, err := openCommandConfig(flagOpts.ConfigPath, flagOpts.ConfigPathSet)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error! Failed to read config: %s\n", err)
+		return 2
+	}
+
+	var (
+		multiDict     *multidict.MultiDict
+		ankiClient    *anki.Anki
+		examplesIndex *examples.Index
+		kanjiDict     *kanji.Dict
+	)
+	app, err := fxapp.NewCommandApp(
+		configMgr,
+		&fxapp.Options{HTTPMode: httpfixture.ModeOff},
+		&multiDict, &ankiClient, &examplesIndex, &kanjiDict,
+	)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error! Failed to create application: %s\n", err)
+		return 3
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := app.Start(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Error! Failed to start application: %s\n", err)
+		return 3
+	}
+	defer func() {
+		_ = app.Stop(context.Background())
+	}()
+
+	lemmas, _, _, err := lemmalookup.Lookup(ctx, multiDict, flagOpts.Query)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error! Lookup failed: %s\n", err)
+		return 1
+	}
+	projectedLemmas := newProjector(examplesIndex, kanjiDict).Project(lemmas)
+	var noteIDs []anki.NoteID
+	if len(projectedLemmas) != 0 {
+		noteIDs, err = ankiClient.SearchProjectedLemmas(ctx, projectedLemmas)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning! Failed to find notes in Anki: %s\n", err)
+			noteIDs = nil
+		}
+	}
+	if err := lemmaprint.Write(os.Stdout, format, lemmaprint.NewEntries(projectedLemmas, noteIDs)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error! Failed to print lemmas: %s\n", err)
+		return 1
+	}
+	return 0
+}
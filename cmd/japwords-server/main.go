@@ -6,16 +6,30 @@ import (
 
 	"github.com/Darkclainer/japwords/cmd/japwords-server/fxapp"
 	"github.com/Darkclainer/japwords/pkg/config"
+	"github.com/Darkclainer/japwords/pkg/httpfixture"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			os.Exit(cmd.Run(os.Args[2:]))
+		}
+	}
 	flagOpts := ParseFlags()
+	httpMode, err := httpfixture.ParseMode(flagOpts.HTTPMode)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error! %s\n", err)
+		os.Exit(2)
+	}
 	configMgr, err := prepareConfig(flagOpts.ConfigPath, flagOpts.ConfigPathSet)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error! Failed to read config: %s\n", err)
 		os.Exit(2)
 	}
-	app, err := fxapp.NewApp(configMgr)
+	app, err := fxapp.NewApp(configMgr, &fxapp.Options{
+		HTTPMode:   httpMode,
+		FixtureDir: flagOpts.FixtureDir,
+	})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error! Failed to create application: %s\n", err)
 		os.Exit(3)
@@ -3,18 +3,37 @@
 package gqlmodel
 
 import (
+	"fmt"
+	"io"
+	"strconv"
+
 	"github.com/Darkclainer/japwords/pkg/anki"
+	"github.com/Darkclainer/japwords/pkg/deinflect"
+	"github.com/Darkclainer/japwords/pkg/history"
 	"github.com/Darkclainer/japwords/pkg/lemma"
+	"github.com/Darkclainer/japwords/pkg/outbox"
 )
 
 type AnkiAddNoteError interface {
 	IsAnkiAddNoteError()
 }
 
+type AnkiAddNotesError interface {
+	IsAnkiAddNotesError()
+}
+
+type AnkiAddNotesItemError interface {
+	IsAnkiAddNotesItemError()
+}
+
 type AnkiError interface {
 	IsAnkiError()
 }
 
+type AnkiUpdateNoteError interface {
+	IsAnkiUpdateNoteError()
+}
+
 type CreateAnkiDeckError interface {
 	IsCreateAnkiDeckError()
 }
@@ -23,21 +42,64 @@ type CreateDefaultAnkiNoteError interface {
 	IsCreateDefaultAnkiNoteError()
 }
 
+type DeleteHistoryEntryError interface {
+	IsDeleteHistoryEntryError()
+}
+
+type DiscardAnkiOutboxEntryError interface {
+	IsDiscardAnkiOutboxEntryError()
+}
+
+type EditAnkiOutboxEntryError interface {
+	IsEditAnkiOutboxEntryError()
+}
+
 type Error interface {
 	IsError()
 	GetMessage() string
 }
 
+type FlushAnkiOutboxError interface {
+	IsFlushAnkiOutboxError()
+}
+
+type LemmasError interface {
+	IsLemmasError()
+}
+
+type LemmasWarning interface {
+	IsLemmasWarning()
+}
+
 type PrepareLemmaError interface {
 	IsPrepareLemmaError()
 }
 
+type RetryAnkiOutboxEntryError interface {
+	IsRetryAnkiOutboxEntryError()
+}
+
 type Anki struct {
 	Decks      *AnkiDecksResult      `json:"decks"`
 	Notes      *AnkiNotesResult      `json:"notes"`
 	NoteFields *AnkiNoteFieldsResult `json:"noteFields"`
 }
 
+type AnkiAddNoteAudioUnavailable struct {
+	Message string `json:"message"`
+}
+
+func (AnkiAddNoteAudioUnavailable) IsError()                {}
+func (this AnkiAddNoteAudioUnavailable) GetMessage() string { return this.Message }
+
+func (AnkiAddNoteAudioUnavailable) IsAnkiAddNoteError() {}
+
+func (AnkiAddNoteAudioUnavailable) IsAnkiAddNotesItemError() {}
+
+func (AnkiAddNoteAudioUnavailable) IsAnkiUpdateNoteError() {}
+
+func (AnkiAddNoteAudioUnavailable) IsRetryAnkiOutboxEntryError() {}
+
 type AnkiAddNoteDuplicateFound struct {
 	Message string `json:"message"`
 }
@@ -47,10 +109,38 @@ func (this AnkiAddNoteDuplicateFound) GetMessage() string { return this.Message
 
 func (AnkiAddNoteDuplicateFound) IsAnkiAddNoteError() {}
 
+func (AnkiAddNoteDuplicateFound) IsAnkiAddNotesItemError() {}
+
+func (AnkiAddNoteDuplicateFound) IsRetryAnkiOutboxEntryError() {}
+
+type AnkiAddNoteFailed struct {
+	Message string `json:"message"`
+}
+
+func (AnkiAddNoteFailed) IsError()                {}
+func (this AnkiAddNoteFailed) GetMessage() string { return this.Message }
+
+func (AnkiAddNoteFailed) IsAnkiAddNotesItemError() {}
+
+func (AnkiAddNoteFailed) IsRetryAnkiOutboxEntryError() {}
+
 type AnkiAddNoteResult struct {
 	NoteID    string           `json:"noteID"`
 	Error     AnkiAddNoteError `json:"error,omitempty"`
 	AnkiError AnkiError        `json:"ankiError,omitempty"`
+	OutboxID  *string          `json:"outboxID,omitempty"`
+}
+
+type AnkiAddNotesItem struct {
+	NoteID   string                `json:"noteID"`
+	Error    AnkiAddNotesItemError `json:"error,omitempty"`
+	OutboxID *string               `json:"outboxID,omitempty"`
+}
+
+type AnkiAddNotesResult struct {
+	Results   []*AnkiAddNotesItem `json:"results"`
+	Error     AnkiAddNotesError   `json:"error,omitempty"`
+	AnkiError AnkiError           `json:"ankiError,omitempty"`
 }
 
 type AnkiCollectionUnavailable struct {
@@ -71,6 +161,7 @@ type AnkiConfig struct {
 	Mapping            []*AnkiMappingElement `json:"mapping"`
 	AudioField         string                `json:"audioField"`
 	AudioPreferredType string                `json:"audioPreferredType"`
+	Tags               *AnkiConfigTags       `json:"tags"`
 }
 
 type AnkiConfigMappingElementError struct {
@@ -106,6 +197,26 @@ type AnkiConfigStateResult struct {
 	Error           AnkiError        `json:"error,omitempty"`
 }
 
+type AnkiConfigTags struct {
+	Static []string `json:"static"`
+	Rules  []string `json:"rules"`
+}
+
+type AnkiConfigTagsElementError struct {
+	Index   int    `json:"index"`
+	Value   string `json:"value"`
+	Message string `json:"message"`
+}
+
+type AnkiConfigTagsError struct {
+	StaticErrors []*AnkiConfigTagsElementError `json:"staticErrors"`
+	RuleErrors   []*AnkiConfigTagsElementError `json:"ruleErrors"`
+	Message      string                        `json:"message"`
+}
+
+func (AnkiConfigTagsError) IsError()                {}
+func (this AnkiConfigTagsError) GetMessage() string { return this.Message }
+
 type AnkiConnectionError struct {
 	Message string `json:"message"`
 }
@@ -140,6 +251,12 @@ func (AnkiIncompleteConfiguration) IsPrepareLemmaError() {}
 
 func (AnkiIncompleteConfiguration) IsAnkiAddNoteError() {}
 
+func (AnkiIncompleteConfiguration) IsAnkiAddNotesError() {}
+
+func (AnkiIncompleteConfiguration) IsRetryAnkiOutboxEntryError() {}
+
+func (AnkiIncompleteConfiguration) IsFlushAnkiOutboxError() {}
+
 type AnkiInvalidAPIKey struct {
 	Message string `json:"message"`
 	Version int    `json:"version"`
@@ -150,6 +267,16 @@ func (this AnkiInvalidAPIKey) GetMessage() string { return this.Message }
 
 func (AnkiInvalidAPIKey) IsAnkiError() {}
 
+type AnkiLookupFailed struct {
+	AnkiError AnkiError `json:"ankiError"`
+	Message   string    `json:"message"`
+}
+
+func (AnkiLookupFailed) IsLemmasWarning() {}
+
+func (AnkiLookupFailed) IsError()                {}
+func (this AnkiLookupFailed) GetMessage() string { return this.Message }
+
 type AnkiMappingElement struct {
 	Key   string `json:"key"`
 	Value string `json:"value"`
@@ -165,6 +292,24 @@ type AnkiNotesResult struct {
 	Error AnkiError `json:"error,omitempty"`
 }
 
+type AnkiOutboxEntryNotFound struct {
+	Message string `json:"message"`
+}
+
+func (AnkiOutboxEntryNotFound) IsError()                {}
+func (this AnkiOutboxEntryNotFound) GetMessage() string { return this.Message }
+
+func (AnkiOutboxEntryNotFound) IsEditAnkiOutboxEntryError() {}
+
+func (AnkiOutboxEntryNotFound) IsRetryAnkiOutboxEntryError() {}
+
+func (AnkiOutboxEntryNotFound) IsDiscardAnkiOutboxEntryError() {}
+
+type AnkiOutboxResult struct {
+	Enabled bool            `json:"enabled"`
+	Entries []*outbox.Entry `json:"entries"`
+}
+
 type AnkiUnknownError struct {
 	Message string `json:"message"`
 }
@@ -174,6 +319,30 @@ func (this AnkiUnknownError) GetMessage() string { return this.Message }
 
 func (AnkiUnknownError) IsAnkiError() {}
 
+type AnkiUpdateNoteNotFound struct {
+	Message string `json:"message"`
+}
+
+func (AnkiUpdateNoteNotFound) IsError()                {}
+func (this AnkiUpdateNoteNotFound) GetMessage() string { return this.Message }
+
+func (AnkiUpdateNoteNotFound) IsAnkiUpdateNoteError() {}
+
+type AnkiUpdateNoteResult struct {
+	Fields    []*anki.NoteFieldDiff `json:"fields"`
+	AddedTags []string              `json:"addedTags"`
+	Error     AnkiUpdateNoteError   `json:"error,omitempty"`
+	AnkiError AnkiError             `json:"ankiError,omitempty"`
+}
+
+type ClearCacheResult struct {
+	Nothing *bool `json:"nothing,omitempty"`
+}
+
+type ClearHistoryResult struct {
+	Nothing *bool `json:"nothing,omitempty"`
+}
+
 type CreateAnkiDeckAlreadyExists struct {
 	Message string `json:"message"`
 }
@@ -210,15 +379,136 @@ type CreateDefaultAnkiNoteResult struct {
 	Error     CreateDefaultAnkiNoteError `json:"error,omitempty"`
 }
 
+type DeleteHistoryEntryResult struct {
+	Error DeleteHistoryEntryError `json:"error,omitempty"`
+}
+
+type DictionaryConfig struct {
+	Workers       int                 `json:"workers"`
+	MergePolicy   string              `json:"mergePolicy"`
+	UserAgent     string              `json:"userAgent"`
+	Headers       []*DictionaryHeader `json:"headers"`
+	JishoURL      string              `json:"jishoURL"`
+	WadokuURL     string              `json:"wadokuURL"`
+	Requests      *DictionaryRequests `json:"requests"`
+	Timeout       string              `json:"timeout"`
+	SourceTimeout string              `json:"sourceTimeout"`
+	SoftPitch     bool                `json:"softPitch"`
+}
+
+type DictionaryHeader struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type DictionaryHeaderInput struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type DictionaryRequests struct {
+	Timeout        string  `json:"timeout"`
+	Retries        int     `json:"retries"`
+	RetryBaseDelay string  `json:"retryBaseDelay"`
+	RetryMaxDelay  string  `json:"retryMaxDelay"`
+	RateLimit      float64 `json:"rateLimit"`
+	RateBurst      int     `json:"rateBurst"`
+	MaxConcurrent  int     `json:"maxConcurrent"`
+}
+
+type DiscardAnkiOutboxEntryResult struct {
+	Error DiscardAnkiOutboxEntryError `json:"error,omitempty"`
+}
+
+type EditAnkiOutboxEntryResult struct {
+	Entry *outbox.Entry            `json:"entry,omitempty"`
+	Error EditAnkiOutboxEntryError `json:"error,omitempty"`
+}
+
+type FlushAnkiOutboxResult struct {
+	Added      int                  `json:"added"`
+	Duplicates int                  `json:"duplicates"`
+	Failed     int                  `json:"failed"`
+	Error      FlushAnkiOutboxError `json:"error,omitempty"`
+	AnkiError  AnkiError            `json:"ankiError,omitempty"`
+}
+
+type HistoryEntryNotFound struct {
+	Message string `json:"message"`
+}
+
+func (HistoryEntryNotFound) IsError()                {}
+func (this HistoryEntryNotFound) GetMessage() string { return this.Message }
+
+func (HistoryEntryNotFound) IsDeleteHistoryEntryError() {}
+
+type HistoryFilter struct {
+	Query     *string `json:"query,omitempty"`
+	NoteAdded *bool   `json:"noteAdded,omitempty"`
+}
+
+type HistoryResult struct {
+	Enabled bool             `json:"enabled"`
+	Entries []*history.Entry `json:"entries"`
+	Total   int              `json:"total"`
+}
+
 type LemmaNoteInfo struct {
 	Lemma  *lemma.ProjectedLemma `json:"lemma"`
 	NoteID string                `json:"noteID"`
 }
 
+type LemmaSourceUnavailable struct {
+	Source           string `json:"source"`
+	DeadlineExceeded bool   `json:"deadlineExceeded"`
+	Message          string `json:"message"`
+}
+
+func (LemmaSourceUnavailable) IsLemmasError() {}
+
+func (LemmaSourceUnavailable) IsError()                {}
+func (this LemmaSourceUnavailable) GetMessage() string { return this.Message }
+
 type LemmasResult struct {
-	Lemmas []*LemmaNoteInfo `json:"lemmas"`
+	Query           string               `json:"query"`
+	NormalizedQuery string               `json:"normalizedQuery"`
+	Lemmas          []*LemmaNoteInfo     `json:"lemmas"`
+	Deinflection    *deinflect.Candidate `json:"deinflection,omitempty"`
+	HistoryID       *string              `json:"historyID,omitempty"`
+	Errors          []LemmasError        `json:"errors"`
+	Warnings        []LemmasWarning      `json:"warnings"`
+}
+
+type LemmasStreamResult struct {
+	Result          *LemmasResult `json:"result"`
+	CompletedStages []LemmasStage `json:"completedStages"`
+	Done            bool          `json:"done"`
+}
+
+type ParseError struct {
+	Source  string `json:"source"`
+	Failed  int    `json:"failed"`
+	Message string `json:"message"`
 }
 
+func (ParseError) IsLemmasError() {}
+
+func (ParseError) IsLemmasWarning() {}
+
+func (ParseError) IsError()                {}
+func (this ParseError) GetMessage() string { return this.Message }
+
+type PitchSourceUnavailable struct {
+	Source           string `json:"source"`
+	DeadlineExceeded bool   `json:"deadlineExceeded"`
+	Message          string `json:"message"`
+}
+
+func (PitchSourceUnavailable) IsLemmasWarning() {}
+
+func (PitchSourceUnavailable) IsError()                {}
+func (this PitchSourceUnavailable) GetMessage() string { return this.Message }
+
 type PrepareLemmaResult struct {
 	Request   *anki.AddNoteRequest `json:"request,omitempty"`
 	Error     PrepareLemmaError    `json:"error,omitempty"`
@@ -237,6 +527,12 @@ type RenderedFields struct {
 	Fields        []*RenderedField `json:"fields"`
 }
 
+type RetryAnkiOutboxEntryResult struct {
+	NoteID    string                    `json:"noteID"`
+	Error     RetryAnkiOutboxEntryError `json:"error,omitempty"`
+	AnkiError AnkiError                 `json:"ankiError,omitempty"`
+}
+
 type SetAnkiConfigAudioFieldInput struct {
 	AudioField string `json:"audioField"`
 }
@@ -286,6 +582,66 @@ type SetAnkiConfigNoteResult struct {
 	Error *ValidationError `json:"error,omitempty"`
 }
 
+type SetAnkiConfigTagsInput struct {
+	Static []string `json:"static"`
+	Rules  []string `json:"rules"`
+}
+
+type SetAnkiConfigTagsResult struct {
+	Error *AnkiConfigTagsError `json:"error,omitempty"`
+}
+
+type SetDictionaryConfigHeadersInput struct {
+	UserAgent string                   `json:"userAgent"`
+	Headers   []*DictionaryHeaderInput `json:"headers"`
+}
+
+type SetDictionaryConfigHeadersResult struct {
+	Error *ValidationError `json:"error,omitempty"`
+}
+
+type SetDictionaryConfigRequestsInput struct {
+	Timeout        string  `json:"timeout"`
+	Retries        int     `json:"retries"`
+	RetryBaseDelay string  `json:"retryBaseDelay"`
+	RetryMaxDelay  string  `json:"retryMaxDelay"`
+	RateLimit      float64 `json:"rateLimit"`
+	RateBurst      int     `json:"rateBurst"`
+	MaxConcurrent  int     `json:"maxConcurrent"`
+}
+
+type SetDictionaryConfigRequestsResult struct {
+	Error *ValidationError `json:"error,omitempty"`
+}
+
+type SetDictionaryConfigTimeoutsInput struct {
+	Timeout       string `json:"timeout"`
+	SourceTimeout string `json:"sourceTimeout"`
+	SoftPitch     bool   `json:"softPitch"`
+}
+
+type SetDictionaryConfigTimeoutsResult struct {
+	Error *ValidationError `json:"error,omitempty"`
+}
+
+type SetDictionaryConfigURLsInput struct {
+	JishoURL  string `json:"jishoURL"`
+	WadokuURL string `json:"wadokuURL"`
+}
+
+type SetDictionaryConfigURLsResult struct {
+	Error *ValidationError `json:"error,omitempty"`
+}
+
+type SetDictionaryConfigWorkersInput struct {
+	Workers     int    `json:"workers"`
+	MergePolicy string `json:"mergePolicy"`
+}
+
+type SetDictionaryConfigWorkersResult struct {
+	Error *ValidationError `json:"error,omitempty"`
+}
+
 type ValidationError struct {
 	Paths   []string `json:"paths"`
 	Message string   `json:"message"`
@@ -297,3 +653,46 @@ func (ValidationError) IsCreateDefaultAnkiNoteError() {}
 
 func (ValidationError) IsError()                {}
 func (this ValidationError) GetMessage() string { return this.Message }
+
+type LemmasStage string
+
+const (
+	LemmasStageLemmas  LemmasStage = "LEMMAS"
+	LemmasStagePitches LemmasStage = "PITCHES"
+	LemmasStageNotes   LemmasStage = "NOTES"
+)
+
+var AllLemmasStage = []LemmasStage{
+	LemmasStageLemmas,
+	LemmasStagePitches,
+	LemmasStageNotes,
+}
+
+func (e LemmasStage) IsValid() bool {
+	switch e {
+	case LemmasStageLemmas, LemmasStagePitches, LemmasStageNotes:
+		return true
+	}
+	return false
+}
+
+func (e LemmasStage) String() string {
+	return string(e)
+}
+
+func (e *LemmasStage) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = LemmasStage(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid LemmasStage", str)
+	}
+	return nil
+}
+
+func (e LemmasStage) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
@@ -9,6 +9,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"slices"
 
 	"github.com/Darkclainer/japwords/graphql/gqlgenerated"
@@ -134,6 +135,25 @@ func (r *mutationResolver) SetAnkiConfigMapping(ctx context.Context, input gqlmo
 	return &gqlmodel.SetAnkiConfigMappingResult{}, nil
 }
 
+// SetAnkiConfigTags is the resolver for the setAnkiConfigTags field.
+func (r *mutationResolver) SetAnkiConfigTags(ctx context.Context, input gqlmodel.SetAnkiConfigTagsInput) (*gqlmodel.SetAnkiConfigTagsResult, error) {
+	err := r.ankiConfig.UpdateTags(input.Static, input.Rules)
+	if err != nil {
+		var ankiTagsErrs *anki.TagsValidationErrors
+		if !errors.As(err, &ankiTagsErrs) {
+			return nil, err
+		}
+		return &gqlmodel.SetAnkiConfigTagsResult{
+			Error: &gqlmodel.AnkiConfigTagsError{
+				StaticErrors: convertTagValidationErrors(ankiTagsErrs.StaticErrors),
+				RuleErrors:   convertTagValidationErrors(ankiTagsErrs.RuleErrors),
+				Message:      "invalid tags",
+			},
+		}, nil
+	}
+	return &gqlmodel.SetAnkiConfigTagsResult{}, nil
+}
+
 // SetAnkiConfigAudioField is the resolver for the setAnkiConfigAudioField field.
 func (r *mutationResolver) SetAnkiConfigAudioField(ctx context.Context, input gqlmodel.SetAnkiConfigAudioFieldInput) (*gqlmodel.SetAnkiConfigAudioFieldResult, error) {
 	err := r.ankiConfig.UpdateAudioField(input.AudioField)
@@ -200,7 +220,7 @@ func (r *mutationResolver) CreateDefaultAnkiNote(ctx context.Context, input *gql
 }
 
 // AddAnkiNote is the resolver for the addAnkiNote field.
-func (r *mutationResolver) AddAnkiNote(ctx context.Context, request *anki.AddNoteRequest) (*gqlmodel.AnkiAddNoteResult, error) {
+func (r *mutationResolver) AddAnkiNote(ctx context.Context, request *anki.AddNoteRequest, historyID *string) (*gqlmodel.AnkiAddNoteResult, error) {
 	noteID, err := r.ankiClient.AddNote(ctx, request)
 	if err != nil {
 		if errors.Is(err, anki.ErrDuplicatedNoteFound) {
@@ -217,19 +237,95 @@ func (r *mutationResolver) AddAnkiNote(ctx context.Context, request *anki.AddNot
 				},
 			}, nil
 		}
+		if errors.Is(err, anki.ErrAudioUnavailable) {
+			return &gqlmodel.AnkiAddNoteResult{
+				Error: &gqlmodel.AnkiAddNoteAudioUnavailable{
+					Message: err.Error(),
+				},
+			}, nil
+		}
 		if ankiErr, _ := convertAnkiError(err); ankiErr != nil {
 			return &gqlmodel.AnkiAddNoteResult{
 				AnkiError: ankiErr,
+				OutboxID:  r.queueNote(request, historyID, err),
 			}, nil
 		}
 		return nil, err
 
 	}
+	if historyID != nil {
+		r.markHistoryNoteAdded(*historyID)
+	}
 	return &gqlmodel.AnkiAddNoteResult{
 		NoteID: noteID.String(),
 	}, nil
 }
 
+// AddAnkiNotes is the resolver for the addAnkiNotes field.
+func (r *mutationResolver) AddAnkiNotes(ctx context.Context, requests []*anki.AddNoteRequest) (*gqlmodel.AnkiAddNotesResult, error) {
+	results, err := r.ankiClient.AddNotes(ctx, requests)
+	if err != nil {
+		if errors.Is(err, anki.ErrIncompleteConfiguration) {
+			return &gqlmodel.AnkiAddNotesResult{
+				Results: []*gqlmodel.AnkiAddNotesItem{},
+				Error: &gqlmodel.AnkiIncompleteConfiguration{
+					Message: err.Error(),
+				},
+			}, nil
+		}
+		if ankiErr, _ := convertAnkiError(err); ankiErr != nil {
+			return &gqlmodel.AnkiAddNotesResult{
+				Results:   r.queueNotes(requests, err),
+				AnkiError: ankiErr,
+			}, nil
+		}
+		return nil, err
+	}
+	items := make([]*gqlmodel.AnkiAddNotesItem, len(results))
+	for i := range results {
+		items[i] = convertAddNoteResult(&results[i])
+	}
+	return &gqlmodel.AnkiAddNotesResult{
+		Results: items,
+	}, nil
+}
+
+// UpdateAnkiNote is the resolver for the updateAnkiNote field.
+func (r *mutationResolver) UpdateAnkiNote(ctx context.Context, noteID string, request anki.AddNoteRequest, onlyEmpty bool, dryRun bool) (*gqlmodel.AnkiUpdateNoteResult, error) {
+	empty := &gqlmodel.AnkiUpdateNoteResult{
+		Fields:    []*anki.NoteFieldDiff{},
+		AddedTags: []string{},
+	}
+	id, err := parseNoteID(noteID)
+	if err == nil {
+		var result *anki.UpdateNoteResult
+		result, err = r.ankiClient.UpdateNote(ctx, id, &request, &anki.UpdateNoteOptions{
+			OnlyEmpty: onlyEmpty,
+			DryRun:    dryRun,
+		})
+		if err == nil {
+			return convertUpdateNoteResult(result), nil
+		}
+	}
+	if errors.Is(err, anki.ErrNoteNotFound) {
+		empty.Error = &gqlmodel.AnkiUpdateNoteNotFound{
+			Message: fmt.Sprintf("note %q not found", noteID),
+		}
+		return empty, nil
+	}
+	if errors.Is(err, anki.ErrAudioUnavailable) {
+		empty.Error = &gqlmodel.AnkiAddNoteAudioUnavailable{
+			Message: err.Error(),
+		}
+		return empty, nil
+	}
+	if ankiErr, _ := convertAnkiError(err); ankiErr != nil {
+		empty.AnkiError = ankiErr
+		return empty, nil
+	}
+	return nil, err
+}
+
 // Anki is the resolver for the Anki field.
 func (r *queryResolver) Anki(ctx context.Context) (*gqlmodel.Anki, error) {
 	return &gqlmodel.Anki{}, nil
@@ -270,6 +366,10 @@ func (r *queryResolver) AnkiConfig(ctx context.Context) (*gqlmodel.AnkiConfig, e
 		Mapping:            nil,
 		AudioField:         ankiConfig.Audio.Field,
 		AudioPreferredType: ankiConfig.Audio.PreferredType,
+		Tags: &gqlmodel.AnkiConfigTags{
+			Static: append([]string{}, ankiConfig.Tags.Static...),
+			Rules:  append([]string{}, ankiConfig.Tags.Rules...),
+		},
 	}
 	mapping := make([]*gqlmodel.AnkiMappingElement, 0, len(ankiConfig.FieldMapping))
 	for key, value := range ankiConfig.FieldMapping {
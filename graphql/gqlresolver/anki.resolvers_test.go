@@ -1,15 +1,20 @@
 package gqlresolver
 
 import (
+	"context"
 	"testing"
 
 	"github.com/99designs/gqlgen/client"
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/Darkclainer/japwords/graphql/gqlgenerated"
 	"github.com/Darkclainer/japwords/graphql/gqlmodel"
 	"github.com/Darkclainer/japwords/pkg/anki"
+	"github.com/Darkclainer/japwords/pkg/anki/ankiconnect"
+	"github.com/Darkclainer/japwords/pkg/config"
+	"github.com/Darkclainer/japwords/pkg/config/configtest"
 )
 
 func Test_queryResolver_RenderFields(t *testing.T) {
@@ -149,3 +154,313 @@ func Test_queryResolver_RenderFields(t *testing.T) {
 func valuePointer[T any](v T) *T {
 	return &v
 }
+
+func Test_mutationResolver_SetAnkiConfigTags(t *testing.T) {
+	configManager := configtest.New(t, &config.UserConfig{
+		Anki: config.Anki{
+			Addr:     "127.0.0.1:8765",
+			Deck:     "deck",
+			NoteType: "note",
+		},
+	})
+	ankiClient := anki.NewAnki(func(*anki.Config) (anki.StatefullClient, error) {
+		return unavailableAnki{}, nil
+	})
+	ankiConfig, err := anki.NewConfigReloader(ankiClient, configManager)
+	require.NoError(t, err)
+	resolvers := Resolver{
+		configManager: configManager,
+		ankiClient:    ankiClient,
+		ankiConfig:    ankiConfig,
+	}
+	c := client.New(handler.NewDefaultServer(gqlgenerated.NewExecutableSchema(gqlgenerated.Config{Resolvers: &resolvers})))
+
+	type ElementError struct {
+		Index int
+		Value string
+	}
+	type SetResponse struct {
+		SetAnkiConfigTags struct {
+			Error *struct {
+				StaticErrors []ElementError
+				RuleErrors   []ElementError
+			}
+		}
+	}
+	const setQuery = `
+		mutation($static: [String!]!, $rules: [String!]!) {
+			setAnkiConfigTags(input: {static: $static, rules: $rules}) {
+				error {
+					staticErrors {
+						index
+						value
+					}
+					ruleErrors {
+						index
+						value
+					}
+				}
+			}
+		}`
+	type ConfigResponse struct {
+		AnkiConfig struct {
+			Tags struct {
+				Static []string
+				Rules  []string
+			}
+		}
+	}
+	const configQuery = `query { AnkiConfig { tags { static rules } } }`
+
+	var configResp ConfigResponse
+	c.MustPost(configQuery, &configResp)
+	assert.Equal(t, []string{}, configResp.AnkiConfig.Tags.Static)
+	assert.Equal(t, []string{}, configResp.AnkiConfig.Tags.Rules)
+
+	var setResp SetResponse
+	c.MustPost(setQuery, &setResp,
+		client.Var("static", []string{"ok", "not ok"}),
+		client.Var("rules", []string{"{{ .NotExists }}"}),
+	)
+	require.NotNil(t, setResp.SetAnkiConfigTags.Error)
+	assert.Equal(t, []ElementError{{Index: 1, Value: "not ok"}}, setResp.SetAnkiConfigTags.Error.StaticErrors)
+	assert.Equal(t, []ElementError{{Index: 0, Value: "{{ .NotExists }}"}}, setResp.SetAnkiConfigTags.Error.RuleErrors)
+
+	setResp = SetResponse{}
+	c.MustPost(setQuery, &setResp,
+		client.Var("static", []string{"japwords"}),
+		client.Var("rules", []string{"{{ .Slug.Word }}"}),
+	)
+	assert.Nil(t, setResp.SetAnkiConfigTags.Error)
+	c.MustPost(configQuery, &configResp)
+	assert.Equal(t, []string{"japwords"}, configResp.AnkiConfig.Tags.Static)
+	assert.Equal(t, []string{"{{ .Slug.Word }}"}, configResp.AnkiConfig.Tags.Rules)
+}
+
+// singleNoteAnki is client for Anki that has only one note
+type singleNoteAnki struct {
+	unavailableAnki
+	note    *ankiconnect.NoteInfo
+	updates []*anki.UpdateNoteRequest
+}
+
+func (a *singleNoteAnki) QueryNotes(ctx context.Context, query string) ([]*ankiconnect.NoteInfo, error) {
+	return []*ankiconnect.NoteInfo{a.note}, nil
+}
+
+func (a *singleNoteAnki) UpdateNote(ctx context.Context, note *anki.UpdateNoteRequest) error {
+	a.updates = append(a.updates, note)
+	return nil
+}
+
+func Test_mutationResolver_UpdateAnkiNote(t *testing.T) {
+	type FieldDiff struct {
+		Name    string
+		Old     string
+		New     string
+		Changed bool
+	}
+	type TypedError struct {
+		Typename string `json:"__typename"`
+	}
+	type Result struct {
+		Fields    []FieldDiff
+		AddedTags []string
+		Error     *TypedError
+	}
+	type Response struct {
+		UpdateAnkiNote Result
+	}
+	const mutation = `
+		mutation($noteID: String!, $onlyEmpty: Boolean!, $dryRun: Boolean!) {
+			updateAnkiNote(
+				noteID: $noteID,
+				request: {
+					fields: [{name: "Front", value: "犬"}, {name: "Back", value: "dog"}],
+					tags: ["japwords"],
+					audioAssets: [],
+				},
+				onlyEmpty: $onlyEmpty,
+				dryRun: $dryRun,
+			) {
+				fields { name old new changed }
+				addedTags
+				error { __typename }
+			}
+		}
+	`
+	testCases := []struct {
+		Name            string
+		NoteID          string
+		OnlyEmpty       bool
+		DryRun          bool
+		Expected        Result
+		ExpectedUpdates int
+	}{
+		{
+			Name:   "update",
+			NoteID: "12",
+			Expected: Result{
+				Fields: []FieldDiff{
+					{Name: "Front", Old: "犬", New: "犬"},
+					{Name: "Back", Old: "", New: "dog", Changed: true},
+				},
+				AddedTags: []string{"japwords"},
+			},
+			ExpectedUpdates: 1,
+		},
+		{
+			Name:   "dry run",
+			NoteID: "12",
+			DryRun: true,
+			Expected: Result{
+				Fields: []FieldDiff{
+					{Name: "Front", Old: "犬", New: "犬"},
+					{Name: "Back", Old: "", New: "dog", Changed: true},
+				},
+				AddedTags: []string{"japwords"},
+			},
+		},
+		{
+			Name:   "not found",
+			NoteID: "13",
+			Expected: Result{
+				Fields:    []FieldDiff{},
+				AddedTags: []string{},
+				Error: &TypedError{
+					Typename: "AnkiUpdateNoteNotFound",
+				},
+			},
+		},
+		{
+			Name:   "invalid id",
+			NoteID: "abc",
+			Expected: Result{
+				Fields:    []FieldDiff{},
+				AddedTags: []string{},
+				Error: &TypedError{
+					Typename: "AnkiUpdateNoteNotFound",
+				},
+			},
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			statefullClient := &singleNoteAnki{
+				note: &ankiconnect.NoteInfo{
+					NoteID: 12,
+					Fields: map[string]*ankiconnect.NoteInfoField{
+						"Front": {Value: "犬", Order: 0},
+						"Back":  {Value: "", Order: 1},
+					},
+				},
+			}
+			ankiClient := anki.NewAnki(func(*anki.Config) (anki.StatefullClient, error) {
+				return statefullClient, nil
+			})
+			require.NoError(t, ankiClient.ReloadConfig(&anki.Config{}))
+			resolvers := Resolver{
+				ankiClient: ankiClient,
+			}
+			c := client.New(handler.NewDefaultServer(gqlgenerated.NewExecutableSchema(gqlgenerated.Config{Resolvers: &resolvers})))
+			var resp Response
+			c.MustPost(mutation, &resp,
+				client.Var("noteID", tc.NoteID),
+				client.Var("onlyEmpty", tc.OnlyEmpty),
+				client.Var("dryRun", tc.DryRun),
+			)
+			assert.Equal(t, tc.Expected, resp.UpdateAnkiNote)
+			assert.Len(t, statefullClient.updates, tc.ExpectedUpdates)
+		})
+	}
+}
+
+// batchAnki is client for Anki that returns predefined results for AddNotes
+type batchAnki struct {
+	unavailableAnki
+	results []anki.AddNoteResult
+}
+
+func (a batchAnki) AddNotes(ctx context.Context, notes []*anki.AddNoteRequest) ([]anki.AddNoteResult, error) {
+	return a.results, nil
+}
+
+func Test_mutationResolver_AddAnkiNotes(t *testing.T) {
+	type TypedError struct {
+		Typename string `json:"__typename"`
+	}
+	type Item struct {
+		NoteID string
+		Error  *TypedError
+	}
+	type Result struct {
+		Results   []Item
+		AnkiError *TypedError
+	}
+	type Response struct {
+		AddAnkiNotes Result
+	}
+	const mutation = `
+		mutation {
+			addAnkiNotes(requests: [
+				{fields: [{name: "Front", value: "a"}], tags: [], audioAssets: []},
+				{fields: [{name: "Front", value: "b"}], tags: [], audioAssets: []},
+				{fields: [{name: "Front", value: "c"}], tags: [], audioAssets: []},
+			]) {
+				results {
+					noteID
+					error { __typename }
+				}
+				ankiError { __typename }
+			}
+		}
+	`
+	testCases := []struct {
+		Name     string
+		Client   anki.StatefullClient
+		Expected Result
+	}{
+		{
+			Name: "results",
+			Client: batchAnki{
+				results: []anki.AddNoteResult{
+					{NoteID: 12},
+					{Err: anki.ErrDuplicatedNoteFound},
+					{Err: anki.ErrNoteCreationFailed},
+				},
+			},
+			Expected: Result{
+				Results: []Item{
+					{NoteID: "12"},
+					{Error: &TypedError{Typename: "AnkiAddNoteDuplicateFound"}},
+					{Error: &TypedError{Typename: "AnkiAddNoteFailed"}},
+				},
+			},
+		},
+		{
+			Name:   "anki unavailable",
+			Client: unavailableAnki{},
+			Expected: Result{
+				Results:   []Item{},
+				AnkiError: &TypedError{Typename: "AnkiUnknownError"},
+			},
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			ankiClient := anki.NewAnki(func(*anki.Config) (anki.StatefullClient, error) {
+				return tc.Client, nil
+			})
+			require.NoError(t, ankiClient.ReloadConfig(&anki.Config{}))
+			resolvers := Resolver{
+				ankiClient: ankiClient,
+			}
+			c := client.New(handler.NewDefaultServer(gqlgenerated.NewExecutableSchema(gqlgenerated.Config{Resolvers: &resolvers})))
+			var resp Response
+			c.MustPost(mutation, &resp)
+			assert.Equal(t, tc.Expected, resp.AddAnkiNotes)
+		})
+	}
+}
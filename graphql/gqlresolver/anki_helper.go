@@ -3,6 +3,7 @@ package gqlresolver
 import (
 	"context"
 	"errors"
+	"strconv"
 
 	"github.com/99designs/gqlgen/graphql"
 
@@ -47,3 +48,62 @@ func convertAnkiError(err error) (gqlmodel.AnkiError, error) {
 		Message: err.Error(),
 	}, nil
 }
+
+// convertTagValidationErrors never returns nil, because lists of errors are required.
+func convertTagValidationErrors(errs []*anki.TagValidationError) []*gqlmodel.AnkiConfigTagsElementError {
+	result := make([]*gqlmodel.AnkiConfigTagsElementError, len(errs))
+	for i, err := range errs {
+		result[i] = &gqlmodel.AnkiConfigTagsElementError{
+			Index:   err.Index,
+			Value:   err.Value,
+			Message: err.Msg,
+		}
+	}
+	return result
+}
+
+// parseNoteID returns anki.ErrNoteNotFound if id is not valid note id.
+func parseNoteID(id string) (anki.NoteID, error) {
+	parsed, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || parsed <= 0 {
+		return 0, anki.ErrNoteNotFound
+	}
+	return anki.NoteID(parsed), nil
+}
+
+func convertUpdateNoteResult(result *anki.UpdateNoteResult) *gqlmodel.AnkiUpdateNoteResult {
+	fields := make([]*anki.NoteFieldDiff, len(result.Fields))
+	for i := range result.Fields {
+		fields[i] = &result.Fields[i]
+	}
+	addedTags := result.AddedTags
+	if addedTags == nil {
+		addedTags = []string{}
+	}
+	return &gqlmodel.AnkiUpdateNoteResult{
+		Fields:    fields,
+		AddedTags: addedTags,
+	}
+}
+
+func convertAddNoteResult(result *anki.AddNoteResult) *gqlmodel.AnkiAddNotesItem {
+	item := &gqlmodel.AnkiAddNotesItem{
+		NoteID: result.NoteID.String(),
+	}
+	switch {
+	case result.Err == nil:
+	case errors.Is(result.Err, anki.ErrDuplicatedNoteFound):
+		item.Error = &gqlmodel.AnkiAddNoteDuplicateFound{
+			Message: result.Err.Error(),
+		}
+	case errors.Is(result.Err, anki.ErrAudioUnavailable):
+		item.Error = &gqlmodel.AnkiAddNoteAudioUnavailable{
+			Message: result.Err.Error(),
+		}
+	default:
+		item.Error = &gqlmodel.AnkiAddNoteFailed{
+			Message: result.Err.Error(),
+		}
+	}
+	return item
+}
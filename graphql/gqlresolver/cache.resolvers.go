@@ -0,0 +1,26 @@
+package gqlresolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.36
+
+import (
+	"context"
+
+	"github.com/Darkclainer/japwords/graphql/gqlmodel"
+	"github.com/Darkclainer/japwords/pkg/cachedict"
+)
+
+// ClearCache is the resolver for the clearCache field.
+func (r *mutationResolver) ClearCache(ctx context.Context) (*gqlmodel.ClearCacheResult, error) {
+	err := r.caches.Clear()
+	if err != nil {
+		return nil, err
+	}
+	return &gqlmodel.ClearCacheResult{}, nil
+}
+
+// CacheStats is the resolver for the CacheStats field.
+func (r *queryResolver) CacheStats(ctx context.Context) ([]*cachedict.Stats, error) {
+	return sliceToPointers(r.caches.Stats()), nil
+}
@@ -0,0 +1,116 @@
+package gqlresolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.36
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/Darkclainer/japwords/graphql/gqlmodel"
+	"github.com/Darkclainer/japwords/pkg/config"
+)
+
+// SetDictionaryConfigHeaders is the resolver for the setDictionaryConfigHeaders field.
+func (r *mutationResolver) SetDictionaryConfigHeaders(ctx context.Context, input gqlmodel.SetDictionaryConfigHeadersInput) (*gqlmodel.SetDictionaryConfigHeadersResult, error) {
+	headers, err := convertDictionaryHeaders(input.Headers)
+	if err == nil {
+		err = r.dictConfig.UpdateHeaders(input.UserAgent, headers)
+	}
+	if validationErr, _ := convertDictionaryValidationError(ctx, err); validationErr != nil {
+		return &gqlmodel.SetDictionaryConfigHeadersResult{
+			Error: validationErr,
+		}, nil
+	}
+	return &gqlmodel.SetDictionaryConfigHeadersResult{}, err
+}
+
+// SetDictionaryConfigWorkers is the resolver for the setDictionaryConfigWorkers field.
+func (r *mutationResolver) SetDictionaryConfigWorkers(ctx context.Context, input gqlmodel.SetDictionaryConfigWorkersInput) (*gqlmodel.SetDictionaryConfigWorkersResult, error) {
+	err := r.dictConfig.UpdateWorkers(input.Workers, input.MergePolicy)
+	if validationErr, _ := convertDictionaryValidationError(ctx, err); validationErr != nil {
+		return &gqlmodel.SetDictionaryConfigWorkersResult{
+			Error: validationErr,
+		}, nil
+	}
+	return &gqlmodel.SetDictionaryConfigWorkersResult{}, err
+}
+
+// SetDictionaryConfigURLs is the resolver for the setDictionaryConfigURLs field.
+func (r *mutationResolver) SetDictionaryConfigURLs(ctx context.Context, input gqlmodel.SetDictionaryConfigURLsInput) (*gqlmodel.SetDictionaryConfigURLsResult, error) {
+	err := r.dictConfig.UpdateURLs(input.JishoURL, input.WadokuURL)
+	if validationErr, _ := convertDictionaryValidationError(ctx, err); validationErr != nil {
+		return &gqlmodel.SetDictionaryConfigURLsResult{
+			Error: validationErr,
+		}, nil
+	}
+	return &gqlmodel.SetDictionaryConfigURLsResult{}, err
+}
+
+// SetDictionaryConfigRequests is the resolver for the setDictionaryConfigRequests field.
+func (r *mutationResolver) SetDictionaryConfigRequests(ctx context.Context, input gqlmodel.SetDictionaryConfigRequestsInput) (*gqlmodel.SetDictionaryConfigRequestsResult, error) {
+	err := r.dictConfig.UpdateRequests(&config.Requests{
+		Timeout:        input.Timeout,
+		Retries:        input.Retries,
+		RetryBaseDelay: input.RetryBaseDelay,
+		RetryMaxDelay:  input.RetryMaxDelay,
+		RateLimit:      input.RateLimit,
+		RateBurst:      input.RateBurst,
+		MaxConcurrent:  input.MaxConcurrent,
+	})
+	if validationErr, _ := convertDictionaryValidationError(ctx, err); validationErr != nil {
+		return &gqlmodel.SetDictionaryConfigRequestsResult{
+			Error: validationErr,
+		}, nil
+	}
+	return &gqlmodel.SetDictionaryConfigRequestsResult{}, err
+}
+
+// SetDictionaryConfigTimeouts is the resolver for the setDictionaryConfigTimeouts field.
+func (r *mutationResolver) SetDictionaryConfigTimeouts(ctx context.Context, input gqlmodel.SetDictionaryConfigTimeoutsInput) (*gqlmodel.SetDictionaryConfigTimeoutsResult, error) {
+	err := r.dictConfig.UpdateTimeouts(input.Timeout, input.SourceTimeout, input.SoftPitch)
+	if validationErr, _ := convertDictionaryValidationError(ctx, err); validationErr != nil {
+		return &gqlmodel.SetDictionaryConfigTimeoutsResult{
+			Error: validationErr,
+		}, nil
+	}
+	return &gqlmodel.SetDictionaryConfigTimeoutsResult{}, err
+}
+
+// DictionaryConfig is the resolver for the DictionaryConfig field.
+func (r *queryResolver) DictionaryConfig(ctx context.Context) (*gqlmodel.DictionaryConfig, error) {
+	dictionary := r.dictConfig.Current()
+	headers := make([]*gqlmodel.DictionaryHeader, 0, len(dictionary.Headers))
+	for key, value := range dictionary.Headers {
+		headers = append(headers, &gqlmodel.DictionaryHeader{
+			Key:   key,
+			Value: value,
+		})
+	}
+	slices.SortStableFunc(headers, func(a, b *gqlmodel.DictionaryHeader) int {
+		return cmp.Compare(a.Key, b.Key)
+	})
+	requests := &dictionary.Requests
+	return &gqlmodel.DictionaryConfig{
+		Workers:     dictionary.Workers,
+		MergePolicy: dictionary.MergePolicy,
+		UserAgent:   dictionary.UserAgent,
+		Headers:     headers,
+		JishoURL:    dictionary.Jisho.URL,
+		WadokuURL:   dictionary.Wadoku.URL,
+		Requests: &gqlmodel.DictionaryRequests{
+			Timeout:        requests.Timeout,
+			Retries:        requests.Retries,
+			RetryBaseDelay: requests.RetryBaseDelay,
+			RetryMaxDelay:  requests.RetryMaxDelay,
+			RateLimit:      requests.RateLimit,
+			RateBurst:      requests.RateBurst,
+			MaxConcurrent:  requests.MaxConcurrent,
+		},
+		Timeout:       dictionary.Timeout,
+		SourceTimeout: dictionary.SourceTimeout,
+		SoftPitch:     dictionary.SoftPitch,
+	}, nil
+}
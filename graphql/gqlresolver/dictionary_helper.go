@@ -0,0 +1,35 @@
+package gqlresolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+
+	"github.com/Darkclainer/japwords/graphql/gqlmodel"
+	"github.com/Darkclainer/japwords/pkg/dictconfig"
+)
+
+func convertDictionaryValidationError(ctx context.Context, err error) (*gqlmodel.ValidationError, error) {
+	var validationErr *dictconfig.ValidationError
+	if !errors.As(err, &validationErr) {
+		return nil, err
+	}
+	result := gqlmodel.ValidationError{
+		Paths:   []string{graphql.GetPath(ctx).String()},
+		Message: validationErr.Msg,
+	}
+	return &result, nil
+}
+
+func convertDictionaryHeaders(headers []*gqlmodel.DictionaryHeaderInput) (map[string]string, error) {
+	result := make(map[string]string, len(headers))
+	for _, header := range headers {
+		if _, ok := result[header.Key]; ok {
+			return nil, &dictconfig.ValidationError{Msg: fmt.Sprintf("header %q is specified more than once", header.Key)}
+		}
+		result[header.Key] = header.Value
+	}
+	return result, nil
+}
@@ -0,0 +1,23 @@
+package gqlresolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.36
+
+import (
+	"context"
+
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+// Examples is the resolver for the Examples field.
+func (r *queryResolver) Examples(ctx context.Context, word string, limit *int) ([]*lemma.Example, error) {
+	if r.examples == nil {
+		return nil, nil
+	}
+	var examplesLimit int
+	if limit != nil {
+		examplesLimit = *limit
+	}
+	return sliceToPointers(r.examples.Query(word, examplesLimit)), nil
+}
@@ -0,0 +1,103 @@
+package gqlresolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.36
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Darkclainer/japwords/graphql/gqlgenerated"
+	"github.com/Darkclainer/japwords/graphql/gqlmodel"
+	"github.com/Darkclainer/japwords/pkg/history"
+)
+
+// ID is the resolver for the id field.
+func (r *historyEntryResolver) ID(ctx context.Context, obj *history.Entry) (string, error) {
+	return formatHistoryID(obj.ID), nil
+}
+
+// Time is the resolver for the time field.
+func (r *historyEntryResolver) Time(ctx context.Context, obj *history.Entry) (string, error) {
+	return obj.Time.Format(time.RFC3339), nil
+}
+
+// DeleteHistoryEntry is the resolver for the deleteHistoryEntry field.
+func (r *mutationResolver) DeleteHistoryEntry(ctx context.Context, id string) (*gqlmodel.DeleteHistoryEntryResult, error) {
+	err := history.ErrNotFound
+	if r.history != nil {
+		var parsed uint64
+		parsed, err = parseHistoryID(id)
+		if err == nil {
+			var entry *history.Entry
+			entry, err = r.history.Delete(parsed)
+			if err == nil {
+				r.forgetSuggestion(entry.Query)
+			}
+		}
+	}
+	if errors.Is(err, history.ErrNotFound) || errors.Is(err, errInvalidHistoryID) {
+		return &gqlmodel.DeleteHistoryEntryResult{
+			Error: &gqlmodel.HistoryEntryNotFound{
+				Message: fmt.Sprintf("history entry %q not found", id),
+			},
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &gqlmodel.DeleteHistoryEntryResult{}, nil
+}
+
+// ClearHistory is the resolver for the clearHistory field.
+func (r *mutationResolver) ClearHistory(ctx context.Context) (*gqlmodel.ClearHistoryResult, error) {
+	if r.history == nil {
+		return &gqlmodel.ClearHistoryResult{}, nil
+	}
+	if err := r.history.Clear(); err != nil {
+		return nil, err
+	}
+	if r.suggest != nil {
+		r.suggest.ClearUsage()
+	}
+	return &gqlmodel.ClearHistoryResult{}, nil
+}
+
+// History is the resolver for the History field.
+func (r *queryResolver) History(ctx context.Context, limit int, offset int, filter *gqlmodel.HistoryFilter) (*gqlmodel.HistoryResult, error) {
+	if limit < 0 || offset < 0 {
+		return nil, errors.New("limit and offset should not be negative")
+	}
+	if r.history == nil {
+		return &gqlmodel.HistoryResult{
+			Entries: []*history.Entry{},
+		}, nil
+	}
+	var historyFilter history.Filter
+	if filter != nil {
+		if filter.Query != nil {
+			historyFilter.Query = *filter.Query
+		}
+		historyFilter.NoteAdded = filter.NoteAdded
+	}
+	entries, total, err := r.history.List(limit, offset, &historyFilter)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []*history.Entry{}
+	}
+	return &gqlmodel.HistoryResult{
+		Enabled: true,
+		Entries: entries,
+		Total:   total,
+	}, nil
+}
+
+// HistoryEntry returns gqlgenerated.HistoryEntryResolver implementation.
+func (r *Resolver) HistoryEntry() gqlgenerated.HistoryEntryResolver { return &historyEntryResolver{r} }
+
+type historyEntryResolver struct{ *Resolver }
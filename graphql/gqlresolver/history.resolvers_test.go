@@ -0,0 +1,250 @@
+package gqlresolver
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Darkclainer/japwords/graphql/gqlgenerated"
+	"github.com/Darkclainer/japwords/pkg/anki"
+	"github.com/Darkclainer/japwords/pkg/history"
+	"github.com/Darkclainer/japwords/pkg/lemma"
+	"github.com/Darkclainer/japwords/pkg/multidict"
+	"github.com/Darkclainer/japwords/pkg/suggest"
+)
+
+func Test_History(t *testing.T) {
+	multiDict, err := multidict.New(&multidict.Options{
+		Workers: 2,
+		LemmaDicts: []multidict.LemmaSource{
+			{
+				Name: "test",
+				Dict: testLemmaDict(func(ctx context.Context, query string) ([]*lemma.Lemma, error) {
+					if query != "犬" {
+						return nil, nil
+					}
+					return []*lemma.Lemma{
+						{
+							Slug: lemma.Word{Word: "犬", Hiragana: "いぬ"},
+							Senses: []lemma.WordSense{
+								{Definition: []string{"dog"}},
+								{Definition: []string{"spy"}},
+							},
+						},
+					}, nil
+				}),
+			},
+		},
+	})
+	require.NoError(t, err)
+	multiDict.Init()
+	defer multiDict.Close()
+	ankiClient := anki.NewAnki(func(*anki.Config) (anki.StatefullClient, error) {
+		return unavailableAnki{}, nil
+	})
+	require.NoError(t, ankiClient.ReloadConfig(&anki.Config{}))
+	historyStore, err := history.Open(filepath.Join(t.TempDir(), "history.db"), history.Options{})
+	require.NoError(t, err)
+	defer historyStore.Close()
+
+	resolvers := Resolver{
+		multiDict:  multiDict,
+		ankiClient: ankiClient,
+		history:    historyStore,
+		logger:     zap.NewNop(),
+	}
+	c := client.New(handler.NewDefaultServer(gqlgenerated.NewExecutableSchema(gqlgenerated.Config{Resolvers: &resolvers})))
+
+	lookup := func(query string) string {
+		var resp struct {
+			Lemmas struct {
+				HistoryID *string
+			}
+		}
+		c.MustPost(`query($query: String!) { Lemmas(query: $query) { historyID } }`, &resp, client.Var("query", query))
+		require.NotNil(t, resp.Lemmas.HistoryID)
+		return *resp.Lemmas.HistoryID
+	}
+	type Entry struct {
+		ID          string
+		Query       string
+		ResultCount int
+		NoteAdded   bool
+	}
+	type HistoryResponse struct {
+		History struct {
+			Enabled bool
+			Entries []Entry
+			Total   int
+		}
+	}
+	const historyQuery = `
+		query($filter: HistoryFilter) {
+			History(limit: 10, filter: $filter) {
+				enabled
+				entries {
+					id
+					query
+					resultCount
+					noteAdded
+				}
+				total
+			}
+		}`
+
+	dogID := lookup("犬")
+	catID := lookup("猫")
+	resolvers.markHistoryNoteAdded(dogID)
+
+	var resp HistoryResponse
+	c.MustPost(historyQuery, &resp)
+	assert.True(t, resp.History.Enabled)
+	assert.Equal(t, 2, resp.History.Total)
+	assert.Equal(t, []Entry{
+		{ID: catID, Query: "猫"},
+		{ID: dogID, Query: "犬", ResultCount: 2, NoteAdded: true},
+	}, resp.History.Entries)
+
+	// zero limit means no limit
+	resp = HistoryResponse{}
+	c.MustPost(`query { History(limit: 0) { enabled entries { id query resultCount noteAdded } total } }`, &resp)
+	assert.Len(t, resp.History.Entries, 2)
+
+	resp = HistoryResponse{}
+	c.MustPost(historyQuery, &resp, client.Var("filter", map[string]any{"noteAdded": true}))
+	assert.Equal(t, []Entry{
+		{ID: dogID, Query: "犬", ResultCount: 2, NoteAdded: true},
+	}, resp.History.Entries)
+
+	type DeleteResponse struct {
+		DeleteHistoryEntry struct {
+			Error *struct {
+				Typename string `json:"__typename"`
+			}
+		}
+	}
+	const deleteQuery = `
+		mutation($id: ID!) {
+			deleteHistoryEntry(id: $id) {
+				error {
+					__typename
+				}
+			}
+		}`
+	var deleteResp DeleteResponse
+	c.MustPost(deleteQuery, &deleteResp, client.Var("id", catID))
+	assert.Nil(t, deleteResp.DeleteHistoryEntry.Error)
+	for _, id := range []string{catID, "invalid"} {
+		deleteResp = DeleteResponse{}
+		c.MustPost(deleteQuery, &deleteResp, client.Var("id", id))
+		require.NotNil(t, deleteResp.DeleteHistoryEntry.Error)
+		assert.Equal(t, "HistoryEntryNotFound", deleteResp.DeleteHistoryEntry.Error.Typename)
+	}
+	resp = HistoryResponse{}
+	c.MustPost(historyQuery, &resp)
+	assert.Equal(t, 1, resp.History.Total)
+
+	var clearResp map[string]any
+	c.MustPost(`mutation { clearHistory { nothing } }`, &clearResp)
+	resp = HistoryResponse{}
+	c.MustPost(historyQuery, &resp)
+	assert.Zero(t, resp.History.Total)
+	assert.Empty(t, resp.History.Entries)
+}
+
+func Test_History_Suggestions(t *testing.T) {
+	words := map[string]lemma.Word{
+		"いぬ": {Word: "犬", Hiragana: "いぬ"},
+		"ねこ": {Word: "猫", Hiragana: "ねこ"},
+	}
+	multiDict, err := multidict.New(&multidict.Options{
+		Workers: 2,
+		LemmaDicts: []multidict.LemmaSource{
+			{
+				Name: "test",
+				Dict: testLemmaDict(func(ctx context.Context, query string) ([]*lemma.Lemma, error) {
+					word, ok := words[query]
+					if !ok {
+						return nil, nil
+					}
+					return []*lemma.Lemma{
+						{
+							Slug:   word,
+							Senses: []lemma.WordSense{{Definition: []string{"animal"}}},
+						},
+					}, nil
+				}),
+			},
+		},
+	})
+	require.NoError(t, err)
+	multiDict.Init()
+	defer multiDict.Close()
+	ankiClient := anki.NewAnki(func(*anki.Config) (anki.StatefullClient, error) {
+		return unavailableAnki{}, nil
+	})
+	require.NoError(t, ankiClient.ReloadConfig(&anki.Config{}))
+	historyStore, err := history.Open(filepath.Join(t.TempDir(), "history.db"), history.Options{})
+	require.NoError(t, err)
+	defer historyStore.Close()
+
+	resolvers := Resolver{
+		multiDict:  multiDict,
+		ankiClient: ankiClient,
+		history:    historyStore,
+		suggest:    suggest.New(),
+		logger:     zap.NewNop(),
+	}
+	c := client.New(handler.NewDefaultServer(gqlgenerated.NewExecutableSchema(gqlgenerated.Config{Resolvers: &resolvers})))
+
+	lookup := func(query string) string {
+		var resp struct {
+			Lemmas struct {
+				HistoryID *string
+			}
+		}
+		c.MustPost(`query($query: String!) { Lemmas(query: $query) { historyID } }`, &resp, client.Var("query", query))
+		require.NotNil(t, resp.Lemmas.HistoryID)
+		return *resp.Lemmas.HistoryID
+	}
+	suggestTexts := func(prefix string) []string {
+		var resp struct {
+			Suggest []struct {
+				Text string
+			}
+		}
+		c.MustPost(`query($prefix: String!) { Suggest(prefix: $prefix, limit: 10) { text } }`, &resp, client.Var("prefix", prefix))
+		var texts []string
+		for _, suggestion := range resp.Suggest {
+			texts = append(texts, suggestion.Text)
+		}
+		return texts
+	}
+	deleteEntry := func(id string) {
+		var resp map[string]any
+		c.MustPost(`mutation($id: ID!) { deleteHistoryEntry(id: $id) { error { __typename } } }`, &resp, client.Var("id", id))
+	}
+
+	firstDogID := lookup("いぬ")
+	lookup("いぬ")
+	catID := lookup("ねこ")
+	assert.Equal(t, []string{"ねこ", "猫"}, suggestTexts("neko"))
+	assert.Equal(t, []string{"いぬ", "犬"}, suggestTexts("inu"))
+
+	// deleted query is not suggested, but dictionary word is
+	deleteEntry(catID)
+	assert.Equal(t, []string{"猫"}, suggestTexts("neko"))
+	// query is still in history
+	deleteEntry(firstDogID)
+	assert.Equal(t, []string{"いぬ", "犬"}, suggestTexts("inu"))
+
+	var clearResp map[string]any
+	c.MustPost(`mutation { clearHistory { nothing } }`, &clearResp)
+	assert.Equal(t, []string{"犬"}, suggestTexts("inu"))
+}
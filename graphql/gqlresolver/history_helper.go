@@ -0,0 +1,55 @@
+package gqlresolver
+
+import (
+	"errors"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/Darkclainer/japwords/pkg/history"
+)
+
+// errInvalidHistoryID is returned if id is not id of history entry at all
+var errInvalidHistoryID = errors.New("history entry id is invalid")
+
+func parseHistoryID(id string) (uint64, error) {
+	parsed, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return 0, errInvalidHistoryID
+	}
+	return parsed, nil
+}
+
+func formatHistoryID(id uint64) string {
+	return strconv.FormatUint(id, 10)
+}
+
+// recordHistory adds query to history and returns id of entry. History is not essential,
+// so failure is only logged and id is nil if history is disabled or failed.
+func (r *Resolver) recordHistory(query string, resultCount int) *string {
+	if r.history == nil {
+		return nil
+	}
+	entry, err := r.history.Add(query, resultCount)
+	if err != nil {
+		r.logger.Warn("query is not recorded to history", zap.String("query", query), zap.Error(err))
+		return nil
+	}
+	id := formatHistoryID(entry.ID)
+	return &id
+}
+
+// markHistoryNoteAdded marks entry with id that note was added from its result. Entry can be already
+// removed, so failure is only logged.
+func (r *Resolver) markHistoryNoteAdded(id string) {
+	if r.history == nil {
+		return
+	}
+	parsed, err := parseHistoryID(id)
+	if err == nil {
+		err = r.history.MarkNoteAdded(parsed)
+	}
+	if err != nil && !errors.Is(err, history.ErrNotFound) {
+		r.logger.Warn("history entry is not marked", zap.String("id", id), zap.Error(err))
+	}
+}
@@ -7,28 +7,46 @@ package gqlresolver
 import (
 	"context"
 
+	"github.com/Darkclainer/japwords/graphql/gqlgenerated"
 	"github.com/Darkclainer/japwords/graphql/gqlmodel"
+	"github.com/Darkclainer/japwords/pkg/anki"
+	"github.com/Darkclainer/japwords/pkg/lemmalookup"
 )
 
 // Lemmas is the resolver for the Lemmas field.
 func (r *queryResolver) Lemmas(ctx context.Context, query string) (*gqlmodel.LemmasResult, error) {
-	lemmas, err := r.multiDict.Query(ctx, query)
+	dict := collectAll(r.multiDict)
+	lemmas, normalizedQuery, deinflection, err := r.lookupLemmas(ctx, dict, query)
 	if err != nil {
 		return nil, err
 	}
-	projectedLemmas := expandLemmas(lemmas)
-	exstingIds, _ := r.ankiClient.SearchProjectedLemmas(ctx, projectedLemmas)
-	result := make([]*gqlmodel.LemmaNoteInfo, len(projectedLemmas))
-	for i, projectedLemma := range projectedLemmas {
-		result[i] = &gqlmodel.LemmaNoteInfo{
-			Lemma:  projectedLemma,
-			NoteID: "",
-		}
-		if len(exstingIds) != 0 {
-			result[i].NoteID = exstingIds[i].String()
+	lemmasErrors, lemmasWarnings := convertSourceErrors(dict.Errors(lemmalookup.Term(normalizedQuery, deinflection)))
+	r.recordSuggestions(normalizedQuery, lemmas)
+	projectedLemmas := r.projectLemmas(lemmas)
+	var exstingIds []anki.NoteID
+	if len(projectedLemmas) != 0 {
+		exstingIds, err = r.ankiClient.SearchProjectedLemmas(ctx, projectedLemmas)
+		if warning := convertAnkiLookupError(err); warning != nil {
+			lemmasWarnings = append(lemmasWarnings, warning)
 		}
 	}
 	return &gqlmodel.LemmasResult{
-		Lemmas: result,
+		Query:           query,
+		NormalizedQuery: normalizedQuery,
+		Lemmas:          lemmaNoteInfos(projectedLemmas, exstingIds),
+		Deinflection:    deinflection,
+		HistoryID:       r.recordHistory(query, len(projectedLemmas)),
+		Errors:          lemmasErrors,
+		Warnings:        lemmasWarnings,
 	}, nil
 }
+
+// LemmasStream is the resolver for the LemmasStream field.
+func (r *subscriptionResolver) LemmasStream(ctx context.Context, query string) (<-chan *gqlmodel.LemmasStreamResult, error) {
+	return r.streamLemmas(ctx, query)
+}
+
+// Subscription returns gqlgenerated.SubscriptionResolver implementation.
+func (r *Resolver) Subscription() gqlgenerated.SubscriptionResolver { return &subscriptionResolver{r} }
+
+type subscriptionResolver struct{ *Resolver }
@@ -0,0 +1,162 @@
+package gqlresolver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Darkclainer/japwords/graphql/gqlgenerated"
+	"github.com/Darkclainer/japwords/pkg/anki"
+	"github.com/Darkclainer/japwords/pkg/lemma"
+	"github.com/Darkclainer/japwords/pkg/multidict"
+)
+
+func Test_queryResolver_Lemmas_Errors(t *testing.T) {
+	multiDict, err := multidict.New(&multidict.Options{
+		Workers: 4,
+		LemmaDicts: []multidict.LemmaSource{
+			{
+				Name: "test",
+				Dict: testLemmaDict(func(ctx context.Context, query string) ([]*lemma.Lemma, error) {
+					if query != "犬" {
+						return nil, nil
+					}
+					return []*lemma.Lemma{
+						{
+							Slug: lemma.Word{Word: "犬", Hiragana: "いぬ"},
+							Senses: []lemma.WordSense{
+								{Definition: []string{"dog"}},
+							},
+						},
+					}, nil
+				}),
+			},
+			{
+				Name: "broken",
+				Dict: testLemmaDict(func(ctx context.Context, query string) ([]*lemma.Lemma, error) {
+					return nil, errors.New("lemma error")
+				}),
+			},
+		},
+		PitchDicts: []multidict.PitchSource{
+			{
+				Name: "broken",
+				Dict: testPitchDict(func(ctx context.Context, query string) ([]*lemma.PitchedLemma, error) {
+					return nil, errors.New("pitch error")
+				}),
+			},
+			{
+				Name: "slow",
+				Dict: testPitchDict(func(ctx context.Context, query string) ([]*lemma.PitchedLemma, error) {
+					<-ctx.Done()
+					return nil, ctx.Err()
+				}),
+			},
+		},
+		Timeouts: multidict.Timeouts{
+			Source:    50 * time.Millisecond,
+			SoftPitch: true,
+		},
+	})
+	require.NoError(t, err)
+	multiDict.Init()
+	defer multiDict.Close()
+	ankiClient := anki.NewAnki(func(*anki.Config) (anki.StatefullClient, error) {
+		return unavailableAnki{}, nil
+	})
+	require.NoError(t, ankiClient.ReloadConfig(&anki.Config{}))
+
+	resolvers := Resolver{
+		multiDict:  multiDict,
+		ankiClient: ankiClient,
+	}
+	c := client.New(handler.NewDefaultServer(gqlgenerated.NewExecutableSchema(gqlgenerated.Config{Resolvers: &resolvers})))
+
+	type Error struct {
+		Typename         string `json:"__typename"`
+		Source           string
+		DeadlineExceeded bool
+		Message          string
+		AnkiError        *struct {
+			Typename string `json:"__typename"`
+		}
+	}
+	var resp struct {
+		Lemmas struct {
+			Lemmas []struct {
+				Lemma struct {
+					Slug struct {
+						Word string
+					}
+				}
+			}
+			Errors   []Error
+			Warnings []Error
+		}
+	}
+	c.MustPost(`
+		fragment sourceFields on Error {
+			__typename
+			message
+			... on LemmaSourceUnavailable {
+				source
+				deadlineExceeded
+			}
+			... on PitchSourceUnavailable {
+				source
+				deadlineExceeded
+			}
+			... on AnkiLookupFailed {
+				ankiError {
+					__typename
+				}
+			}
+		}
+		query {
+			Lemmas(query: "犬") {
+				lemmas {
+					lemma {
+						slug {
+							word
+						}
+					}
+				}
+				errors {
+					...sourceFields
+				}
+				warnings {
+					...sourceFields
+				}
+			}
+		}`, &resp)
+	require.Len(t, resp.Lemmas.Lemmas, 1)
+	assert.Equal(t, "犬", resp.Lemmas.Lemmas[0].Lemma.Slug.Word)
+	assert.Equal(t, []Error{
+		{
+			Typename: "LemmaSourceUnavailable",
+			Source:   "broken",
+			Message:  `lemma dict "broken" request failed: lemma error`,
+		},
+	}, resp.Lemmas.Errors)
+	require.Len(t, resp.Lemmas.Warnings, 3)
+	assert.Equal(t, Error{
+		Typename: "PitchSourceUnavailable",
+		Source:   "broken",
+		Message:  `pitch dict "broken" request failed: pitch error`,
+	}, resp.Lemmas.Warnings[0])
+	assert.Equal(t, Error{
+		Typename:         "PitchSourceUnavailable",
+		Source:           "slow",
+		Message:          `pitch dict "slow" missed deadline`,
+		DeadlineExceeded: true,
+	}, resp.Lemmas.Warnings[1])
+	assert.Equal(t, "AnkiLookupFailed", resp.Lemmas.Warnings[2].Typename)
+	require.NotNil(t, resp.Lemmas.Warnings[2].AnkiError)
+	assert.Equal(t, "AnkiConnectionError", resp.Lemmas.Warnings[2].AnkiError.Typename)
+}
@@ -0,0 +1,19 @@
+package gqlresolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.36
+
+import (
+	"context"
+
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+// Kanji is the resolver for the Kanji field.
+func (r *queryResolver) Kanji(ctx context.Context, characters string) ([]*lemma.Kanji, error) {
+	if r.kanji == nil {
+		return nil, nil
+	}
+	return sliceToPointers(r.kanji.Query(characters)), nil
+}
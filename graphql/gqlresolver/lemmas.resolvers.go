@@ -11,6 +11,14 @@ import (
 	"github.com/Darkclainer/japwords/pkg/lemma"
 )
 
+// MediaURL is the resolver for the mediaURL field.
+func (r *audioResolver) MediaURL(ctx context.Context, obj *lemma.Audio) (string, error) {
+	if r.media == nil {
+		return obj.Source, nil
+	}
+	return r.media.URL(MediaPrefix, obj.Source), nil
+}
+
 // Furigana is the resolver for the furigana field.
 func (r *wordResolver) Furigana(ctx context.Context, obj *lemma.Word) ([]*lemma.FuriganaChar, error) {
 	return sliceToPointers(obj.Furigana), nil
@@ -22,11 +30,15 @@ func (r *wordInputResolver) Furigana(ctx context.Context, obj *lemma.Word, data
 	return nil
 }
 
+// Audio returns gqlgenerated.AudioResolver implementation.
+func (r *Resolver) Audio() gqlgenerated.AudioResolver { return &audioResolver{r} }
+
 // Word returns gqlgenerated.WordResolver implementation.
 func (r *Resolver) Word() gqlgenerated.WordResolver { return &wordResolver{r} }
 
 // WordInput returns gqlgenerated.WordInputResolver implementation.
 func (r *Resolver) WordInput() gqlgenerated.WordInputResolver { return &wordInputResolver{r} }
 
+type audioResolver struct{ *Resolver }
 type wordResolver struct{ *Resolver }
 type wordInputResolver struct{ *Resolver }
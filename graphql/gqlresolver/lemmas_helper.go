@@ -1,21 +1,223 @@
 package gqlresolver
 
-import "github.com/Darkclainer/japwords/pkg/lemma"
-
-func expandLemmas(lemmas []*lemma.Lemma) []*lemma.ProjectedLemma {
-	var projectedLemmas []*lemma.ProjectedLemma
-	for _, l := range lemmas {
-		for _, wordSense := range l.Senses {
-			projectedLemmas = append(projectedLemmas, &lemma.ProjectedLemma{
-				Slug:          l.Slug,
-				Tags:          l.Tags,
-				Forms:         l.Forms,
-				Definitions:   wordSense.Definition,
-				PartsOfSpeech: wordSense.PartOfSpeech,
-				SenseTags:     wordSense.Tags,
-				Audio:         l.Audio,
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.uber.org/multierr"
+
+	"github.com/Darkclainer/japwords/graphql/gqlmodel"
+	"github.com/Darkclainer/japwords/pkg/anki"
+	"github.com/Darkclainer/japwords/pkg/deinflect"
+	"github.com/Darkclainer/japwords/pkg/jisho"
+	"github.com/Darkclainer/japwords/pkg/lemma"
+	"github.com/Darkclainer/japwords/pkg/lemmalookup"
+	"github.com/Darkclainer/japwords/pkg/multidict"
+	"github.com/Darkclainer/japwords/pkg/wadoku"
+)
+
+// projector returns projector with enabled sources of resolver.
+func (r *Resolver) projector() *lemmalookup.Projector {
+	projector := &lemmalookup.Projector{}
+	if r.examples != nil {
+		projector.Examples = r.examples
+	}
+	if r.kanji != nil {
+		projector.Kanji = r.kanji
+	}
+	return projector
+}
+
+// projectLemmas expands lemmas by senses and adds examples and kanji to them.
+func (r *Resolver) projectLemmas(lemmas []*lemma.Lemma) []*lemma.ProjectedLemma {
+	return r.projector().Project(lemmas)
+}
+
+// lemmaNoteInfos combines projected lemmas with ids of their notes, ids can be empty if they are unknown.
+func lemmaNoteInfos(projectedLemmas []*lemma.ProjectedLemma, noteIDs []anki.NoteID) []*gqlmodel.LemmaNoteInfo {
+	result := make([]*gqlmodel.LemmaNoteInfo, len(projectedLemmas))
+	for i, projectedLemma := range projectedLemmas {
+		result[i] = &gqlmodel.LemmaNoteInfo{
+			Lemma:  projectedLemma,
+			NoteID: "",
+		}
+		if len(noteIDs) != 0 {
+			result[i].NoteID = noteIDs[i].String()
+		}
+	}
+	return result
+}
+
+// lookupLemmas looks up query like lemmalookup.Lookup. If dict is unavailable, nothing is found
+// and failures are reported with result.
+func (r *Resolver) lookupLemmas(ctx context.Context, dict deinflect.Dict, query string) ([]*lemma.Lemma, string, *deinflect.Candidate, error) {
+	lemmas, normalizedQuery, deinflection, err := lemmalookup.Lookup(ctx, dict, query)
+	if errors.Is(err, deinflect.ErrDictUnavailable) {
+		return nil, normalizedQuery, nil, nil
+	}
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return lemmas, normalizedQuery, deinflection, nil
+}
+
+// sourceErrorsCollector queries dictionary and remembers failures of separate sources for every query,
+// so lookup doesn't fail if only some of dictionaries are unavailable. Lookup queries several candidate
+// terms, but only failures for the term that was actually used are interesting. If every lemma dictionary
+// failed, Query returns deinflect.ErrDictUnavailable, so candidates are not queried in vain.
+type sourceErrorsCollector struct {
+	query        func(ctx context.Context, query string) ([]*lemma.Lemma, []*multidict.SourceError, error)
+	lemmaSources int
+
+	lock sync.Mutex
+	errs map[string][]*multidict.SourceError
+	// unavailableErrs are failures of query after which dictionary was unavailable
+	unavailableErrs []*multidict.SourceError
+}
+
+func newSourceErrorsCollector(
+	lemmaSources int,
+	query func(ctx context.Context, query string) ([]*lemma.Lemma, []*multidict.SourceError, error),
+) *sourceErrorsCollector {
+	return &sourceErrorsCollector{
+		query:        query,
+		lemmaSources: lemmaSources,
+		errs:         map[string][]*multidict.SourceError{},
+	}
+}
+
+// collectAll queries all dictionaries of multidict.
+func collectAll(multiDict *multidict.MultiDict) *sourceErrorsCollector {
+	return newSourceErrorsCollector(multiDict.LemmaSourceCount(), multiDict.QueryWithWarnings)
+}
+
+// collectLemmaDictsOnly queries only lemma dictionaries of multidict, pitches are queried separately.
+func collectLemmaDictsOnly(multiDict *multidict.MultiDict) *sourceErrorsCollector {
+	return newSourceErrorsCollector(multiDict.LemmaSourceCount(), func(ctx context.Context, query string) ([]*lemma.Lemma, []*multidict.SourceError, error) {
+		lemmas, err := multiDict.QueryLemmas(ctx, query)
+		return lemmas, nil, err
+	})
+}
+
+// Query is implementation of deinflect.Dict interface.
+func (c *sourceErrorsCollector) Query(ctx context.Context, query string) ([]*lemma.Lemma, error) {
+	lemmas, warnings, err := c.query(ctx, query)
+	sourceErrs, err := splitSourceErrors(err)
+	if err != nil {
+		return lemmas, err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.errs[query] = append(sourceErrs, warnings...)
+	if len(lemmas) == 0 && c.lemmaSources != 0 && countLemmaSources(sourceErrs) == c.lemmaSources {
+		c.unavailableErrs = c.errs[query]
+		return nil, fmt.Errorf("every lemma dictionary failed: %w", deinflect.ErrDictUnavailable)
+	}
+	return lemmas, nil
+}
+
+// Errors returns failures of sources for specified query. If dictionary became unavailable during lookup,
+// failures that made it unavailable are returned, because they are the reason why nothing was found.
+func (c *sourceErrorsCollector) Errors(query string) []*multidict.SourceError {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.unavailableErrs != nil {
+		return c.unavailableErrs
+	}
+	return c.errs[query]
+}
+
+// countLemmaSources returns number of distinct lemma dictionaries among failures.
+func countLemmaSources(sourceErrs []*multidict.SourceError) int {
+	sources := map[string]struct{}{}
+	for _, sourceErr := range sourceErrs {
+		if sourceErr.SourceType == multidict.SourceTypeLemma {
+			sources[sourceErr.Source] = struct{}{}
+		}
+	}
+	return len(sources)
+}
+
+// splitSourceErrors separates failures of single dictionaries from other errors
+// (for example cancelled query).
+func splitSourceErrors(err error) ([]*multidict.SourceError, error) {
+	var (
+		sourceErrs []*multidict.SourceError
+		otherErrs  []error
+	)
+	for _, err := range multierr.Errors(err) {
+		var sourceErr *multidict.SourceError
+		if errors.As(err, &sourceErr) {
+			sourceErrs = append(sourceErrs, sourceErr)
+		} else {
+			otherErrs = append(otherErrs, err)
+		}
+	}
+	return sourceErrs, multierr.Combine(otherErrs...)
+}
+
+// convertSourceErrors converts failures of lemma dictionaries to errors and failures
+// of pitch dictionaries to warnings.
+func convertSourceErrors(sourceErrs []*multidict.SourceError) ([]gqlmodel.LemmasError, []gqlmodel.LemmasWarning) {
+	lemmasErrors := []gqlmodel.LemmasError{}
+	lemmasWarnings := []gqlmodel.LemmasWarning{}
+	for _, sourceErr := range sourceErrs {
+		if parseErr := convertParseError(sourceErr); parseErr != nil {
+			if sourceErr.SourceType == multidict.SourceTypeLemma {
+				lemmasErrors = append(lemmasErrors, parseErr)
+			} else {
+				lemmasWarnings = append(lemmasWarnings, parseErr)
+			}
+			continue
+		}
+		switch sourceErr.SourceType {
+		case multidict.SourceTypeLemma:
+			lemmasErrors = append(lemmasErrors, &gqlmodel.LemmaSourceUnavailable{
+				Source:           sourceErr.Source,
+				DeadlineExceeded: sourceErr.DeadlineMissed(),
+				Message:          sourceErr.Error(),
+			})
+		default:
+			lemmasWarnings = append(lemmasWarnings, &gqlmodel.PitchSourceUnavailable{
+				Source:           sourceErr.Source,
+				DeadlineExceeded: sourceErr.DeadlineMissed(),
+				Message:          sourceErr.Error(),
 			})
 		}
 	}
-	return projectedLemmas
+	return lemmasErrors, lemmasWarnings
+}
+
+// convertParseError returns nil if sourceErr is not about failed parsing of dictionary entries.
+func convertParseError(sourceErr *multidict.SourceError) *gqlmodel.ParseError {
+	var failed int
+	var jishoErr *jisho.LemmaBatchError
+	var wadokuErr *wadoku.LemmaBatchError
+	switch {
+	case errors.As(sourceErr, &jishoErr):
+		failed = len(jishoErr.Errs)
+	case errors.As(sourceErr, &wadokuErr):
+		failed = len(wadokuErr.Errs)
+	default:
+		return nil
+	}
+	return &gqlmodel.ParseError{
+		Source:  sourceErr.Source,
+		Failed:  failed,
+		Message: sourceErr.Error(),
+	}
+}
+
+// convertAnkiLookupError returns warning for failed search of notes, nil error is converted to nil.
+func convertAnkiLookupError(err error) gqlmodel.LemmasWarning {
+	if err == nil {
+		return nil
+	}
+	ankiErr, _ := convertAnkiError(err)
+	return &gqlmodel.AnkiLookupFailed{
+		AnkiError: ankiErr,
+		Message:   "failed to search notes in Anki: " + err.Error(),
+	}
 }
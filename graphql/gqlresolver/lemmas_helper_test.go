@@ -1,105 +1,148 @@
 package gqlresolver
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/multierr"
 
+	"github.com/Darkclainer/japwords/graphql/gqlmodel"
+	"github.com/Darkclainer/japwords/pkg/deinflect"
+	"github.com/Darkclainer/japwords/pkg/jisho"
 	"github.com/Darkclainer/japwords/pkg/lemma"
+	"github.com/Darkclainer/japwords/pkg/multidict"
+	"github.com/Darkclainer/japwords/pkg/wadoku"
 )
 
-func Test_expandLemmas(t *testing.T) {
-	word1 := lemma.Word{
-		Word:     "foo",
-		Hiragana: "foo",
-		Furigana: []lemma.FuriganaChar{
-			{},
-		},
-		PitchShapes: []lemma.PitchShape{
-			{},
-		},
+func Test_splitSourceErrors(t *testing.T) {
+	lemmaErr := &multidict.SourceError{
+		SourceType: multidict.SourceTypeLemma,
+		Source:     "jisho",
+		Err:        errors.New("lemma error"),
 	}
-	word2 := lemma.Word{
-		Word:     "bar",
-		Hiragana: "bar",
-		Furigana: []lemma.FuriganaChar{
-			{}, {},
-		},
-		PitchShapes: []lemma.PitchShape{
-			{}, {},
-		},
+	sourceErrs, err := splitSourceErrors(multierr.Combine(lemmaErr, context.Canceled))
+	assert.Equal(t, []*multidict.SourceError{lemmaErr}, sourceErrs)
+	assert.Equal(t, context.Canceled, err)
+
+	sourceErrs, err = splitSourceErrors(nil)
+	assert.Empty(t, sourceErrs)
+	assert.NoError(t, err)
+}
+
+func Test_sourceErrorsCollector(t *testing.T) {
+	jishoErr := &multidict.SourceError{
+		SourceType: multidict.SourceTypeLemma,
+		Source:     "jisho",
+		Err:        errors.New("jisho error"),
+	}
+	jmdictErr := &multidict.SourceError{
+		SourceType: multidict.SourceTypeLemma,
+		Source:     "jmdict",
+		Err:        errors.New("jmdict error"),
 	}
-	lemmas := []*lemma.Lemma{
+	dog := &lemma.Lemma{Slug: lemma.Word{Word: "犬"}}
+	collector := newSourceErrorsCollector(2, func(ctx context.Context, query string) ([]*lemma.Lemma, []*multidict.SourceError, error) {
+		switch query {
+		case "partial":
+			return []*lemma.Lemma{dog}, nil, jishoErr
+		case "nothing":
+			return nil, nil, jishoErr
+		default:
+			return nil, nil, multierr.Combine(jishoErr, jmdictErr)
+		}
+	})
+
+	lemmas, err := collector.Query(context.Background(), "partial")
+	require.NoError(t, err)
+	assert.Equal(t, []*lemma.Lemma{dog}, lemmas)
+	assert.Equal(t, []*multidict.SourceError{jishoErr}, collector.Errors("partial"))
+
+	lemmas, err = collector.Query(context.Background(), "nothing")
+	require.NoError(t, err)
+	assert.Empty(t, lemmas)
+
+	_, err = collector.Query(context.Background(), "failed")
+	assert.ErrorIs(t, err, deinflect.ErrDictUnavailable)
+	// failures that made dictionary unavailable are reported for any query
+	assert.Equal(t, []*multidict.SourceError{jishoErr, jmdictErr}, collector.Errors("partial"))
+}
+
+func Test_convertSourceErrors(t *testing.T) {
+	newSourceError := func(sourceType multidict.SourceType, source string, err error) *multidict.SourceError {
+		return &multidict.SourceError{
+			SourceType: sourceType,
+			Source:     source,
+			Err:        err,
+		}
+	}
+	testCases := []struct {
+		Name             string
+		SourceError      *multidict.SourceError
+		ExpectedErrors   []gqlmodel.LemmasError
+		ExpectedWarnings []gqlmodel.LemmasWarning
+	}{
 		{
-			Slug:  word1,
-			Tags:  []string{"first"},
-			Forms: []lemma.Word{word1},
-			Senses: []lemma.WordSense{
-				{
-					Definition:   []string{"a", "b"},
-					PartOfSpeech: []string{"pos"},
-					Tags:         []string{"sensetag"},
-				},
-				{
-					Definition:   []string{"c", "d"},
-					PartOfSpeech: []string{"sop"},
-				},
-			},
-			Audio: []lemma.Audio{
-				{
-					MediaType: "hello",
-					Source:    "world",
+			Name:        "lemma unavailable",
+			SourceError: newSourceError(multidict.SourceTypeLemma, "jisho", errors.New("error")),
+			ExpectedErrors: []gqlmodel.LemmasError{
+				&gqlmodel.LemmaSourceUnavailable{
+					Source:  "jisho",
+					Message: `lemma dict "jisho" request failed: error`,
 				},
 			},
+			ExpectedWarnings: []gqlmodel.LemmasWarning{},
 		},
 		{
-			Slug:  word2,
-			Tags:  []string{"second"},
-			Forms: []lemma.Word{word1, word2},
-			Senses: []lemma.WordSense{
-				{
-					Definition:   []string{"second"},
-					PartOfSpeech: []string{"pos2"},
+			Name:           "pitch deadline",
+			SourceError:    newSourceError(multidict.SourceTypePitch, "wadoku", context.DeadlineExceeded),
+			ExpectedErrors: []gqlmodel.LemmasError{},
+			ExpectedWarnings: []gqlmodel.LemmasWarning{
+				&gqlmodel.PitchSourceUnavailable{
+					Source:           "wadoku",
+					DeadlineExceeded: true,
+					Message:          `pitch dict "wadoku" missed deadline`,
 				},
 			},
 		},
-	}
-	expected := []*lemma.ProjectedLemma{
 		{
-			Slug:          word1,
-			Tags:          []string{"first"},
-			Forms:         []lemma.Word{word1},
-			Definitions:   []string{"a", "b"},
-			PartsOfSpeech: []string{"pos"},
-			SenseTags:     []string{"sensetag"},
-			Audio: []lemma.Audio{
-				{
-					MediaType: "hello",
-					Source:    "world",
+			Name: "lemma parse error",
+			SourceError: newSourceError(multidict.SourceTypeLemma, "jisho", fmt.Errorf("wrapped: %w", &jisho.LemmaBatchError{
+				Errs: []error{errors.New("first"), errors.New("second")},
+			})),
+			ExpectedErrors: []gqlmodel.LemmasError{
+				&gqlmodel.ParseError{
+					Source:  "jisho",
+					Failed:  2,
+					Message: `lemma dict "jisho" request failed: wrapped: 2 lemma parsing failed`,
 				},
 			},
+			ExpectedWarnings: []gqlmodel.LemmasWarning{},
 		},
 		{
-			Slug:          word1,
-			Tags:          []string{"first"},
-			Forms:         []lemma.Word{word1},
-			Definitions:   []string{"c", "d"},
-			PartsOfSpeech: []string{"sop"},
-			Audio: []lemma.Audio{
-				{
-					MediaType: "hello",
-					Source:    "world",
+			Name: "pitch parse error",
+			SourceError: newSourceError(multidict.SourceTypePitch, "wadoku", &wadoku.LemmaBatchError{
+				Errs: []*wadoku.LemmaError{{ID: 1, Err: errors.New("first")}},
+			}),
+			ExpectedErrors: []gqlmodel.LemmasError{},
+			ExpectedWarnings: []gqlmodel.LemmasWarning{
+				&gqlmodel.ParseError{
+					Source:  "wadoku",
+					Failed:  1,
+					Message: `pitch dict "wadoku" request failed: 1 lemma parsing failed`,
 				},
 			},
 		},
-		{
-			Slug:          word2,
-			Tags:          []string{"second"},
-			Forms:         []lemma.Word{word1, word2},
-			Definitions:   []string{"second"},
-			PartsOfSpeech: []string{"pos2"},
-		},
 	}
-	actual := expandLemmas(lemmas)
-	assert.Equal(t, expected, actual)
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			lemmasErrors, lemmasWarnings := convertSourceErrors([]*multidict.SourceError{tc.SourceError})
+			require.Equal(t, tc.ExpectedErrors, lemmasErrors)
+			require.Equal(t, tc.ExpectedWarnings, lemmasWarnings)
+		})
+	}
 }
@@ -0,0 +1,157 @@
+package gqlresolver
+
+import (
+	"context"
+
+	clone "github.com/huandu/go-clone/generic"
+
+	"github.com/Darkclainer/japwords/graphql/gqlmodel"
+	"github.com/Darkclainer/japwords/pkg/anki"
+	"github.com/Darkclainer/japwords/pkg/kana"
+	"github.com/Darkclainer/japwords/pkg/lemma"
+	"github.com/Darkclainer/japwords/pkg/lemmalookup"
+	"github.com/Darkclainer/japwords/pkg/multidict"
+)
+
+// lemmasStream holds state of single LemmasStream subscription.
+type lemmasStream struct {
+	result          gqlmodel.LemmasResult
+	lemmas          []*lemma.Lemma
+	projectedLemmas []*lemma.ProjectedLemma
+	noteIDs         []anki.NoteID
+	completedStages []gqlmodel.LemmasStage
+}
+
+func (s *lemmasStream) complete(stage gqlmodel.LemmasStage) {
+	s.completedStages = append(s.completedStages, stage)
+}
+
+func (s *lemmasStream) emit() *gqlmodel.LemmasStreamResult {
+	result := s.result
+	result.Lemmas = lemmaNoteInfos(s.projectedLemmas, s.noteIDs)
+	return &gqlmodel.LemmasStreamResult{
+		Result:          &result,
+		CompletedStages: append([]gqlmodel.LemmasStage(nil), s.completedStages...),
+		Done:            len(s.completedStages) == len(gqlmodel.AllLemmasStage),
+	}
+}
+
+// streamLemmas looks up lemmas and returns channel with result, that is emitted again
+// every time it is enriched with pitches or ids of notes. Pitches are queried concurrently
+// with lemmas, so slow pitch dictionaries don't delay lemmas.
+func (r *Resolver) streamLemmas(ctx context.Context, query string) (<-chan *gqlmodel.LemmasStreamResult, error) {
+	pitchQuery := kana.NormalizeQuery(query)
+	pitchCtx, cancelPitch := context.WithCancel(ctx)
+	pitchChan := r.queryPitchesAsync(pitchCtx, pitchQuery)
+	dict := collectLemmaDictsOnly(r.multiDict)
+	lemmas, normalizedQuery, deinflection, err := r.lookupLemmas(ctx, dict, query)
+	if err != nil {
+		cancelPitch()
+		return nil, err
+	}
+	// pitches should be queried for the term that was actually found
+	term := lemmalookup.Term(normalizedQuery, deinflection)
+	lemmasErrors, lemmasWarnings := convertSourceErrors(dict.Errors(term))
+	if term != pitchQuery {
+		cancelPitch()
+		pitchCtx, cancelPitch = context.WithCancel(ctx)
+		pitchChan = r.queryPitchesAsync(pitchCtx, term)
+	}
+
+	stream := &lemmasStream{
+		result: gqlmodel.LemmasResult{
+			Query:           query,
+			NormalizedQuery: normalizedQuery,
+			Deinflection:    deinflection,
+			Errors:          lemmasErrors,
+			Warnings:        lemmasWarnings,
+		},
+		lemmas:          lemmas,
+		projectedLemmas: r.projectLemmas(lemmas),
+	}
+	stream.result.HistoryID = r.recordHistory(query, len(stream.projectedLemmas))
+	r.recordSuggestions(normalizedQuery, lemmas)
+	stream.complete(gqlmodel.LemmasStageLemmas)
+	var notesChan <-chan notesResult
+	if len(lemmas) == 0 {
+		// nothing to enrich
+		cancelPitch()
+		pitchChan = nil
+		stream.complete(gqlmodel.LemmasStagePitches)
+		stream.complete(gqlmodel.LemmasStageNotes)
+	} else {
+		notesChan = r.searchNotesAsync(ctx, stream.projectedLemmas)
+	}
+
+	results := make(chan *gqlmodel.LemmasStreamResult, 1)
+	results <- stream.emit()
+	go func() {
+		defer close(results)
+		defer cancelPitch()
+		for pitchChan != nil || notesChan != nil {
+			select {
+			case pitches := <-pitchChan:
+				pitchChan = nil
+				// previous results see only their part of warnings, so append doesn't affect them
+				_, pitchWarnings := convertSourceErrors(pitches.Warnings)
+				stream.result.Warnings = append(stream.result.Warnings, pitchWarnings...)
+				// lemmas of previous result can be still in use, so they are not modified
+				stream.lemmas = clone.Clone(stream.lemmas)
+				lemma.Enrich(stream.lemmas, pitches.Pitches)
+				stream.projectedLemmas = r.projectLemmas(stream.lemmas)
+				stream.complete(gqlmodel.LemmasStagePitches)
+			case notes := <-notesChan:
+				notesChan = nil
+				stream.noteIDs = notes.NoteIDs
+				if warning := convertAnkiLookupError(notes.Err); warning != nil {
+					stream.result.Warnings = append(stream.result.Warnings, warning)
+				}
+				stream.complete(gqlmodel.LemmasStageNotes)
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case results <- stream.emit():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return results, nil
+}
+
+type pitchesResult struct {
+	Pitches  []*lemma.PitchedLemma
+	Warnings []*multidict.SourceError
+}
+
+// queryPitchesAsync queries pitch dictionaries, pitches are optional, so failures are reported as warnings.
+func (r *Resolver) queryPitchesAsync(ctx context.Context, query string) <-chan pitchesResult {
+	result := make(chan pitchesResult, 1)
+	go func() {
+		pitches, warnings := r.multiDict.QueryPitches(ctx, query)
+		result <- pitchesResult{
+			Pitches:  pitches,
+			Warnings: warnings,
+		}
+	}()
+	return result
+}
+
+type notesResult struct {
+	NoteIDs []anki.NoteID
+	Err     error
+}
+
+// searchNotesAsync searches notes for lemmas, if Anki is not available ids are empty.
+func (r *Resolver) searchNotesAsync(ctx context.Context, projectedLemmas []*lemma.ProjectedLemma) <-chan notesResult {
+	result := make(chan notesResult, 1)
+	go func() {
+		noteIDs, err := r.ankiClient.SearchProjectedLemmas(ctx, projectedLemmas)
+		result <- notesResult{
+			NoteIDs: noteIDs,
+			Err:     err,
+		}
+	}()
+	return result
+}
@@ -0,0 +1,199 @@
+package gqlresolver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Darkclainer/japwords/graphql/gqlgenerated"
+	"github.com/Darkclainer/japwords/graphql/gqlmodel"
+	"github.com/Darkclainer/japwords/pkg/anki"
+	"github.com/Darkclainer/japwords/pkg/anki/ankiconnect"
+	"github.com/Darkclainer/japwords/pkg/lemma"
+	"github.com/Darkclainer/japwords/pkg/multidict"
+)
+
+type testLemmaDict func(ctx context.Context, query string) ([]*lemma.Lemma, error)
+
+func (f testLemmaDict) Query(ctx context.Context, query string) ([]*lemma.Lemma, error) {
+	return f(ctx, query)
+}
+
+type testPitchDict func(ctx context.Context, query string) ([]*lemma.PitchedLemma, error)
+
+func (f testPitchDict) Query(ctx context.Context, query string) ([]*lemma.PitchedLemma, error) {
+	return f(ctx, query)
+}
+
+// unavailableAnki is client for Anki that is never available
+type unavailableAnki struct{}
+
+func (unavailableAnki) Stop()                      {}
+func (unavailableAnki) Config() *anki.Config       { return &anki.Config{} }
+func (unavailableAnki) SetConnectedHandler(func()) {}
+func (unavailableAnki) GetState(ctx context.Context) (*anki.State, error) {
+	return nil, &anki.ConnectionError{Msg: "unavailable"}
+}
+
+func (unavailableAnki) CreateDeck(ctx context.Context, name string) error {
+	return errors.New("unavailable")
+}
+
+func (unavailableAnki) CreateDefaultNoteType(ctx context.Context, name string) error {
+	return errors.New("unavailable")
+}
+
+func (unavailableAnki) AddNote(ctx context.Context, note *anki.AddNoteRequest) (int64, error) {
+	return 0, errors.New("unavailable")
+}
+
+func (unavailableAnki) AddNotes(ctx context.Context, notes []*anki.AddNoteRequest) ([]anki.AddNoteResult, error) {
+	return nil, errors.New("unavailable")
+}
+
+func (unavailableAnki) QueryNotes(ctx context.Context, query string) ([]*ankiconnect.NoteInfo, error) {
+	return nil, errors.New("unavailable")
+}
+
+func (unavailableAnki) UpdateNote(ctx context.Context, note *anki.UpdateNoteRequest) error {
+	return errors.New("unavailable")
+}
+
+func Test_subscriptionResolver_LemmasStream(t *testing.T) {
+	// pitch dictionary answers only after lemmas were emitted
+	lemmasEmitted := make(chan struct{})
+	pitchShapes := []lemma.PitchShape{
+		{
+			Hiragana:   "いぬ",
+			Directions: []lemma.AccentDirection{lemma.AccentDirectionDown},
+		},
+	}
+	multiDict, err := multidict.New(&multidict.Options{
+		Workers: 2,
+		LemmaDicts: []multidict.LemmaSource{
+			{
+				Name: "test",
+				Dict: testLemmaDict(func(ctx context.Context, query string) ([]*lemma.Lemma, error) {
+					if query != "犬" {
+						return nil, nil
+					}
+					return []*lemma.Lemma{
+						{
+							Slug: lemma.Word{Word: "犬", Hiragana: "いぬ"},
+							Senses: []lemma.WordSense{
+								{Definition: []string{"dog"}},
+							},
+						},
+					}, nil
+				}),
+			},
+		},
+		PitchDicts: []multidict.PitchSource{
+			{
+				Name: "test",
+				Dict: testPitchDict(func(ctx context.Context, query string) ([]*lemma.PitchedLemma, error) {
+					select {
+					case <-lemmasEmitted:
+					case <-ctx.Done():
+						return nil, ctx.Err()
+					}
+					return []*lemma.PitchedLemma{
+						{Slug: "犬", Hiragana: "いぬ", PitchShapes: pitchShapes},
+					}, nil
+				}),
+			},
+		},
+	})
+	require.NoError(t, err)
+	multiDict.Init()
+	defer multiDict.Close()
+	ankiClient := anki.NewAnki(func(*anki.Config) (anki.StatefullClient, error) {
+		return unavailableAnki{}, nil
+	})
+	require.NoError(t, ankiClient.ReloadConfig(&anki.Config{}))
+
+	resolvers := Resolver{
+		multiDict:  multiDict,
+		ankiClient: ankiClient,
+	}
+	c := client.New(handler.NewDefaultServer(gqlgenerated.NewExecutableSchema(gqlgenerated.Config{Resolvers: &resolvers})))
+
+	type Response struct {
+		LemmasStream struct {
+			Result struct {
+				Lemmas []struct {
+					Lemma struct {
+						Slug struct {
+							Word        string
+							PitchShapes []struct {
+								Directions []string
+							}
+						}
+					}
+				}
+			}
+			CompletedStages []gqlmodel.LemmasStage
+			Done            bool
+		}
+	}
+	t.Run("found", func(t *testing.T) {
+		subscription := c.Websocket(`
+			subscription {
+				LemmasStream(query: "犬") {
+					result {
+						lemmas {
+							lemma {
+								slug {
+									word
+									pitchShapes {
+										directions
+									}
+								}
+							}
+						}
+					}
+					completedStages
+					done
+				}
+			}`)
+		defer subscription.Close()
+
+		var resp Response
+		require.NoError(t, subscription.Next(&resp))
+		require.Len(t, resp.LemmasStream.Result.Lemmas, 1)
+		assert.Equal(t, "犬", resp.LemmasStream.Result.Lemmas[0].Lemma.Slug.Word)
+		assert.Empty(t, resp.LemmasStream.Result.Lemmas[0].Lemma.Slug.PitchShapes)
+		assert.Equal(t, []gqlmodel.LemmasStage{gqlmodel.LemmasStageLemmas}, resp.LemmasStream.CompletedStages)
+		assert.False(t, resp.LemmasStream.Done)
+		close(lemmasEmitted)
+
+		// notes and pitches can arrive in any order
+		for !resp.LemmasStream.Done {
+			resp = Response{}
+			require.NoError(t, subscription.Next(&resp))
+		}
+		assert.ElementsMatch(t, gqlmodel.AllLemmasStage, resp.LemmasStream.CompletedStages)
+		require.Len(t, resp.LemmasStream.Result.Lemmas, 1)
+		assert.Len(t, resp.LemmasStream.Result.Lemmas[0].Lemma.Slug.PitchShapes, 1)
+	})
+	t.Run("not found", func(t *testing.T) {
+		subscription := c.Websocket(`
+			subscription {
+				LemmasStream(query: "猫") {
+					completedStages
+					done
+				}
+			}`)
+		defer subscription.Close()
+
+		var resp Response
+		require.NoError(t, subscription.Next(&resp))
+		assert.True(t, resp.LemmasStream.Done)
+		assert.ElementsMatch(t, gqlmodel.AllLemmasStage, resp.LemmasStream.CompletedStages)
+	})
+}
@@ -0,0 +1,179 @@
+package gqlresolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.36
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Darkclainer/japwords/graphql/gqlgenerated"
+	"github.com/Darkclainer/japwords/graphql/gqlmodel"
+	"github.com/Darkclainer/japwords/pkg/anki"
+	"github.com/Darkclainer/japwords/pkg/outbox"
+)
+
+// ID is the resolver for the id field.
+func (r *ankiOutboxEntryResolver) ID(ctx context.Context, obj *outbox.Entry) (string, error) {
+	return formatOutboxID(obj.ID), nil
+}
+
+// Time is the resolver for the time field.
+func (r *ankiOutboxEntryResolver) Time(ctx context.Context, obj *outbox.Entry) (string, error) {
+	return obj.Time.Format(time.RFC3339), nil
+}
+
+// EditAnkiOutboxEntry is the resolver for the editAnkiOutboxEntry field.
+func (r *mutationResolver) EditAnkiOutboxEntry(ctx context.Context, id string, request anki.AddNoteRequest) (*gqlmodel.EditAnkiOutboxEntryResult, error) {
+	err := outbox.ErrNotFound
+	var entry *outbox.Entry
+	if r.outbox != nil {
+		var parsed uint64
+		parsed, err = parseOutboxID(id)
+		if err == nil {
+			entry, err = r.outbox.Edit(parsed, &request)
+		}
+	}
+	if errors.Is(err, outbox.ErrNotFound) || errors.Is(err, errInvalidOutboxID) {
+		return &gqlmodel.EditAnkiOutboxEntryResult{
+			Error: outboxEntryNotFound(id),
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &gqlmodel.EditAnkiOutboxEntryResult{
+		Entry: entry,
+	}, nil
+}
+
+// RetryAnkiOutboxEntry is the resolver for the retryAnkiOutboxEntry field.
+func (r *mutationResolver) RetryAnkiOutboxEntry(ctx context.Context, id string) (*gqlmodel.RetryAnkiOutboxEntryResult, error) {
+	err := outbox.ErrNotFound
+	var noteID anki.NoteID
+	if r.outbox != nil {
+		var parsed uint64
+		parsed, err = parseOutboxID(id)
+		if err == nil {
+			noteID, err = r.outbox.Retry(ctx, parsed)
+		}
+	}
+	switch {
+	case err == nil:
+		return &gqlmodel.RetryAnkiOutboxEntryResult{
+			NoteID: noteID.String(),
+		}, nil
+	case errors.Is(err, outbox.ErrNotFound) || errors.Is(err, errInvalidOutboxID):
+		return &gqlmodel.RetryAnkiOutboxEntryResult{
+			Error: outboxEntryNotFound(id),
+		}, nil
+	case errors.Is(err, anki.ErrDuplicatedNoteFound):
+		return &gqlmodel.RetryAnkiOutboxEntryResult{
+			Error: &gqlmodel.AnkiAddNoteDuplicateFound{
+				Message: err.Error(),
+			},
+		}, nil
+	case errors.Is(err, anki.ErrIncompleteConfiguration):
+		return &gqlmodel.RetryAnkiOutboxEntryResult{
+			Error: &gqlmodel.AnkiIncompleteConfiguration{
+				Message: err.Error(),
+			},
+		}, nil
+	case errors.Is(err, anki.ErrAudioUnavailable):
+		return &gqlmodel.RetryAnkiOutboxEntryResult{
+			Error: &gqlmodel.AnkiAddNoteAudioUnavailable{
+				Message: err.Error(),
+			},
+		}, nil
+	}
+	ankiErr, _ := convertAnkiError(err)
+	if _, ok := ankiErr.(*gqlmodel.AnkiUnknownError); ok {
+		// Anki is available, but rejected note itself
+		return &gqlmodel.RetryAnkiOutboxEntryResult{
+			Error: &gqlmodel.AnkiAddNoteFailed{
+				Message: err.Error(),
+			},
+		}, nil
+	}
+	return &gqlmodel.RetryAnkiOutboxEntryResult{
+		AnkiError: ankiErr,
+	}, nil
+}
+
+// DiscardAnkiOutboxEntry is the resolver for the discardAnkiOutboxEntry field.
+func (r *mutationResolver) DiscardAnkiOutboxEntry(ctx context.Context, id string) (*gqlmodel.DiscardAnkiOutboxEntryResult, error) {
+	err := outbox.ErrNotFound
+	if r.outbox != nil {
+		var parsed uint64
+		parsed, err = parseOutboxID(id)
+		if err == nil {
+			err = r.outbox.Discard(parsed)
+		}
+	}
+	if errors.Is(err, outbox.ErrNotFound) || errors.Is(err, errInvalidOutboxID) {
+		return &gqlmodel.DiscardAnkiOutboxEntryResult{
+			Error: outboxEntryNotFound(id),
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &gqlmodel.DiscardAnkiOutboxEntryResult{}, nil
+}
+
+// FlushAnkiOutbox is the resolver for the flushAnkiOutbox field.
+func (r *mutationResolver) FlushAnkiOutbox(ctx context.Context) (*gqlmodel.FlushAnkiOutboxResult, error) {
+	if r.outbox == nil {
+		return &gqlmodel.FlushAnkiOutboxResult{}, nil
+	}
+	flushResult, err := r.outbox.Flush(ctx)
+	result := &gqlmodel.FlushAnkiOutboxResult{
+		Added:      flushResult.Added,
+		Duplicates: flushResult.Duplicates,
+		Failed:     flushResult.Failed,
+	}
+	if errors.Is(err, anki.ErrIncompleteConfiguration) {
+		result.Error = &gqlmodel.AnkiIncompleteConfiguration{
+			Message: err.Error(),
+		}
+		return result, nil
+	}
+	if err != nil {
+		ankiErr, _ := convertAnkiError(err)
+		if _, ok := ankiErr.(*gqlmodel.AnkiUnknownError); ok {
+			// flush is stopped only if Anki is unavailable, so it's something else
+			return nil, err
+		}
+		result.AnkiError = ankiErr
+	}
+	return result, nil
+}
+
+// AnkiOutbox is the resolver for the AnkiOutbox field.
+func (r *queryResolver) AnkiOutbox(ctx context.Context) (*gqlmodel.AnkiOutboxResult, error) {
+	if r.outbox == nil {
+		return &gqlmodel.AnkiOutboxResult{
+			Entries: []*outbox.Entry{},
+		}, nil
+	}
+	entries, err := r.outbox.List()
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []*outbox.Entry{}
+	}
+	return &gqlmodel.AnkiOutboxResult{
+		Enabled: true,
+		Entries: entries,
+	}, nil
+}
+
+// AnkiOutboxEntry returns gqlgenerated.AnkiOutboxEntryResolver implementation.
+func (r *Resolver) AnkiOutboxEntry() gqlgenerated.AnkiOutboxEntryResolver {
+	return &ankiOutboxEntryResolver{r}
+}
+
+type ankiOutboxEntryResolver struct{ *Resolver }
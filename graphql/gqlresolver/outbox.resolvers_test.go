@@ -0,0 +1,289 @@
+package gqlresolver
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Darkclainer/japwords/graphql/gqlgenerated"
+	"github.com/Darkclainer/japwords/pkg/anki"
+	"github.com/Darkclainer/japwords/pkg/history"
+	"github.com/Darkclainer/japwords/pkg/outbox"
+)
+
+// unreachableAnki is client for Anki that fails to add notes with connection error
+type unreachableAnki struct {
+	unavailableAnki
+}
+
+func (unreachableAnki) AddNote(ctx context.Context, note *anki.AddNoteRequest) (int64, error) {
+	return 0, &anki.ConnectionError{Msg: "unreachable"}
+}
+
+func (unreachableAnki) AddNotes(ctx context.Context, notes []*anki.AddNoteRequest) ([]anki.AddNoteResult, error) {
+	return nil, &anki.ConnectionError{Msg: "unreachable"}
+}
+
+// addingAnki is client for Anki that adds every note with the same id
+type addingAnki struct {
+	unavailableAnki
+}
+
+func (addingAnki) AddNote(ctx context.Context, note *anki.AddNoteRequest) (int64, error) {
+	return 42, nil
+}
+
+func Test_Outbox(t *testing.T) {
+	var statefullClient anki.StatefullClient = unreachableAnki{}
+	ankiClient := anki.NewAnki(func(*anki.Config) (anki.StatefullClient, error) {
+		return statefullClient, nil
+	})
+	require.NoError(t, ankiClient.ReloadConfig(&anki.Config{}))
+	historyStore, err := history.Open(filepath.Join(t.TempDir(), "history.db"), history.Options{})
+	require.NoError(t, err)
+	defer historyStore.Close()
+	historyEntry, err := historyStore.Add("犬", 1)
+	require.NoError(t, err)
+	outboxStore, err := outbox.Open(filepath.Join(t.TempDir(), "outbox.db"), outbox.Options{
+		Anki: ankiClient,
+		OnAdded: func(entry *outbox.Entry, noteID anki.NoteID) {
+			if entry.HistoryID == 0 {
+				return
+			}
+			require.NoError(t, historyStore.MarkNoteAdded(entry.HistoryID))
+		},
+	})
+	require.NoError(t, err)
+	defer outboxStore.Close()
+
+	resolvers := Resolver{
+		ankiClient: ankiClient,
+		history:    historyStore,
+		outbox:     outboxStore,
+		logger:     zap.NewNop(),
+	}
+	c := client.New(handler.NewDefaultServer(gqlgenerated.NewExecutableSchema(gqlgenerated.Config{Resolvers: &resolvers})))
+
+	type TypedError struct {
+		Typename string `json:"__typename"`
+	}
+	var addResp struct {
+		AddAnkiNote struct {
+			NoteID    string
+			OutboxID  *string
+			AnkiError *TypedError
+		}
+	}
+	c.MustPost(`
+		mutation($historyID: ID) {
+			addAnkiNote(request: {fields: [{name: "Front", value: "犬"}], tags: [], audioAssets: []}, historyID: $historyID) {
+				noteID
+				outboxID
+				ankiError { __typename }
+			}
+		}`, &addResp, client.Var("historyID", formatHistoryID(historyEntry.ID)))
+	require.NotNil(t, addResp.AddAnkiNote.OutboxID)
+	assert.Empty(t, addResp.AddAnkiNote.NoteID)
+	assert.Equal(t, &TypedError{Typename: "AnkiConnectionError"}, addResp.AddAnkiNote.AnkiError)
+	dogID := *addResp.AddAnkiNote.OutboxID
+
+	var addNotesResp struct {
+		AddAnkiNotes struct {
+			Results []struct {
+				OutboxID *string
+			}
+		}
+	}
+	c.MustPost(`
+		mutation {
+			addAnkiNotes(requests: [
+				{fields: [{name: "Front", value: "猫"}], tags: [], audioAssets: []},
+				{fields: [{name: "Front", value: "鳥"}], tags: [], audioAssets: []},
+			]) {
+				results { outboxID }
+			}
+		}`, &addNotesResp)
+	require.Len(t, addNotesResp.AddAnkiNotes.Results, 2)
+	require.NotNil(t, addNotesResp.AddAnkiNotes.Results[0].OutboxID)
+	require.NotNil(t, addNotesResp.AddAnkiNotes.Results[1].OutboxID)
+	catID := *addNotesResp.AddAnkiNotes.Results[0].OutboxID
+	birdID := *addNotesResp.AddAnkiNotes.Results[1].OutboxID
+
+	type Entry struct {
+		ID      string
+		Request struct {
+			Fields []struct {
+				Value string
+			}
+		}
+		Attempts  int
+		LastError string
+	}
+	listWords := func() []string {
+		var resp struct {
+			AnkiOutbox struct {
+				Enabled bool
+				Entries []Entry
+			}
+		}
+		c.MustPost(`{ AnkiOutbox { enabled entries { id request { fields { value } } attempts lastError } } }`, &resp)
+		assert.True(t, resp.AnkiOutbox.Enabled)
+		words := []string{}
+		for _, entry := range resp.AnkiOutbox.Entries {
+			words = append(words, entry.Request.Fields[0].Value)
+		}
+		return words
+	}
+	assert.Equal(t, []string{"犬", "猫", "鳥"}, listWords())
+
+	type EditResponse struct {
+		EditAnkiOutboxEntry struct {
+			Entry *Entry
+			Error *TypedError
+		}
+	}
+	const editQuery = `
+		mutation($id: ID!) {
+			editAnkiOutboxEntry(id: $id, request: {fields: [{name: "Front", value: "猫猫"}], tags: [], audioAssets: []}) {
+				entry { id }
+				error { __typename }
+			}
+		}`
+	var editResp EditResponse
+	c.MustPost(editQuery, &editResp, client.Var("id", catID))
+	assert.Nil(t, editResp.EditAnkiOutboxEntry.Error)
+	require.NotNil(t, editResp.EditAnkiOutboxEntry.Entry)
+	assert.Equal(t, catID, editResp.EditAnkiOutboxEntry.Entry.ID)
+	editResp = EditResponse{}
+	c.MustPost(editQuery, &editResp, client.Var("id", "invalid"))
+	assert.Equal(t, &TypedError{Typename: "AnkiOutboxEntryNotFound"}, editResp.EditAnkiOutboxEntry.Error)
+	assert.Equal(t, []string{"犬", "猫猫", "鳥"}, listWords())
+
+	type DiscardResponse struct {
+		DiscardAnkiOutboxEntry struct {
+			Error *TypedError
+		}
+	}
+	const discardQuery = `mutation($id: ID!) { discardAnkiOutboxEntry(id: $id) { error { __typename } } }`
+	var discardResp DiscardResponse
+	c.MustPost(discardQuery, &discardResp, client.Var("id", birdID))
+	assert.Nil(t, discardResp.DiscardAnkiOutboxEntry.Error)
+	discardResp = DiscardResponse{}
+	c.MustPost(discardQuery, &discardResp, client.Var("id", birdID))
+	assert.Equal(t, &TypedError{Typename: "AnkiOutboxEntryNotFound"}, discardResp.DiscardAnkiOutboxEntry.Error)
+	assert.Equal(t, []string{"犬", "猫猫"}, listWords())
+
+	type RetryResponse struct {
+		RetryAnkiOutboxEntry struct {
+			NoteID    string
+			Error     *TypedError
+			AnkiError *TypedError
+		}
+	}
+	const retryQuery = `
+		mutation($id: ID!) {
+			retryAnkiOutboxEntry(id: $id) {
+				noteID
+				error { __typename }
+				ankiError { __typename }
+			}
+		}`
+	var retryResp RetryResponse
+	c.MustPost(retryQuery, &retryResp, client.Var("id", dogID))
+	assert.Equal(t, &TypedError{Typename: "AnkiConnectionError"}, retryResp.RetryAnkiOutboxEntry.AnkiError)
+
+	// Anki is available again
+	statefullClient = addingAnki{}
+	require.NoError(t, ankiClient.ReloadConfig(&anki.Config{}))
+
+	retryResp = RetryResponse{}
+	c.MustPost(retryQuery, &retryResp, client.Var("id", dogID))
+	assert.Equal(t, "42", retryResp.RetryAnkiOutboxEntry.NoteID)
+	assert.Nil(t, retryResp.RetryAnkiOutboxEntry.Error)
+	assert.Nil(t, retryResp.RetryAnkiOutboxEntry.AnkiError)
+	historyEntries, _, err := historyStore.List(10, 0, &history.Filter{})
+	require.NoError(t, err)
+	require.Len(t, historyEntries, 1)
+	assert.True(t, historyEntries[0].NoteAdded)
+
+	retryResp = RetryResponse{}
+	c.MustPost(retryQuery, &retryResp, client.Var("id", dogID))
+	assert.Equal(t, &TypedError{Typename: "AnkiOutboxEntryNotFound"}, retryResp.RetryAnkiOutboxEntry.Error)
+
+	var flushResp struct {
+		FlushAnkiOutbox struct {
+			Added      int
+			Duplicates int
+			Failed     int
+		}
+	}
+	c.MustPost(`mutation { flushAnkiOutbox { added duplicates failed } }`, &flushResp)
+	assert.Equal(t, 1, flushResp.FlushAnkiOutbox.Added)
+	assert.Equal(t, []string{}, listWords())
+}
+
+// incompleteAnki is client for Anki that doesn't have deck or note type
+type incompleteAnki struct {
+	unavailableAnki
+}
+
+func (incompleteAnki) AddNote(ctx context.Context, note *anki.AddNoteRequest) (int64, error) {
+	return 0, anki.ErrIncompleteConfiguration
+}
+
+func Test_Outbox_FlushIncompleteConfiguration(t *testing.T) {
+	ankiClient := anki.NewAnki(func(*anki.Config) (anki.StatefullClient, error) {
+		return incompleteAnki{}, nil
+	})
+	require.NoError(t, ankiClient.ReloadConfig(&anki.Config{}))
+	outboxStore, err := outbox.Open(filepath.Join(t.TempDir(), "outbox.db"), outbox.Options{
+		Anki: ankiClient,
+	})
+	require.NoError(t, err)
+	defer outboxStore.Close()
+	_, err = outboxStore.Queue(&anki.AddNoteRequest{
+		Fields: []anki.AddNoteField{{Name: "Front", Value: "犬"}},
+	}, 0)
+	require.NoError(t, err)
+
+	resolvers := Resolver{
+		ankiClient: ankiClient,
+		outbox:     outboxStore,
+		logger:     zap.NewNop(),
+	}
+	c := client.New(handler.NewDefaultServer(gqlgenerated.NewExecutableSchema(gqlgenerated.Config{Resolvers: &resolvers})))
+
+	type TypedError struct {
+		Typename string `json:"__typename"`
+	}
+	var flushResp struct {
+		FlushAnkiOutbox struct {
+			Added     int
+			Failed    int
+			Error     *TypedError
+			AnkiError *TypedError
+		}
+	}
+	c.MustPost(`
+		mutation {
+			flushAnkiOutbox {
+				added
+				failed
+				error { __typename }
+				ankiError { __typename }
+			}
+		}`, &flushResp)
+	assert.Zero(t, flushResp.FlushAnkiOutbox.Added)
+	assert.Zero(t, flushResp.FlushAnkiOutbox.Failed)
+	assert.Equal(t, &TypedError{Typename: "AnkiIncompleteConfiguration"}, flushResp.FlushAnkiOutbox.Error)
+	assert.Nil(t, flushResp.FlushAnkiOutbox.AnkiError)
+	entries, err := outboxStore.List()
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
@@ -0,0 +1,68 @@
+package gqlresolver
+
+import (
+	"errors"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/Darkclainer/japwords/graphql/gqlmodel"
+	"github.com/Darkclainer/japwords/pkg/anki"
+	"github.com/Darkclainer/japwords/pkg/outbox"
+)
+
+// errInvalidOutboxID is returned if id is not id of outbox entry at all
+var errInvalidOutboxID = errors.New("outbox entry id is invalid")
+
+func parseOutboxID(id string) (uint64, error) {
+	parsed, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return 0, errInvalidOutboxID
+	}
+	return parsed, nil
+}
+
+func formatOutboxID(id uint64) string {
+	return strconv.FormatUint(id, 10)
+}
+
+// queueNote queues note to outbox if err means that Anki is unreachable and returns id of entry.
+// Id is nil if note is not queued: outbox is disabled, error is different or queue failed (it's only logged,
+// because caller returns original error anyway).
+func (r *Resolver) queueNote(request *anki.AddNoteRequest, historyID *string, err error) *string {
+	if r.outbox == nil || !outbox.IsUnreachable(err) {
+		return nil
+	}
+	var parsedHistoryID uint64
+	if historyID != nil {
+		// invalid history id only means that there is nothing to mark
+		parsedHistoryID, _ = parseHistoryID(*historyID)
+	}
+	entry, err := r.outbox.Queue(request, parsedHistoryID)
+	if err != nil {
+		r.logger.Warn("note is not queued to outbox", zap.Error(err))
+		return nil
+	}
+	id := formatOutboxID(entry.ID)
+	return &id
+}
+
+func outboxEntryNotFound(id string) *gqlmodel.AnkiOutboxEntryNotFound {
+	return &gqlmodel.AnkiOutboxEntryNotFound{
+		Message: "outbox entry " + strconv.Quote(id) + " not found",
+	}
+}
+
+// queueNotes queues all notes like queueNote. Results are empty if notes are not queued.
+func (r *Resolver) queueNotes(requests []*anki.AddNoteRequest, err error) []*gqlmodel.AnkiAddNotesItem {
+	if r.outbox == nil || !outbox.IsUnreachable(err) {
+		return []*gqlmodel.AnkiAddNotesItem{}
+	}
+	items := make([]*gqlmodel.AnkiAddNotesItem, len(requests))
+	for i, request := range requests {
+		items[i] = &gqlmodel.AnkiAddNotesItem{
+			OutboxID: r.queueNote(request, nil, err),
+		}
+	}
+	return items
+}
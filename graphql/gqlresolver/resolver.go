@@ -2,28 +2,59 @@ package gqlresolver
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/handler/extension"
 	"github.com/99designs/gqlgen/graphql/handler/lru"
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"go.uber.org/fx"
+	"go.uber.org/zap"
 
 	"github.com/Darkclainer/japwords/graphql/gqlgenerated"
 	"github.com/Darkclainer/japwords/pkg/anki"
+	"github.com/Darkclainer/japwords/pkg/cachedict"
 	"github.com/Darkclainer/japwords/pkg/config"
+	"github.com/Darkclainer/japwords/pkg/dictconfig"
+	"github.com/Darkclainer/japwords/pkg/examples"
+	"github.com/Darkclainer/japwords/pkg/history"
+	"github.com/Darkclainer/japwords/pkg/kanji"
+	"github.com/Darkclainer/japwords/pkg/media"
 	"github.com/Darkclainer/japwords/pkg/multidict"
+	"github.com/Darkclainer/japwords/pkg/outbox"
+	"github.com/Darkclainer/japwords/pkg/suggest"
 )
 
 // This file will not be regenerated automatically.
 //
 // It serves as dependency injection for your app, add any dependencies you require here.
 
+// MediaPrefix is path where downloaded audio is served.
+const MediaPrefix = "/media/"
+
+// websocketKeepAlive is interval of pings for subscriptions
+const websocketKeepAlive = 10 * time.Second
+
 type Resolver struct {
 	configManager *config.Manager
 	multiDict     *multidict.MultiDict
 	ankiClient    *anki.Anki
 	ankiConfig    *anki.ConfigReloader
+	dictConfig    *dictconfig.Updater
+	caches        *cachedict.Group
+	// examples is nil if examples are disabled
+	examples *examples.Index
+	// kanji is nil if kanji information is disabled
+	kanji *kanji.Dict
+	// media is nil if audio is not downloaded by server
+	media *media.Store
+	// history is nil if history is disabled
+	history *history.Store
+	// outbox is nil if outbox is disabled
+	outbox *outbox.Outbox
+	// suggest is nil if suggestions are not needed (in tests)
+	suggest *suggest.Index
+	logger  *zap.Logger
 }
 
 type In struct {
@@ -33,6 +64,15 @@ type In struct {
 	MultiDict     *multidict.MultiDict
 	AnkiClient    *anki.Anki
 	AnkiConfig    *anki.ConfigReloader
+	DictConfig    *dictconfig.Updater
+	Caches        *cachedict.Group
+	Examples      *examples.Index
+	Kanji         *kanji.Dict
+	Media         *media.Store
+	History       *history.Store
+	Outbox        *outbox.Outbox
+	Suggest       *suggest.Index
+	Logger        *zap.Logger
 }
 
 func New(in In) (*Resolver, error) {
@@ -41,6 +81,15 @@ func New(in In) (*Resolver, error) {
 		multiDict:     in.MultiDict,
 		ankiClient:    in.AnkiClient,
 		ankiConfig:    in.AnkiConfig,
+		dictConfig:    in.DictConfig,
+		caches:        in.Caches,
+		examples:      in.Examples,
+		kanji:         in.Kanji,
+		media:         in.Media,
+		history:       in.History,
+		outbox:        in.Outbox,
+		suggest:       in.Suggest,
+		logger:        in.Logger,
 	}, nil
 }
 
@@ -49,6 +98,10 @@ func (r *Resolver) Handler() http.Handler {
 		Resolvers: r,
 	}))
 	h.AddTransport(transport.POST{})
+	// default upgrader accepts only requests from the same origin
+	h.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: websocketKeepAlive,
+	})
 	const (
 		queryCacheSize              = 1000
 		autoPersistedQueryCacheSize = 100
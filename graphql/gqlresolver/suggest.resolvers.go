@@ -0,0 +1,24 @@
+package gqlresolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.36
+
+import (
+	"context"
+
+	"github.com/Darkclainer/japwords/pkg/suggest"
+)
+
+// Suggest is the resolver for the Suggest field.
+func (r *queryResolver) Suggest(ctx context.Context, prefix string, limit int) ([]*suggest.Suggestion, error) {
+	result := []*suggest.Suggestion{}
+	if r.suggest == nil {
+		return result, nil
+	}
+	for _, suggestion := range r.suggest.Suggest(prefix, min(limit, maxSuggestLimit)) {
+		suggestion := suggestion
+		result = append(result, &suggestion)
+	}
+	return result, nil
+}
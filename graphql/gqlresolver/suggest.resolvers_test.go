@@ -0,0 +1,88 @@
+package gqlresolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Darkclainer/japwords/graphql/gqlgenerated"
+	"github.com/Darkclainer/japwords/pkg/anki"
+	"github.com/Darkclainer/japwords/pkg/lemma"
+	"github.com/Darkclainer/japwords/pkg/multidict"
+	"github.com/Darkclainer/japwords/pkg/suggest"
+)
+
+func Test_queryResolver_Suggest(t *testing.T) {
+	multiDict, err := multidict.New(&multidict.Options{
+		Workers: 2,
+		LemmaDicts: []multidict.LemmaSource{
+			{
+				Name: "test",
+				Dict: testLemmaDict(func(ctx context.Context, query string) ([]*lemma.Lemma, error) {
+					if query != "いぬ" {
+						return nil, nil
+					}
+					return []*lemma.Lemma{
+						{
+							Slug: lemma.Word{Word: "犬", Hiragana: "いぬ"},
+							Senses: []lemma.WordSense{
+								{Definition: []string{"dog"}},
+							},
+						},
+					}, nil
+				}),
+			},
+		},
+	})
+	require.NoError(t, err)
+	multiDict.Init()
+	defer multiDict.Close()
+	ankiClient := anki.NewAnki(func(*anki.Config) (anki.StatefullClient, error) {
+		return unavailableAnki{}, nil
+	})
+	require.NoError(t, ankiClient.ReloadConfig(&anki.Config{}))
+	index := suggest.New()
+	index.AddWords(suggest.Word{Text: "稲", Reading: "いね", Common: true})
+
+	resolvers := Resolver{
+		multiDict:  multiDict,
+		ankiClient: ankiClient,
+		suggest:    index,
+	}
+	c := client.New(handler.NewDefaultServer(gqlgenerated.NewExecutableSchema(gqlgenerated.Config{Resolvers: &resolvers})))
+
+	type Suggestion struct {
+		Text    string
+		Reading string
+	}
+	suggestQuery := func(prefix string, limit int) []Suggestion {
+		var resp struct {
+			Suggest []Suggestion
+		}
+		c.MustPost(
+			`query($prefix: String!, $limit: Int!) { Suggest(prefix: $prefix, limit: $limit) { text reading } }`,
+			&resp,
+			client.Var("prefix", prefix),
+			client.Var("limit", limit),
+		)
+		return resp.Suggest
+	}
+
+	assert.Equal(t, []Suggestion{{Text: "稲", Reading: "いね"}}, suggestQuery("in", 10))
+
+	// successful lookup is suggested, failed is not
+	var resp map[string]any
+	c.MustPost(`query { Lemmas(query: "inu") { query } }`, &resp)
+	c.MustPost(`query { Lemmas(query: "inuk") { query } }`, &resp)
+	assert.Equal(t, []Suggestion{
+		{Text: "いぬ"},
+		{Text: "稲", Reading: "いね"},
+		{Text: "犬", Reading: "いぬ"},
+	}, suggestQuery("i", 10))
+	assert.Equal(t, []Suggestion{{Text: "いぬ"}}, suggestQuery("inu", 1))
+	assert.Empty(t, suggestQuery("inu", 0))
+}
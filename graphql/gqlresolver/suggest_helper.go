@@ -0,0 +1,55 @@
+package gqlresolver
+
+import (
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Darkclainer/japwords/pkg/history"
+	"github.com/Darkclainer/japwords/pkg/kana"
+	"github.com/Darkclainer/japwords/pkg/lemma"
+	"github.com/Darkclainer/japwords/pkg/suggest"
+)
+
+// maxSuggestLimit limits number of suggestions, so request stays cheap
+const maxSuggestLimit = 50
+
+// recordSuggestions adds successful query and found lemmas to suggestions.
+// Queries without result are probably typos, so they are not suggested.
+func (r *Resolver) recordSuggestions(normalizedQuery string, lemmas []*lemma.Lemma) {
+	if r.suggest == nil || len(lemmas) == 0 {
+		return
+	}
+	r.suggest.AddUsage(normalizedQuery, time.Now())
+	var words []suggest.Word
+	for _, l := range lemmas {
+		words = append(words, suggest.LemmaWords(l)...)
+	}
+	r.suggest.AddWords(words...)
+}
+
+// forgetSuggestion removes usage of query from deleted history entry from suggestions. Usage is
+// restored from other history entries with the same query, the same way as it's loaded on start.
+func (r *Resolver) forgetSuggestion(query string) {
+	if r.suggest == nil {
+		return
+	}
+	// query could be recorded as kana or as english word
+	normalizedQuery := kana.NormalizeQuery(query)
+	r.suggest.RemoveUsage(normalizedQuery)
+	r.suggest.RemoveUsage(strings.TrimSpace(query))
+	if r.history == nil {
+		return
+	}
+	entries, _, err := r.history.List(0, 0, &history.Filter{Query: query})
+	if err != nil {
+		r.logger.Warn("suggestions are not restored from history", zap.String("query", query), zap.Error(err))
+		return
+	}
+	for _, entry := range entries {
+		if entry.Query == query && entry.ResultCount != 0 {
+			r.suggest.AddUsage(normalizedQuery, entry.Time)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// accentdict is offline pitch dictionary that uses tab separated file
+// in format of Kanjium project (https://github.com/mifunetoshiro/kanjium):
+//
+//	word<TAB>reading<TAB>accents
+//
+// where accents is comma separated list of accent numbers, optionally prefixed
+// with part of speech in parentheses, for example `(名)0,(副)1`.
+package accentdict
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+// AccentDict is implementation of multidict.PitchDict.
+type AccentDict struct {
+	// index maps word and reading to all entries that have it
+	index map[string][]*lemma.PitchedLemma
+}
+
+// Open reads accent file located at path.
+func Open(path string) (*AccentDict, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("accent file is not available: %w", err)
+	}
+	defer file.Close()
+	return New(file)
+}
+
+// New reads accent data from src.
+func New(src io.Reader) (*AccentDict, error) {
+	dict := &AccentDict{
+		index: map[string][]*lemma.PitchedLemma{},
+	}
+	scanner := bufio.NewScanner(src)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		pitchedLemmas, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		for _, pitched := range pitchedLemmas {
+			dict.add(pitched.Slug, pitched)
+			if pitched.Hiragana != pitched.Slug {
+				dict.add(pitched.Hiragana, pitched)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("accent file reading failed: %w", err)
+	}
+	return dict, nil
+}
+
+func (d *AccentDict) add(key string, pitched *lemma.PitchedLemma) {
+	d.index[key] = append(d.index[key], pitched)
+}
+
+// Query returns pitched lemmas which word or reading is exactly query.
+func (d *AccentDict) Query(_ context.Context, query string) ([]*lemma.PitchedLemma, error) {
+	pitchedLemmas := d.index[query]
+	if len(pitchedLemmas) == 0 {
+		return nil, nil
+	}
+	// we return copies, so that caller can not modify our index
+	result := make([]*lemma.PitchedLemma, len(pitchedLemmas))
+	for i, pitched := range pitchedLemmas {
+		pitchedCopy := *pitched
+		result[i] = &pitchedCopy
+	}
+	return result, nil
+}
+
+var partOfSpeechPrefix = regexp.MustCompile(`^\([^)]*\)`)
+
+func parseLine(line string) ([]*lemma.PitchedLemma, error) {
+	columns := strings.Split(line, "\t")
+	if len(columns) != 3 {
+		return nil, fmt.Errorf("expected 3 columns, got %d", len(columns))
+	}
+	word, reading, accents := columns[0], columns[1], columns[2]
+	if word == "" {
+		return nil, fmt.Errorf("word is empty")
+	}
+	if reading == "" {
+		reading = word
+	}
+	var result []*lemma.PitchedLemma
+	seen := map[int]struct{}{}
+	for _, rawAccent := range strings.Split(accents, ",") {
+		rawAccent = partOfSpeechPrefix.ReplaceAllString(strings.TrimSpace(rawAccent), "")
+		accent, err := strconv.Atoi(rawAccent)
+		if err != nil {
+			return nil, fmt.Errorf("invalid accent %q", rawAccent)
+		}
+		if _, ok := seen[accent]; ok {
+			continue
+		}
+		seen[accent] = struct{}{}
+		shapes := PitchShapes(reading, accent)
+		if shapes == nil {
+			return nil, fmt.Errorf("accent %d is out of range for reading %q", accent, reading)
+		}
+		result = append(result, &lemma.PitchedLemma{
+			Slug:        word,
+			Hiragana:    reading,
+			PitchShapes: shapes,
+		})
+	}
+	return result, nil
+}
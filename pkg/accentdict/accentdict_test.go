@@ -0,0 +1,129 @@
+package accentdict
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+func Test_AccentDict_Query(t *testing.T) {
+	dict, err := Open(filepath.Join("testdata", "accents.txt"))
+	require.NoError(t, err)
+	testCases := []struct {
+		Name     string
+		Query    string
+		Expected []*lemma.PitchedLemma
+	}{
+		{
+			Name:  "by word",
+			Query: "犬",
+			Expected: []*lemma.PitchedLemma{
+				{
+					Slug:     "犬",
+					Hiragana: "いぬ",
+					PitchShapes: []lemma.PitchShape{
+						{Hiragana: "い", Directions: []lemma.AccentDirection{down}},
+						{Hiragana: "ぬ", Directions: []lemma.AccentDirection{up, left, right}},
+					},
+				},
+			},
+		},
+		{
+			Name:  "by reading",
+			Query: "きょう",
+			Expected: []*lemma.PitchedLemma{
+				{
+					Slug:     "今日",
+					Hiragana: "きょう",
+					PitchShapes: []lemma.PitchShape{
+						{Hiragana: "きょ", Directions: []lemma.AccentDirection{up}},
+						{Hiragana: "う", Directions: []lemma.AccentDirection{down, left}},
+					},
+				},
+			},
+		},
+		{
+			Name:  "several accents with part of speech",
+			Query: "嗚呼",
+			Expected: []*lemma.PitchedLemma{
+				{
+					Slug:     "嗚呼",
+					Hiragana: "ああ",
+					PitchShapes: []lemma.PitchShape{
+						{Hiragana: "あ", Directions: []lemma.AccentDirection{up}},
+						{Hiragana: "あ", Directions: []lemma.AccentDirection{down, left}},
+					},
+				},
+				{
+					Slug:     "嗚呼",
+					Hiragana: "ああ",
+					PitchShapes: []lemma.PitchShape{
+						{Hiragana: "あ", Directions: []lemma.AccentDirection{down}},
+						{Hiragana: "あ", Directions: []lemma.AccentDirection{up, left}},
+					},
+				},
+			},
+		},
+		{
+			Name:  "not found",
+			Query: "猫",
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			result, err := dict.Query(context.Background(), tc.Query)
+			require.NoError(t, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+	t.Run("several readings", func(t *testing.T) {
+		result, err := dict.Query(context.Background(), "日本")
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		assert.Equal(t, "にほん", result[0].Hiragana)
+		assert.Equal(t, "にっぽん", result[1].Hiragana)
+	})
+}
+
+func Test_New_Errors(t *testing.T) {
+	testCases := []struct {
+		Name  string
+		Src   string
+		Error string
+	}{
+		{
+			Name:  "columns",
+			Src:   "犬\tいぬ",
+			Error: "line 1: expected 3 columns, got 2",
+		},
+		{
+			Name:  "accent",
+			Src:   "\n犬\tいぬ\tx",
+			Error: `line 2: invalid accent "x"`,
+		},
+		{
+			Name:  "range",
+			Src:   "犬\tいぬ\t3",
+			Error: `line 1: accent 3 is out of range for reading "いぬ"`,
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			_, err := New(strings.NewReader(tc.Src))
+			assert.EqualError(t, err, tc.Error)
+		})
+	}
+}
+
+func Test_Open_NotExists(t *testing.T) {
+	_, err := Open(filepath.Join(t.TempDir(), "notexists"))
+	assert.ErrorContains(t, err, "accent file is not available")
+}
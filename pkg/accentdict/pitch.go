@@ -0,0 +1,74 @@
+package accentdict
+
+import (
+	"strings"
+
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+// PitchShapes converts accent number (position of mora after which pitch drops,
+// 0 means that pitch doesn't drop) to pitch shapes in the same format as wadoku uses:
+// consecutive moras with the same pitch are grouped together, every group except first
+// has AccentDirectionLeft and drop after last mora is marked with AccentDirectionRight.
+// It returns nil if accent is out of range.
+func PitchShapes(reading string, accent int) []lemma.PitchShape {
+	moras := SplitMoras(reading)
+	if len(moras) == 0 || accent < 0 || accent > len(moras) {
+		return nil
+	}
+	high := make([]bool, len(moras))
+	for i := range moras {
+		switch {
+		case accent == 1:
+			high[i] = i == 0
+		case accent == 0:
+			high[i] = i != 0
+		default:
+			high[i] = i != 0 && i < accent
+		}
+	}
+	var shapes []lemma.PitchShape
+	var buffer strings.Builder
+	for i := range moras {
+		buffer.WriteString(moras[i])
+		if i+1 < len(moras) && high[i] == high[i+1] {
+			continue
+		}
+		direction := lemma.AccentDirectionDown
+		if high[i] {
+			direction = lemma.AccentDirectionUp
+		}
+		shape := lemma.PitchShape{
+			Hiragana:   buffer.String(),
+			Directions: []lemma.AccentDirection{direction},
+		}
+		if len(shapes) != 0 {
+			shape.Directions = append(shape.Directions, lemma.AccentDirectionLeft)
+		}
+		shapes = append(shapes, shape)
+		buffer.Reset()
+	}
+	// pitch drops after the last mora (odaka or atamadaka with single mora)
+	if accent == len(moras) {
+		last := &shapes[len(shapes)-1]
+		last.Directions = append(last.Directions, lemma.AccentDirectionRight)
+	}
+	return shapes
+}
+
+// smallKana are kana that don't form mora on their own, but modify previous one.
+const smallKana = "ゃゅょぁぃぅぇぉゎャュョァィゥェォヮ"
+
+// SplitMoras splits reading to moras. Small kana are attached to previous
+// symbol, while long vowel mark, sokuon and n are separate moras.
+func SplitMoras(reading string) []string {
+	var moras []string
+	for _, r := range reading {
+		if strings.ContainsRune(smallKana, r) && len(moras) != 0 {
+			moras[len(moras)-1] += string(r)
+			continue
+		}
+		moras = append(moras, string(r))
+	}
+	return moras
+}
@@ -0,0 +1,117 @@
+package accentdict
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+const (
+	up    = lemma.AccentDirectionUp
+	right = lemma.AccentDirectionRight
+	down  = lemma.AccentDirectionDown
+	left  = lemma.AccentDirectionLeft
+)
+
+func Test_PitchShapes(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Reading  string
+		Accent   int
+		Expected []lemma.PitchShape
+	}{
+		{
+			Name:    "heiban",
+			Reading: "いぬばしり",
+			Accent:  0,
+			Expected: []lemma.PitchShape{
+				{Hiragana: "い", Directions: []lemma.AccentDirection{down}},
+				{Hiragana: "ぬばしり", Directions: []lemma.AccentDirection{up, left}},
+			},
+		},
+		{
+			Name:    "heiban single mora",
+			Reading: "き",
+			Accent:  0,
+			Expected: []lemma.PitchShape{
+				{Hiragana: "き", Directions: []lemma.AccentDirection{down}},
+			},
+		},
+		{
+			Name:    "atamadaka",
+			Reading: "きょうと",
+			Accent:  1,
+			Expected: []lemma.PitchShape{
+				{Hiragana: "きょ", Directions: []lemma.AccentDirection{up}},
+				{Hiragana: "うと", Directions: []lemma.AccentDirection{down, left}},
+			},
+		},
+		{
+			Name:    "atamadaka single mora",
+			Reading: "き",
+			Accent:  1,
+			Expected: []lemma.PitchShape{
+				{Hiragana: "き", Directions: []lemma.AccentDirection{up, right}},
+			},
+		},
+		{
+			Name:    "nakadaka",
+			Reading: "たべる",
+			Accent:  2,
+			Expected: []lemma.PitchShape{
+				{Hiragana: "た", Directions: []lemma.AccentDirection{down}},
+				{Hiragana: "べ", Directions: []lemma.AccentDirection{up, left}},
+				{Hiragana: "る", Directions: []lemma.AccentDirection{down, left}},
+			},
+		},
+		{
+			Name:    "odaka",
+			Reading: "いぬ",
+			Accent:  2,
+			Expected: []lemma.PitchShape{
+				{Hiragana: "い", Directions: []lemma.AccentDirection{down}},
+				{Hiragana: "ぬ", Directions: []lemma.AccentDirection{up, left, right}},
+			},
+		},
+		{
+			Name:    "long vowel and sokuon",
+			Reading: "にっぽん",
+			Accent:  3,
+			Expected: []lemma.PitchShape{
+				{Hiragana: "に", Directions: []lemma.AccentDirection{down}},
+				{Hiragana: "っぽ", Directions: []lemma.AccentDirection{up, left}},
+				{Hiragana: "ん", Directions: []lemma.AccentDirection{down, left}},
+			},
+		},
+		{
+			Name:    "out of range",
+			Reading: "いぬ",
+			Accent:  3,
+		},
+		{
+			Name:    "negative",
+			Reading: "いぬ",
+			Accent:  -1,
+		},
+		{
+			Name:   "empty",
+			Accent: 0,
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, PitchShapes(tc.Reading, tc.Accent))
+		})
+	}
+}
+
+func Test_SplitMoras(t *testing.T) {
+	assert.Equal(t, []string{"きょ", "う", "と"}, SplitMoras("きょうと"))
+	assert.Equal(t, []string{"コ", "ー", "ヒ", "ー"}, SplitMoras("コーヒー"))
+	assert.Equal(t, []string{"に", "っ", "ぽ", "ん"}, SplitMoras("にっぽん"))
+	assert.Equal(t, []string{"ゃ"}, SplitMoras("ゃ"))
+	assert.Nil(t, SplitMoras(""))
+}
@@ -3,6 +3,10 @@ package anki
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
 	"strings"
 	"sync"
 
@@ -20,7 +24,10 @@ type StatefullClient interface {
 	CreateDeck(ctx context.Context, name string) error
 	CreateDefaultNoteType(ctx context.Context, name string) error
 	AddNote(ctx context.Context, note *AddNoteRequest) (int64, error)
+	AddNotes(ctx context.Context, notes []*AddNoteRequest) ([]AddNoteResult, error)
 	QueryNotes(ctx context.Context, query string) ([]*ankiconnect.NoteInfo, error)
+	UpdateNote(ctx context.Context, note *UpdateNoteRequest) error
+	SetConnectedHandler(handler func())
 }
 
 type StatefullClientConstructorFn func(*Config) (StatefullClient, error)
@@ -34,12 +41,21 @@ func DefaultStatefullClientConstructor(conf *Config) (StatefullClient, error) {
 	return client, nil
 }
 
+// AudioFetcher downloads and checks audio, so it can be sent to Anki as data.
+type AudioFetcher interface {
+	FetchAudio(ctx context.Context, source string) ([]byte, error)
+}
+
 // Anki is wrapper the main purpose is to support config reloading
 type Anki struct {
 	constructor StatefullClientConstructorFn
+	// audioFetcher is nil if audio should be downloaded by Anki itself
+	audioFetcher AudioFetcher
 
 	mu     sync.Mutex
 	client StatefullClient
+	// connectedHandler is passed to every new client
+	connectedHandler func()
 }
 
 // NewAnki return uninitialized Anki instance.
@@ -51,6 +67,12 @@ func NewAnki(constuctor StatefullClientConstructorFn) *Anki {
 	}
 }
 
+// SetAudioFetcher makes AddNote to download audio assets before sending them to Anki.
+// It should be called before use.
+func (a *Anki) SetAudioFetcher(audioFetcher AudioFetcher) {
+	a.audioFetcher = audioFetcher
+}
+
 // ReloadConfig intialize internal client with config.
 func (a *Anki) ReloadConfig(config *Config) error {
 	statefullClient, err := a.constructor(config)
@@ -64,9 +86,24 @@ func (a *Anki) ReloadConfig(config *Config) error {
 		a.client.Stop()
 	}
 	a.client = statefullClient
+	if a.connectedHandler != nil {
+		statefullClient.SetConnectedHandler(a.connectedHandler)
+	}
 	return nil
 }
 
+// SetConnectedHandler sets handler that is called in its own goroutine every time Anki becomes available:
+// when state check succeeds after failed one, when state becomes ready to add notes (e.g. deck or note type
+// was created) and when client is created with new config.
+func (a *Anki) SetConnectedHandler(handler func()) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.connectedHandler = handler
+	if a.client != nil {
+		a.client.SetConnectedHandler(handler)
+	}
+}
+
 type StateResult struct {
 	Version int
 
@@ -164,11 +201,15 @@ func (a *Anki) PrepareProjectedLemma(ctx context.Context, lemma *lemma.Projected
 		// Probably best to leave as unexported error
 		return nil, err
 	}
+	tags, err := prepareTagsForNoteRequest(lemma, config.Tags)
+	if err != nil {
+		return nil, err
+	}
 	audioAssets := prepareAudiosForNoteRequest(lemma, config)
 	return &AddNoteRequest{
 		Fields:      fields,
+		Tags:        tags,
 		AudioAssets: audioAssets,
-		// TODO: add tags
 	}, nil
 }
 
@@ -240,25 +281,235 @@ func sortLemmaAudios(audios []lemma.Audio, preferredType string) []lemma.Audio {
 
 // AddNote sends request to anki-connect to add specified note.
 // If there are assets with equal Field name, then only first of these asset is saved.
+// If AudioFetcher is set, assets with URL are downloaded and sent as data, assets that
+// failed to download are replaced by the next asset with the same Field. If all assets
+// for the field failed, note is not added.
 func (a *Anki) AddNote(ctx context.Context, note *AddNoteRequest) (NoteID, error) {
 	// create copy with filtered out assets that has duplicated field
 	noteCopy := *note
+	assets, err := selectAudioAssets(ctx, a.audioFetcher, note.AudioAssets)
+	if err != nil {
+		return 0, err
+	}
+	noteCopy.AudioAssets = assets
+	id, err := a.getClient().AddNote(ctx, &noteCopy)
+	return NoteID(id), err
+}
+
+// AddNoteResult is result of adding single note with AddNotes, either NoteID or Err is set.
+type AddNoteResult struct {
+	NoteID NoteID
+	Err    error
+}
+
+// AddNotes adds many notes with single request to anki-connect. Audio assets of every note are
+// selected and downloaded like in AddNote, notes without audio get ErrAudioUnavailable and are not sent.
+// Other notes get ErrDuplicatedNoteFound or ErrNoteCreationFailed if they were not added.
+// Returned error means that no note was added.
+func (a *Anki) AddNotes(ctx context.Context, notes []*AddNoteRequest) ([]AddNoteResult, error) {
+	results := make([]AddNoteResult, len(notes))
+	var prepared []*AddNoteRequest
+	var indexes []int
+	for i, note := range notes {
+		assets, err := selectAudioAssets(ctx, a.audioFetcher, note.AudioAssets)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		noteCopy := *note
+		noteCopy.AudioAssets = assets
+		prepared = append(prepared, &noteCopy)
+		indexes = append(indexes, i)
+	}
+	if len(prepared) == 0 {
+		return results, nil
+	}
+	added, err := a.getClient().AddNotes(ctx, prepared)
+	if err != nil {
+		return nil, err
+	}
+	for j := range added {
+		results[indexes[j]] = added[j]
+	}
+	return results, nil
+}
+
+// selectAudioAssets returns only first asset for every field. If audioFetcher is not nil, assets are
+// downloaded and the next asset for the same field is tried if download failed.
+func selectAudioAssets(ctx context.Context, audioFetcher AudioFetcher, noteAssets []AddNoteAudioAsset) ([]AddNoteAudioAsset, error) {
 	var assets []AddNoteAudioAsset
 	usedFields := map[string]struct{}{}
-	for _, asset := range note.AudioAssets {
+	failedFields := map[string][]error{}
+	for _, asset := range noteAssets {
 		_, ok := usedFields[asset.Field]
 		if ok {
 			continue
 		}
+		if audioFetcher != nil && asset.Data == "" && asset.URL != "" {
+			data, err := audioFetcher.FetchAudio(ctx, asset.URL)
+			if err != nil {
+				failedFields[asset.Field] = append(failedFields[asset.Field], fmt.Errorf("%s: %w", asset.URL, err))
+				continue
+			}
+			asset.Data = base64.StdEncoding.EncodeToString(data)
+			asset.URL = ""
+		}
 		usedFields[asset.Field] = struct{}{}
 		assets = append(assets, asset)
 	}
-	noteCopy.AudioAssets = assets
-	// TODO: we can also predownload resource ourselves, instead of passing this task to Anki
-	// probably we can do better caching and error handling, but it require to configure another
-	// http client.
-	id, err := a.getClient().AddNote(ctx, &noteCopy)
-	return NoteID(id), err
+	var errs []error
+	for _, asset := range noteAssets {
+		if _, ok := usedFields[asset.Field]; ok {
+			continue
+		}
+		errs = append(errs, failedFields[asset.Field]...)
+		delete(failedFields, asset.Field)
+	}
+	if len(errs) != 0 {
+		return nil, fmt.Errorf("%w: %w", ErrAudioUnavailable, errors.Join(errs...))
+	}
+	return assets, nil
+}
+
+type UpdateNoteOptions struct {
+	// OnlyEmpty allows to change only fields that are empty in current note
+	OnlyEmpty bool
+	// DryRun only compares note with request, nothing is written
+	DryRun bool
+}
+
+// NoteFieldDiff is comparison of current field value with the new one.
+type NoteFieldDiff struct {
+	Name string
+	Old  string
+	// New is rendered value, it is written only if Changed is true
+	New     string
+	Changed bool
+}
+
+type UpdateNoteResult struct {
+	Fields []NoteFieldDiff
+	// AddedTags are tags from request that note doesn't have. Tags are never removed.
+	AddedTags []string
+}
+
+// UpdateNoteRequest is request to change existing note.
+type UpdateNoteRequest struct {
+	NoteID NoteID
+	// Fields contains only fields that should be changed
+	Fields []AddNoteField
+	// Tags replace tags of note, nil means that tags are not changed
+	Tags []string
+	// AudioAssets are stored in media collection before fields are changed,
+	// fields should already reference them
+	AudioAssets []AddNoteAudioAsset
+}
+
+// UpdateNote compares existing note with request (usually prepared by PrepareProjectedLemma) and
+// writes changed fields and missing tags. Audio is stored in media collection and its reference
+// replaces content of audio field. Fields that note doesn't have are ignored.
+// With DryRun audio is not downloaded, so the first asset is shown in diff even if it can't be fetched.
+func (a *Anki) UpdateNote(ctx context.Context, id NoteID, note *AddNoteRequest, opts *UpdateNoteOptions) (*UpdateNoteResult, error) {
+	client := a.getClient()
+	current, err := getNoteByID(ctx, client, id)
+	if err != nil {
+		return nil, err
+	}
+	canChange := func(field string) bool {
+		currentField, ok := current.Fields[field]
+		if !ok {
+			return false
+		}
+		return !opts.OnlyEmpty || currentField.Value == ""
+	}
+	var audioAssets []AddNoteAudioAsset
+	for _, asset := range note.AudioAssets {
+		if canChange(asset.Field) {
+			audioAssets = append(audioAssets, asset)
+		}
+	}
+	var audioFetcher AudioFetcher
+	if !opts.DryRun {
+		audioFetcher = a.audioFetcher
+	}
+	audioAssets, err = selectAudioAssets(ctx, audioFetcher, audioAssets)
+	if err != nil {
+		return nil, err
+	}
+	newValues := make(map[string]string, len(note.Fields))
+	var fieldNames []string
+	addValue := func(name, value string) {
+		if _, ok := newValues[name]; !ok {
+			fieldNames = append(fieldNames, name)
+		}
+		newValues[name] = newValues[name] + value
+	}
+	for _, field := range note.Fields {
+		addValue(field.Name, field.Value)
+	}
+	for _, asset := range audioAssets {
+		addValue(asset.Field, "[sound:"+asset.Filename+"]")
+	}
+	result := &UpdateNoteResult{
+		Fields: []NoteFieldDiff{},
+	}
+	update := &UpdateNoteRequest{
+		NoteID: id,
+	}
+	for _, name := range fieldNames {
+		currentField, ok := current.Fields[name]
+		if !ok {
+			continue
+		}
+		diff := NoteFieldDiff{
+			Name:    name,
+			Old:     currentField.Value,
+			New:     newValues[name],
+			Changed: currentField.Value != newValues[name] && canChange(name),
+		}
+		result.Fields = append(result.Fields, diff)
+		if diff.Changed {
+			update.Fields = append(update.Fields, AddNoteField{
+				Name:  name,
+				Value: diff.New,
+			})
+		}
+	}
+	for _, asset := range audioAssets {
+		if slices.ContainsFunc(update.Fields, func(f AddNoteField) bool { return f.Name == asset.Field }) {
+			update.AudioAssets = append(update.AudioAssets, asset)
+		}
+	}
+	for _, tag := range note.Tags {
+		// anki ignores case of tags
+		hasTag := func(t string) bool { return strings.EqualFold(t, tag) }
+		if !slices.ContainsFunc(current.Tags, hasTag) && !slices.ContainsFunc(result.AddedTags, hasTag) {
+			result.AddedTags = append(result.AddedTags, tag)
+		}
+	}
+	if len(result.AddedTags) != 0 {
+		update.Tags = append(slices.Clone(current.Tags), result.AddedTags...)
+	}
+	if opts.DryRun || (len(update.Fields) == 0 && update.Tags == nil) {
+		return result, nil
+	}
+	if err := client.UpdateNote(ctx, update); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func getNoteByID(ctx context.Context, client StatefullClient, id NoteID) (*ankiconnect.NoteInfo, error) {
+	notes, err := client.QueryNotes(ctx, query.Render(query.Exact("nid", id.String())))
+	if err != nil {
+		return nil, err
+	}
+	for _, note := range notes {
+		if note.NoteID == int64(id) {
+			return note, nil
+		}
+	}
+	return nil, ErrNoteNotFound
 }
 
 // SearchProjectedLemmas returns note id for specified lemmas, empty string means not found.
@@ -80,6 +80,33 @@ func Test_Anki_ReloadConfig(t *testing.T) {
 	})
 }
 
+func Test_Anki_SetConnectedHandler(t *testing.T) {
+	var clients []*MockStatefullClient
+	constructor := func(config *Config) (StatefullClient, error) {
+		client := NewMockStatefullClient(t)
+		client.On("Stop").Return().Maybe()
+		client.On("SetConnectedHandler", mock.Anything).Return().Maybe()
+		clients = append(clients, client)
+		return client, nil
+	}
+	anki := NewAnki(constructor)
+	err := anki.ReloadConfig(&Config{})
+	require.NoError(t, err)
+	clients[0].AssertNotCalled(t, "SetConnectedHandler", mock.Anything)
+
+	called := 0
+	anki.SetConnectedHandler(func() { called++ })
+	clients[0].AssertNumberOfCalls(t, "SetConnectedHandler", 1)
+	handler := clients[0].Calls[0].Arguments.Get(0).(func())
+	handler()
+	assert.Equal(t, 1, called)
+
+	// new client gets the same handler
+	err = anki.ReloadConfig(&Config{})
+	require.NoError(t, err)
+	clients[1].AssertNumberOfCalls(t, "SetConnectedHandler", 1)
+}
+
 func Test_Anki_FullStateCheck(t *testing.T) {
 	testCases := []struct {
 		Name        string
@@ -414,6 +441,7 @@ func Test_Anki_PrepareProjectedLemma(t *testing.T) {
 						Value: "",
 					},
 				},
+				Tags: []string{},
 			},
 		},
 		{
@@ -442,6 +470,7 @@ func Test_Anki_PrepareProjectedLemma(t *testing.T) {
 						Value: "",
 					},
 				},
+				Tags: []string{},
 			},
 		},
 		{
@@ -467,6 +496,7 @@ func Test_Anki_PrepareProjectedLemma(t *testing.T) {
 						Name: "b",
 					},
 				},
+				Tags: []string{},
 				AudioAssets: []AddNoteAudioAsset{
 					{
 						Field:    "Audio",
@@ -481,6 +511,30 @@ func Test_Anki_PrepareProjectedLemma(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "tags",
+			InitClient: func(conf *Config, client *MockStatefullClient) {
+				newState := *readyState
+				newState.CurrentFields = []string{"a"}
+				client.On("GetState", mock.Anything).
+					Return(&newState, nil)
+				client.On("Config").Return(conf)
+			},
+			Config: &Config{
+				Tags: mustConvertTagRules(t, []string{"japwords"}, []string{
+					`{{ range .Tags }}{{ if hasPrefix "JLPT " . }}{{ replace "JLPT " "JLPT::" . }}{{ end }}{{ end }}`,
+				}),
+			},
+			AssertError: assert.NoError,
+			Expected: &AddNoteRequest{
+				Fields: []AddNoteField{
+					{
+						Name: "a",
+					},
+				},
+				Tags: []string{"japwords", "JLPT::N5"},
+			},
+		},
 	}
 	for i := range testCases {
 		tc := testCases[i]
@@ -811,6 +865,305 @@ func Test_Anki_AddNote(t *testing.T) {
 		assert.Equal(t, NoteID(32), noteID)
 		assert.NoError(t, err)
 	})
+	t.Run("fetch audio", func(t *testing.T) {
+		request := &AddNoteRequest{
+			AudioAssets: []AddNoteAudioAsset{
+				{
+					Field:    "foo",
+					Filename: "a",
+					URL:      "broken",
+				},
+				{
+					Field:    "foo",
+					Filename: "b",
+					URL:      "good",
+				},
+				{
+					Field:    "bar",
+					Filename: "c",
+					Data:     "ZGF0YQ==",
+				},
+			},
+		}
+		expectedRequest := &AddNoteRequest{
+			AudioAssets: []AddNoteAudioAsset{
+				{
+					Field:    "foo",
+					Filename: "b",
+					Data:     "Z29vZCBhdWRpbw==",
+				},
+				{
+					Field:    "bar",
+					Filename: "c",
+					Data:     "ZGF0YQ==",
+				},
+			},
+		}
+		anki := NewAnki(func(_ *Config) (StatefullClient, error) {
+			client := NewMockStatefullClient(t)
+			client.On("AddNote", mock.Anything, expectedRequest).Return(int64(32), nil).Once()
+			return client, nil
+		})
+		anki.SetAudioFetcher(testAudioFetcher{
+			"good": []byte("good audio"),
+		})
+		err := anki.ReloadConfig(&Config{})
+		require.NoError(t, err)
+		noteID, err := anki.AddNote(context.Background(), request)
+		assert.Equal(t, NoteID(32), noteID)
+		assert.NoError(t, err)
+	})
+	t.Run("all audio failed", func(t *testing.T) {
+		request := &AddNoteRequest{
+			AudioAssets: []AddNoteAudioAsset{
+				{
+					Field:    "foo",
+					Filename: "a",
+					URL:      "broken",
+				},
+				{
+					Field:    "foo",
+					Filename: "b",
+					URL:      "alsobroken",
+				},
+			},
+		}
+		anki := NewAnki(func(_ *Config) (StatefullClient, error) {
+			return NewMockStatefullClient(t), nil
+		})
+		anki.SetAudioFetcher(testAudioFetcher{})
+		err := anki.ReloadConfig(&Config{})
+		require.NoError(t, err)
+		_, err = anki.AddNote(context.Background(), request)
+		assert.ErrorIs(t, err, ErrAudioUnavailable)
+		assert.ErrorContains(t, err, "alsobroken")
+	})
+}
+
+func Test_Anki_AddNotes(t *testing.T) {
+	t.Run("client error", func(t *testing.T) {
+		anki := NewAnki(func(_ *Config) (StatefullClient, error) {
+			client := NewMockStatefullClient(t)
+			client.On("AddNotes", mock.Anything, mock.Anything).Return(nil, errors.New("myerror")).Once()
+			return client, nil
+		})
+		require.NoError(t, anki.ReloadConfig(&Config{}))
+		results, err := anki.AddNotes(context.Background(), []*AddNoteRequest{{}})
+		assert.Nil(t, results)
+		assert.ErrorContains(t, err, "myerror")
+	})
+	t.Run("audio", func(t *testing.T) {
+		requests := []*AddNoteRequest{
+			{
+				Tags: []string{"first"},
+				AudioAssets: []AddNoteAudioAsset{
+					{Field: "foo", Filename: "a", URL: "broken"},
+					{Field: "foo", Filename: "b", URL: "good"},
+					{Field: "foo", Filename: "c", URL: "good"},
+				},
+			},
+			{
+				Tags: []string{"second"},
+				AudioAssets: []AddNoteAudioAsset{
+					{Field: "foo", Filename: "a", URL: "broken"},
+				},
+			},
+			{
+				Tags: []string{"third"},
+			},
+		}
+		expectedRequests := []*AddNoteRequest{
+			{
+				Tags: []string{"first"},
+				AudioAssets: []AddNoteAudioAsset{
+					{Field: "foo", Filename: "b", Data: "Z29vZCBhdWRpbw=="},
+				},
+			},
+			{
+				Tags: []string{"third"},
+			},
+		}
+		anki := NewAnki(func(_ *Config) (StatefullClient, error) {
+			client := NewMockStatefullClient(t)
+			client.On("AddNotes", mock.Anything, expectedRequests).
+				Return([]AddNoteResult{{NoteID: 1}, {Err: ErrDuplicatedNoteFound}}, nil).
+				Once()
+			return client, nil
+		})
+		anki.SetAudioFetcher(testAudioFetcher{
+			"good": []byte("good audio"),
+		})
+		require.NoError(t, anki.ReloadConfig(&Config{}))
+		results, err := anki.AddNotes(context.Background(), requests)
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		assert.Equal(t, AddNoteResult{NoteID: 1}, results[0])
+		assert.ErrorIs(t, results[1].Err, ErrAudioUnavailable)
+		assert.Equal(t, AddNoteResult{Err: ErrDuplicatedNoteFound}, results[2])
+	})
+	t.Run("nothing to add", func(t *testing.T) {
+		anki := NewAnki(func(_ *Config) (StatefullClient, error) {
+			return NewMockStatefullClient(t), nil
+		})
+		anki.SetAudioFetcher(testAudioFetcher{})
+		require.NoError(t, anki.ReloadConfig(&Config{}))
+		results, err := anki.AddNotes(context.Background(), []*AddNoteRequest{
+			{
+				AudioAssets: []AddNoteAudioAsset{
+					{Field: "foo", Filename: "a", URL: "broken"},
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.ErrorIs(t, results[0].Err, ErrAudioUnavailable)
+	})
+}
+
+func Test_Anki_UpdateNote(t *testing.T) {
+	currentNote := func() *ankiconnect.NoteInfo {
+		return &ankiconnect.NoteInfo{
+			NoteID:    12,
+			ModelName: "note1",
+			Tags:      []string{"Japwords", "other"},
+			Fields: map[string]*ankiconnect.NoteInfoField{
+				"Kanji":   {Value: "犬", Order: 0},
+				"Meaning": {Value: "old meaning", Order: 1},
+				"Pitch":   {Value: "", Order: 2},
+				"Audio":   {Value: "", Order: 3},
+			},
+		}
+	}
+	request := func() *AddNoteRequest {
+		return &AddNoteRequest{
+			Fields: []AddNoteField{
+				{Name: "Kanji", Value: "犬"},
+				{Name: "Meaning", Value: "dog"},
+				{Name: "Pitch", Value: "い\\ぬ"},
+				{Name: "Unknown", Value: "skipped"},
+			},
+			Tags: []string{"japwords", "JLPT::N5", "JLPT::N5"},
+			AudioAssets: []AddNoteAudioAsset{
+				{Field: "Audio", Filename: "犬-いぬ.ogg", URL: "broken"},
+				{Field: "Audio", Filename: "犬-いぬ.mp3", URL: "good"},
+			},
+		}
+	}
+	newAnki := func(t *testing.T, note *ankiconnect.NoteInfo, update *UpdateNoteRequest) *Anki {
+		anki := NewAnki(func(_ *Config) (StatefullClient, error) {
+			client := NewMockStatefullClient(t)
+			var notes []*ankiconnect.NoteInfo
+			if note != nil {
+				notes = append(notes, note)
+			}
+			client.On("QueryNotes", mock.Anything, `"nid:12"`).Return(notes, nil).Once()
+			if update != nil {
+				client.On("UpdateNote", mock.Anything, update).Return(nil).Once()
+			}
+			return client, nil
+		})
+		anki.SetAudioFetcher(testAudioFetcher{
+			"good": []byte("good audio"),
+		})
+		require.NoError(t, anki.ReloadConfig(&Config{}))
+		return anki
+	}
+	t.Run("not found", func(t *testing.T) {
+		anki := newAnki(t, nil, nil)
+		_, err := anki.UpdateNote(context.Background(), 12, request(), &UpdateNoteOptions{})
+		assert.ErrorIs(t, err, ErrNoteNotFound)
+	})
+	t.Run("all fields", func(t *testing.T) {
+		anki := newAnki(t, currentNote(), &UpdateNoteRequest{
+			NoteID: 12,
+			Fields: []AddNoteField{
+				{Name: "Meaning", Value: "dog"},
+				{Name: "Pitch", Value: "い\\ぬ"},
+				{Name: "Audio", Value: "[sound:犬-いぬ.mp3]"},
+			},
+			Tags: []string{"Japwords", "other", "JLPT::N5"},
+			AudioAssets: []AddNoteAudioAsset{
+				{Field: "Audio", Filename: "犬-いぬ.mp3", Data: "Z29vZCBhdWRpbw=="},
+			},
+		})
+		result, err := anki.UpdateNote(context.Background(), 12, request(), &UpdateNoteOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, &UpdateNoteResult{
+			Fields: []NoteFieldDiff{
+				{Name: "Kanji", Old: "犬", New: "犬", Changed: false},
+				{Name: "Meaning", Old: "old meaning", New: "dog", Changed: true},
+				{Name: "Pitch", Old: "", New: "い\\ぬ", Changed: true},
+				{Name: "Audio", Old: "", New: "[sound:犬-いぬ.mp3]", Changed: true},
+			},
+			AddedTags: []string{"JLPT::N5"},
+		}, result)
+	})
+	t.Run("only empty", func(t *testing.T) {
+		note := currentNote()
+		note.Fields["Audio"].Value = "[sound:old.mp3]"
+		note.Tags = append(note.Tags, "jlpt::n5")
+		anki := newAnki(t, note, &UpdateNoteRequest{
+			NoteID: 12,
+			Fields: []AddNoteField{
+				{Name: "Pitch", Value: "い\\ぬ"},
+			},
+		})
+		result, err := anki.UpdateNote(context.Background(), 12, request(), &UpdateNoteOptions{OnlyEmpty: true})
+		require.NoError(t, err)
+		assert.Equal(t, &UpdateNoteResult{
+			Fields: []NoteFieldDiff{
+				{Name: "Kanji", Old: "犬", New: "犬", Changed: false},
+				{Name: "Meaning", Old: "old meaning", New: "dog", Changed: false},
+				{Name: "Pitch", Old: "", New: "い\\ぬ", Changed: true},
+			},
+		}, result)
+	})
+	t.Run("dry run", func(t *testing.T) {
+		anki := newAnki(t, currentNote(), nil)
+		result, err := anki.UpdateNote(context.Background(), 12, request(), &UpdateNoteOptions{DryRun: true})
+		require.NoError(t, err)
+		assert.Equal(t, &UpdateNoteResult{
+			Fields: []NoteFieldDiff{
+				{Name: "Kanji", Old: "犬", New: "犬", Changed: false},
+				{Name: "Meaning", Old: "old meaning", New: "dog", Changed: true},
+				{Name: "Pitch", Old: "", New: "い\\ぬ", Changed: true},
+				// audio is not fetched in dry run
+				{Name: "Audio", Old: "", New: "[sound:犬-いぬ.ogg]", Changed: true},
+			},
+			AddedTags: []string{"JLPT::N5"},
+		}, result)
+	})
+	t.Run("nothing changed", func(t *testing.T) {
+		note := currentNote()
+		note.Fields["Meaning"].Value = "dog"
+		note.Fields["Pitch"].Value = "い\\ぬ"
+		note.Fields["Audio"].Value = "[sound:犬-いぬ.mp3]"
+		note.Tags = append(note.Tags, "JLPT::N5")
+		anki := newAnki(t, note, nil)
+		result, err := anki.UpdateNote(context.Background(), 12, request(), &UpdateNoteOptions{})
+		require.NoError(t, err)
+		for _, diff := range result.Fields {
+			assert.False(t, diff.Changed, diff.Name)
+		}
+		assert.Empty(t, result.AddedTags)
+	})
+	t.Run("audio unavailable", func(t *testing.T) {
+		anki := newAnki(t, currentNote(), nil)
+		req := request()
+		req.AudioAssets = req.AudioAssets[:1]
+		_, err := anki.UpdateNote(context.Background(), 12, req, &UpdateNoteOptions{})
+		assert.ErrorIs(t, err, ErrAudioUnavailable)
+	})
+}
+
+type testAudioFetcher map[string][]byte
+
+func (f testAudioFetcher) FetchAudio(_ context.Context, source string) ([]byte, error) {
+	data, ok := f[source]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return data, nil
 }
 
 func Test_Anki_SearchProjectedLemmas(t *testing.T) {
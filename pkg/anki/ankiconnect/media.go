@@ -1,6 +1,7 @@
 package ankiconnect
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 	"slices"
@@ -67,3 +68,30 @@ type MediaAssetRequest struct {
 	SkipHash       string   `json:"skipHash,omitempty"`
 	DeleteExisting bool     `json:"deleteExisting,omitempty"`
 }
+
+// StoreMediaFile saves asset in media collection and returns its filename. Filename can differ
+// from requested if file with the same name exists and DeleteExisting is false.
+// Fields of asset are ignored.
+func (a *Anki) StoreMediaFile(ctx context.Context, asset *MediaAssetRequest) (string, error) {
+	request := storeMediaFileRequest{
+		Filename:       asset.Filename,
+		Data:           asset.Data,
+		Path:           asset.Path,
+		URL:            asset.URL,
+		DeleteExisting: asset.DeleteExisting,
+	}
+	var filename string
+	err := a.request(ctx, "storeMediaFile", &request, &filename)
+	if err != nil {
+		return "", err
+	}
+	return filename, nil
+}
+
+type storeMediaFileRequest struct {
+	Filename       string `json:"filename"`
+	Data           string `json:"data,omitempty"`
+	Path           string `json:"path,omitempty"`
+	URL            string `json:"url,omitempty"`
+	DeleteExisting bool   `json:"deleteExisting"`
+}
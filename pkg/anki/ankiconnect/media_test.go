@@ -1,6 +1,7 @@
 package ankiconnect
 
 import (
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -201,3 +202,85 @@ func Test_NewMediaBlob(t *testing.T) {
 		})
 	}
 }
+
+func Test_Anki_StoreMediaFile(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		Asset       *MediaAssetRequest
+		Handlers    []http.Handler
+		Expected    string
+		ErrorAssert assert.ErrorAssertionFunc
+	}{
+		{
+			Name: "data",
+			Asset: &MediaAssetRequest{
+				Filename:       "hello.mp3",
+				Data:           "aGVsbG8=",
+				Fields:         []string{"ignored"},
+				SkipHash:       "ignored",
+				DeleteExisting: true,
+			},
+			Handlers: []http.Handler{
+				handlerAssertRequest(t, &fullRequest{
+					Action: "storeMediaFile",
+					Params: map[string]any{
+						"filename":       "hello.mp3",
+						"data":           "aGVsbG8=",
+						"deleteExisting": true,
+					},
+				}),
+				handlerRespondJSON(t, &fullResponse{
+					Result: "hello.mp3",
+				}),
+			},
+			Expected:    "hello.mp3",
+			ErrorAssert: assert.NoError,
+		},
+		{
+			Name: "url renamed",
+			Asset: &MediaAssetRequest{
+				Filename: "hello.mp3",
+				URL:      "http://hello",
+			},
+			Handlers: []http.Handler{
+				handlerAssertRequest(t, &fullRequest{
+					Action: "storeMediaFile",
+					Params: map[string]any{
+						"filename":       "hello.mp3",
+						"url":            "http://hello",
+						"deleteExisting": false,
+					},
+				}),
+				handlerRespondJSON(t, &fullResponse{
+					Result: "hello-1.mp3",
+				}),
+			},
+			Expected:    "hello-1.mp3",
+			ErrorAssert: assert.NoError,
+		},
+		{
+			Name: "error",
+			Asset: &MediaAssetRequest{
+				Filename: "hello.mp3",
+			},
+			Handlers: []http.Handler{
+				handlerRespondJSON(t, &fullResponse{
+					Error: "myspecificerr",
+				}),
+			},
+			Expected: "",
+			ErrorAssert: func(t assert.TestingT, err error, _ ...any) bool {
+				return assert.ErrorContains(t, err, "myspecificerr")
+			},
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx, a := prepareMockServer(t, tc.Handlers...)
+			filename, err := a.StoreMediaFile(ctx, tc.Asset)
+			tc.ErrorAssert(t, err)
+			assert.Equal(t, tc.Expected, filename)
+		})
+	}
+}
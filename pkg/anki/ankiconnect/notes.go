@@ -1,6 +1,10 @@
 package ankiconnect
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
 
 func (a *Anki) FindNotes(ctx context.Context, query string) ([]int64, error) {
 	request := struct {
@@ -50,6 +54,77 @@ type AddNoteParams struct {
 // AddNote creates new notes. Params specify note data and opts specifies where it belongs and how it should be
 // added. So likely, that many notes can be added with different params and same opts.
 func (a *Anki) AddNote(ctx context.Context, params *AddNoteParams, opts *AddNoteOptions) (int64, error) {
+	realRequest := addNoteRequest{
+		Note: newAddNoteRequestNote(params, opts),
+	}
+	var response int64
+
+	err := a.request(ctx, "addNote", &realRequest, &response)
+	if err != nil {
+		return 0, err
+	}
+	// anki-connect specify that response can be null (converted as 0, so we have to cases here, but make no distinction)
+	// which means that note wasn't created.
+	if response == 0 {
+		return 0, newServerError("note creation failed")
+	}
+	return response, nil
+}
+
+// AddNotes creates many notes with the same opts at once. Result has id for every note in params,
+// id is 0 if note wasn't created. Newer versions of anki-connect return error instead if any note
+// wasn't created, so it's better to check notes with CanAddNotes first.
+func (a *Anki) AddNotes(ctx context.Context, params []*AddNoteParams, opts *AddNoteOptions) ([]int64, error) {
+	request := addNotesRequest{
+		Notes: make([]addNoteRequestNote, len(params)),
+	}
+	for i := range params {
+		request.Notes[i] = newAddNoteRequestNote(params[i], opts)
+	}
+	var response []*int64
+	err := a.request(ctx, "addNotes", &request, &response)
+	if err != nil {
+		return nil, err
+	}
+	if len(response) != len(params) {
+		return nil, &UnexpectedResponseError{
+			Status: http.StatusOK,
+			Err:    fmt.Errorf("expected %d note ids, got %d", len(params), len(response)),
+		}
+	}
+	result := make([]int64, len(response))
+	for i, id := range response {
+		if id != nil {
+			result[i] = *id
+		}
+	}
+	return result, nil
+}
+
+// CanAddNotes checks if notes can be added with the same opts. Note can't be added
+// if it is duplicate (according to opts) or its first field is empty.
+func (a *Anki) CanAddNotes(ctx context.Context, params []*AddNoteParams, opts *AddNoteOptions) ([]bool, error) {
+	request := addNotesRequest{
+		Notes: make([]addNoteRequestNote, len(params)),
+	}
+	for i := range params {
+		request.Notes[i] = newAddNoteRequestNote(params[i], opts)
+	}
+	var response []bool
+	err := a.request(ctx, "canAddNotes", &request, &response)
+	if err != nil {
+		return nil, err
+	}
+	if len(response) != len(params) {
+		return nil, &UnexpectedResponseError{
+			Status: http.StatusOK,
+			Err:    fmt.Errorf("expected %d results, got %d", len(params), len(response)),
+		}
+	}
+	return response, nil
+}
+
+func newAddNoteRequestNote(params *AddNoteParams, opts *AddNoteOptions) addNoteRequestNote {
 	request := addNoteRequestNote{
 		DeckName:  opts.Deck,
 		ModelName: opts.Model,
@@ -86,21 +161,11 @@ func (a *Anki) AddNote(ctx context.Context, params *AddNoteParams, opts *AddNote
 			panic("unknown asset type")
 		}
 	}
-	realRequest := addNoteRequest{
-		Note: request,
-	}
-	var response int64
+	return request
+}
 
-	err := a.request(ctx, "addNote", &realRequest, &response)
-	if err != nil {
-		return 0, err
-	}
-	// anki-connect specify that response can be null (converted as 0, so we have to cases here, but make no distinction)
-	// which means that note wasn't created.
-	if response == 0 {
-		return 0, newServerError("note creation failed")
-	}
-	return response, nil
+type addNotesRequest struct {
+	Notes []addNoteRequestNote `json:"notes"`
 }
 
 type addNoteRequest struct {
@@ -161,3 +226,60 @@ func (a *Anki) DeleteNotes(ctx context.Context, ids []int64) error {
 	}
 	return a.request(ctx, "deleteNotes", &request, nil)
 }
+
+type UpdateNoteFieldsParams struct {
+	ID     int64
+	Fields map[string]string
+	Assets []*AddNoteAsset
+}
+
+// UpdateNoteFields changes fields of existing note. Fields that are not specified in params are left as is.
+// Assets are stored in media collection and their references are appended to specified fields.
+func (a *Anki) UpdateNoteFields(ctx context.Context, params *UpdateNoteFieldsParams) error {
+	note := updateNoteFieldsRequestNote{
+		ID:     params.ID,
+		Fields: params.Fields,
+	}
+	for _, asset := range params.Assets {
+		switch asset.Type {
+		case MediaTypeAudio:
+			note.Audio = append(note.Audio, &asset.Asset)
+		case MediaTypeVideo:
+			note.Video = append(note.Video, &asset.Asset)
+		case MediaTypePicture:
+			note.Picture = append(note.Picture, &asset.Asset)
+		default:
+			panic("unknown asset type")
+		}
+	}
+	request := struct {
+		Note updateNoteFieldsRequestNote `json:"note"`
+	}{
+		Note: note,
+	}
+	return a.request(ctx, "updateNoteFields", &request, nil)
+}
+
+type updateNoteFieldsRequestNote struct {
+	ID      int64                `json:"id"`
+	Fields  map[string]string    `json:"fields"`
+	Audio   []*MediaAssetRequest `json:"audio,omitempty"`
+	Video   []*MediaAssetRequest `json:"video,omitempty"`
+	Picture []*MediaAssetRequest `json:"picture,omitempty"`
+}
+
+// UpdateNoteTags replaces all tags of note with specified tags.
+func (a *Anki) UpdateNoteTags(ctx context.Context, id int64, tags []string) error {
+	if tags == nil {
+		// anki-connect expects list, null is not allowed
+		tags = []string{}
+	}
+	request := struct {
+		Note int64    `json:"note"`
+		Tags []string `json:"tags"`
+	}{
+		Note: id,
+		Tags: tags,
+	}
+	return a.request(ctx, "updateNoteTags", &request, nil)
+}
@@ -540,3 +540,351 @@ func Test_Anki_DeleteNotes(t *testing.T) {
 		})
 	}
 }
+
+func Test_Anki_UpdateNoteFields(t *testing.T) {
+	testCases := []struct {
+		Name            string
+		Parameters      *UpdateNoteFieldsParams
+		Response        *fullResponse
+		ExpectedRequest map[string]any
+		ErrorAssert     assert.ErrorAssertionFunc
+	}{
+		{
+			Name: "fields",
+			Parameters: &UpdateNoteFieldsParams{
+				ID: 123,
+				Fields: map[string]string{
+					"Front": "hello",
+					"Back":  "world",
+				},
+			},
+			Response: &fullResponse{},
+			ExpectedRequest: map[string]any{
+				"id": float64(123),
+				"fields": map[string]any{
+					"Front": "hello",
+					"Back":  "world",
+				},
+			},
+			ErrorAssert: assert.NoError,
+		},
+		{
+			Name: "assets",
+			Parameters: &UpdateNoteFieldsParams{
+				ID:     1,
+				Fields: map[string]string{},
+				Assets: []*AddNoteAsset{
+					{
+						Asset: MediaAssetRequest{
+							URL:      "http://audio",
+							Filename: "audio.mp3",
+							Fields:   []string{"Audio"},
+						},
+						Type: MediaTypeAudio,
+					},
+					{
+						Asset: MediaAssetRequest{
+							Path:     "/picture",
+							Filename: "picture.png",
+						},
+						Type: MediaTypePicture,
+					},
+				},
+			},
+			Response: &fullResponse{},
+			ExpectedRequest: map[string]any{
+				"id":     float64(1),
+				"fields": map[string]any{},
+				"audio": []any{
+					map[string]any{
+						"url":      "http://audio",
+						"filename": "audio.mp3",
+						"fields":   []any{"Audio"},
+					},
+				},
+				"picture": []any{
+					map[string]any{
+						"path":     "/picture",
+						"filename": "picture.png",
+					},
+				},
+			},
+			ErrorAssert: assert.NoError,
+		},
+		{
+			Name: "error",
+			Parameters: &UpdateNoteFieldsParams{
+				ID:     1,
+				Fields: map[string]string{},
+			},
+			Response: &fullResponse{
+				Error: "myspecificerr",
+			},
+			ExpectedRequest: map[string]any{
+				"id":     float64(1),
+				"fields": map[string]any{},
+			},
+			ErrorAssert: func(t assert.TestingT, err error, _ ...any) bool {
+				return assert.ErrorContains(t, err, "myspecificerr")
+			},
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx, a := prepareMockServer(t,
+				handlerAssertRequest(t, &fullRequest{
+					Action: "updateNoteFields",
+					Params: map[string]any{
+						"note": tc.ExpectedRequest,
+					},
+				}),
+				handlerRespondJSON(t, tc.Response),
+			)
+			err := a.UpdateNoteFields(ctx, tc.Parameters)
+			tc.ErrorAssert(t, err)
+		})
+	}
+}
+
+func Test_Anki_UpdateNoteTags(t *testing.T) {
+	testCases := []struct {
+		Name         string
+		ID           int64
+		Tags         []string
+		Response     *fullResponse
+		ExpectedTags []any
+		ErrorAssert  assert.ErrorAssertionFunc
+	}{
+		{
+			Name:         "tags",
+			ID:           12,
+			Tags:         []string{"a", "b"},
+			Response:     &fullResponse{},
+			ExpectedTags: []any{"a", "b"},
+			ErrorAssert:  assert.NoError,
+		},
+		{
+			Name:         "nil tags",
+			ID:           12,
+			Response:     &fullResponse{},
+			ExpectedTags: []any{},
+			ErrorAssert:  assert.NoError,
+		},
+		{
+			Name: "error",
+			ID:   12,
+			Response: &fullResponse{
+				Error: "myspecificerr",
+			},
+			ExpectedTags: []any{},
+			ErrorAssert: func(t assert.TestingT, err error, _ ...any) bool {
+				return assert.ErrorContains(t, err, "myspecificerr")
+			},
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx, a := prepareMockServer(t,
+				handlerAssertRequest(t, &fullRequest{
+					Action: "updateNoteTags",
+					Params: map[string]any{
+						"note": float64(tc.ID),
+						"tags": tc.ExpectedTags,
+					},
+				}),
+				handlerRespondJSON(t, tc.Response),
+			)
+			err := a.UpdateNoteTags(ctx, tc.ID, tc.Tags)
+			tc.ErrorAssert(t, err)
+		})
+	}
+}
+
+func Test_Anki_AddNotes(t *testing.T) {
+	options := &AddNoteOptions{
+		Deck:           "deck",
+		Model:          "model",
+		DuplicateFlags: DuplicateFlagsCheck,
+	}
+	params := []*AddNoteParams{
+		{
+			Fields: map[string]string{"Front": "a"},
+			Tags:   []string{"tag"},
+		},
+		{
+			Fields: map[string]string{"Front": "b"},
+		},
+	}
+	expectedNotes := []any{
+		map[string]any{
+			"deckName":  "deck",
+			"modelName": "model",
+			"fields":    map[string]any{"Front": "a"},
+			"tags":      []any{"tag"},
+			"options": map[string]any{
+				"duplicateScope":        "deck",
+				"duplicateScopeOptions": map[string]any{},
+			},
+		},
+		map[string]any{
+			"deckName":  "deck",
+			"modelName": "model",
+			"fields":    map[string]any{"Front": "b"},
+			"options": map[string]any{
+				"duplicateScope":        "deck",
+				"duplicateScopeOptions": map[string]any{},
+			},
+		},
+	}
+	testCases := []struct {
+		Name        string
+		Response    *fullResponse
+		Expected    []int64
+		ErrorAssert assert.ErrorAssertionFunc
+	}{
+		{
+			Name: "all added",
+			Response: &fullResponse{
+				Result: []any{1, 2},
+			},
+			Expected:    []int64{1, 2},
+			ErrorAssert: assert.NoError,
+		},
+		{
+			Name: "one failed",
+			Response: &fullResponse{
+				Result: []any{nil, 2},
+			},
+			Expected:    []int64{0, 2},
+			ErrorAssert: assert.NoError,
+		},
+		{
+			Name: "wrong length",
+			Response: &fullResponse{
+				Result: []any{1},
+			},
+			Expected: nil,
+			ErrorAssert: func(t assert.TestingT, err error, _ ...any) bool {
+				var responseErr *UnexpectedResponseError
+				return assert.ErrorAs(t, err, &responseErr)
+			},
+		},
+		{
+			Name: "error",
+			Response: &fullResponse{
+				Error: "myspecificerr",
+			},
+			Expected: nil,
+			ErrorAssert: func(t assert.TestingT, err error, _ ...any) bool {
+				return assert.ErrorContains(t, err, "myspecificerr")
+			},
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx, a := prepareMockServer(t,
+				handlerAssertRequest(t, &fullRequest{
+					Action: "addNotes",
+					Params: map[string]any{
+						"notes": expectedNotes,
+					},
+				}),
+				handlerRespondJSON(t, tc.Response),
+			)
+			ids, err := a.AddNotes(ctx, params, options)
+			tc.ErrorAssert(t, err)
+			assert.Equal(t, tc.Expected, ids)
+		})
+	}
+}
+
+func Test_Anki_CanAddNotes(t *testing.T) {
+	options := &AddNoteOptions{
+		Deck:           "deck",
+		Model:          "model",
+		DuplicateFlags: DuplicateFlagsCheck,
+	}
+	params := []*AddNoteParams{
+		{
+			Fields: map[string]string{"Front": "a"},
+		},
+		{
+			Fields: map[string]string{"Front": "b"},
+		},
+	}
+	testCases := []struct {
+		Name        string
+		Response    *fullResponse
+		Expected    []bool
+		ErrorAssert assert.ErrorAssertionFunc
+	}{
+		{
+			Name: "ok",
+			Response: &fullResponse{
+				Result: []bool{true, false},
+			},
+			Expected:    []bool{true, false},
+			ErrorAssert: assert.NoError,
+		},
+		{
+			Name: "wrong length",
+			Response: &fullResponse{
+				Result: []bool{true},
+			},
+			Expected: nil,
+			ErrorAssert: func(t assert.TestingT, err error, _ ...any) bool {
+				var responseErr *UnexpectedResponseError
+				return assert.ErrorAs(t, err, &responseErr)
+			},
+		},
+		{
+			Name: "error",
+			Response: &fullResponse{
+				Error: "myspecificerr",
+			},
+			Expected: nil,
+			ErrorAssert: func(t assert.TestingT, err error, _ ...any) bool {
+				return assert.ErrorContains(t, err, "myspecificerr")
+			},
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx, a := prepareMockServer(t,
+				handlerAssertRequest(t, &fullRequest{
+					Action: "canAddNotes",
+					Params: map[string]any{
+						"notes": []any{
+							map[string]any{
+								"deckName":  "deck",
+								"modelName": "model",
+								"fields":    map[string]any{"Front": "a"},
+								"options": map[string]any{
+									"duplicateScope":        "deck",
+									"duplicateScopeOptions": map[string]any{},
+								},
+							},
+							map[string]any{
+								"deckName":  "deck",
+								"modelName": "model",
+								"fields":    map[string]any{"Front": "b"},
+								"options": map[string]any{
+									"duplicateScope":        "deck",
+									"duplicateScopeOptions": map[string]any{},
+								},
+							},
+						},
+					},
+				}),
+				handlerRespondJSON(t, tc.Response),
+			)
+			result, err := a.CanAddNotes(ctx, params, options)
+			tc.ErrorAssert(t, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
@@ -19,6 +19,7 @@ type Config struct {
 	AudioPreferredType string
 
 	Mapping TemplateMapping
+	Tags    TagRules
 }
 
 func (c *Config) Equal(o any) bool {
@@ -42,7 +43,7 @@ func (c *Config) Equal(o any) bool {
 	if !scalarEq {
 		return false
 	}
-	return c.Mapping.Equal(oc.Mapping)
+	return c.Mapping.Equal(oc.Mapping) && c.Tags.Equal(oc.Tags)
 }
 
 func (c *Config) options() *ankiconnect.Options {
@@ -79,6 +80,19 @@ func NewConfigReloader(anki *Anki, configManager *config.Manager) (*ConfigReload
 // Config is implementation of config.Consumer interface.
 // It returns errors, but they are not supposed to be examined.
 func (cr *ConfigReloader) Config(uc *config.UserConfig) (config.Part, error) {
+	return NewConfig(uc)
+}
+
+var (
+	// ErrConfigDeckInvalid and ErrConfigNoteTypeInvalid are returned by NewConfig, so
+	// callers can tell what values of config can't be used.
+	ErrConfigDeckInvalid     = errors.New("anki config Deck validation failed")
+	ErrConfigNoteTypeInvalid = errors.New("anki config NoteType validation failed")
+)
+
+// NewConfig converts anki part of user config. Config is returned even if validation failed,
+// so it can be used for diagnostics.
+func NewConfig(uc *config.UserConfig) (*Config, error) {
 	conf := uc.Anki
 	var errs []error
 	err := validateAddr(conf.Addr)
@@ -87,11 +101,11 @@ func (cr *ConfigReloader) Config(uc *config.UserConfig) (config.Part, error) {
 	}
 	err = validateDeckName(conf.Deck)
 	if err != nil {
-		errs = append(errs, fmt.Errorf("anki config Deck validation failed: %w", err))
+		errs = append(errs, fmt.Errorf("%w: %w", ErrConfigDeckInvalid, err))
 	}
 	err = validateNoteType(conf.NoteType)
 	if err != nil {
-		errs = append(errs, fmt.Errorf("anki config NoteType validation failed: %w", err))
+		errs = append(errs, fmt.Errorf("%w: %w", ErrConfigNoteTypeInvalid, err))
 	}
 	mappingErrs := validateMappingKeys(conf.FieldMapping)
 	for _, mappingErr := range mappingErrs {
@@ -101,6 +115,13 @@ func (cr *ConfigReloader) Config(uc *config.UserConfig) (config.Part, error) {
 	for _, mappingErr := range mappingErrs {
 		errs = append(errs, mappingErr)
 	}
+	tags, staticErrs, ruleErrs := convertTagRules(conf.Tags.Static, conf.Tags.Rules)
+	for _, tagErr := range staticErrs {
+		errs = append(errs, fmt.Errorf("anki config Tags.Static validation failed: %w", tagErr))
+	}
+	for _, tagErr := range ruleErrs {
+		errs = append(errs, fmt.Errorf("anki config Tags.Rules validation failed: %w", tagErr))
+	}
 	// if empty, it is disabled, so we don't need to check
 	if conf.Audio.Field != "" {
 		err = validateFieldName(conf.Audio.Field)
@@ -117,6 +138,7 @@ func (cr *ConfigReloader) Config(uc *config.UserConfig) (config.Part, error) {
 		AudioField:         uc.Anki.Audio.Field,
 		AudioPreferredType: uc.Anki.Audio.PreferredType,
 		Mapping:            mapping,
+		Tags:               tags,
 	}, errors.Join(errs...)
 }
 
@@ -175,6 +197,21 @@ func (cr *ConfigReloader) UpdateMapping(mapping map[string]string) error {
 	})
 }
 
+func (cr *ConfigReloader) UpdateTags(static, rules []string) error {
+	_, staticErrs, ruleErrs := convertTagRules(static, rules)
+	if len(staticErrs) != 0 || len(ruleErrs) != 0 {
+		return &TagsValidationErrors{
+			StaticErrors: staticErrs,
+			RuleErrors:   ruleErrs,
+		}
+	}
+	return cr.updateConfigFn(func(uc *config.UserConfig) error {
+		uc.Anki.Tags.Static = static
+		uc.Anki.Tags.Rules = rules
+		return nil
+	})
+}
+
 func (cr *ConfigReloader) UpdateAudioField(field string) error {
 	if field != "" {
 		if err := validateFieldName(field); err != nil {
@@ -99,6 +99,20 @@ func Test_Config_Equal(t *testing.T) {
 			},
 			Expected: false,
 		},
+		{
+			Name: "neq Tags",
+			First: &Config{
+				Tags: TagRules{
+					Static: []string{"a"},
+				},
+			},
+			Second: &Config{
+				Tags: TagRules{
+					Static: []string{"b"},
+				},
+			},
+			Expected: false,
+		},
 		{
 			Name: "mapping eq nonempty",
 			First: &Config{
@@ -267,6 +281,10 @@ func Test_ConfigReloader_Config(t *testing.T) {
 						Field:         "myaudiofield",
 						PreferredType: "mypreferredtype",
 					},
+					Tags: config.AnkiTags{
+						Static: []string{"mytag"},
+						Rules:  []string{"{{ .Slug.Word }}"},
+					},
 				},
 			},
 			Expected: &Config{
@@ -281,6 +299,12 @@ func Test_ConfigReloader_Config(t *testing.T) {
 						Src: "mymapping",
 					},
 				},
+				Tags: TagRules{
+					Static: []string{"mytag"},
+					Rules: []*Template{
+						{Src: "{{ .Slug.Word }}"},
+					},
+				},
 			},
 			ErrorAssert: assert.NoError,
 		},
@@ -304,7 +328,9 @@ func Test_ConfigReloader_Config(t *testing.T) {
 					NoteType: "testnote",
 				},
 			},
-			ErrorAssert: assert.Error,
+			ErrorAssert: func(t assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(t, err, ErrConfigDeckInvalid, msgAndArgs...)
+			},
 		},
 		{
 			Name: "invalid note type",
@@ -315,7 +341,9 @@ func Test_ConfigReloader_Config(t *testing.T) {
 					NoteType: "test\"note",
 				},
 			},
-			ErrorAssert: assert.Error,
+			ErrorAssert: func(t assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(t, err, ErrConfigNoteTypeInvalid, msgAndArgs...)
+			},
 		},
 		{
 			Name: "invalid audio field",
@@ -344,6 +372,34 @@ func Test_ConfigReloader_Config(t *testing.T) {
 			},
 			ErrorAssert: assert.Error,
 		},
+		{
+			Name: "invalid static tag",
+			UserConfig: &config.UserConfig{
+				Anki: config.Anki{
+					Addr:     "testaddr:3030",
+					Deck:     "testdeck",
+					NoteType: "testnote",
+					Tags: config.AnkiTags{
+						Static: []string{"my tag"},
+					},
+				},
+			},
+			ErrorAssert: assert.Error,
+		},
+		{
+			Name: "invalid tag rule",
+			UserConfig: &config.UserConfig{
+				Anki: config.Anki{
+					Addr:     "testaddr:3030",
+					Deck:     "testdeck",
+					NoteType: "testnote",
+					Tags: config.AnkiTags{
+						Rules: []string{"{{ .NotExists }}"},
+					},
+				},
+			},
+			ErrorAssert: assert.Error,
+		},
 		{
 			Name: "invalid mapping field value",
 			UserConfig: &config.UserConfig{
@@ -377,6 +433,9 @@ func Test_ConfigReloader_Config(t *testing.T) {
 						template.Tmpl = nil
 					}
 				}
+				for _, template := range actual.Tags.Rules {
+					template.Tmpl = nil
+				}
 				assert.Equal(t, tc.Expected, actual)
 			}
 		})
@@ -620,6 +679,62 @@ func Test_ConfigReloader_UpdateMapping(t *testing.T) {
 	}
 }
 
+func Test_ConfigReloader_UpdateTags(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		Static      []string
+		Rules       []string
+		ErrorAssert assert.ErrorAssertionFunc
+	}{
+		{
+			Name:        "ok",
+			Static:      []string{"japwords"},
+			Rules:       []string{"{{ .Slug.Word }}"},
+			ErrorAssert: assert.NoError,
+		},
+		{
+			Name:   "invalid static tag",
+			Static: []string{"japwords", "my tag"},
+			ErrorAssert: func(tt assert.TestingT, err error, i ...interface{}) bool {
+				var tagsValidationErrors *TagsValidationErrors
+				if !assert.ErrorAs(tt, err, &tagsValidationErrors, i...) {
+					return false
+				}
+				return !assert.Len(tt, tagsValidationErrors.StaticErrors, 1) ||
+					!assert.Len(tt, tagsValidationErrors.RuleErrors, 0)
+			},
+		},
+		{
+			Name:  "invalid rule",
+			Rules: []string{"{{ .NotExists }}"},
+			ErrorAssert: func(tt assert.TestingT, err error, i ...interface{}) bool {
+				var tagsValidationErrors *TagsValidationErrors
+				if !assert.ErrorAs(tt, err, &tagsValidationErrors, i...) {
+					return false
+				}
+				return !assert.Len(tt, tagsValidationErrors.StaticErrors, 0) ||
+					!assert.Len(tt, tagsValidationErrors.RuleErrors, 1)
+			},
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			configReloader, anki, initialConfig := NewTestReloader(t)
+			err := configReloader.UpdateTags(tc.Static, tc.Rules)
+			tc.ErrorAssert(t, err)
+			if err != nil {
+				return
+			}
+			expectedTags := mustConvertTagRules(t, tc.Static, tc.Rules)
+			// we will check it seperately
+			initialConfig.Tags = anki.client.Config().Tags
+			assert.Equal(t, initialConfig, anki.client.Config())
+			assert.True(t, expectedTags.Equal(anki.client.Config().Tags))
+		})
+	}
+}
+
 func Test_ConfigReloader_UpdateAudioField(t *testing.T) {
 	testCases := []struct {
 		Name        string
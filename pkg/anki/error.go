@@ -13,11 +13,16 @@ var (
 	ErrInvalidAPIKey         = errors.New("anki-connect rejected request because api key is invalid")
 	ErrCollectionUnavailable = errors.New("anki-connect is not ready for specified action")
 
-	ErrNoteTypeNotExists       = errors.New("selected note type doesn't exists")
-	ErrDeckAlreadyExists       = errors.New("deck with the same name already exists")
-	ErrNoteTypeAlreadyExists   = errors.New("note type with the same name already exists")
-	ErrDuplicatedNoteFound     = errors.New("failed to add note, because the same note already exists")
+	ErrNoteTypeNotExists     = errors.New("selected note type doesn't exists")
+	ErrDeckAlreadyExists     = errors.New("deck with the same name already exists")
+	ErrNoteTypeAlreadyExists = errors.New("note type with the same name already exists")
+	ErrDuplicatedNoteFound   = errors.New("failed to add note, because the same note already exists")
+	ErrNoteCreationFailed    = errors.New("anki-connect didn't create note")
+	// ErrEmptyOrderField is returned for note without the first field, anki-connect doesn't add such notes
+	ErrEmptyOrderField         = fmt.Errorf("%w, because its first field is empty", ErrNoteCreationFailed)
 	ErrIncompleteConfiguration = errors.New("configuration is incomplete")
+	ErrAudioUnavailable        = errors.New("failed to download audio for note")
+	ErrNoteNotFound            = errors.New("note not found")
 
 	// ErrUnknownServerError unrecognized error from anki-connect, but probably should
 	ErrUnknownServerError = errors.New("anki-connect returned unknown error")
@@ -91,6 +91,45 @@ var DefaultExampleLemma = lemma.ProjectedLemma{
 		{MediaType: "audio/mpeg", Source: "https://example.com/somelink/mp3"},
 		{MediaType: "audio/ogg", Source: "https://example.com/somelink/ogg"},
 	},
+	Examples: []lemma.Example{
+		{
+			Japanese: "一二わ三はいと言った。",
+			English:  "He said one, two, three.",
+			Reading:  "一二[いちに]わ三[さん]はいと 言[い]った。",
+		},
+	},
+	Kanji: []lemma.Kanji{
+		{
+			Character:   "一",
+			StrokeCount: 1,
+			Grade:       1,
+			JLPT:        4,
+			Meanings:    []string{"one"},
+			OnReadings:  []string{"イチ", "イツ"},
+			KunReadings: []string{"ひと-", "ひと.つ"},
+			Radicals:    []lemma.KanjiRadical{{Number: 1, Character: "⼀", Type: "classical"}},
+		},
+		{
+			Character:   "二",
+			StrokeCount: 2,
+			Grade:       1,
+			JLPT:        4,
+			Meanings:    []string{"two"},
+			OnReadings:  []string{"ニ", "ジ"},
+			KunReadings: []string{"ふた", "ふた.つ"},
+			Radicals:    []lemma.KanjiRadical{{Number: 7, Character: "⼆", Type: "classical"}},
+		},
+		{
+			Character:   "三",
+			StrokeCount: 3,
+			Grade:       1,
+			JLPT:        4,
+			Meanings:    []string{"three"},
+			OnReadings:  []string{"サン", "ゾウ"},
+			KunReadings: []string{"み", "み.つ", "みっ.つ"},
+			Radicals:    []lemma.KanjiRadical{{Number: 1, Character: "⼀", Type: "classical"}},
+		},
+	},
 }
 
 var GetDefaultExampleLemmaJSON = sync.OnceValue(func() string {
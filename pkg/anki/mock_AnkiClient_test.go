@@ -39,6 +39,58 @@ func (_m *MockAnkiClient) AddNote(ctx context.Context, params *ankiconnect.AddNo
 	return r0, r1
 }
 
+// AddNotes provides a mock function with given fields: ctx, params, opts
+func (_m *MockAnkiClient) AddNotes(ctx context.Context, params []*ankiconnect.AddNoteParams, opts *ankiconnect.AddNoteOptions) ([]int64, error) {
+	ret := _m.Called(ctx, params, opts)
+
+	var r0 []int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*ankiconnect.AddNoteParams, *ankiconnect.AddNoteOptions) ([]int64, error)); ok {
+		return rf(ctx, params, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []*ankiconnect.AddNoteParams, *ankiconnect.AddNoteOptions) []int64); ok {
+		r0 = rf(ctx, params, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []*ankiconnect.AddNoteParams, *ankiconnect.AddNoteOptions) error); ok {
+		r1 = rf(ctx, params, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CanAddNotes provides a mock function with given fields: ctx, params, opts
+func (_m *MockAnkiClient) CanAddNotes(ctx context.Context, params []*ankiconnect.AddNoteParams, opts *ankiconnect.AddNoteOptions) ([]bool, error) {
+	ret := _m.Called(ctx, params, opts)
+
+	var r0 []bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*ankiconnect.AddNoteParams, *ankiconnect.AddNoteOptions) ([]bool, error)); ok {
+		return rf(ctx, params, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []*ankiconnect.AddNoteParams, *ankiconnect.AddNoteOptions) []bool); ok {
+		r0 = rf(ctx, params, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]bool)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []*ankiconnect.AddNoteParams, *ankiconnect.AddNoteOptions) error); ok {
+		r1 = rf(ctx, params, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateDeck provides a mock function with given fields: ctx, name
 func (_m *MockAnkiClient) CreateDeck(ctx context.Context, name string) (int64, error) {
 	ret := _m.Called(ctx, name)
@@ -243,6 +295,58 @@ func (_m *MockAnkiClient) RequestPermission(ctx context.Context) (*ankiconnect.R
 	return r0, r1
 }
 
+// StoreMediaFile provides a mock function with given fields: ctx, asset
+func (_m *MockAnkiClient) StoreMediaFile(ctx context.Context, asset *ankiconnect.MediaAssetRequest) (string, error) {
+	ret := _m.Called(ctx, asset)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ankiconnect.MediaAssetRequest) (string, error)); ok {
+		return rf(ctx, asset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *ankiconnect.MediaAssetRequest) string); ok {
+		r0 = rf(ctx, asset)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *ankiconnect.MediaAssetRequest) error); ok {
+		r1 = rf(ctx, asset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateNoteFields provides a mock function with given fields: ctx, params
+func (_m *MockAnkiClient) UpdateNoteFields(ctx context.Context, params *ankiconnect.UpdateNoteFieldsParams) error {
+	ret := _m.Called(ctx, params)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ankiconnect.UpdateNoteFieldsParams) error); ok {
+		r0 = rf(ctx, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateNoteTags provides a mock function with given fields: ctx, id, tags
+func (_m *MockAnkiClient) UpdateNoteTags(ctx context.Context, id int64, tags []string) error {
+	ret := _m.Called(ctx, id, tags)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []string) error); ok {
+		r0 = rf(ctx, id, tags)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewMockAnkiClient interface {
 	mock.TestingT
 	Cleanup(func())
@@ -39,6 +39,32 @@ func (_m *MockStatefullClient) AddNote(ctx context.Context, note *AddNoteRequest
 	return r0, r1
 }
 
+// AddNotes provides a mock function with given fields: ctx, notes
+func (_m *MockStatefullClient) AddNotes(ctx context.Context, notes []*AddNoteRequest) ([]AddNoteResult, error) {
+	ret := _m.Called(ctx, notes)
+
+	var r0 []AddNoteResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*AddNoteRequest) ([]AddNoteResult, error)); ok {
+		return rf(ctx, notes)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []*AddNoteRequest) []AddNoteResult); ok {
+		r0 = rf(ctx, notes)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]AddNoteResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []*AddNoteRequest) error); ok {
+		r1 = rf(ctx, notes)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Config provides a mock function with given fields:
 func (_m *MockStatefullClient) Config() *Config {
 	ret := _m.Called()
@@ -135,11 +161,30 @@ func (_m *MockStatefullClient) QueryNotes(ctx context.Context, query string) ([]
 	return r0, r1
 }
 
+// SetConnectedHandler provides a mock function with given fields: handler
+func (_m *MockStatefullClient) SetConnectedHandler(handler func()) {
+	_m.Called(handler)
+}
+
 // Stop provides a mock function with given fields:
 func (_m *MockStatefullClient) Stop() {
 	_m.Called()
 }
 
+// UpdateNote provides a mock function with given fields: ctx, note
+func (_m *MockStatefullClient) UpdateNote(ctx context.Context, note *UpdateNoteRequest) error {
+	ret := _m.Called(ctx, note)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *UpdateNoteRequest) error); ok {
+		r0 = rf(ctx, note)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewMockStatefullClient interface {
 	mock.TestingT
 	Cleanup(func())
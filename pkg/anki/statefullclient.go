@@ -26,6 +26,11 @@ type AnkiClient interface {
 	CreateDeck(ctx context.Context, name string) (int64, error)
 	CreateModel(ctx context.Context, parameters *ankiconnect.CreateModelRequest) (int64, error)
 	AddNote(ctx context.Context, params *ankiconnect.AddNoteParams, opts *ankiconnect.AddNoteOptions) (int64, error)
+	AddNotes(ctx context.Context, params []*ankiconnect.AddNoteParams, opts *ankiconnect.AddNoteOptions) ([]int64, error)
+	CanAddNotes(ctx context.Context, params []*ankiconnect.AddNoteParams, opts *ankiconnect.AddNoteOptions) ([]bool, error)
+	UpdateNoteFields(ctx context.Context, params *ankiconnect.UpdateNoteFieldsParams) error
+	UpdateNoteTags(ctx context.Context, id int64, tags []string) error
+	StoreMediaFile(ctx context.Context, asset *ankiconnect.MediaAssetRequest) (string, error)
 }
 
 type AnkiState struct {
@@ -115,6 +120,8 @@ type statefullClient struct {
 	mu     sync.Mutex
 	client AnkiClient
 	state  *State
+	// connectedHandler is called when state check succeeds after failed one or state becomes ready to add notes
+	connectedHandler func()
 
 	// after is for testing only, in production it is time.After
 	after func(time.Duration) <-chan time.Time
@@ -165,7 +172,7 @@ func (sc *statefullClient) run() {
 			newState := sc.getNewState(ctx)
 			cancel()
 			sleepTimeout = newState.nextUpdateTimeout()
-			sc.state = newState
+			sc.setState(newState)
 			sc.mu.Unlock()
 		}
 	}
@@ -189,15 +196,40 @@ func (sc *statefullClient) withClient(fn func(client AnkiClient, config *Config,
 	}
 	// update new state if get one from error or not
 	if newState != nil {
-		sc.state = newState
+		sc.setState(newState)
 	}
 	return err
 }
 
+// setState replaces current state and calls connected handler if Anki became available:
+// state check succeeded after failed one or state became ready to add notes (e.g. deck was created).
+// Must be called with sc.mu held.
+func (sc *statefullClient) setState(newState *State) {
+	oldState := sc.state
+	connected := oldState.LastError != nil && newState.LastError == nil
+	becameReady := !oldState.IsReadyToAddNote() && newState.IsReadyToAddNote()
+	sc.state = newState
+	if (connected || becameReady) && sc.connectedHandler != nil {
+		go sc.connectedHandler()
+	}
+}
+
 func (sc *statefullClient) Config() *Config {
 	return sc.config
 }
 
+// SetConnectedHandler sets handler that is called in its own goroutine every time Anki becomes
+// available: immediately if it's available now, when state check succeeds after failed one and
+// when state becomes ready to add notes.
+func (sc *statefullClient) SetConnectedHandler(handler func()) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.connectedHandler = handler
+	if handler != nil && sc.state != nil && sc.state.LastError == nil {
+		go handler()
+	}
+}
+
 func (sc *statefullClient) Stop() {
 	sc.exitContextCancel()
 	<-sc.exited
@@ -317,32 +349,19 @@ func (sc *statefullClient) AddNote(ctx context.Context, note *AddNoteRequest) (i
 			return nil, ErrIncompleteConfiguration
 		}
 		// NOTE: we could assert request on known state, but why would we?
-		fields := make(map[string]string, len(note.Fields))
-		for i := range note.Fields {
-			fields[note.Fields[i].Name] = note.Fields[i].Value
-		}
-		assets, err := convertAddNoteAudioAssets(note.AudioAssets)
+		params, err := newAddNoteParams(note)
 		if err != nil {
 			return nil, err
 		}
-
-		noteID, err = client.AddNote(ctx,
-			&ankiconnect.AddNoteParams{
-				Fields: fields,
-				Assets: assets,
-				// TODO:
-				// Tags:   note.Tags,
-			},
-			&ankiconnect.AddNoteOptions{
-				Deck:           config.Deck,
-				Model:          config.NoteType,
-				DuplicateScope: ankiconnect.DuplicateScopeDeck,
-				DuplicateFlags: ankiconnect.DuplicateFlagsCheck,
-			},
-		)
+		noteID, err = client.AddNote(ctx, params, newAddNoteOptions(config))
 		var serverError *ankiconnect.ServerError
-		if errors.As(err, &serverError) && serverError.Message == "cannot create note because it is a duplicate" {
-			return nil, ErrDuplicatedNoteFound
+		if errors.As(err, &serverError) {
+			switch serverError.Message {
+			case "cannot create note because it is a duplicate":
+				return nil, ErrDuplicatedNoteFound
+			case "cannot create note because it is empty":
+				return nil, ErrEmptyOrderField
+			}
 		}
 		return nil, err
 	})
@@ -352,6 +371,96 @@ func (sc *statefullClient) AddNote(ctx context.Context, note *AddNoteRequest) (i
 	return noteID, nil
 }
 
+// AddNotes adds notes with single addNotes request. Notes are checked with canAddNotes first,
+// notes with empty order field get ErrEmptyOrderField, other notes that can't be added and notes with
+// the same order field as previous note in the batch get ErrDuplicatedNoteFound, these notes are not sent.
+// Returned error means that no note was added.
+func (sc *statefullClient) AddNotes(ctx context.Context, notes []*AddNoteRequest) ([]AddNoteResult, error) {
+	results := make([]AddNoteResult, len(notes))
+	err := sc.withClient(func(client AnkiClient, config *Config, state *State) (*State, error) {
+		if !state.IsReadyToAddNote() {
+			return nil, ErrIncompleteConfiguration
+		}
+		params := make([]*ankiconnect.AddNoteParams, len(notes))
+		for i, note := range notes {
+			var err error
+			params[i], err = newAddNoteParams(note)
+			if err != nil {
+				return nil, err
+			}
+		}
+		opts := newAddNoteOptions(config)
+		canAdd, err := client.CanAddNotes(ctx, params, opts)
+		if err != nil {
+			return nil, err
+		}
+		// anki-connect checks duplicates only in collection, so we need to check them inside batch
+		orderField := state.CurrentFields[0]
+		orderValues := map[string]struct{}{}
+		var toAdd []*ankiconnect.AddNoteParams
+		var indexes []int
+		for i := range params {
+			orderValue := params[i].Fields[orderField]
+			// anki-connect can't add note with empty first field too, but it's not duplicate
+			if strings.TrimSpace(orderValue) == "" {
+				results[i].Err = ErrEmptyOrderField
+				continue
+			}
+			if _, ok := orderValues[orderValue]; ok || !canAdd[i] {
+				results[i].Err = ErrDuplicatedNoteFound
+				continue
+			}
+			orderValues[orderValue] = struct{}{}
+			toAdd = append(toAdd, params[i])
+			indexes = append(indexes, i)
+		}
+		if len(toAdd) == 0 {
+			return nil, nil
+		}
+		ids, err := client.AddNotes(ctx, toAdd, opts)
+		if err != nil {
+			return nil, err
+		}
+		for j, id := range ids {
+			if id == 0 {
+				results[indexes[j]].Err = ErrNoteCreationFailed
+			} else {
+				results[indexes[j]].NoteID = NoteID(id)
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func newAddNoteParams(note *AddNoteRequest) (*ankiconnect.AddNoteParams, error) {
+	fields := make(map[string]string, len(note.Fields))
+	for i := range note.Fields {
+		fields[note.Fields[i].Name] = note.Fields[i].Value
+	}
+	assets, err := convertAddNoteAudioAssets(note.AudioAssets)
+	if err != nil {
+		return nil, err
+	}
+	return &ankiconnect.AddNoteParams{
+		Fields: fields,
+		Assets: assets,
+		Tags:   note.Tags,
+	}, nil
+}
+
+func newAddNoteOptions(config *Config) *ankiconnect.AddNoteOptions {
+	return &ankiconnect.AddNoteOptions{
+		Deck:           config.Deck,
+		Model:          config.NoteType,
+		DuplicateScope: ankiconnect.DuplicateScopeDeck,
+		DuplicateFlags: ankiconnect.DuplicateFlagsCheck,
+	}
+}
+
 func convertAddNoteAudioAssets(noteAssets []AddNoteAudioAsset) ([]*ankiconnect.AddNoteAsset, error) {
 	var assets []*ankiconnect.AddNoteAsset
 	for _, asset := range noteAssets {
@@ -407,3 +516,41 @@ func (sc *statefullClient) QueryNotes(ctx context.Context, query string) ([]*ank
 	}
 	return notes, nil
 }
+
+// UpdateNote stores audio assets in media collection (replacing files with the same name),
+// then changes fields and tags of existing note.
+func (sc *statefullClient) UpdateNote(ctx context.Context, note *UpdateNoteRequest) error {
+	return sc.withClient(func(client AnkiClient, _ *Config, _ *State) (*State, error) {
+		for _, asset := range note.AudioAssets {
+			_, err := client.StoreMediaFile(ctx, &ankiconnect.MediaAssetRequest{
+				Filename:       asset.Filename,
+				Data:           asset.Data,
+				URL:            asset.URL,
+				DeleteExisting: true,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		if len(note.Fields) != 0 {
+			fields := make(map[string]string, len(note.Fields))
+			for i := range note.Fields {
+				fields[note.Fields[i].Name] = note.Fields[i].Value
+			}
+			err := client.UpdateNoteFields(ctx, &ankiconnect.UpdateNoteFieldsParams{
+				ID:     int64(note.NoteID),
+				Fields: fields,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		if note.Tags != nil {
+			err := client.UpdateNoteTags(ctx, int64(note.NoteID), note.Tags)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+}
@@ -550,6 +550,127 @@ func Test_statefullClient_run(t *testing.T) {
 	})
 }
 
+func Test_statefullClient_SetConnectedHandler(t *testing.T) {
+	okState := func(client *MockAnkiClient) {
+		client.On("RequestPermission", mock.Anything).
+			Return(&ankiconnect.RequestPermissionResponse{
+				Permission: "granted",
+			}, nil).Once()
+		client.On("DeckNames", mock.Anything).
+			Return(nil, nil).
+			Once()
+		client.On("ModelNames", mock.Anything).
+			Return(nil, nil).
+			Once()
+		client.On("ModelFieldNames", mock.Anything, mock.Anything).
+			Return(nil, nil).
+			Once()
+	}
+	errorState := func(client *MockAnkiClient) {
+		client.On("RequestPermission", mock.Anything).
+			Return(nil, errors.New("unavailable")).Once()
+	}
+	newHandler := func() (func(), chan struct{}) {
+		calls := make(chan struct{}, 10)
+		return func() { calls <- struct{}{} }, calls
+	}
+	waitCall := func(t *testing.T, calls chan struct{}) {
+		select {
+		case <-calls:
+		case <-time.After(time.Second):
+			t.Fatal("handler was not called")
+		}
+	}
+	t.Run("called immediately if available", func(t *testing.T) {
+		client, _, _ := newTestStatefullClient(t, &Config{}, okState)
+		defer client.Stop()
+		handler, calls := newHandler()
+		client.SetConnectedHandler(handler)
+		waitCall(t, calls)
+	})
+	t.Run("called after recovery", func(t *testing.T) {
+		client, _, updateCh := newTestStatefullClient(t, &Config{}, func(client *MockAnkiClient) {
+			errorState(client)
+			errorState(client)
+			okState(client)
+			okState(client)
+		})
+		handler, calls := newHandler()
+		client.SetConnectedHandler(handler)
+		// still unavailable
+		updateCh <- time.Now()
+		// recovered
+		updateCh <- time.Now()
+		// still available, handler should not be called again
+		updateCh <- time.Now()
+		client.Stop()
+		waitCall(t, calls)
+		assert.Empty(t, calls)
+	})
+	readyConfig := &Config{
+		NoteType: "note1",
+		Deck:     "deck1",
+		Mapping: TemplateMapping{
+			"field1": {},
+		},
+	}
+	stateWithDecks := func(decks ...string) func(client *MockAnkiClient) {
+		return func(client *MockAnkiClient) {
+			client.On("RequestPermission", mock.Anything).
+				Return(&ankiconnect.RequestPermissionResponse{
+					Permission: "granted",
+				}, nil).Once()
+			client.On("DeckNames", mock.Anything).
+				Return(decks, nil).
+				Once()
+			client.On("ModelNames", mock.Anything).
+				Return([]string{"note1"}, nil).
+				Once()
+			client.On("ModelFieldNames", mock.Anything, "note1").
+				Return([]string{"field1"}, nil).
+				Once()
+		}
+	}
+	t.Run("called when becomes ready", func(t *testing.T) {
+		client, _, updateCh := newTestStatefullClient(t, readyConfig, func(client *MockAnkiClient) {
+			stateWithDecks()(client)
+			stateWithDecks()(client)
+			stateWithDecks("deck1")(client)
+			stateWithDecks("deck1")(client)
+		})
+		handler, calls := newHandler()
+		client.SetConnectedHandler(handler)
+		// called immediately, because Anki is available
+		waitCall(t, calls)
+		// still not ready
+		updateCh <- time.Now()
+		// deck appeared
+		updateCh <- time.Now()
+		// still ready, handler should not be called again
+		updateCh <- time.Now()
+		client.Stop()
+		waitCall(t, calls)
+		assert.Empty(t, calls)
+	})
+	t.Run("called when deck is created", func(t *testing.T) {
+		client, ankiClient, _ := newTestStatefullClient(t, readyConfig, stateWithDecks())
+		handler, calls := newHandler()
+		client.SetConnectedHandler(handler)
+		waitCall(t, calls)
+		ankiClient.On("CreateDeck", mock.Anything, "deck1").
+			Return(int64(1), nil).
+			Once()
+		ankiClient.On("DeckNames", mock.Anything).
+			Return([]string{"deck1"}, nil).
+			Once()
+		err := client.CreateDeck(context.Background(), "deck1")
+		client.Stop()
+		require.NoError(t, err)
+		waitCall(t, calls)
+		assert.Empty(t, calls)
+	})
+}
+
 func Test_statefullClient_Config(t *testing.T) {
 	expectedConfig := &Config{
 		Addr:     "testaddr",
@@ -970,6 +1091,20 @@ func Test_statefullClient_AddNote(t *testing.T) {
 		assert.Equal(t, int64(0), noteID)
 		assert.ErrorIs(t, err, ErrDuplicatedNoteFound)
 	})
+	t.Run("empty note", func(t *testing.T) {
+		client, ankiClient, _ := newTestNormalStatefullClient(t, readyConfig)
+		ankiClient.On("AddNote", mock.Anything, mock.Anything, mock.Anything).
+			Return(
+				int64(0),
+				&ankiconnect.ServerError{
+					Message: "cannot create note because it is empty",
+				}).
+			Once()
+		noteID, err := client.AddNote(context.Background(), &AddNoteRequest{})
+		assert.Equal(t, int64(0), noteID)
+		assert.ErrorIs(t, err, ErrEmptyOrderField)
+		assert.NotErrorIs(t, err, ErrDuplicatedNoteFound)
+	})
 	t.Run("anki error", func(t *testing.T) {
 		client, ankiClient, _ := newTestNormalStatefullClient(t, readyConfig)
 		ankiClient.On("AddNote", mock.Anything, mock.Anything, mock.Anything).
@@ -1002,6 +1137,7 @@ func Test_statefullClient_AddNote(t *testing.T) {
 					"a": "avalue",
 					"b": "bvalue",
 				},
+				Tags: []string{"japwords", "JLPT::N5"},
 				Assets: []*ankiconnect.AddNoteAsset{
 					{
 						Asset: ankiconnect.MediaAssetRequest{
@@ -1036,6 +1172,7 @@ func Test_statefullClient_AddNote(t *testing.T) {
 					Value: "bvalue",
 				},
 			},
+			Tags: []string{"japwords", "JLPT::N5"},
 			AudioAssets: []AddNoteAudioAsset{
 				{
 					Field:    "audiofield",
@@ -1049,6 +1186,126 @@ func Test_statefullClient_AddNote(t *testing.T) {
 	})
 }
 
+func Test_statefullClient_AddNotes(t *testing.T) {
+	readyConfig := &Config{
+		NoteType: "note1",
+		Deck:     "deck1",
+		Mapping: TemplateMapping{
+			"field1": {},
+		},
+	}
+	notes := []*AddNoteRequest{
+		{
+			Fields: []AddNoteField{{Name: "field1", Value: "a"}},
+		},
+		{
+			Fields: []AddNoteField{{Name: "field1", Value: "b"}},
+		},
+		{
+			Fields: []AddNoteField{{Name: "field1", Value: "a"}},
+		},
+		{
+			Fields: []AddNoteField{{Name: "field1", Value: "c"}},
+		},
+	}
+	params := func(values ...string) []*ankiconnect.AddNoteParams {
+		result := make([]*ankiconnect.AddNoteParams, len(values))
+		for i, value := range values {
+			result[i] = &ankiconnect.AddNoteParams{
+				Fields: map[string]string{"field1": value},
+			}
+		}
+		return result
+	}
+	options := &ankiconnect.AddNoteOptions{
+		Deck:           "deck1",
+		Model:          "note1",
+		DuplicateScope: ankiconnect.DuplicateScopeDeck,
+		DuplicateFlags: ankiconnect.DuplicateFlagsCheck,
+	}
+	t.Run("note type not exists", func(t *testing.T) {
+		client, _, _ := newTestNormalStatefullClient(t, &Config{
+			NoteType: "noexists",
+		})
+		results, err := client.AddNotes(context.Background(), notes)
+		assert.Nil(t, results)
+		assert.ErrorIs(t, err, ErrIncompleteConfiguration)
+	})
+	t.Run("can add notes error", func(t *testing.T) {
+		client, ankiClient, _ := newTestNormalStatefullClient(t, readyConfig)
+		ankiClient.On("CanAddNotes", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, &ankiconnect.ServerError{Err: ankiconnect.ErrCollectionUnavailable}).
+			Once()
+		results, err := client.AddNotes(context.Background(), notes)
+		assert.Nil(t, results)
+		assert.ErrorIs(t, err, ErrCollectionUnavailable)
+	})
+	t.Run("all duplicates", func(t *testing.T) {
+		client, ankiClient, _ := newTestNormalStatefullClient(t, readyConfig)
+		ankiClient.On("CanAddNotes", mock.Anything, params("a", "b", "a", "c"), options).
+			Return([]bool{false, false, false, false}, nil).
+			Once()
+		results, err := client.AddNotes(context.Background(), notes)
+		require.NoError(t, err)
+		assert.Equal(t, []AddNoteResult{
+			{Err: ErrDuplicatedNoteFound},
+			{Err: ErrDuplicatedNoteFound},
+			{Err: ErrDuplicatedNoteFound},
+			{Err: ErrDuplicatedNoteFound},
+		}, results)
+	})
+	t.Run("ok", func(t *testing.T) {
+		client, ankiClient, _ := newTestNormalStatefullClient(t, readyConfig)
+		ankiClient.On("CanAddNotes", mock.Anything, params("a", "b", "a", "c"), options).
+			Return([]bool{true, false, true, true}, nil).
+			Once()
+		ankiClient.On("AddNotes", mock.Anything, params("a", "c"), options).
+			Return([]int64{10, 0}, nil).
+			Once()
+		results, err := client.AddNotes(context.Background(), notes)
+		require.NoError(t, err)
+		assert.Equal(t, []AddNoteResult{
+			{NoteID: 10},
+			{Err: ErrDuplicatedNoteFound},
+			{Err: ErrDuplicatedNoteFound},
+			{Err: ErrNoteCreationFailed},
+		}, results)
+	})
+	t.Run("empty order field", func(t *testing.T) {
+		client, ankiClient, _ := newTestNormalStatefullClient(t, readyConfig)
+		ankiClient.On("CanAddNotes", mock.Anything, params("a", " ", "b"), options).
+			Return([]bool{true, false, false}, nil).
+			Once()
+		ankiClient.On("AddNotes", mock.Anything, params("a"), options).
+			Return([]int64{10}, nil).
+			Once()
+		results, err := client.AddNotes(context.Background(), []*AddNoteRequest{
+			{Fields: []AddNoteField{{Name: "field1", Value: "a"}}},
+			{Fields: []AddNoteField{{Name: "field1", Value: " "}}},
+			{Fields: []AddNoteField{{Name: "field1", Value: "b"}}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []AddNoteResult{
+			{NoteID: 10},
+			{Err: ErrEmptyOrderField},
+			{Err: ErrDuplicatedNoteFound},
+		}, results)
+		assert.ErrorIs(t, results[1].Err, ErrNoteCreationFailed)
+	})
+	t.Run("add notes error", func(t *testing.T) {
+		client, ankiClient, _ := newTestNormalStatefullClient(t, readyConfig)
+		ankiClient.On("CanAddNotes", mock.Anything, mock.Anything, mock.Anything).
+			Return([]bool{true, true, true, true}, nil).
+			Once()
+		ankiClient.On("AddNotes", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, &ankiconnect.ServerError{Err: ankiconnect.ErrCollectionUnavailable}).
+			Once()
+		results, err := client.AddNotes(context.Background(), notes)
+		assert.Nil(t, results)
+		assert.ErrorIs(t, err, ErrCollectionUnavailable)
+	})
+}
+
 func Test_convertAddNoteAudioAssets(t *testing.T) {
 	testCases := []struct {
 		Name        string
@@ -1245,3 +1502,89 @@ func Test_statefullClient_QueryNote(t *testing.T) {
 		assert.Equal(t, notesExpected, notesActual)
 	})
 }
+
+func Test_statefullClient_UpdateNote(t *testing.T) {
+	readyConfig := &Config{
+		NoteType: "note1",
+		Deck:     "deck1",
+		Mapping: TemplateMapping{
+			"field1": {},
+		},
+	}
+	t.Run("error state", func(t *testing.T) {
+		client, _, _ := newTestErrorStatefullClient(t, &Config{})
+		err := client.UpdateNote(context.Background(), &UpdateNoteRequest{NoteID: 1})
+		assert.ErrorIs(t, err, ErrForbiddenOrigin)
+	})
+	t.Run("store media error", func(t *testing.T) {
+		client, ankiClient, _ := newTestNormalStatefullClient(t, readyConfig)
+		ankiClient.On("StoreMediaFile", mock.Anything, mock.Anything).
+			Return("", &ankiconnect.ServerError{Err: ankiconnect.ErrCollectionUnavailable}).
+			Once()
+		err := client.UpdateNote(context.Background(), &UpdateNoteRequest{
+			NoteID: 1,
+			Fields: []AddNoteField{
+				{Name: "audio", Value: "[sound:a.mp3]"},
+			},
+			AudioAssets: []AddNoteAudioAsset{
+				{Field: "audio", Filename: "a.mp3", URL: "http://a"},
+			},
+		})
+		assert.ErrorIs(t, err, ErrCollectionUnavailable)
+	})
+	t.Run("fields only", func(t *testing.T) {
+		client, ankiClient, _ := newTestNormalStatefullClient(t, readyConfig)
+		ankiClient.On("UpdateNoteFields", mock.Anything, &ankiconnect.UpdateNoteFieldsParams{
+			ID: 12,
+			Fields: map[string]string{
+				"a": "aa",
+				"b": "",
+			},
+		}).Return(nil).Once()
+		err := client.UpdateNote(context.Background(), &UpdateNoteRequest{
+			NoteID: 12,
+			Fields: []AddNoteField{
+				{Name: "a", Value: "aa"},
+				{Name: "b", Value: ""},
+			},
+		})
+		assert.NoError(t, err)
+	})
+	t.Run("everything", func(t *testing.T) {
+		client, ankiClient, _ := newTestNormalStatefullClient(t, readyConfig)
+		ankiClient.On("StoreMediaFile", mock.Anything, &ankiconnect.MediaAssetRequest{
+			Filename:       "a.mp3",
+			Data:           "ZGF0YQ==",
+			DeleteExisting: true,
+		}).Return("a.mp3", nil).Once()
+		ankiClient.On("UpdateNoteFields", mock.Anything, &ankiconnect.UpdateNoteFieldsParams{
+			ID: 12,
+			Fields: map[string]string{
+				"audio": "[sound:a.mp3]",
+			},
+		}).Return(nil).Once()
+		ankiClient.On("UpdateNoteTags", mock.Anything, int64(12), []string{"x", "y"}).Return(nil).Once()
+		err := client.UpdateNote(context.Background(), &UpdateNoteRequest{
+			NoteID: 12,
+			Fields: []AddNoteField{
+				{Name: "audio", Value: "[sound:a.mp3]"},
+			},
+			Tags: []string{"x", "y"},
+			AudioAssets: []AddNoteAudioAsset{
+				{Field: "audio", Filename: "a.mp3", Data: "ZGF0YQ=="},
+			},
+		})
+		assert.NoError(t, err)
+	})
+	t.Run("update tags error", func(t *testing.T) {
+		client, ankiClient, _ := newTestNormalStatefullClient(t, readyConfig)
+		ankiClient.On("UpdateNoteTags", mock.Anything, int64(12), []string{"x"}).
+			Return(&ankiconnect.ServerError{Err: ankiconnect.ErrCollectionUnavailable}).
+			Once()
+		err := client.UpdateNote(context.Background(), &UpdateNoteRequest{
+			NoteID: 12,
+			Tags:   []string{"x"},
+		})
+		assert.ErrorIs(t, err, ErrCollectionUnavailable)
+	})
+}
@@ -0,0 +1,123 @@
+package anki
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+// TagRules specifies tags that are added to new notes.
+type TagRules struct {
+	// Static tags are added to every note
+	Static []string
+	// Rules are templates that are executed with lemma, result is split by spaces to tags,
+	// so single rule can produce any number of tags
+	Rules []*Template
+}
+
+func (tr TagRules) Equal(otr TagRules) bool {
+	return slices.Equal(tr.Static, otr.Static) &&
+		slices.EqualFunc(tr.Rules, otr.Rules, func(a, b *Template) bool {
+			return a.Src == b.Src
+		})
+}
+
+// TagValidationError describes invalid static tag or tag rule with specified index.
+type TagValidationError struct {
+	Index int
+	Value string
+	Msg   string
+}
+
+func (e *TagValidationError) Error() string {
+	return fmt.Sprintf("tag %q validation failed: %s", e.Value, e.Msg)
+}
+
+type TagsValidationErrors struct {
+	StaticErrors []*TagValidationError
+	RuleErrors   []*TagValidationError
+}
+
+func (*TagsValidationErrors) Error() string {
+	return "tags validation failed"
+}
+
+var (
+	tagRegex      = regexp.MustCompile(`^[^\s\pZ"]+$`)
+	errTagInvalid = errors.New("must not be empty string or contain spaces or '\"'")
+)
+
+// validateTag checks that tag is non-empty string without spaces (Anki uses them as separator)
+// and symbol `"`.
+func validateTag(tag string) error {
+	if !tagRegex.MatchString(tag) {
+		return errTagInvalid
+	}
+	return nil
+}
+
+// convertTagRules validates static tags and parses rules. It returns errors of static tags and rules separately.
+func convertTagRules(static, rules []string) (TagRules, []*TagValidationError, []*TagValidationError) {
+	var staticErrs, ruleErrs []*TagValidationError
+	for i, tag := range static {
+		if err := validateTag(tag); err != nil {
+			staticErrs = append(staticErrs, &TagValidationError{
+				Index: i,
+				Value: tag,
+				Msg:   err.Error(),
+			})
+		}
+	}
+	var result TagRules
+	// config can have empty list instead of nil, but it should be equal
+	if len(static) != 0 {
+		result.Static = static
+	}
+	root := template.New("")
+	for i, src := range rules {
+		ruleTemplate := root.New(fmt.Sprintf("tag rule %d", i))
+		if err := initTemplate(ruleTemplate, src); err != nil {
+			ruleErrs = append(ruleErrs, &TagValidationError{
+				Index: i,
+				Value: src,
+				Msg:   err.Error(),
+			})
+		}
+		result.Rules = append(result.Rules, &Template{
+			Src:  src,
+			Tmpl: ruleTemplate,
+		})
+	}
+	return result, staticErrs, ruleErrs
+}
+
+// prepareTagsForNoteRequest returns static tags and tags produced by rules without duplicates.
+// Rules can produce tags that Anki doesn't allow, they are skipped.
+func prepareTagsForNoteRequest(lemma *lemma.ProjectedLemma, rules TagRules) ([]string, error) {
+	tags := []string{}
+	addTag := func(tag string) {
+		if validateTag(tag) == nil && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	for _, tag := range rules.Static {
+		addTag(tag)
+	}
+	var buffer bytes.Buffer
+	for _, rule := range rules.Rules {
+		buffer.Reset()
+		if err := rule.Tmpl.Execute(&buffer, lemma); err != nil {
+			return nil, err
+		}
+		for _, tag := range strings.Fields(buffer.String()) {
+			addTag(tag)
+		}
+	}
+	return tags, nil
+}
@@ -0,0 +1,180 @@
+package anki
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Darkclainer/japwords/pkg/config"
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+func mustConvertTagRules(t *testing.T, static, rules []string) TagRules {
+	tagRules, staticErrs, ruleErrs := convertTagRules(static, rules)
+	for _, tagErr := range append(staticErrs, ruleErrs...) {
+		t.Fatalf("mustConvertTagRules failed: %s", tagErr)
+	}
+	return tagRules
+}
+
+func Test_validateTag(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		Tag         string
+		ErrorAssert assert.ErrorAssertionFunc
+	}{
+		{
+			Name:        "simple",
+			Tag:         "japwords",
+			ErrorAssert: assert.NoError,
+		},
+		{
+			Name:        "hierarchical",
+			Tag:         "JLPT::N5",
+			ErrorAssert: assert.NoError,
+		},
+		{
+			Name:        "unicode",
+			Tag:         "日本語",
+			ErrorAssert: assert.NoError,
+		},
+		{
+			Name:        "empty",
+			Tag:         "",
+			ErrorAssert: assert.Error,
+		},
+		{
+			Name:        "space",
+			Tag:         "JLPT N5",
+			ErrorAssert: assert.Error,
+		},
+		{
+			Name:        "full width space",
+			Tag:         "JLPT　N5",
+			ErrorAssert: assert.Error,
+		},
+		{
+			Name:        "quote",
+			Tag:         `my"tag`,
+			ErrorAssert: assert.Error,
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.ErrorAssert(t, validateTag(tc.Tag))
+		})
+	}
+}
+
+func Test_convertTagRules(t *testing.T) {
+	testCases := []struct {
+		Name           string
+		Static         []string
+		Rules          []string
+		ExpectedStatic []*TagValidationError
+		ExpectedRules  []int
+	}{
+		{
+			Name:   "ok",
+			Static: []string{"a", "b"},
+			Rules:  []string{"{{ .Slug.Word }}"},
+		},
+		{
+			Name:   "invalid static",
+			Static: []string{"a", "b c", ""},
+			ExpectedStatic: []*TagValidationError{
+				{Index: 1, Value: "b c", Msg: errTagInvalid.Error()},
+				{Index: 2, Value: "", Msg: errTagInvalid.Error()},
+			},
+		},
+		{
+			Name:          "invalid rules",
+			Rules:         []string{"{{ .Slug.Word }}", "{{ .NotExists }}", "{{"},
+			ExpectedRules: []int{1, 2},
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			tagRules, staticErrs, ruleErrs := convertTagRules(tc.Static, tc.Rules)
+			assert.Equal(t, tc.ExpectedStatic, staticErrs)
+			var ruleIndexes []int
+			for _, ruleErr := range ruleErrs {
+				ruleIndexes = append(ruleIndexes, ruleErr.Index)
+				assert.Equal(t, tc.Rules[ruleErr.Index], ruleErr.Value)
+			}
+			assert.Equal(t, tc.ExpectedRules, ruleIndexes)
+			assert.Equal(t, tc.Static, tagRules.Static)
+			require.Len(t, tagRules.Rules, len(tc.Rules))
+		})
+	}
+}
+
+func Test_TagRules_Equal(t *testing.T) {
+	first := mustConvertTagRules(t, []string{"a"}, []string{"{{ .Slug.Word }}"})
+	second := mustConvertTagRules(t, []string{"a"}, []string{"{{ .Slug.Word }}"})
+	assert.True(t, first.Equal(second))
+	assert.True(t, TagRules{}.Equal(mustConvertTagRules(t, []string{}, nil)))
+	assert.False(t, first.Equal(mustConvertTagRules(t, []string{"b"}, []string{"{{ .Slug.Word }}"})))
+	assert.False(t, first.Equal(mustConvertTagRules(t, []string{"a"}, []string{"{{ .Slug.Hiragana }}"})))
+	assert.False(t, first.Equal(mustConvertTagRules(t, []string{"a"}, nil)))
+}
+
+func Test_prepareTagsForNoteRequest(t *testing.T) {
+	testLemma := &lemma.ProjectedLemma{
+		Slug: lemma.Word{Word: "犬"},
+		Tags: []string{"Common word", "JLPT N5", "JLPT N4"},
+	}
+	testCases := []struct {
+		Name     string
+		Static   []string
+		Rules    []string
+		Expected []string
+	}{
+		{
+			Name:     "empty",
+			Expected: []string{},
+		},
+		{
+			Name:     "static",
+			Static:   []string{"a", "b"},
+			Expected: []string{"a", "b"},
+		},
+		{
+			Name:   "rule with several tags",
+			Static: []string{"a"},
+			Rules: []string{
+				`{{ range .Tags }}{{ if hasPrefix "JLPT " . }} {{ replace "JLPT " "JLPT::" . }}{{ end }}{{ end }}`,
+			},
+			Expected: []string{"a", "JLPT::N5", "JLPT::N4"},
+		},
+		{
+			Name:     "duplicates",
+			Static:   []string{"犬"},
+			Rules:    []string{"{{ .Slug.Word }}", "{{ .Slug.Word }} a 犬"},
+			Expected: []string{"犬", "a"},
+		},
+		{
+			Name:     "invalid and empty are skipped",
+			Rules:    []string{`a"b c`, ""},
+			Expected: []string{"c"},
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			tags, err := prepareTagsForNoteRequest(testLemma, mustConvertTagRules(t, tc.Static, tc.Rules))
+			require.NoError(t, err)
+			assert.Equal(t, tc.Expected, tags)
+		})
+	}
+}
+
+func Test_prepareTagsForNoteRequest_DefaultConfig(t *testing.T) {
+	defaultTags := config.DefaultUserConfig().Anki.Tags
+	tags, err := prepareTagsForNoteRequest(&DefaultExampleLemma, mustConvertTagRules(t, defaultTags.Static, defaultTags.Rules))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"japwords", "JLPT::N5"}, tags)
+}
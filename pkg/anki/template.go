@@ -10,6 +10,8 @@ import (
 
 	"github.com/Masterminds/sprig/v3"
 
+	"github.com/Darkclainer/japwords/pkg/kana"
+	"github.com/Darkclainer/japwords/pkg/kanji"
 	"github.com/Darkclainer/japwords/pkg/lemma"
 )
 
@@ -78,8 +80,14 @@ func templateFuncs() template.FuncMap {
 	templateFuncsSync.Do(func() {
 		sprigFuncs := sprig.TxtFuncMap()
 		newFuncs := map[string]any{
-			"renderFurigana": renderFuriganaTemplate,
-			"renderPitch":    renderPitchTemplate,
+			"renderFurigana":   renderFuriganaTemplate,
+			"renderPitch":      renderPitchTemplate,
+			"toHiragana":       kana.ToHiragana,
+			"toKatakana":       kana.ToKatakana,
+			"romajiToHiragana": kana.RomajiToHiragana,
+			"expandLongVowels": kana.ExpandLongVowels,
+			"kanjiCharacters":  kanji.Characters,
+			"renderKanji":      renderKanjiTemplate,
 		}
 		for name, f := range sprigFuncs {
 			newFuncs[name] = f
@@ -184,3 +192,36 @@ func renderPitch(word *lemma.Word, tag string, directionClasses []string) (strin
 	}
 	return buffer.String(), nil
 }
+
+// renderKanjiTemplate renders breakdown of kanji: every kanji is wrapped in tag with class "kanji"
+// and contains spans with character, meanings, on and kun readings. Empty parts are omitted.
+func renderKanjiTemplate(kanji []lemma.Kanji, tag string) (string, error) {
+	if tag == "" {
+		return "", errors.New("tag should be non empty string")
+	}
+	var buffer strings.Builder
+	for _, k := range kanji {
+		buffer.WriteByte('<')
+		buffer.WriteString(tag)
+		buffer.WriteString(` class="kanji">`)
+		writeKanjiSpan(&buffer, "kanji-character", k.Character)
+		writeKanjiSpan(&buffer, "kanji-meanings", strings.Join(k.Meanings, ", "))
+		writeKanjiSpan(&buffer, "kanji-on", strings.Join(k.OnReadings, "、"))
+		writeKanjiSpan(&buffer, "kanji-kun", strings.Join(k.KunReadings, "、"))
+		buffer.WriteString("</")
+		buffer.WriteString(tag)
+		buffer.WriteByte('>')
+	}
+	return buffer.String(), nil
+}
+
+func writeKanjiSpan(buffer *strings.Builder, class string, text string) {
+	if text == "" {
+		return
+	}
+	buffer.WriteString(`<span class="`)
+	buffer.WriteString(class)
+	buffer.WriteString(`">`)
+	buffer.WriteString(text)
+	buffer.WriteString("</span>")
+}
@@ -215,6 +215,46 @@ func Test_initTemplate(t *testing.T) {
 			Expected:    `<span class="d">h</span><span class="u l">ello</span>`,
 			ErrorAssert: assert.NoError,
 		},
+		{
+			Name: "kana functions",
+			Lemma: lemma.ProjectedLemma{
+				Slug: lemma.Word{
+					Word:     "ラーメン",
+					Hiragana: "らーめん",
+				},
+			},
+			Tmpl:        `{{toHiragana .Slug.Word}} {{toKatakana .Slug.Hiragana}} {{expandLongVowels .Slug.Word}} {{romajiToHiragana "neko"}}`,
+			Expected:    `らーめん ラーメン ラアメン ねこ`,
+			ErrorAssert: assert.NoError,
+		},
+		{
+			Name: "kanji",
+			Lemma: lemma.ProjectedLemma{
+				Slug: lemma.Word{
+					Word: "犬小屋",
+				},
+				Kanji: []lemma.Kanji{
+					{
+						Character:   "犬",
+						Meanings:    []string{"dog"},
+						OnReadings:  []string{"ケン"},
+						KunReadings: []string{"いぬ", "いぬ-"},
+					},
+					{
+						Character: "屋",
+						Meanings:  []string{"roof", "house"},
+					},
+				},
+			},
+			Tmpl:        `{{kanjiCharacters .Slug.Word | join ","}} {{renderKanji .Kanji "div"}}`,
+			Expected:    `犬,小,屋 <div class="kanji"><span class="kanji-character">犬</span><span class="kanji-meanings">dog</span><span class="kanji-on">ケン</span><span class="kanji-kun">いぬ、いぬ-</span></div><div class="kanji"><span class="kanji-character">屋</span><span class="kanji-meanings">roof, house</span></div>`,
+			ErrorAssert: assert.NoError,
+		},
+		{
+			Name:        "kanji empty tag",
+			Tmpl:        `{{renderKanji .Kanji ""}}`,
+			ErrorAssert: assert.Error,
+		},
 	}
 	for i := range testCases {
 		tc := testCases[i]
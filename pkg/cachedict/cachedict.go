@@ -2,44 +2,285 @@ package cachedict
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	lru "github.com/hashicorp/golang-lru/v2"
+	"go.uber.org/multierr"
+)
+
+const (
+	// DefaultSize is default number of results that are kept in memory
+	DefaultSize = 256
+	// DefaultErrorTTL is default time for which failed result is cached
+	DefaultErrorTTL = 10 * time.Second
 )
 
 type Dict[T any] interface {
 	Query(ctx context.Context, query string) (T, error)
 }
 
+// Options specifies behaviour of CacheDict, zero value is valid.
+type Options struct {
+	// Name identifies cache in store and in statistics
+	Name string
+	// Store is persistent storage of successful results. Nil means that results are cached only in memory.
+	Store *Store
+	// Size is number of successful results kept in memory. DefaultSize is used if it's zero.
+	Size int
+	// ErrorTTL is time for which failed result is cached, so failing dictionary is not queried on every
+	// request. DefaultErrorTTL is used if it's zero, negative value disables caching of errors.
+	// Errors caused by cancellation or exceeded deadline are never cached.
+	ErrorTTL time.Duration
+}
+
+// CacheDict caches results of Dict in memory and optionally in persistent Store.
+// Concurrent identical queries are coalesced, so Dict is queried only once.
 type CacheDict[T any] struct {
-	cache *lru.Cache[string, result[T]]
-	dict  Dict[T]
+	dict     Dict[T]
+	name     string
+	store    *Store
+	cache    *lru.Cache[string, T]
+	errors   *lru.Cache[string, failedResult[T]]
+	errorTTL time.Duration
+	now      func() time.Time
+
+	callsLock sync.Mutex
+	// calls is queries that are in flight right now
+	calls map[string]*call[T]
+
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+	coalesced atomic.Uint64
 }
 
-func New[T any](dict Dict[T]) (*CacheDict[T], error) {
-	cache, err := lru.New[string, result[T]](256)
+func New[T any](dict Dict[T], opts Options) (*CacheDict[T], error) {
+	size := opts.Size
+	if size == 0 {
+		size = DefaultSize
+	}
+	errorTTL := opts.ErrorTTL
+	if errorTTL == 0 {
+		errorTTL = DefaultErrorTTL
+	}
+	cache, err := lru.New[string, T](size)
+	if err != nil {
+		return nil, err
+	}
+	errorsCache, err := lru.New[string, failedResult[T]](size)
 	if err != nil {
 		return nil, err
 	}
 	return &CacheDict[T]{
-		cache: cache,
-		dict:  dict,
+		dict:     dict,
+		name:     opts.Name,
+		store:    opts.Store,
+		cache:    cache,
+		errors:   errorsCache,
+		errorTTL: errorTTL,
+		now:      time.Now,
+		calls:    map[string]*call[T]{},
 	}, nil
 }
 
 func (c *CacheDict[T]) Query(ctx context.Context, query string) (T, error) {
-	r, ok := c.cache.Get(query)
-	if ok {
-		return r.Value, r.Err
+	for {
+		if value, ok, err := c.cached(query); ok {
+			c.hits.Add(1)
+			return value, err
+		}
+		c.callsLock.Lock()
+		inFlight, ok := c.calls[query]
+		if !ok {
+			inFlight = &call[T]{
+				done: make(chan struct{}),
+			}
+			c.calls[query] = inFlight
+			c.callsLock.Unlock()
+			c.do(ctx, query, inFlight)
+			return inFlight.value, inFlight.err
+		}
+		c.callsLock.Unlock()
+		select {
+		case <-inFlight.done:
+		case <-ctx.Done():
+			var value T
+			return value, ctx.Err()
+		}
+		// query that we waited was cancelled by its caller, but we still can make it ourselves
+		if isContextError(inFlight.err) && ctx.Err() == nil {
+			continue
+		}
+		c.coalesced.Add(1)
+		return inFlight.value, inFlight.err
+	}
+}
+
+// cached returns result from memory, including failed results that are not expired.
+func (c *CacheDict[T]) cached(query string) (T, bool, error) {
+	if value, ok := c.cache.Get(query); ok {
+		return value, true, nil
+	}
+	failed, ok := c.errors.Get(query)
+	if !ok || !c.now().Before(failed.Expires) {
+		var value T
+		return value, false, nil
 	}
+	return failed.Value, true, failed.Err
+}
+
+// do makes query and saves its result to inFlight.
+func (c *CacheDict[T]) do(ctx context.Context, query string, inFlight *call[T]) {
+	defer func() {
+		c.callsLock.Lock()
+		delete(c.calls, query)
+		c.callsLock.Unlock()
+		close(inFlight.done)
+	}()
+	if value, ok := c.load(query); ok {
+		c.hits.Add(1)
+		c.add(query, value)
+		inFlight.value = value
+		return
+	}
+	c.misses.Add(1)
 	value, err := c.dict.Query(ctx, query)
-	c.cache.Add(query, result[T]{
-		Value: value,
-		Err:   err,
-	})
-	return value, err
+	inFlight.value, inFlight.err = value, err
+	switch {
+	case err == nil:
+		c.add(query, value)
+		c.save(query, value)
+	case c.errorTTL > 0 && !isContextError(err):
+		c.errors.Add(query, failedResult[T]{
+			Value:   value,
+			Err:     err,
+			Expires: c.now().Add(c.errorTTL),
+		})
+	}
+}
+
+func (c *CacheDict[T]) add(query string, value T) {
+	c.errors.Remove(query)
+	if evicted := c.cache.Add(query, value); evicted {
+		c.evictions.Add(1)
+	}
 }
 
-type result[T any] struct {
-	Value T
-	Err   error
+// Clear removes all cached results from memory and store.
+func (c *CacheDict[T]) Clear() error {
+	c.cache.Purge()
+	c.errors.Purge()
+	if c.store == nil {
+		return nil
+	}
+	return c.store.Clear(c.name)
+}
+
+// Stats returns counters of cache since its creation.
+func (c *CacheDict[T]) Stats() Stats {
+	return Stats{
+		Name:      c.name,
+		Hits:      int(c.hits.Load()),
+		Misses:    int(c.misses.Load()),
+		Evictions: int(c.evictions.Load()),
+		Coalesced: int(c.coalesced.Load()),
+	}
+}
+
+// load returns result from store. Store errors are treated as cache miss,
+// because cache should not break lookup.
+func (c *CacheDict[T]) load(query string) (T, bool) {
+	var value T
+	if c.store == nil {
+		return value, false
+	}
+	src, ok, err := c.store.Get(c.name, query)
+	if err != nil || !ok {
+		return value, false
+	}
+	if err := json.Unmarshal(src, &value); err != nil {
+		return value, false
+	}
+	return value, true
+}
+
+// save puts result to store, errors are ignored for the same reason as in load.
+func (c *CacheDict[T]) save(query string, value T) {
+	if c.store == nil {
+		return
+	}
+	src, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	_ = c.store.Put(c.name, query, src)
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+type call[T any] struct {
+	// done is closed when value and err are set
+	done  chan struct{}
+	value T
+	err   error
+}
+
+type failedResult[T any] struct {
+	Value   T
+	Err     error
+	Expires time.Time
+}
+
+// Stats is counters of cache.
+type Stats struct {
+	Name string
+	// Hits is number of queries answered from memory or store
+	Hits int
+	// Misses is number of queries that were passed to dictionary
+	Misses int
+	// Evictions is number of results evicted from memory because of size limit
+	Evictions int
+	// Coalesced is number of queries that waited for identical query in flight
+	Coalesced int
+}
+
+// Cache is cache that can be cleared and inspected.
+type Cache interface {
+	Clear() error
+	Stats() Stats
+}
+
+// Group is set of caches that are managed together.
+type Group struct {
+	caches []Cache
+}
+
+func NewGroup(caches ...Cache) *Group {
+	return &Group{
+		caches: caches,
+	}
+}
+
+// Clear clears all caches of group. All caches are cleared even if some fail.
+func (g *Group) Clear() error {
+	var err error
+	for _, cache := range g.caches {
+		err = multierr.Append(err, cache.Clear())
+	}
+	return err
+}
+
+// Stats returns statistics of all caches in group.
+func (g *Group) Stats() []Stats {
+	result := make([]Stats, len(g.caches))
+	for i, cache := range g.caches {
+		result[i] = cache.Stats()
+	}
+	return result
 }
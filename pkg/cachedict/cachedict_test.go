@@ -3,7 +3,11 @@ package cachedict
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -15,7 +19,7 @@ func Test_CacheDict_Query(t *testing.T) {
 		called++
 		return "my result", errors.New("my error")
 	})
-	cacheDict, err := New[string](dict)
+	cacheDict, err := New[string](dict, Options{})
 	require.NoError(t, err)
 	ctx := context.Background()
 
@@ -35,6 +39,208 @@ func Test_CacheDict_Query(t *testing.T) {
 	assert.Equal(t, 2, called)
 }
 
+func Test_CacheDict_Persistent(t *testing.T) {
+	store, _ := newTestStore(t, StoreOptions{})
+	var called int
+	dict := TestDictHandler(func(_ context.Context, query string) (string, error) {
+		called++
+		if query == "bad" {
+			return "", errors.New("my error")
+		}
+		return "result " + query, nil
+	})
+	ctx := context.Background()
+
+	cacheDict, err := New[string](dict, Options{Name: "test", Store: store})
+	require.NoError(t, err)
+	result, err := cacheDict.Query(ctx, "good")
+	require.NoError(t, err)
+	assert.Equal(t, "result good", result)
+	_, err = cacheDict.Query(ctx, "bad")
+	assert.ErrorContains(t, err, "my error")
+	assert.Equal(t, 2, called)
+
+	// new instance has empty memory cache, so results are taken from store
+	cacheDict, err = New[string](dict, Options{Name: "test", Store: store})
+	require.NoError(t, err)
+	result, err = cacheDict.Query(ctx, "good")
+	require.NoError(t, err)
+	assert.Equal(t, "result good", result)
+	assert.Equal(t, 2, called)
+	// failed results are not stored
+	_, err = cacheDict.Query(ctx, "bad")
+	assert.ErrorContains(t, err, "my error")
+	assert.Equal(t, 3, called)
+
+	require.NoError(t, NewGroup(cacheDict).Clear())
+	result, err = cacheDict.Query(ctx, "good")
+	require.NoError(t, err)
+	assert.Equal(t, "result good", result)
+	assert.Equal(t, 4, called)
+}
+
+func Test_CacheDict_ErrorTTL(t *testing.T) {
+	var called int
+	dict := TestDictHandler(func(_ context.Context, _ string) (string, error) {
+		called++
+		return "", errors.New("my error")
+	})
+	cacheDict, err := New[string](dict, Options{ErrorTTL: time.Minute})
+	require.NoError(t, err)
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	cacheDict.now = func() time.Time {
+		return now
+	}
+	ctx := context.Background()
+
+	_, err = cacheDict.Query(ctx, "query")
+	assert.ErrorContains(t, err, "my error")
+	now = now.Add(59 * time.Second)
+	_, err = cacheDict.Query(ctx, "query")
+	assert.ErrorContains(t, err, "my error")
+	assert.Equal(t, 1, called)
+
+	now = now.Add(time.Second)
+	_, err = cacheDict.Query(ctx, "query")
+	assert.ErrorContains(t, err, "my error")
+	assert.Equal(t, 2, called)
+
+	disabled, err := New[string](dict, Options{ErrorTTL: -1})
+	require.NoError(t, err)
+	_, err = disabled.Query(ctx, "query")
+	assert.ErrorContains(t, err, "my error")
+	_, err = disabled.Query(ctx, "query")
+	assert.ErrorContains(t, err, "my error")
+	assert.Equal(t, 4, called)
+}
+
+func Test_CacheDict_ContextErrors(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Err  error
+	}{
+		{
+			Name: "canceled",
+			Err:  context.Canceled,
+		},
+		{
+			Name: "deadline",
+			Err:  fmt.Errorf("request failed: %w", context.DeadlineExceeded),
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			var called int
+			dict := TestDictHandler(func(_ context.Context, _ string) (string, error) {
+				called++
+				return "", tc.Err
+			})
+			cacheDict, err := New[string](dict, Options{})
+			require.NoError(t, err)
+			ctx := context.Background()
+			_, err = cacheDict.Query(ctx, "query")
+			assert.ErrorIs(t, err, tc.Err)
+			_, err = cacheDict.Query(ctx, "query")
+			assert.ErrorIs(t, err, tc.Err)
+			assert.Equal(t, 2, called)
+		})
+	}
+}
+
+func Test_CacheDict_Coalesce(t *testing.T) {
+	const queries = 10
+	var called atomic.Int32
+	release := make(chan struct{})
+	dict := TestDictHandler(func(_ context.Context, query string) (string, error) {
+		called.Add(1)
+		<-release
+		return "result " + query, nil
+	})
+	cacheDict, err := New[string](dict, Options{})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	results := make([]string, queries)
+	for i := 0; i < queries; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = cacheDict.Query(context.Background(), "query")
+		}()
+	}
+	// wait until all goroutines wait for the first one
+	require.Eventually(t, func() bool {
+		cacheDict.callsLock.Lock()
+		defer cacheDict.callsLock.Unlock()
+		return len(cacheDict.calls) == 1
+	}, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), called.Load())
+	for _, result := range results {
+		assert.Equal(t, "result query", result)
+	}
+	stats := cacheDict.Stats()
+	assert.Equal(t, 1, stats.Misses)
+	assert.Equal(t, queries-1, stats.Hits+stats.Coalesced)
+}
+
+func Test_CacheDict_CoalesceCanceled(t *testing.T) {
+	var called atomic.Int32
+	started := make(chan struct{})
+	dict := TestDictHandler(func(ctx context.Context, query string) (string, error) {
+		if called.Add(1) == 1 {
+			close(started)
+			<-ctx.Done()
+			return "", ctx.Err()
+		}
+		return "result " + query, nil
+	})
+	cacheDict, err := New[string](dict, Options{})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error)
+	go func() {
+		_, err := cacheDict.Query(ctx, "query")
+		leaderErr <- err
+	}()
+	<-started
+	followerResult := make(chan string)
+	go func() {
+		result, _ := cacheDict.Query(context.Background(), "query")
+		followerResult <- result
+	}()
+	cancel()
+	assert.ErrorIs(t, <-leaderErr, context.Canceled)
+	// follower is not affected by cancellation of leader
+	assert.Equal(t, "result query", <-followerResult)
+}
+
+func Test_CacheDict_Stats(t *testing.T) {
+	dict := TestDictHandler(func(_ context.Context, query string) (string, error) {
+		return "result " + query, nil
+	})
+	cacheDict, err := New[string](dict, Options{Name: "test", Size: 2})
+	require.NoError(t, err)
+	ctx := context.Background()
+	for _, query := range []string{"a", "a", "b", "c", "a"} {
+		_, err := cacheDict.Query(ctx, query)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []Stats{
+		{
+			Name:      "test",
+			Hits:      1,
+			Misses:    4,
+			Evictions: 2,
+		},
+	}, NewGroup(cacheDict).Stats())
+}
+
 type TestDictHandler func(context.Context, string) (string, error)
 
 func (td TestDictHandler) Query(ctx context.Context, query string) (string, error) {
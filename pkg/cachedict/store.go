@@ -0,0 +1,229 @@
+package cachedict
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// expiresSize is size of expiration time that prefixes every stored value
+	expiresSize = 8
+	// pruneRatio is part of MaxEntries that is left in namespace after pruning,
+	// so pruning doesn't happen on every put
+	pruneRatio = 0.9
+)
+
+// StoreOptions specifies limits of Store.
+type StoreOptions struct {
+	// TTL is time after which entry is considered stale. Zero means that entries never expire.
+	TTL time.Duration
+	// MaxEntries is maximum number of entries in every namespace. When it's exceeded, expired
+	// and then the oldest entries are removed. Zero means no limit.
+	MaxEntries int
+}
+
+// Store is persistent storage of serialized dictionary results based on embedded key-value database.
+// Entries are kept in namespaces, so several dictionaries can share the same Store.
+type Store struct {
+	db   *bolt.DB
+	opts StoreOptions
+	now  func() time.Time
+
+	countsLock sync.Mutex
+	// counts caches number of entries in namespaces, so we don't need to count them on every put
+	counts map[string]int
+}
+
+// OpenStore opens (or creates) store located at path.
+func OpenStore(path string, opts StoreOptions) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("cache directory creation failed: %w", err)
+	}
+	db, err := bolt.Open(path, 0o644, &bolt.Options{
+		Timeout: time.Second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cache open failed: %w", err)
+	}
+	return &Store{
+		db:     db,
+		opts:   opts,
+		now:    time.Now,
+		counts: map[string]int{},
+	}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Get returns value stored in namespace by key. Expired values are not returned.
+func (s *Store) Get(namespace, key string) ([]byte, bool, error) {
+	var value []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(namespace))
+		if bucket == nil {
+			return nil
+		}
+		raw := bucket.Get([]byte(key))
+		if len(raw) < expiresSize || s.expired(raw) {
+			return nil
+		}
+		// raw is valid only during transaction
+		value = append([]byte{}, raw[expiresSize:]...)
+		return nil
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("cache get failed: %w", err)
+	}
+	return value, value != nil, nil
+}
+
+// Put stores value in namespace by key.
+func (s *Store) Put(namespace, key string, value []byte) error {
+	s.countsLock.Lock()
+	defer s.countsLock.Unlock()
+	var count int
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(namespace))
+		if err != nil {
+			return err
+		}
+		var ok bool
+		count, ok = s.counts[namespace]
+		if !ok {
+			count = bucket.Stats().KeyN
+		}
+		if bucket.Get([]byte(key)) == nil {
+			count++
+		}
+		raw := make([]byte, expiresSize+len(value))
+		binary.BigEndian.PutUint64(raw, uint64(s.expiresAt()))
+		copy(raw[expiresSize:], value)
+		if err := bucket.Put([]byte(key), raw); err != nil {
+			return err
+		}
+		if s.opts.MaxEntries > 0 && count > s.opts.MaxEntries {
+			count, err = s.prune(bucket, int(float64(s.opts.MaxEntries)*pruneRatio))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cache put failed: %w", err)
+	}
+	s.counts[namespace] = count
+	return nil
+}
+
+// ForEach calls fn for every not expired entry of namespace. Value is valid only during fn call.
+// Iteration stops if fn returns error.
+func (s *Store) ForEach(namespace string, fn func(key string, value []byte) error) error {
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(namespace))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			if len(v) < expiresSize || s.expired(v) {
+				return nil
+			}
+			return fn(string(k), v[expiresSize:])
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("cache iteration failed: %w", err)
+	}
+	return nil
+}
+
+// Clear removes all entries of namespace.
+func (s *Store) Clear(namespace string) error {
+	s.countsLock.Lock()
+	defer s.countsLock.Unlock()
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket([]byte(namespace)) == nil {
+			return nil
+		}
+		return tx.DeleteBucket([]byte(namespace))
+	})
+	if err != nil {
+		return fmt.Errorf("cache clear failed: %w", err)
+	}
+	delete(s.counts, namespace)
+	return nil
+}
+
+// prune removes expired entries and then the oldest entries, so at most limit entries are left.
+// It returns number of left entries.
+func (s *Store) prune(bucket *bolt.Bucket, limit int) (int, error) {
+	type entry struct {
+		key     []byte
+		expires int64
+	}
+	var (
+		entries []entry
+		expired [][]byte
+	)
+	err := bucket.ForEach(func(k, v []byte) error {
+		key := append([]byte{}, k...)
+		if len(v) < expiresSize || s.expired(v) {
+			expired = append(expired, key)
+			return nil
+		}
+		entries = append(entries, entry{
+			key:     key,
+			expires: int64(binary.BigEndian.Uint64(v)),
+		})
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	// bucket can't be modified during ForEach
+	for _, key := range expired {
+		if err := bucket.Delete(key); err != nil {
+			return 0, err
+		}
+	}
+	if len(entries) <= limit {
+		return len(entries), nil
+	}
+	// all entries have the same TTL, so the oldest entries expire first
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].expires < entries[j].expires
+	})
+	for _, e := range entries[:len(entries)-limit] {
+		if err := bucket.Delete(e.key); err != nil {
+			return 0, err
+		}
+	}
+	return limit, nil
+}
+
+// expiresAt returns expiration time of entry put now. Entries without TTL are still
+// ordered by time when they were put, but never expire.
+func (s *Store) expiresAt() int64 {
+	now := s.now()
+	if s.opts.TTL <= 0 {
+		return now.UnixNano()
+	}
+	return now.Add(s.opts.TTL).UnixNano()
+}
+
+func (s *Store) expired(raw []byte) bool {
+	if s.opts.TTL <= 0 {
+		return false
+	}
+	expires := int64(binary.BigEndian.Uint64(raw))
+	return s.now().UnixNano() >= expires
+}
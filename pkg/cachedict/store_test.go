@@ -0,0 +1,126 @@
+package cachedict
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStore(t *testing.T, opts StoreOptions) (*Store, *time.Time) {
+	store, err := OpenStore(filepath.Join(t.TempDir(), "cache", "cache.db"), opts)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		store.Close()
+	})
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time {
+		return now
+	}
+	return store, &now
+}
+
+func Test_Store_GetPut(t *testing.T) {
+	store, _ := newTestStore(t, StoreOptions{})
+
+	_, ok, err := store.Get("ns1", "key")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, store.Put("ns1", "key", []byte("value1")))
+	require.NoError(t, store.Put("ns2", "key", []byte("value2")))
+
+	value, ok, err := store.Get("ns1", "key")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("value1"), value)
+
+	value, ok, err = store.Get("ns2", "key")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("value2"), value)
+
+	require.NoError(t, store.Clear("ns1"))
+	_, ok, err = store.Get("ns1", "key")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	_, ok, err = store.Get("ns2", "key")
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func Test_Store_TTL(t *testing.T) {
+	store, now := newTestStore(t, StoreOptions{TTL: time.Hour})
+
+	require.NoError(t, store.Put("ns", "key", []byte("value")))
+	*now = now.Add(59 * time.Minute)
+	_, ok, err := store.Get("ns", "key")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	*now = now.Add(time.Minute)
+	_, ok, err = store.Get("ns", "key")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func Test_Store_MaxEntries(t *testing.T) {
+	store, now := newTestStore(t, StoreOptions{TTL: time.Hour, MaxEntries: 10})
+
+	for i := 0; i < 11; i++ {
+		*now = now.Add(time.Second)
+		require.NoError(t, store.Put("ns", fmt.Sprintf("key%d", i), []byte("value")))
+	}
+	// after pruning 9 the newest entries are left
+	for i := 0; i < 11; i++ {
+		_, ok, err := store.Get("ns", fmt.Sprintf("key%d", i))
+		require.NoError(t, err)
+		assert.Equal(t, i >= 2, ok, "key%d", i)
+	}
+	// rewriting existing key doesn't increase number of entries
+	require.NoError(t, store.Put("ns", "key10", []byte("value")))
+	_, ok, err := store.Get("ns", "key2")
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func Test_Store_Reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+	store, err := OpenStore(path, StoreOptions{})
+	require.NoError(t, err)
+	require.NoError(t, store.Put("ns", "key", []byte("value")))
+	require.NoError(t, store.Close())
+
+	store, err = OpenStore(path, StoreOptions{})
+	require.NoError(t, err)
+	defer store.Close()
+	value, ok, err := store.Get("ns", "key")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("value"), value)
+}
+
+func Test_Store_ForEach(t *testing.T) {
+	store, now := newTestStore(t, StoreOptions{TTL: time.Hour})
+	require.NoError(t, store.Put("ns", "old", []byte("old value")))
+	*now = now.Add(30 * time.Minute)
+	require.NoError(t, store.Put("ns", "new", []byte("new value")))
+	require.NoError(t, store.Put("other", "key", []byte("value")))
+	*now = now.Add(45 * time.Minute)
+
+	entries := map[string]string{}
+	require.NoError(t, store.ForEach("ns", func(key string, value []byte) error {
+		entries[key] = string(value)
+		return nil
+	}))
+	// expired entry is skipped
+	assert.Equal(t, map[string]string{"new": "new value"}, entries)
+
+	require.NoError(t, store.ForEach("missing", func(string, []byte) error {
+		t.Fatal("unexpected entry")
+		return nil
+	}))
+}
@@ -8,6 +8,9 @@ type UserConfig struct {
 	Addr       string     `yaml:"addr" koanf:"addr"`
 	Anki       Anki       `yaml:"anki" koanf:"anki"`
 	Dictionary Dictionary `yaml:"dictionary" koanf:"dictionary"`
+	Media      Media      `yaml:"media" koanf:"media"`
+	History    History    `yaml:"history" koanf:"history"`
+	Outbox     Outbox     `yaml:"outbox" koanf:"outbox"`
 }
 
 type Anki struct {
@@ -40,6 +43,9 @@ type Anki struct {
 
 	// Audio specifies how audio should be mapped to anki notes.
 	Audio AnkiAudio `yaml:"audio" koanf:"audio"`
+
+	// Tags specifies tags of new notes.
+	Tags AnkiTags `yaml:"tags" koanf:"tags"`
 }
 
 type AnkiAudio struct {
@@ -49,12 +55,43 @@ type AnkiAudio struct {
 	PreferredType string
 }
 
+type AnkiTags struct {
+	// Static tags are added to every note. Tag must not contain spaces or `"`.
+	Static []string `yaml:"static" koanf:"static"`
+	// Rules are go text/templates (see pkg/anki/template.go) that are executed with lemma,
+	// their results are split by spaces, so every rule can produce several tags or none.
+	// Produced tags that Anki doesn't allow are skipped.
+	Rules []string `yaml:"rules" koanf:"rules"`
+}
+
 type Dictionary struct {
 	Workers   int               `yaml:"workers" koanf:"workers"`
 	UserAgent string            `yaml:"user-agent" koanf:"user-agent"`
 	Headers   map[string]string `yaml:"headers" koanf:"headers"`
-	Jisho     Jisho             `yaml:"jisho" koanf:"jisho"`
-	Wadoku    Wadoku            `yaml:"wadoku" koanf:"wadoku"`
+	// LemmaDicts is the names of dictionaries that are used for lemma lookup.
+	// Possible values are "jisho" and "jmdict". Order of dictionaries matters for merge policy.
+	LemmaDicts []string `yaml:"lemma-dicts" koanf:"lemma-dicts"`
+	// PitchDicts is the names of dictionaries that are used for pitch accent lookup.
+	// Possible values are "wadoku" and "accents". Pitch from the first dictionary wins.
+	PitchDicts []string `yaml:"pitch-dicts" koanf:"pitch-dicts"`
+	// MergePolicy specifies how lemmas from several dictionaries are merged.
+	// Possible values are "first-wins" (default), "union" and "priority".
+	MergePolicy string `yaml:"merge-policy" koanf:"merge-policy"`
+	// Timeout is overall deadline of lookup in format of go durations (for example "45s").
+	Timeout string `yaml:"timeout" koanf:"timeout"`
+	// SourceTimeout is deadline of lookup in single dictionary, empty means no separate deadline.
+	SourceTimeout string `yaml:"source-timeout" koanf:"source-timeout"`
+	// SoftPitch makes pitch dictionaries optional: lemmas are returned without pitches
+	// and with warning if pitch dictionary failed or missed its deadline.
+	SoftPitch bool     `yaml:"soft-pitch" koanf:"soft-pitch"`
+	Jisho     Jisho    `yaml:"jisho" koanf:"jisho"`
+	Wadoku    Wadoku   `yaml:"wadoku" koanf:"wadoku"`
+	JMdict    JMdict   `yaml:"jmdict" koanf:"jmdict"`
+	Accents   Accents  `yaml:"accents" koanf:"accents"`
+	Examples  Examples `yaml:"examples" koanf:"examples"`
+	Kanji     Kanji    `yaml:"kanji" koanf:"kanji"`
+	Cache     Cache    `yaml:"cache" koanf:"cache"`
+	Requests  Requests `yaml:"requests" koanf:"requests"`
 }
 
 type Jisho struct {
@@ -65,6 +102,94 @@ type Wadoku struct {
 	URL string `yaml:"url" koanf:"url"`
 }
 
+type JMdict struct {
+	// Path is the path to index imported by japwords-jmdict-import.
+	// Relative path is resolved against directory of config file.
+	Path string `yaml:"path" koanf:"path"`
+}
+
+type Accents struct {
+	// Path is the path to tab separated file with accents in format word, reading, accents.
+	// Relative path is resolved against directory of config file.
+	Path string `yaml:"path" koanf:"path"`
+}
+
+type Examples struct {
+	// Path is the path to file with example sentences in format of Tanaka corpus (examples.utf).
+	// Relative path is resolved against directory of config file. Empty path disables examples.
+	Path string `yaml:"path" koanf:"path"`
+}
+
+type Kanji struct {
+	// Path is the path to KANJIDIC2 file (kanjidic2.xml or kanjidic2.xml.gz).
+	// Relative path is resolved against directory of config file. Empty path disables kanji information.
+	Path string `yaml:"path" koanf:"path"`
+}
+
+// Cache specifies persistent cache of online dictionaries (jisho and wadoku).
+type Cache struct {
+	// Path is the path to cache database. Relative path is resolved against directory
+	// of config file. Empty path disables persistent cache.
+	Path string `yaml:"path" koanf:"path"`
+	// TTL is how long entries are kept, in format of go durations (for example "720h").
+	// Empty or zero TTL means that entries never expire.
+	TTL string `yaml:"ttl" koanf:"ttl"`
+	// MaxEntries is maximum number of entries for every dictionary. Zero means no limit.
+	MaxEntries int `yaml:"max-entries" koanf:"max-entries"`
+}
+
+// Media specifies where downloaded audio is stored.
+type Media struct {
+	// Path is directory for downloaded audio, relative paths are resolved against config directory.
+	// Empty path disables downloading, in this case audio is downloaded by Anki itself.
+	Path string `yaml:"path" koanf:"path"`
+	// MaxSize is maximum size of single file in bytes.
+	MaxSize int64 `yaml:"max-size" koanf:"max-size"`
+	// MaxTotalSize is maximum size of all files in bytes, the oldest files are removed when
+	// it's exceeded. Zero means that directory is not limited.
+	MaxTotalSize int64 `yaml:"max-total-size" koanf:"max-total-size"`
+}
+
+// History specifies where looked up queries are kept and for how long.
+type History struct {
+	// Path is the path to history database. Relative path is resolved against directory
+	// of config file. Empty path disables history.
+	Path string `yaml:"path" koanf:"path"`
+	// Retention is how long entries are kept, in format of go durations (for example "2160h").
+	// Empty or zero retention means that entries are kept forever.
+	Retention string `yaml:"retention" koanf:"retention"`
+	// MaxEntries is maximum number of entries, the oldest entries are removed first. Zero means no limit.
+	MaxEntries int `yaml:"max-entries" koanf:"max-entries"`
+}
+
+// Outbox specifies where notes are kept while Anki is unreachable.
+type Outbox struct {
+	// Path is the path to outbox database. Relative path is resolved against directory
+	// of config file. Empty path disables outbox, so notes fail if Anki is unreachable.
+	Path string `yaml:"path" koanf:"path"`
+}
+
+// Requests specifies how requests to online dictionaries are retried and limited.
+type Requests struct {
+	// Timeout of single request in format of go durations (for example "30s").
+	Timeout string `yaml:"timeout" koanf:"timeout"`
+	// Retries is how many times request is retried if it failed with network error,
+	// status 429 (too many requests) or 502-504.
+	Retries int `yaml:"retries" koanf:"retries"`
+	// RetryBaseDelay is delay before the first retry in format of go durations (for example "500ms"),
+	// every next delay is doubled and randomized.
+	RetryBaseDelay string `yaml:"retry-base-delay" koanf:"retry-base-delay"`
+	// RetryMaxDelay limits delay between retries. If server asks to wait longer with
+	// Retry-After header, request is not retried.
+	RetryMaxDelay string `yaml:"retry-max-delay" koanf:"retry-max-delay"`
+	// RateLimit is maximum number of requests per second to single host. Zero means no limit.
+	RateLimit float64 `yaml:"rate-limit" koanf:"rate-limit"`
+	// RateBurst is number of requests to single host that can be made at once despite rate limit.
+	RateBurst int `yaml:"rate-burst" koanf:"rate-burst"`
+	// MaxConcurrent is maximum number of simultaneous requests to single host. Zero means no limit.
+	MaxConcurrent int `yaml:"max-concurrent" koanf:"max-concurrent"`
+}
+
 func DefaultUserConfig() *UserConfig {
 	return &UserConfig{
 		Addr: "",
@@ -95,23 +220,78 @@ func DefaultUserConfig() *UserConfig {
 				"SenseTags": `{{- $lastIndex := sub (len .SenseTags) 1 -}}
 {{- range $index, $_ := .SenseTags -}}
 	<span class="sensetag">{{.}}</span>{{ ne $index $lastIndex | ternary " " ""  }}
+{{- end -}}`,
+				"Example": `{{- range .Examples -}}
+	<div>{{ or .Reading .Japanese }}<br>{{ .English }}</div>
 {{- end -}}`,
 			},
 			Audio: AnkiAudio{
 				Field:         "Audio",
 				PreferredType: "mp3",
 			},
+			Tags: AnkiTags{
+				Static: []string{"japwords"},
+				Rules: []string{
+					// JLPT N5 -> JLPT::N5
+					`{{- range .Tags -}}{{- if hasPrefix "JLPT " . }} {{ replace "JLPT " "JLPT::" . }}{{ end -}}{{- end -}}`,
+				},
+			},
 		},
 		Dictionary: Dictionary{
-			Workers:   0,
-			UserAgent: "",
-			Headers:   map[string]string{},
+			Workers:       0,
+			UserAgent:     "",
+			Headers:       map[string]string{},
+			LemmaDicts:    []string{"jisho"},
+			PitchDicts:    []string{"wadoku"},
+			MergePolicy:   "first-wins",
+			Timeout:       "45s",
+			SourceTimeout: "15s",
+			SoftPitch:     true,
 			Jisho: Jisho{
 				URL: "",
 			},
 			Wadoku: Wadoku{
 				URL: "",
 			},
+			JMdict: JMdict{
+				Path: "jmdict.db",
+			},
+			Accents: Accents{
+				Path: "accents.txt",
+			},
+			Examples: Examples{
+				Path: "",
+			},
+			Kanji: Kanji{
+				Path: "",
+			},
+			Cache: Cache{
+				Path:       "cache.db",
+				TTL:        "720h",
+				MaxEntries: 10000,
+			},
+			Requests: Requests{
+				Timeout:        "30s",
+				Retries:        3,
+				RetryBaseDelay: "500ms",
+				RetryMaxDelay:  "10s",
+				RateLimit:      2,
+				RateBurst:      4,
+				MaxConcurrent:  4,
+			},
+		},
+		Media: Media{
+			Path:         "media",
+			MaxSize:      10 << 20,
+			MaxTotalSize: 500 << 20,
+		},
+		History: History{
+			Path:       "history.db",
+			Retention:  "2160h",
+			MaxEntries: 10000,
+		},
+		Outbox: Outbox{
+			Path: "outbox.db",
 		},
 	}
 }
@@ -165,6 +165,32 @@ func Test_DefaultUserConfig_AnkiFieldMapping(t *testing.T) {
 				"SenseTags": `<span class="sensetag">one</span> <span class="sensetag">two</span> <span class="sensetag">three</span>`,
 			},
 		},
+		{
+			Name: "Example",
+			Lemma: lemma.ProjectedLemma{
+				Examples: []lemma.Example{
+					{
+						Japanese: "犬が好きです。",
+						English:  "I like dogs.",
+					},
+					{
+						Japanese: "彼は犬を飼っている。",
+						English:  "He keeps a dog.",
+						Reading:  "彼[かれ]は 犬[いぬ]を 飼[か]っている。",
+					},
+				},
+			},
+			Expected: map[string]string{
+				"Example": `<div>犬が好きです。<br>I like dogs.</div><div>彼[かれ]は 犬[いぬ]を 飼[か]っている。<br>He keeps a dog.</div>`,
+			},
+		},
+		{
+			Name:  "Example empty",
+			Lemma: lemma.ProjectedLemma{},
+			Expected: map[string]string{
+				"Example": "",
+			},
+		},
 	}
 	for i := range testCases {
 		tc := testCases[i]
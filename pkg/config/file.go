@@ -35,6 +35,12 @@ func DefaultConfigPath() string {
 	return filepath.Join(dir, "japwords", "config.yaml")
 }
 
+// DefaultDataPath returns path to data file with specified name that is located
+// in the same directory as default config.
+func DefaultDataPath(name string) string {
+	return filepath.Join(filepath.Dir(DefaultConfigPath()), name)
+}
+
 // EnsureConfigFile checks that file on path exists and if not, writes default config
 func EnsureConfigFile(path string) error {
 	// TODO write config
@@ -96,6 +96,10 @@ func Test_LoadConfig(t *testing.T) {
 			Addr: "someaddr",
 			Anki: Anki{
 				FieldMapping: map[string]string{},
+				Tags: AnkiTags{
+					Static: []string{"mytag"},
+					Rules:  []string{"{{ .Slug.Word }}"},
+				},
 			},
 			Dictionary: Dictionary{
 				Workers:   4,
@@ -103,12 +107,45 @@ func Test_LoadConfig(t *testing.T) {
 				Headers: map[string]string{
 					"here": "there",
 				},
+				LemmaDicts:    []string{"jmdict", "jisho"},
+				PitchDicts:    []string{"accents"},
+				MergePolicy:   "union",
+				Timeout:       "1m",
+				SourceTimeout: "5s",
+				SoftPitch:     true,
 				Jisho: Jisho{
 					URL: "jisho",
 				},
 				Wadoku: Wadoku{
 					URL: "wadoku",
 				},
+				Cache: Cache{
+					Path:       "mycache.db",
+					TTL:        "24h",
+					MaxEntries: 100,
+				},
+				Requests: Requests{
+					Timeout:        "10s",
+					Retries:        1,
+					RetryBaseDelay: "1s",
+					RetryMaxDelay:  "1m",
+					RateLimit:      0.5,
+					RateBurst:      1,
+					MaxConcurrent:  2,
+				},
+			},
+			Media: Media{
+				Path:         "mymedia",
+				MaxSize:      1024,
+				MaxTotalSize: 4096,
+			},
+			History: History{
+				Path:       "myhistory.db",
+				Retention:  "24h",
+				MaxEntries: 10,
+			},
+			Outbox: Outbox{
+				Path: "myoutbox.db",
 			},
 		}
 		err := SaveConfig(path, config)
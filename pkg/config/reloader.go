@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"path/filepath"
 	"sync"
 )
 
@@ -30,6 +31,15 @@ func (m *Manager) Current() *UserConfig {
 	return m.config.Clone()
 }
 
+// ResolvePath returns path relative to directory with config file.
+// Absolute paths are returned as is.
+func (m *Manager) ResolvePath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(filepath.Dir(m.configPath), path)
+}
+
 type Part interface {
 	Equal(any) bool
 }
@@ -462,3 +462,12 @@ func newManager(t testing.TB, uc *UserConfig) *Manager {
 	require.NoError(t, err)
 	return mgr
 }
+
+func Test_Manager_ResolvePath(t *testing.T) {
+	mgr := &Manager{
+		configPath: filepath.Join("/", "config", "dir", "config.yaml"),
+	}
+	assert.Equal(t, filepath.Join("/", "config", "dir", "data.db"), mgr.ResolvePath("data.db"))
+	assert.Equal(t, filepath.Join("/", "config", "data.db"), mgr.ResolvePath(filepath.Join("..", "data.db")))
+	assert.Equal(t, filepath.Join("/", "other", "data.db"), mgr.ResolvePath(filepath.Join("/", "other", "data.db")))
+}
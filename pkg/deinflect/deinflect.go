@@ -0,0 +1,118 @@
+// deinflect converts conjugated verbs and adjectives to their dictionary forms.
+// It doesn't know anything about words, so it generates all candidates that can
+// be produced by rules and caller should check them against dictionary.
+package deinflect
+
+import (
+	"strings"
+
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+// maxCandidates limits number of candidates, it protects us from explosion of
+// candidates for long queries.
+const maxCandidates = 64
+
+// Candidate is possible dictionary form of the word.
+type Candidate struct {
+	// Term is supposed dictionary form of the word
+	Term string
+	// Rules is names of applied rules, the first rule is the outermost inflection,
+	// for example 食べられなかった is "past", "negative", "potential or passive" of 食べる.
+	// Rules is empty for the original word.
+	Rules []string
+
+	types wordType
+}
+
+// Deinflect returns candidates for dictionary form of word. The first candidate is
+// always the word itself. Candidates with shorter rule chain go first.
+func Deinflect(word string) []Candidate {
+	candidates := []Candidate{
+		{
+			Term: word,
+		},
+	}
+	for i := 0; i < len(candidates); i++ {
+		candidate := candidates[i]
+		for _, rule := range rules {
+			for _, v := range rule.Variants {
+				if !v.canApply(&candidate) {
+					continue
+				}
+				if len(candidates) >= maxCandidates {
+					return candidates
+				}
+				candidates = append(candidates, Candidate{
+					Term:  strings.TrimSuffix(candidate.Term, v.KanaIn) + v.KanaOut,
+					Rules: append(candidate.Rules[:len(candidate.Rules):len(candidate.Rules)], rule.Name),
+					types: v.TypesOut,
+				})
+			}
+		}
+	}
+	return candidates
+}
+
+func (v *variant) canApply(candidate *Candidate) bool {
+	// zero types means that candidate is original word and every rule can be applied
+	if candidate.types != 0 && candidate.types&v.TypesIn == 0 {
+		return false
+	}
+	// result should not be empty
+	if len(candidate.Term)-len(v.KanaIn)+len(v.KanaOut) <= 0 {
+		return false
+	}
+	return strings.HasSuffix(candidate.Term, v.KanaIn)
+}
+
+// Match reports if lemma can be dictionary form of candidate: lemma should have
+// the same writing or reading as candidate term and appropriate part of speech.
+func (c *Candidate) Match(l *lemma.Lemma) bool {
+	if !c.matchWriting(l) {
+		return false
+	}
+	if c.types == 0 {
+		return true
+	}
+	for _, sense := range l.Senses {
+		for _, partOfSpeech := range sense.PartOfSpeech {
+			if partOfSpeechType(partOfSpeech)&c.types != 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *Candidate) matchWriting(l *lemma.Lemma) bool {
+	if l.Slug.Word == c.Term || l.Slug.Hiragana == c.Term {
+		return true
+	}
+	for _, form := range l.Forms {
+		if form.Word == c.Term || form.Hiragana == c.Term {
+			return true
+		}
+	}
+	return false
+}
+
+// partOfSpeechType returns types of word by part of speech in format of jisho or JMdict,
+// for example "Godan verb with 'ku' ending" or "I-adjective (keiyoushi)".
+func partOfSpeechType(partOfSpeech string) wordType {
+	partOfSpeech = strings.ToLower(partOfSpeech)
+	switch {
+	case strings.Contains(partOfSpeech, "ichidan"):
+		return typeV1
+	case strings.Contains(partOfSpeech, "godan"):
+		return typeV5
+	case strings.Contains(partOfSpeech, "kuru verb"):
+		return typeVK
+	case strings.Contains(partOfSpeech, "suru"):
+		return typeVS
+	case strings.Contains(partOfSpeech, "keiyoushi"):
+		return typeAdjI
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,155 @@
+package deinflect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+func Test_Deinflect(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Word          string
+		ExpectedTerm  string
+		ExpectedRules []string
+	}{
+		{
+			Name:          "ichidan past negative potential",
+			Word:          "食べられなかった",
+			ExpectedTerm:  "食べる",
+			ExpectedRules: []string{"past", "negative", "potential or passive"},
+		},
+		{
+			Name:          "adjective te",
+			Word:          "高くて",
+			ExpectedTerm:  "高い",
+			ExpectedRules: []string{"-te"},
+		},
+		{
+			Name:          "godan negative",
+			Word:          "読まない",
+			ExpectedTerm:  "読む",
+			ExpectedRules: []string{"negative"},
+		},
+		{
+			Name:          "godan polite progressive",
+			Word:          "書いています",
+			ExpectedTerm:  "書く",
+			ExpectedRules: []string{"polite", "progressive or perfect", "-te"},
+		},
+		{
+			Name:          "irregular iku",
+			Word:          "行った",
+			ExpectedTerm:  "行く",
+			ExpectedRules: []string{"past"},
+		},
+		{
+			Name:          "kuru",
+			Word:          "来なかった",
+			ExpectedTerm:  "来る",
+			ExpectedRules: []string{"past", "negative"},
+		},
+		{
+			Name:          "suru",
+			Word:          "勉強しました",
+			ExpectedTerm:  "勉強する",
+			ExpectedRules: []string{"polite past"},
+		},
+		{
+			Name:          "causative passive",
+			Word:          "行かせられた",
+			ExpectedTerm:  "行く",
+			ExpectedRules: []string{"past", "potential or passive", "causative"},
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			candidates := Deinflect(tc.Word)
+			require.NotEmpty(t, candidates)
+			assert.Equal(t, Candidate{Term: tc.Word}, candidates[0])
+			var rules [][]string
+			for _, candidate := range candidates {
+				if candidate.Term == tc.ExpectedTerm {
+					rules = append(rules, candidate.Rules)
+				}
+			}
+			assert.Contains(t, rules, tc.ExpectedRules)
+		})
+	}
+}
+
+func Test_Deinflect_NoRules(t *testing.T) {
+	assert.Equal(t, []Candidate{{Term: ""}}, Deinflect(""))
+	assert.Equal(t, []Candidate{{Term: "dog"}}, Deinflect("dog"))
+}
+
+func Test_Candidate_Match(t *testing.T) {
+	taberu := &lemma.Lemma{
+		Slug: lemma.Word{Word: "食べる", Hiragana: "たべる"},
+		Senses: []lemma.WordSense{
+			{PartOfSpeech: []string{"Ichidan verb", "Transitive verb"}},
+		},
+	}
+	testCases := []struct {
+		Name      string
+		Candidate Candidate
+		Lemma     *lemma.Lemma
+		Expected  bool
+	}{
+		{
+			Name:      "original",
+			Candidate: Candidate{Term: "食べる"},
+			Lemma:     taberu,
+			Expected:  true,
+		},
+		{
+			Name:      "reading",
+			Candidate: Candidate{Term: "たべる", types: typeV1},
+			Lemma:     taberu,
+			Expected:  true,
+		},
+		{
+			Name:      "form",
+			Candidate: Candidate{Term: "喰べる", types: typeV1},
+			Lemma: &lemma.Lemma{
+				Slug:   taberu.Slug,
+				Forms:  []lemma.Word{{Word: "喰べる", Hiragana: "たべる"}},
+				Senses: taberu.Senses,
+			},
+			Expected: true,
+		},
+		{
+			Name:      "wrong part of speech",
+			Candidate: Candidate{Term: "食べる", types: typeV5},
+			Lemma:     taberu,
+			Expected:  false,
+		},
+		{
+			Name:      "wrong word",
+			Candidate: Candidate{Term: "食う"},
+			Lemma:     taberu,
+			Expected:  false,
+		},
+		{
+			Name:      "jmdict adjective",
+			Candidate: Candidate{Term: "高い", types: typeAdjI},
+			Lemma: &lemma.Lemma{
+				Slug: lemma.Word{Word: "高い", Hiragana: "たかい"},
+				Senses: []lemma.WordSense{
+					{PartOfSpeech: []string{"adjective (keiyoushi)"}},
+				},
+			},
+			Expected: true,
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, tc.Candidate.Match(tc.Lemma))
+		})
+	}
+}
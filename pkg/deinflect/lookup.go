@@ -0,0 +1,72 @@
+package deinflect
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+// ErrDictUnavailable should be wrapped by errors of Dict that can't answer any query at all (for example
+// every source of dictionary failed), Lookup doesn't query the rest of candidates after such error.
+var ErrDictUnavailable = errors.New("dictionary is unavailable")
+
+// maxQueriedTerms limits number of distinct candidate terms that Lookup queries. Every query can be
+// request to online dictionary, so miss shouldn't cost dozens of them. Real inflections are found
+// among the first terms, because candidates with shorter rule chains go first.
+const maxQueriedTerms = 12
+
+// Dict is dictionary that is used to check candidates, for example multidict.MultiDict.
+type Dict interface {
+	Query(ctx context.Context, query string) ([]*lemma.Lemma, error)
+}
+
+// Lookup queries dict with query and if dict doesn't have exactly this word, queries
+// dict with deinflected candidates one by one. It returns lemmas for the first candidate (in order of Deinflect)
+// that has match in dict, and this candidate, the rest of candidates are not queried. At most maxQueriedTerms
+// distinct terms are queried. If no candidate matched, lemmas for original query are returned without candidate.
+func Lookup(ctx context.Context, dict Dict, query string) ([]*lemma.Lemma, *Candidate, error) {
+	lemmas, err := dict.Query(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	candidates := Deinflect(query)
+	original := &candidates[0]
+	for _, l := range lemmas {
+		if original.Match(l) {
+			return lemmas, nil, nil
+		}
+	}
+	// candidates are queried one by one in order of Deinflect (shorter rule chains first),
+	// so usually only a few requests are made. Different rule chains can produce the same term.
+	queried := map[string][]*lemma.Lemma{}
+	for i := 1; i < len(candidates); i++ {
+		candidate := &candidates[i]
+		termLemmas, ok := queried[candidate.Term]
+		if !ok {
+			if len(queried) == maxQueriedTerms {
+				break
+			}
+			// candidates are only guesses, so failed requests are not errors
+			// unless dictionary is unavailable at all
+			termLemmas, err = dict.Query(ctx, candidate.Term)
+			if errors.Is(err, ErrDictUnavailable) {
+				return nil, nil, err
+			}
+			if err := ctx.Err(); err != nil {
+				return nil, nil, err
+			}
+			queried[candidate.Term] = termLemmas
+		}
+		var matched []*lemma.Lemma
+		for _, l := range termLemmas {
+			if candidate.Match(l) {
+				matched = append(matched, l)
+			}
+		}
+		if len(matched) != 0 {
+			return matched, candidate, nil
+		}
+	}
+	return lemmas, nil, nil
+}
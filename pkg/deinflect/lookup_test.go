@@ -0,0 +1,188 @@
+package deinflect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+type DictTest map[string][]*lemma.Lemma
+
+func (d DictTest) Query(ctx context.Context, query string) ([]*lemma.Lemma, error) {
+	if query == "error" {
+		return nil, errors.New("dict error")
+	}
+	return d[query], nil
+}
+
+func Test_Lookup(t *testing.T) {
+	taberu := &lemma.Lemma{
+		Slug: lemma.Word{Word: "食べる", Hiragana: "たべる"},
+		Senses: []lemma.WordSense{
+			{PartOfSpeech: []string{"Ichidan verb"}},
+		},
+	}
+	takai := &lemma.Lemma{
+		Slug: lemma.Word{Word: "高い", Hiragana: "たかい"},
+		Senses: []lemma.WordSense{
+			{PartOfSpeech: []string{"I-adjective (keiyoushi)"}},
+		},
+	}
+	fuzzy := &lemma.Lemma{
+		Slug: lemma.Word{Word: "高く", Hiragana: "たかく"},
+	}
+	dict := DictTest{
+		"食べる":  {taberu},
+		"たべる":  {taberu},
+		"高い":   {takai},
+		"高くて":  {fuzzy},
+		"食べない": nil,
+	}
+	testCases := []struct {
+		Name                 string
+		Query                string
+		ExpectedLemmas       []*lemma.Lemma
+		ExpectedDeinflection *Candidate
+		ErrorAssert          require.ErrorAssertionFunc
+	}{
+		{
+			Name:           "exact",
+			Query:          "食べる",
+			ExpectedLemmas: []*lemma.Lemma{taberu},
+			ErrorAssert:    require.NoError,
+		},
+		{
+			Name:           "deinflected",
+			Query:          "食べられなかった",
+			ExpectedLemmas: []*lemma.Lemma{taberu},
+			ExpectedDeinflection: &Candidate{
+				Term:  "食べる",
+				Rules: []string{"past", "negative", "potential or passive"},
+				types: typeV1,
+			},
+			ErrorAssert: require.NoError,
+		},
+		{
+			Name:           "original result without exact match",
+			Query:          "高くて",
+			ExpectedLemmas: []*lemma.Lemma{takai},
+			ExpectedDeinflection: &Candidate{
+				Term:  "高い",
+				Rules: []string{"-te"},
+				types: typeAdjI,
+			},
+			ErrorAssert: require.NoError,
+		},
+		{
+			Name:           "nothing found",
+			Query:          "dog",
+			ExpectedLemmas: nil,
+			ErrorAssert:    require.NoError,
+		},
+		{
+			Name:        "error",
+			Query:       "error",
+			ErrorAssert: require.Error,
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			lemmas, deinflection, err := Lookup(context.Background(), dict, tc.Query)
+			tc.ErrorAssert(t, err)
+			assert.Equal(t, tc.ExpectedLemmas, lemmas)
+			assert.Equal(t, tc.ExpectedDeinflection, deinflection)
+		})
+	}
+}
+
+// countingDict remembers queries in order
+type countingDict struct {
+	DictTest
+	queries []string
+}
+
+func (d *countingDict) Query(ctx context.Context, query string) ([]*lemma.Lemma, error) {
+	d.queries = append(d.queries, query)
+	return d.DictTest.Query(ctx, query)
+}
+
+func Test_Lookup_StopsAtFirstMatch(t *testing.T) {
+	taberu := &lemma.Lemma{
+		Slug: lemma.Word{Word: "食べる", Hiragana: "たべる"},
+		Senses: []lemma.WordSense{
+			{PartOfSpeech: []string{"Ichidan verb"}},
+		},
+	}
+	dict := &countingDict{
+		DictTest: DictTest{
+			"食べる": {taberu},
+		},
+	}
+	const query = "食べられなかった"
+	lemmas, candidate, err := Lookup(context.Background(), dict, query)
+	require.NoError(t, err)
+	assert.Equal(t, []*lemma.Lemma{taberu}, lemmas)
+	require.NotNil(t, candidate)
+
+	// terms are queried in order of candidates without repeats and only until the first match
+	var expected []string
+	seen := map[string]bool{}
+	for _, c := range Deinflect(query) {
+		if seen[c.Term] {
+			continue
+		}
+		seen[c.Term] = true
+		if len(expected) == 0 || expected[len(expected)-1] != candidate.Term {
+			expected = append(expected, c.Term)
+		}
+	}
+	assert.Equal(t, expected, dict.queries)
+	assert.Less(t, len(dict.queries), len(seen))
+}
+
+func Test_Lookup_MaxQueriedTerms(t *testing.T) {
+	dict := &countingDict{
+		DictTest: DictTest{},
+	}
+	const query = "食べさせられなかった"
+	terms := map[string]bool{}
+	for _, c := range Deinflect(query)[1:] {
+		terms[c.Term] = true
+	}
+	require.Greater(t, len(terms), maxQueriedTerms)
+	lemmas, candidate, err := Lookup(context.Background(), dict, query)
+	require.NoError(t, err)
+	assert.Empty(t, lemmas)
+	assert.Nil(t, candidate)
+	// original query and limited number of candidates
+	assert.Len(t, dict.queries, 1+maxQueriedTerms)
+}
+
+// unavailableDict answers only for original query, every other query fails like all its sources are down
+type unavailableDict struct {
+	countingDict
+	original string
+}
+
+func (d *unavailableDict) Query(ctx context.Context, query string) ([]*lemma.Lemma, error) {
+	d.queries = append(d.queries, query)
+	if query != d.original {
+		return nil, fmt.Errorf("all sources failed: %w", ErrDictUnavailable)
+	}
+	return nil, nil
+}
+
+func Test_Lookup_DictUnavailable(t *testing.T) {
+	dict := &unavailableDict{original: "食べられなかった"}
+	_, _, err := Lookup(context.Background(), dict, dict.original)
+	assert.ErrorIs(t, err, ErrDictUnavailable)
+	// the first candidate failed, so the rest are not queried
+	assert.Len(t, dict.queries, 2)
+}
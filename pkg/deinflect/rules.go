@@ -0,0 +1,248 @@
+package deinflect
+
+// wordType is bitmask of word types, that rule can be applied to or that rule produces.
+type wordType uint
+
+const (
+	// typeV1 is ichidan verb
+	typeV1 wordType = 1 << iota
+	// typeV5 is godan verb
+	typeV5
+	// typeVS is suru verb
+	typeVS
+	// typeVK is kuru verb
+	typeVK
+	// typeAdjI is i-adjective, also negative and desiderative forms conjugate as i-adjective.
+	typeAdjI
+	// typeIru is te-form followed by iru, it's special type used by -te rule
+	typeIru
+)
+
+// variant is single suffix transformation of rule.
+type variant struct {
+	// KanaIn is suffix of inflected word
+	KanaIn string
+	// KanaOut replaces KanaIn
+	KanaOut string
+	// TypesIn is types of inflected word the variant can be applied to.
+	// Zero means that variant can be applied only to original query.
+	TypesIn wordType
+	// TypesOut is types of resulting word
+	TypesOut wordType
+}
+
+type rule struct {
+	Name     string
+	Variants []variant
+}
+
+// godanEnding is ending of godan verb with kana of other rows that are used in
+// conjugations. It's used to generate variants for all godan verbs.
+type godanEnding struct {
+	U string
+	A string
+	I string
+	E string
+	O string
+	// Past is ending of past form (た or だ is not included)
+	Past string
+	// Voiced is true if past and te forms use voiced だ and で
+	Voiced bool
+}
+
+var godanEndings = []godanEnding{
+	{U: "う", A: "わ", I: "い", E: "え", O: "お", Past: "っ"},
+	{U: "く", A: "か", I: "き", E: "け", O: "こ", Past: "い"},
+	{U: "ぐ", A: "が", I: "ぎ", E: "げ", O: "ご", Past: "い", Voiced: true},
+	{U: "す", A: "さ", I: "し", E: "せ", O: "そ", Past: "し"},
+	{U: "つ", A: "た", I: "ち", E: "て", O: "と", Past: "っ"},
+	{U: "ぬ", A: "な", I: "に", E: "ね", O: "の", Past: "ん", Voiced: true},
+	{U: "ぶ", A: "ば", I: "び", E: "べ", O: "ぼ", Past: "ん", Voiced: true},
+	{U: "む", A: "ま", I: "み", E: "め", O: "も", Past: "ん", Voiced: true},
+	{U: "る", A: "ら", I: "り", E: "れ", O: "ろ", Past: "っ"},
+}
+
+// godanVariants generates variants for every godan ending, suffix returns
+// kanaIn for specified ending.
+func godanVariants(typesIn, typesOut wordType, suffix func(e godanEnding) string) []variant {
+	variants := make([]variant, 0, len(godanEndings))
+	for _, ending := range godanEndings {
+		variants = append(variants, variant{
+			KanaIn:   suffix(ending),
+			KanaOut:  ending.U,
+			TypesIn:  typesIn,
+			TypesOut: typesOut,
+		})
+	}
+	return variants
+}
+
+// stemRule returns rule for suffix that is attached to masu stem (食べ, 書き, し, き).
+func stemRule(name string, suffix string, typesIn wordType) rule {
+	variants := []variant{
+		{KanaIn: suffix, KanaOut: "る", TypesIn: typesIn, TypesOut: typeV1},
+		{KanaIn: "し" + suffix, KanaOut: "する", TypesIn: typesIn, TypesOut: typeVS},
+		{KanaIn: "き" + suffix, KanaOut: "くる", TypesIn: typesIn, TypesOut: typeVK},
+		{KanaIn: "来" + suffix, KanaOut: "来る", TypesIn: typesIn, TypesOut: typeVK},
+	}
+	variants = append(variants, godanVariants(typesIn, typeV5, func(e godanEnding) string {
+		return e.I + suffix
+	})...)
+	return rule{Name: name, Variants: variants}
+}
+
+// pastRule returns rule for past (た) and te (て) forms, voiced is voiced variant of suffix.
+func pastRule(name string, suffix string, voiced string, typesIn wordType, adjective string) rule {
+	variants := []variant{
+		{KanaIn: suffix, KanaOut: "る", TypesIn: typesIn, TypesOut: typeV1},
+		{KanaIn: "し" + suffix, KanaOut: "する", TypesIn: typesIn, TypesOut: typeVS},
+		{KanaIn: "き" + suffix, KanaOut: "くる", TypesIn: typesIn, TypesOut: typeVK},
+		{KanaIn: "来" + suffix, KanaOut: "来る", TypesIn: typesIn, TypesOut: typeVK},
+		// 行く is irregular
+		{KanaIn: "行っ" + suffix, KanaOut: "行く", TypesIn: typesIn, TypesOut: typeV5},
+		{KanaIn: "いっ" + suffix, KanaOut: "いく", TypesIn: typesIn, TypesOut: typeV5},
+		{KanaIn: adjective, KanaOut: "い", TypesIn: typesIn, TypesOut: typeAdjI},
+	}
+	variants = append(variants, godanVariants(typesIn, typeV5, func(e godanEnding) string {
+		if e.Voiced {
+			return e.Past + voiced
+		}
+		return e.Past + suffix
+	})...)
+	return rule{Name: name, Variants: variants}
+}
+
+// rules are similar to rules of Yomichan deinflector
+// (https://github.com/FooSoft/yomichan/blob/master/ext/data/deinflect.json).
+var rules = []rule{
+	pastRule("past", "た", "だ", 0, "かった"),
+	pastRule("-te", "て", "で", typeIru, "くて"),
+	{
+		Name: "negative",
+		Variants: append([]variant{
+			{KanaIn: "ない", KanaOut: "る", TypesIn: typeAdjI, TypesOut: typeV1},
+			{KanaIn: "しない", KanaOut: "する", TypesIn: typeAdjI, TypesOut: typeVS},
+			{KanaIn: "こない", KanaOut: "くる", TypesIn: typeAdjI, TypesOut: typeVK},
+			{KanaIn: "来ない", KanaOut: "来る", TypesIn: typeAdjI, TypesOut: typeVK},
+		}, godanVariants(typeAdjI, typeV5, func(e godanEnding) string {
+			return e.A + "ない"
+		})...),
+	},
+	stemRule("polite", "ます", 0),
+	stemRule("polite negative", "ません", 0),
+	stemRule("polite past", "ました", 0),
+	stemRule("polite past negative", "ませんでした", 0),
+	stemRule("polite volitional", "ましょう", 0),
+	stemRule("-tai", "たい", typeAdjI),
+	{
+		Name: "potential or passive",
+		Variants: []variant{
+			{KanaIn: "られる", KanaOut: "る", TypesIn: typeV1, TypesOut: typeV1},
+			{KanaIn: "こられる", KanaOut: "くる", TypesIn: typeV1, TypesOut: typeVK},
+			{KanaIn: "来られる", KanaOut: "来る", TypesIn: typeV1, TypesOut: typeVK},
+		},
+	},
+	{
+		Name: "potential",
+		Variants: append([]variant{
+			{KanaIn: "これる", KanaOut: "くる", TypesIn: typeV1, TypesOut: typeVK},
+			{KanaIn: "来れる", KanaOut: "来る", TypesIn: typeV1, TypesOut: typeVK},
+			{KanaIn: "できる", KanaOut: "する", TypesIn: typeV1, TypesOut: typeVS},
+		}, godanVariants(typeV1, typeV5, func(e godanEnding) string {
+			return e.E + "る"
+		})...),
+	},
+	{
+		Name: "passive",
+		Variants: append([]variant{
+			{KanaIn: "される", KanaOut: "する", TypesIn: typeV1, TypesOut: typeVS},
+		}, godanVariants(typeV1, typeV5, func(e godanEnding) string {
+			return e.A + "れる"
+		})...),
+	},
+	{
+		Name: "causative",
+		Variants: append([]variant{
+			{KanaIn: "させる", KanaOut: "る", TypesIn: typeV1, TypesOut: typeV1},
+			{KanaIn: "させる", KanaOut: "する", TypesIn: typeV1, TypesOut: typeVS},
+			{KanaIn: "こさせる", KanaOut: "くる", TypesIn: typeV1, TypesOut: typeVK},
+			{KanaIn: "来させる", KanaOut: "来る", TypesIn: typeV1, TypesOut: typeVK},
+		}, godanVariants(typeV1, typeV5, func(e godanEnding) string {
+			return e.A + "せる"
+		})...),
+	},
+	{
+		Name: "volitional",
+		Variants: append([]variant{
+			{KanaIn: "よう", KanaOut: "る", TypesOut: typeV1},
+			{KanaIn: "しよう", KanaOut: "する", TypesOut: typeVS},
+			{KanaIn: "こよう", KanaOut: "くる", TypesOut: typeVK},
+			{KanaIn: "来よう", KanaOut: "来る", TypesOut: typeVK},
+		}, godanVariants(0, typeV5, func(e godanEnding) string {
+			return e.O + "う"
+		})...),
+	},
+	{
+		Name: "imperative",
+		Variants: append([]variant{
+			{KanaIn: "ろ", KanaOut: "る", TypesOut: typeV1},
+			{KanaIn: "よ", KanaOut: "る", TypesOut: typeV1},
+			{KanaIn: "しろ", KanaOut: "する", TypesOut: typeVS},
+			{KanaIn: "せよ", KanaOut: "する", TypesOut: typeVS},
+			{KanaIn: "こい", KanaOut: "くる", TypesOut: typeVK},
+			{KanaIn: "来い", KanaOut: "来る", TypesOut: typeVK},
+		}, godanVariants(0, typeV5, func(e godanEnding) string {
+			return e.E
+		})...),
+	},
+	{
+		Name: "-ba",
+		Variants: append([]variant{
+			{KanaIn: "れば", KanaOut: "る", TypesOut: typeV1},
+			{KanaIn: "すれば", KanaOut: "する", TypesOut: typeVS},
+			{KanaIn: "くれば", KanaOut: "くる", TypesOut: typeVK},
+			{KanaIn: "来れば", KanaOut: "来る", TypesOut: typeVK},
+			{KanaIn: "ければ", KanaOut: "い", TypesOut: typeAdjI},
+		}, godanVariants(0, typeV5, func(e godanEnding) string {
+			return e.E + "ば"
+		})...),
+	},
+	{
+		Name: "-tara",
+		Variants: append([]variant{
+			{KanaIn: "たら", KanaOut: "る", TypesOut: typeV1},
+			{KanaIn: "したら", KanaOut: "する", TypesOut: typeVS},
+			{KanaIn: "きたら", KanaOut: "くる", TypesOut: typeVK},
+			{KanaIn: "来たら", KanaOut: "来る", TypesOut: typeVK},
+			{KanaIn: "かったら", KanaOut: "い", TypesOut: typeAdjI},
+		}, godanVariants(0, typeV5, func(e godanEnding) string {
+			if e.Voiced {
+				return e.Past + "だら"
+			}
+			return e.Past + "たら"
+		})...),
+	},
+	{
+		Name: "progressive or perfect",
+		Variants: []variant{
+			{KanaIn: "ている", KanaOut: "て", TypesIn: typeV1, TypesOut: typeIru},
+			{KanaIn: "ておる", KanaOut: "て", TypesIn: typeV5, TypesOut: typeIru},
+			{KanaIn: "てる", KanaOut: "て", TypesIn: typeV1, TypesOut: typeIru},
+			{KanaIn: "でいる", KanaOut: "で", TypesIn: typeV1, TypesOut: typeIru},
+			{KanaIn: "でおる", KanaOut: "で", TypesIn: typeV5, TypesOut: typeIru},
+			{KanaIn: "でる", KanaOut: "で", TypesIn: typeV1, TypesOut: typeIru},
+		},
+	},
+	{
+		Name: "adv",
+		Variants: []variant{
+			{KanaIn: "く", KanaOut: "い", TypesOut: typeAdjI},
+		},
+	},
+	{
+		Name: "noun",
+		Variants: []variant{
+			{KanaIn: "さ", KanaOut: "い", TypesOut: typeAdjI},
+		},
+	},
+}
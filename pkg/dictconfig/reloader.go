@@ -0,0 +1,191 @@
+// dictconfig allows to change dictionary part of user config without restart.
+// It contains reloaders for components of dictionary stack and Updater
+// that validates and applies changes made by user.
+package dictconfig
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/Darkclainer/japwords/pkg/config"
+	"github.com/Darkclainer/japwords/pkg/fetcher"
+	"github.com/Darkclainer/japwords/pkg/multidict"
+)
+
+// FetcherReloader reloads headers, timeout, retries and limits of fetcher.
+type FetcherReloader struct {
+	fetcher *fetcher.Fetcher
+}
+
+func NewFetcherReloader(f *fetcher.Fetcher) *FetcherReloader {
+	return &FetcherReloader{
+		fetcher: f,
+	}
+}
+
+// Config is implementation of config.Consumer interface.
+func (r *FetcherReloader) Config(uc *config.UserConfig) (config.Part, error) {
+	return FetcherConfig(&uc.Dictionary)
+}
+
+// Reload is implementation of config.Reloader interface.
+func (r *FetcherReloader) Reload(o config.Part) error {
+	conf, ok := o.(*fetcher.Config)
+	if !ok {
+		panic("unreachable")
+	}
+	return r.fetcher.ReloadConfig(conf)
+}
+
+// FetcherConfig converts dictionary config to fetcher config.
+func FetcherConfig(dictionary *config.Dictionary) (*fetcher.Config, error) {
+	if err := validateHeaders(dictionary.Headers); err != nil {
+		return nil, err
+	}
+	requests := &dictionary.Requests
+	if err := validateRequests(requests); err != nil {
+		return nil, err
+	}
+	conf := &fetcher.Config{
+		Headers:       maps.Clone(dictionary.Headers),
+		Retries:       requests.Retries,
+		RateLimit:     requests.RateLimit,
+		RateBurst:     requests.RateBurst,
+		MaxConcurrent: requests.MaxConcurrent,
+	}
+	if conf.Headers == nil {
+		conf.Headers = make(map[string]string)
+	}
+	if dictionary.UserAgent != "" {
+		conf.Headers["User-Agent"] = dictionary.UserAgent
+	}
+	// errors are already checked by validateRequests
+	conf.Timeout, _ = parseDuration("timeout", requests.Timeout)
+	conf.RetryBaseDelay, _ = parseDuration("retry base delay", requests.RetryBaseDelay)
+	conf.RetryMaxDelay, _ = parseDuration("retry max delay", requests.RetryMaxDelay)
+	return conf, nil
+}
+
+type MultiDictConfig struct {
+	Workers     int
+	MergePolicy multidict.MergePolicy
+	Timeouts    multidict.Timeouts
+}
+
+func (c *MultiDictConfig) Equal(o any) bool {
+	oc, ok := o.(*MultiDictConfig)
+	if !ok {
+		return false
+	}
+	return *c == *oc
+}
+
+// MultiDictReloader reloads number of workers, merge policy and timeouts of multidict.
+type MultiDictReloader struct {
+	dict *multidict.MultiDict
+}
+
+func NewMultiDictReloader(dict *multidict.MultiDict) *MultiDictReloader {
+	return &MultiDictReloader{
+		dict: dict,
+	}
+}
+
+// Config is implementation of config.Consumer interface.
+func (r *MultiDictReloader) Config(uc *config.UserConfig) (config.Part, error) {
+	if err := validateWorkers(uc.Dictionary.Workers); err != nil {
+		return nil, err
+	}
+	mergePolicy, err := validateMergePolicy(uc.Dictionary.MergePolicy)
+	if err != nil {
+		return nil, err
+	}
+	timeouts, err := validateTimeouts(uc.Dictionary.Timeout, uc.Dictionary.SourceTimeout, uc.Dictionary.SoftPitch)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiDictConfig{
+		Workers:     uc.Dictionary.Workers,
+		MergePolicy: mergePolicy,
+		Timeouts:    timeouts,
+	}, nil
+}
+
+// Reload is implementation of config.Reloader interface.
+func (r *MultiDictReloader) Reload(o config.Part) error {
+	conf, ok := o.(*MultiDictConfig)
+	if !ok {
+		panic("unreachable")
+	}
+	if err := r.dict.SetWorkers(conf.Workers); err != nil {
+		return err
+	}
+	r.dict.SetMergePolicy(conf.MergePolicy)
+	r.dict.SetTimeouts(conf.Timeouts)
+	return nil
+}
+
+type BaseURLConfig struct {
+	URL string
+}
+
+func (c *BaseURLConfig) Equal(o any) bool {
+	oc, ok := o.(*BaseURLConfig)
+	if !ok {
+		return false
+	}
+	return c.URL == oc.URL
+}
+
+// BaseURLSetter is dictionary that queries site with configurable url, like jisho or wadoku.
+type BaseURLSetter interface {
+	SetBaseURL(baseURL string)
+}
+
+// BaseURLReloader reloads url of online dictionary.
+type BaseURLReloader struct {
+	name   string
+	dict   BaseURLSetter
+	getURL func(*config.Dictionary) string
+}
+
+func NewJishoReloader(dict BaseURLSetter) *BaseURLReloader {
+	return &BaseURLReloader{
+		name: "jisho",
+		dict: dict,
+		getURL: func(d *config.Dictionary) string {
+			return d.Jisho.URL
+		},
+	}
+}
+
+func NewWadokuReloader(dict BaseURLSetter) *BaseURLReloader {
+	return &BaseURLReloader{
+		name: "wadoku",
+		dict: dict,
+		getURL: func(d *config.Dictionary) string {
+			return d.Wadoku.URL
+		},
+	}
+}
+
+// Config is implementation of config.Consumer interface.
+func (r *BaseURLReloader) Config(uc *config.UserConfig) (config.Part, error) {
+	baseURL := r.getURL(&uc.Dictionary)
+	if err := validateBaseURL(fmt.Sprintf("%s url", r.name), baseURL); err != nil {
+		return nil, err
+	}
+	return &BaseURLConfig{
+		URL: baseURL,
+	}, nil
+}
+
+// Reload is implementation of config.Reloader interface.
+func (r *BaseURLReloader) Reload(o config.Part) error {
+	conf, ok := o.(*BaseURLConfig)
+	if !ok {
+		panic("unreachable")
+	}
+	r.dict.SetBaseURL(conf.URL)
+	return nil
+}
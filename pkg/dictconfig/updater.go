@@ -0,0 +1,88 @@
+package dictconfig
+
+import (
+	"maps"
+
+	"github.com/Darkclainer/japwords/pkg/config"
+)
+
+// Updater validates and applies changes of dictionary config.
+// Changes are reloaded by registered reloaders and saved to config file.
+type Updater struct {
+	configManager *config.Manager
+}
+
+func NewUpdater(configManager *config.Manager) *Updater {
+	return &Updater{
+		configManager: configManager,
+	}
+}
+
+// Current returns copy of current dictionary config.
+func (u *Updater) Current() *config.Dictionary {
+	return &u.configManager.Current().Dictionary
+}
+
+func (u *Updater) UpdateHeaders(userAgent string, headers map[string]string) error {
+	if err := validateHeaders(headers); err != nil {
+		return err
+	}
+	if err := validateHeaders(map[string]string{"User-Agent": userAgent}); err != nil {
+		return err
+	}
+	return u.configManager.UpdateConfig(func(uc *config.UserConfig) error {
+		uc.Dictionary.UserAgent = userAgent
+		uc.Dictionary.Headers = maps.Clone(headers)
+		return nil
+	})
+}
+
+func (u *Updater) UpdateWorkers(workers int, mergePolicy string) error {
+	if err := validateWorkers(workers); err != nil {
+		return err
+	}
+	if _, err := validateMergePolicy(mergePolicy); err != nil {
+		return err
+	}
+	return u.configManager.UpdateConfig(func(uc *config.UserConfig) error {
+		uc.Dictionary.Workers = workers
+		uc.Dictionary.MergePolicy = mergePolicy
+		return nil
+	})
+}
+
+func (u *Updater) UpdateTimeouts(timeout string, sourceTimeout string, softPitch bool) error {
+	if _, err := validateTimeouts(timeout, sourceTimeout, softPitch); err != nil {
+		return err
+	}
+	return u.configManager.UpdateConfig(func(uc *config.UserConfig) error {
+		uc.Dictionary.Timeout = timeout
+		uc.Dictionary.SourceTimeout = sourceTimeout
+		uc.Dictionary.SoftPitch = softPitch
+		return nil
+	})
+}
+
+func (u *Updater) UpdateURLs(jishoURL string, wadokuURL string) error {
+	if err := validateBaseURL("jisho url", jishoURL); err != nil {
+		return err
+	}
+	if err := validateBaseURL("wadoku url", wadokuURL); err != nil {
+		return err
+	}
+	return u.configManager.UpdateConfig(func(uc *config.UserConfig) error {
+		uc.Dictionary.Jisho.URL = jishoURL
+		uc.Dictionary.Wadoku.URL = wadokuURL
+		return nil
+	})
+}
+
+func (u *Updater) UpdateRequests(requests *config.Requests) error {
+	if err := validateRequests(requests); err != nil {
+		return err
+	}
+	return u.configManager.UpdateConfig(func(uc *config.UserConfig) error {
+		uc.Dictionary.Requests = *requests
+		return nil
+	})
+}
@@ -0,0 +1,193 @@
+package dictconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Darkclainer/japwords/pkg/config"
+	"github.com/Darkclainer/japwords/pkg/config/configtest"
+	"github.com/Darkclainer/japwords/pkg/fetcher"
+	"github.com/Darkclainer/japwords/pkg/multidict"
+)
+
+type fakeBaseURLSetter struct {
+	urls []string
+}
+
+func (s *fakeBaseURLSetter) SetBaseURL(baseURL string) {
+	s.urls = append(s.urls, baseURL)
+}
+
+type testUpdater struct {
+	*Updater
+	configManager *config.Manager
+	jisho         *fakeBaseURLSetter
+	wadoku        *fakeBaseURLSetter
+}
+
+func newTestUpdater(t *testing.T) *testUpdater {
+	userConfig := config.DefaultUserConfig()
+	userConfig.Dictionary.Jisho.URL = "https://jisho.test/"
+	configManager := configtest.New(t, userConfig)
+	f, err := fetcher.New(&fetcher.Config{})
+	require.NoError(t, err)
+	dict, err := multidict.New(&multidict.Options{})
+	require.NoError(t, err)
+	jisho := &fakeBaseURLSetter{}
+	wadoku := &fakeBaseURLSetter{}
+	for _, reloader := range []config.Reloader{
+		NewFetcherReloader(f),
+		NewMultiDictReloader(dict),
+		NewJishoReloader(jisho),
+		NewWadokuReloader(wadoku),
+	} {
+		_, _, err := configManager.Register(reloader)
+		require.NoError(t, err)
+	}
+	return &testUpdater{
+		Updater:       NewUpdater(configManager),
+		configManager: configManager,
+		jisho:         jisho,
+		wadoku:        wadoku,
+	}
+}
+
+func Test_Updater_UpdateURLs(t *testing.T) {
+	updater := newTestUpdater(t)
+	assert.Equal(t, []string{"https://jisho.test/"}, updater.jisho.urls)
+	assert.Equal(t, []string{""}, updater.wadoku.urls)
+
+	err := updater.UpdateURLs("https://jisho.test/", "http://wadoku.test/")
+	require.NoError(t, err)
+	// jisho url is not changed, so it should not be reloaded
+	assert.Equal(t, []string{"https://jisho.test/"}, updater.jisho.urls)
+	assert.Equal(t, []string{"", "http://wadoku.test/"}, updater.wadoku.urls)
+	assert.Equal(t, "http://wadoku.test/", updater.Current().Wadoku.URL)
+
+	err = updater.UpdateURLs("jisho", "")
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "http://wadoku.test/", updater.Current().Wadoku.URL)
+	assert.Len(t, updater.wadoku.urls, 2)
+}
+
+func Test_Updater_Update(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Update   func(*Updater) error
+		Expected func(*config.Dictionary)
+		Error    bool
+	}{
+		{
+			Name: "headers",
+			Update: func(u *Updater) error {
+				return u.UpdateHeaders("myagent", map[string]string{"X-Custom": "value"})
+			},
+			Expected: func(d *config.Dictionary) {
+				d.UserAgent = "myagent"
+				d.Headers = map[string]string{"X-Custom": "value"}
+			},
+		},
+		{
+			Name: "invalid headers",
+			Update: func(u *Updater) error {
+				return u.UpdateHeaders("myagent", map[string]string{"X Custom": "value"})
+			},
+			Error: true,
+		},
+		{
+			Name: "invalid user agent",
+			Update: func(u *Updater) error {
+				return u.UpdateHeaders("myagent\n", nil)
+			},
+			Error: true,
+		},
+		{
+			Name: "workers",
+			Update: func(u *Updater) error {
+				return u.UpdateWorkers(8, "union")
+			},
+			Expected: func(d *config.Dictionary) {
+				d.Workers = 8
+				d.MergePolicy = "union"
+			},
+		},
+		{
+			Name: "negative workers",
+			Update: func(u *Updater) error {
+				return u.UpdateWorkers(-1, "union")
+			},
+			Error: true,
+		},
+		{
+			Name: "unknown merge policy",
+			Update: func(u *Updater) error {
+				return u.UpdateWorkers(1, "random")
+			},
+			Error: true,
+		},
+		{
+			Name: "timeouts",
+			Update: func(u *Updater) error {
+				return u.UpdateTimeouts("1m", "10s", false)
+			},
+			Expected: func(d *config.Dictionary) {
+				d.Timeout = "1m"
+				d.SourceTimeout = "10s"
+				d.SoftPitch = false
+			},
+		},
+		{
+			Name: "source timeout greater than timeout",
+			Update: func(u *Updater) error {
+				return u.UpdateTimeouts("10s", "1m", true)
+			},
+			Error: true,
+		},
+		{
+			Name: "requests",
+			Update: func(u *Updater) error {
+				return u.UpdateRequests(&config.Requests{
+					Timeout: "1m",
+					Retries: 1,
+				})
+			},
+			Expected: func(d *config.Dictionary) {
+				d.Requests = config.Requests{
+					Timeout: "1m",
+					Retries: 1,
+				}
+			},
+		},
+		{
+			Name: "invalid requests",
+			Update: func(u *Updater) error {
+				return u.UpdateRequests(&config.Requests{
+					Timeout: "1 minute",
+				})
+			},
+			Error: true,
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			updater := newTestUpdater(t)
+			expected := updater.Current()
+			err := tc.Update(updater.Updater)
+			if tc.Error {
+				var validationErr *ValidationError
+				require.ErrorAs(t, err, &validationErr)
+			} else {
+				require.NoError(t, err)
+				tc.Expected(expected)
+			}
+			assert.Equal(t, expected, updater.Current())
+			saved, err := config.LoadConfig(updater.configManager.ResolvePath("config.yaml"))
+			require.NoError(t, err)
+			assert.Equal(t, expected, &saved.Dictionary)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package dictconfig
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Darkclainer/japwords/pkg/config"
+	"github.com/Darkclainer/japwords/pkg/multidict"
+)
+
+// maxWorkers is arbitrary limit, there is no point to have more workers than dictionaries by much
+const maxWorkers = 64
+
+// ValidationError is a wrapper for error to make possible to distinguish this error in API layer.
+type ValidationError struct {
+	Msg string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Msg
+}
+
+func validationErrorf(format string, args ...any) error {
+	return &ValidationError{Msg: fmt.Sprintf(format, args...)}
+}
+
+// parseDuration parses non negative duration in go format, empty string is zero duration.
+func parseDuration(name string, src string) (time.Duration, error) {
+	if src == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(src)
+	if err != nil {
+		return 0, validationErrorf("%s is invalid: %s", name, err)
+	}
+	if duration < 0 {
+		return 0, validationErrorf("%s should not be negative", name)
+	}
+	return duration, nil
+}
+
+// validateBaseURL checks that baseURL is empty (default url is used) or absolute http url.
+func validateBaseURL(name string, baseURL string) error {
+	if baseURL == "" {
+		return nil
+	}
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return validationErrorf("%s is invalid: %s", name, err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" || parsed.Host == "" {
+		return validationErrorf("%s should be absolute http or https url", name)
+	}
+	return nil
+}
+
+func validateWorkers(workers int) error {
+	if workers < 0 || workers > maxWorkers {
+		return validationErrorf("workers should be between 0 and %d", maxWorkers)
+	}
+	return nil
+}
+
+func validateMergePolicy(policy string) (multidict.MergePolicy, error) {
+	mergePolicy, err := multidict.ParseMergePolicy(policy)
+	if err != nil {
+		return mergePolicy, &ValidationError{Msg: err.Error()}
+	}
+	return mergePolicy, nil
+}
+
+// validateHeaders checks that header names are valid http tokens
+// and that values don't contain line breaks.
+func validateHeaders(headers map[string]string) error {
+	var errs []error
+	for name, value := range headers {
+		if name == "" || strings.IndexFunc(name, func(r rune) bool { return !isTokenRune(r) }) >= 0 {
+			errs = append(errs, fmt.Errorf("header name %q is invalid", name))
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			errs = append(errs, fmt.Errorf("value of header %q contains line break", name))
+		}
+	}
+	if len(errs) != 0 {
+		return &ValidationError{Msg: errors.Join(errs...).Error()}
+	}
+	return nil
+}
+
+// isTokenRune reports whether r can be used in http token (RFC 7230).
+func isTokenRune(r rune) bool {
+	if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+		return true
+	}
+	return strings.ContainsRune("!#$%&'*+-.^_`|~", r)
+}
+
+func validateRequests(requests *config.Requests) error {
+	if requests.Retries < 0 {
+		return validationErrorf("retries should not be negative")
+	}
+	if requests.RateLimit < 0 || requests.RateBurst < 0 {
+		return validationErrorf("rate limit and rate burst should not be negative")
+	}
+	if requests.MaxConcurrent < 0 {
+		return validationErrorf("max concurrent should not be negative")
+	}
+	for _, duration := range []struct {
+		Name  string
+		Value string
+	}{
+		{"timeout", requests.Timeout},
+		{"retry base delay", requests.RetryBaseDelay},
+		{"retry max delay", requests.RetryMaxDelay},
+	} {
+		if _, err := parseDuration(duration.Name, duration.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateTimeouts(timeout string, sourceTimeout string, softPitch bool) (multidict.Timeouts, error) {
+	overall, err := parseDuration("timeout", timeout)
+	if err != nil {
+		return multidict.Timeouts{}, err
+	}
+	source, err := parseDuration("source timeout", sourceTimeout)
+	if err != nil {
+		return multidict.Timeouts{}, err
+	}
+	if overall != 0 && source > overall {
+		return multidict.Timeouts{}, validationErrorf("source timeout should not be greater than timeout")
+	}
+	return multidict.Timeouts{
+		Overall:   overall,
+		Source:    source,
+		SoftPitch: softPitch,
+	}, nil
+}
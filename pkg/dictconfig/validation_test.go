@@ -0,0 +1,259 @@
+package dictconfig
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Darkclainer/japwords/pkg/config"
+	"github.com/Darkclainer/japwords/pkg/fetcher"
+	"github.com/Darkclainer/japwords/pkg/multidict"
+)
+
+func Test_validateBaseURL(t *testing.T) {
+	testCases := []struct {
+		Name  string
+		URL   string
+		Error bool
+	}{
+		{
+			Name: "empty",
+		},
+		{
+			Name: "https",
+			URL:  "https://jisho.org/search/",
+		},
+		{
+			Name: "http with port",
+			URL:  "http://localhost:8080/",
+		},
+		{
+			Name:  "relative",
+			URL:   "/search/",
+			Error: true,
+		},
+		{
+			Name:  "wrong scheme",
+			URL:   "ftp://jisho.org/",
+			Error: true,
+		},
+		{
+			Name:  "malformed",
+			URL:   "http://[::1",
+			Error: true,
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			err := validateBaseURL("url", tc.URL)
+			if tc.Error {
+				var validationErr *ValidationError
+				assert.ErrorAs(t, err, &validationErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_validateHeaders(t *testing.T) {
+	testCases := []struct {
+		Name    string
+		Headers map[string]string
+		Error   string
+	}{
+		{
+			Name: "nil",
+		},
+		{
+			Name: "ok",
+			Headers: map[string]string{
+				"X-Custom":        "value",
+				"Accept-Language": "en",
+			},
+		},
+		{
+			Name: "empty name",
+			Headers: map[string]string{
+				"": "value",
+			},
+			Error: `header name "" is invalid`,
+		},
+		{
+			Name: "space in name",
+			Headers: map[string]string{
+				"X Custom": "value",
+			},
+			Error: `header name "X Custom" is invalid`,
+		},
+		{
+			Name: "line break in value",
+			Headers: map[string]string{
+				"X-Custom": "value\r\nX-Other: value",
+			},
+			Error: `value of header "X-Custom" contains line break`,
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			err := validateHeaders(tc.Headers)
+			if tc.Error != "" {
+				assert.ErrorContains(t, err, tc.Error)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_validateRequests(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Requests config.Requests
+		Error    string
+	}{
+		{
+			Name:     "default",
+			Requests: config.DefaultUserConfig().Dictionary.Requests,
+		},
+		{
+			Name: "zero",
+		},
+		{
+			Name: "negative retries",
+			Requests: config.Requests{
+				Retries: -1,
+			},
+			Error: "retries",
+		},
+		{
+			Name: "negative rate",
+			Requests: config.Requests{
+				RateLimit: -1,
+			},
+			Error: "rate limit",
+		},
+		{
+			Name: "invalid timeout",
+			Requests: config.Requests{
+				Timeout: "soon",
+			},
+			Error: "timeout is invalid",
+		},
+		{
+			Name: "negative delay",
+			Requests: config.Requests{
+				RetryMaxDelay: "-1s",
+			},
+			Error: "retry max delay should not be negative",
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			err := validateRequests(&tc.Requests)
+			if tc.Error != "" {
+				assert.ErrorContains(t, err, tc.Error)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_validateTimeouts(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Timeout       string
+		SourceTimeout string
+		Expected      multidict.Timeouts
+		Error         string
+	}{
+		{
+			Name: "empty",
+		},
+		{
+			Name:          "ok",
+			Timeout:       "45s",
+			SourceTimeout: "15s",
+			Expected: multidict.Timeouts{
+				Overall: 45 * time.Second,
+				Source:  15 * time.Second,
+			},
+		},
+		{
+			Name:          "only source",
+			SourceTimeout: "15s",
+			Expected: multidict.Timeouts{
+				Source: 15 * time.Second,
+			},
+		},
+		{
+			Name:    "invalid timeout",
+			Timeout: "soon",
+			Error:   "timeout is invalid",
+		},
+		{
+			Name:          "negative source timeout",
+			SourceTimeout: "-1s",
+			Error:         "source timeout should not be negative",
+		},
+		{
+			Name:          "source greater than overall",
+			Timeout:       "1s",
+			SourceTimeout: "2s",
+			Error:         "source timeout should not be greater than timeout",
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			timeouts, err := validateTimeouts(tc.Timeout, tc.SourceTimeout, false)
+			if tc.Error != "" {
+				assert.ErrorContains(t, err, tc.Error)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.Expected, timeouts)
+			}
+		})
+	}
+}
+
+func Test_FetcherConfig(t *testing.T) {
+	dictionary := config.Dictionary{
+		UserAgent: "myagent",
+		Headers: map[string]string{
+			"X-Custom": "value",
+		},
+		Requests: config.Requests{
+			Timeout:        "5s",
+			Retries:        2,
+			RetryBaseDelay: "100ms",
+			RetryMaxDelay:  "1s",
+			RateLimit:      1.5,
+			RateBurst:      3,
+			MaxConcurrent:  4,
+		},
+	}
+	conf, err := FetcherConfig(&dictionary)
+	require.NoError(t, err)
+	assert.Equal(t, &fetcher.Config{
+		Headers: map[string]string{
+			"X-Custom":   "value",
+			"User-Agent": "myagent",
+		},
+		Timeout:        5 * time.Second,
+		Retries:        2,
+		RetryBaseDelay: 100 * time.Millisecond,
+		RetryMaxDelay:  time.Second,
+		RateLimit:      1.5,
+		RateBurst:      3,
+		MaxConcurrent:  4,
+	}, conf)
+	// user agent should not leak into config
+	assert.Len(t, dictionary.Headers, 1)
+}
@@ -0,0 +1,165 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Darkclainer/japwords/pkg/anki"
+)
+
+// Anki is part of anki.Anki that is used for checks and fixes.
+type Anki interface {
+	FullStateCheck(ctx context.Context) (*anki.StateResult, error)
+	NoteTypeFields(ctx context.Context) ([]string, error)
+	CreateDeck(ctx context.Context, name string) error
+	CreateDefaultNote(ctx context.Context, name string) error
+}
+
+// CheckAnkiConfig reports validation error of anki config returned by anki.NewConfig.
+func CheckAnkiConfig(confErr error) *Check {
+	const name = "Config is valid"
+	if confErr == nil {
+		return passed(name)
+	}
+	return failed(name, "Anki section of config is invalid, notes can't be added until it's fixed:\n%s", confErr)
+}
+
+// CheckAnki runs the same checks as Anki does before adding note. If Anki is unavailable
+// or note type doesn't exist, checks that depend on them are not returned. confErr is validation
+// error of conf, fixes are not offered for invalid values.
+func CheckAnki(ctx context.Context, client Anki, conf *anki.Config, confErr error) *Section {
+	section := &Section{
+		Title:  "Anki",
+		Checks: []*Check{CheckAnkiConfig(confErr)},
+	}
+	const connectionName = "AnkiConnect is available"
+	state, err := client.FullStateCheck(ctx)
+	if err != nil {
+		section.Checks = append(section.Checks, &Check{
+			Name:    connectionName,
+			Problem: explainAnkiError(err, conf),
+		})
+		return section
+	}
+	section.Checks = append(section.Checks, passed(fmt.Sprintf("%s (version %d)", connectionName, state.Version)))
+
+	const deckName = "Deck exists"
+	if state.DeckExists {
+		section.Checks = append(section.Checks, passed(deckName))
+	} else {
+		check := failed(deckName, "Deck %q (anki.deck in config) doesn't exist in Anki, notes can't be added to it.", conf.Deck)
+		if !errors.Is(confErr, anki.ErrConfigDeckInvalid) {
+			check.Fix = &Fix{
+				Description: fmt.Sprintf("create deck %q", conf.Deck),
+				Apply: func(ctx context.Context) error {
+					return client.CreateDeck(ctx, conf.Deck)
+				},
+			}
+		}
+		section.Checks = append(section.Checks, check)
+	}
+
+	const noteTypeName = "Note type exists"
+	if !state.NoteTypeExists {
+		check := failed(noteTypeName, "Note type %q (anki.note-type in config) doesn't exist in Anki.", conf.NoteType)
+		if !errors.Is(confErr, anki.ErrConfigNoteTypeInvalid) {
+			check.Fix = &Fix{
+				Description: fmt.Sprintf("create note type %q with default fields and cards", conf.NoteType),
+				Apply: func(ctx context.Context) error {
+					return client.CreateDefaultNote(ctx, conf.NoteType)
+				},
+			}
+		}
+		section.Checks = append(section.Checks, check)
+		return section
+	}
+	section.Checks = append(section.Checks, passed(noteTypeName))
+
+	fields, err := client.NoteTypeFields(ctx)
+	if err != nil {
+		section.Checks = append(section.Checks, failed("Note type fields are available", "%s", explainAnkiError(err, conf)))
+		return section
+	}
+
+	const allFieldsName = "Note type has all fields from config"
+	if missing := missingFields(fields, conf.Mapping); len(missing) != 0 {
+		section.Checks = append(section.Checks, failed(allFieldsName,
+			"Note type %q doesn't have fields %s that are filled by anki.fields in config.\n"+
+				"Add them to note type in Anki or remove them from config.",
+			conf.NoteType, quoteJoin(missing),
+		))
+	} else {
+		section.Checks = append(section.Checks, passed(allFieldsName))
+	}
+
+	const orderName = "First field of note type is filled"
+	switch {
+	case state.OrderDefined:
+		section.Checks = append(section.Checks, passed(orderName))
+	case len(fields) == 0:
+		section.Checks = append(section.Checks, failed(orderName, "Note type %q doesn't have any fields.", conf.NoteType))
+	default:
+		section.Checks = append(section.Checks, failed(orderName,
+			"The first field of note type %q is %q, Anki uses it to find duplicates and doesn't add notes\n"+
+				"where it's empty, but there is no template for it in anki.fields in config.",
+			conf.NoteType, fields[0],
+		))
+	}
+
+	const audioName = "Audio field exists"
+	if state.AudioFieldExists {
+		section.Checks = append(section.Checks, passed(audioName))
+	} else {
+		section.Checks = append(section.Checks, failed(audioName,
+			"Audio is added to field %q (anki.audio.field in config), but note type %q doesn't have it.\n"+
+				"Add field in Anki, change config or set empty field to disable audio.",
+			conf.AudioField, conf.NoteType,
+		))
+	}
+	return section
+}
+
+// explainAnkiError explains errors of Anki in plain language.
+func explainAnkiError(err error, conf *anki.Config) string {
+	var connectionError *anki.ConnectionError
+	switch {
+	case errors.As(err, &connectionError):
+		return fmt.Sprintf("Can't connect to AnkiConnect at %s (anki.addr in config).\n"+
+			"Make sure that Anki is running and AnkiConnect add-on is installed.\n"+
+			"Details: %s", conf.Addr, connectionError.Msg)
+	case errors.Is(err, anki.ErrForbiddenOrigin):
+		return "AnkiConnect doesn't allow requests from japwords.\n" +
+			"Add address of japwords to webCorsOriginList in AnkiConnect settings."
+	case errors.Is(err, anki.ErrInvalidAPIKey):
+		return "AnkiConnect rejected API key.\n" +
+			"anki.api-key in config should be the same as apiKey in AnkiConnect settings."
+	case errors.Is(err, anki.ErrCollectionUnavailable):
+		return "Anki collection is not available.\n" +
+			"Open your profile in Anki and close dialogs that can block it."
+	default:
+		return err.Error()
+	}
+}
+
+// missingFields returns sorted fields from mapping that are not among fields.
+func missingFields(fields []string, mapping anki.TemplateMapping) []string {
+	var missing []string
+	for field := range mapping {
+		if !slices.Contains(fields, field) {
+			missing = append(missing, field)
+		}
+	}
+	slices.Sort(missing)
+	return missing
+}
+
+func quoteJoin(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("%q", value)
+	}
+	return strings.Join(quoted, ", ")
+}
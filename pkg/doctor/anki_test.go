@@ -0,0 +1,257 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Darkclainer/japwords/pkg/anki"
+)
+
+type testAnki struct {
+	State     *anki.StateResult
+	StateErr  error
+	Fields    []string
+	FieldsErr error
+
+	createdDecks     []string
+	createdNoteTypes []string
+}
+
+func (a *testAnki) FullStateCheck(context.Context) (*anki.StateResult, error) {
+	return a.State, a.StateErr
+}
+
+func (a *testAnki) NoteTypeFields(context.Context) ([]string, error) {
+	return a.Fields, a.FieldsErr
+}
+
+func (a *testAnki) CreateDeck(_ context.Context, name string) error {
+	a.createdDecks = append(a.createdDecks, name)
+	return nil
+}
+
+func (a *testAnki) CreateDefaultNote(_ context.Context, name string) error {
+	a.createdNoteTypes = append(a.createdNoteTypes, name)
+	return nil
+}
+
+func testAnkiConfig() *anki.Config {
+	return &anki.Config{
+		Addr:       "127.0.0.1:8765",
+		Deck:       "Japwords",
+		NoteType:   "JapwordsNote",
+		AudioField: "Audio",
+		Mapping: anki.TemplateMapping{
+			"Kanji":   {Src: "{{ .Slug.Word }}"},
+			"Meaning": {Src: "{{ .Definitions }}"},
+			"Pitch":   {Src: "{{ .Slug.PitchShapes }}"},
+		},
+	}
+}
+
+func Test_CheckAnki(t *testing.T) {
+	type checkResult struct {
+		Name   string
+		OK     bool
+		HasFix bool
+	}
+	testCases := []struct {
+		Name             string
+		Anki             *testAnki
+		ConfigErr        error
+		Expected         []checkResult
+		ExpectedProblems map[string]string
+	}{
+		{
+			Name: "all ok",
+			Anki: &testAnki{
+				State: &anki.StateResult{
+					Version:          6,
+					DeckExists:       true,
+					NoteTypeExists:   true,
+					NoteHasAllFields: true,
+					OrderDefined:     true,
+					AudioFieldExists: true,
+				},
+				Fields: []string{"Kanji", "Meaning", "Pitch", "Audio"},
+			},
+			Expected: []checkResult{
+				{Name: "Config is valid", OK: true},
+				{Name: "AnkiConnect is available (version 6)", OK: true},
+				{Name: "Deck exists", OK: true},
+				{Name: "Note type exists", OK: true},
+				{Name: "Note type has all fields from config", OK: true},
+				{Name: "First field of note type is filled", OK: true},
+				{Name: "Audio field exists", OK: true},
+			},
+		},
+		{
+			Name: "connection error",
+			Anki: &testAnki{
+				StateErr: &anki.ConnectionError{Msg: "connection refused"},
+			},
+			Expected: []checkResult{
+				{Name: "Config is valid", OK: true},
+				{Name: "AnkiConnect is available"},
+			},
+			ExpectedProblems: map[string]string{
+				"AnkiConnect is available": "Can't connect to AnkiConnect at 127.0.0.1:8765 (anki.addr in config).\n" +
+					"Make sure that Anki is running and AnkiConnect add-on is installed.\n" +
+					"Details: connection refused",
+			},
+		},
+		{
+			Name: "invalid api key",
+			Anki: &testAnki{
+				StateErr: anki.ErrInvalidAPIKey,
+			},
+			Expected: []checkResult{
+				{Name: "Config is valid", OK: true},
+				{Name: "AnkiConnect is available"},
+			},
+			ExpectedProblems: map[string]string{
+				"AnkiConnect is available": "AnkiConnect rejected API key.\n" +
+					"anki.api-key in config should be the same as apiKey in AnkiConnect settings.",
+			},
+		},
+		{
+			Name: "no deck and note type",
+			Anki: &testAnki{
+				State: &anki.StateResult{
+					Version: 6,
+				},
+			},
+			Expected: []checkResult{
+				{Name: "Config is valid", OK: true},
+				{Name: "AnkiConnect is available (version 6)", OK: true},
+				{Name: "Deck exists", HasFix: true},
+				{Name: "Note type exists", HasFix: true},
+			},
+		},
+		{
+			Name: "invalid deck and note type",
+			Anki: &testAnki{
+				State: &anki.StateResult{
+					Version: 6,
+				},
+			},
+			ConfigErr: errors.Join(
+				fmt.Errorf("%w: invalid name", anki.ErrConfigDeckInvalid),
+				fmt.Errorf("%w: invalid name", anki.ErrConfigNoteTypeInvalid),
+			),
+			Expected: []checkResult{
+				{Name: "Config is valid"},
+				{Name: "AnkiConnect is available (version 6)", OK: true},
+				{Name: "Deck exists"},
+				{Name: "Note type exists"},
+			},
+			ExpectedProblems: map[string]string{
+				"Config is valid": "Anki section of config is invalid, notes can't be added until it's fixed:\n" +
+					"anki config Deck validation failed: invalid name\n" +
+					"anki config NoteType validation failed: invalid name",
+			},
+		},
+		{
+			Name: "invalid note type",
+			Anki: &testAnki{
+				State: &anki.StateResult{
+					Version: 6,
+				},
+			},
+			ConfigErr: fmt.Errorf("%w: invalid name", anki.ErrConfigNoteTypeInvalid),
+			Expected: []checkResult{
+				{Name: "Config is valid"},
+				{Name: "AnkiConnect is available (version 6)", OK: true},
+				{Name: "Deck exists", HasFix: true},
+				{Name: "Note type exists"},
+			},
+		},
+		{
+			Name: "wrong fields",
+			Anki: &testAnki{
+				State: &anki.StateResult{
+					Version:        6,
+					DeckExists:     true,
+					NoteTypeExists: true,
+				},
+				Fields: []string{"Front", "Kanji"},
+			},
+			Expected: []checkResult{
+				{Name: "Config is valid", OK: true},
+				{Name: "AnkiConnect is available (version 6)", OK: true},
+				{Name: "Deck exists", OK: true},
+				{Name: "Note type exists", OK: true},
+				{Name: "Note type has all fields from config"},
+				{Name: "First field of note type is filled"},
+				{Name: "Audio field exists"},
+			},
+			ExpectedProblems: map[string]string{
+				"Note type has all fields from config": "Note type \"JapwordsNote\" doesn't have fields \"Meaning\", \"Pitch\" " +
+					"that are filled by anki.fields in config.\nAdd them to note type in Anki or remove them from config.",
+				"First field of note type is filled": "The first field of note type \"JapwordsNote\" is \"Front\", " +
+					"Anki uses it to find duplicates and doesn't add notes\nwhere it's empty, but there is no template for it in anki.fields in config.",
+			},
+		},
+		{
+			Name: "fields error",
+			Anki: &testAnki{
+				State: &anki.StateResult{
+					Version:        6,
+					DeckExists:     true,
+					NoteTypeExists: true,
+				},
+				FieldsErr: errors.New("boom"),
+			},
+			Expected: []checkResult{
+				{Name: "Config is valid", OK: true},
+				{Name: "AnkiConnect is available (version 6)", OK: true},
+				{Name: "Deck exists", OK: true},
+				{Name: "Note type exists", OK: true},
+				{Name: "Note type fields are available"},
+			},
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			section := CheckAnki(context.Background(), tc.Anki, testAnkiConfig(), tc.ConfigErr)
+			assert.Equal(t, "Anki", section.Title)
+			var actual []checkResult
+			for _, check := range section.Checks {
+				actual = append(actual, checkResult{
+					Name:   check.Name,
+					OK:     check.OK(),
+					HasFix: check.Fix != nil,
+				})
+				if expectedProblem, ok := tc.ExpectedProblems[check.Name]; ok {
+					assert.Equal(t, expectedProblem, check.Problem)
+				}
+			}
+			assert.Equal(t, tc.Expected, actual)
+		})
+	}
+}
+
+func Test_CheckAnki_Fixes(t *testing.T) {
+	client := &testAnki{
+		State: &anki.StateResult{},
+	}
+	section := CheckAnki(context.Background(), client, testAnkiConfig(), nil)
+	var fixes []string
+	for _, check := range section.Failed() {
+		require.NotNil(t, check.Fix)
+		fixes = append(fixes, check.Fix.Description)
+		require.NoError(t, check.Fix.Apply(context.Background()))
+	}
+	assert.Equal(t, []string{
+		`create deck "Japwords"`,
+		`create note type "JapwordsNote" with default fields and cards`,
+	}, fixes)
+	assert.Equal(t, []string{"Japwords"}, client.createdDecks)
+	assert.Equal(t, []string{"JapwordsNote"}, client.createdNoteTypes)
+}
@@ -0,0 +1,80 @@
+// Package doctor diagnoses problems that prevent adding notes to Anki
+// and explains them in plain language.
+package doctor
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Check is result of single diagnostic check.
+type Check struct {
+	Name string
+	// Problem is explanation of failure, it's empty if check passed
+	Problem string
+	// Fix is action that can solve problem, it's nil if problem can't be fixed automatically
+	Fix *Fix
+}
+
+func (c *Check) OK() bool {
+	return c.Problem == ""
+}
+
+type Fix struct {
+	// Description is imperative description of action, for example `create deck "Japwords"`
+	Description string
+	Apply       func(ctx context.Context) error
+}
+
+func passed(name string) *Check {
+	return &Check{
+		Name: name,
+	}
+}
+
+func failed(name string, format string, args ...any) *Check {
+	return &Check{
+		Name:    name,
+		Problem: fmt.Sprintf(format, args...),
+	}
+}
+
+// Section is group of checks with title.
+type Section struct {
+	Title  string
+	Checks []*Check
+}
+
+// Failed returns checks that failed.
+func (s *Section) Failed() []*Check {
+	var result []*Check
+	for _, check := range s.Checks {
+		if !check.OK() {
+			result = append(result, check)
+		}
+	}
+	return result
+}
+
+// WriteSection writes checks of section with their problems and available fixes.
+func WriteSection(w io.Writer, section *Section) error {
+	buffer := bufio.NewWriter(w)
+	fmt.Fprintln(buffer, section.Title)
+	for _, check := range section.Checks {
+		if check.OK() {
+			fmt.Fprintf(buffer, "  [ OK ] %s\n", check.Name)
+			continue
+		}
+		fmt.Fprintf(buffer, "  [FAIL] %s\n", check.Name)
+		for _, line := range strings.Split(check.Problem, "\n") {
+			fmt.Fprintf(buffer, "         %s\n", line)
+		}
+		if check.Fix != nil {
+			fmt.Fprintf(buffer, "         Can be fixed: %s\n", check.Fix.Description)
+		}
+	}
+	return buffer.Flush()
+}
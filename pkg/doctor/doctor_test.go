@@ -0,0 +1,42 @@
+package doctor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WriteSection(t *testing.T) {
+	section := &Section{
+		Title: "Anki",
+		Checks: []*Check{
+			passed("AnkiConnect is available"),
+			{
+				Name:    "Deck exists",
+				Problem: "Deck doesn't exist.\nSecond line.",
+				Fix: &Fix{
+					Description: "create deck",
+				},
+			},
+			failed("Audio field exists", "Field %q is missing.", "Audio"),
+		},
+	}
+	var buffer strings.Builder
+	err := WriteSection(&buffer, section)
+	require.NoError(t, err)
+	assert.Equal(t, `Anki
+  [ OK ] AnkiConnect is available
+  [FAIL] Deck exists
+         Deck doesn't exist.
+         Second line.
+         Can be fixed: create deck
+  [FAIL] Audio field exists
+         Field "Audio" is missing.
+`, buffer.String())
+	failedChecks := section.Failed()
+	require.Len(t, failedChecks, 2)
+	assert.Equal(t, "Deck exists", failedChecks[0].Name)
+	assert.Equal(t, "Audio field exists", failedChecks[1].Name)
+}
@@ -0,0 +1,45 @@
+package doctor
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Darkclainer/japwords/pkg/anki"
+	"github.com/Darkclainer/japwords/pkg/config"
+)
+
+// CheckTemplates renders every template of field mapping and every tag rule with example lemma.
+func CheckTemplates(conf *config.Anki) *Section {
+	section := &Section{
+		Title: "Templates",
+	}
+	fields := make([]string, 0, len(conf.FieldMapping))
+	for field := range conf.FieldMapping {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+	for _, field := range fields {
+		section.Checks = append(section.Checks, checkTemplate(
+			fmt.Sprintf("Template of field %q", field),
+			conf.FieldMapping[field],
+		))
+	}
+	for i, rule := range conf.Tags.Rules {
+		section.Checks = append(section.Checks, checkTemplate(
+			fmt.Sprintf("Tag rule #%d", i+1),
+			rule,
+		))
+	}
+	if len(section.Checks) == 0 {
+		section.Checks = append(section.Checks, failed("Field mapping is defined",
+			"anki.fields in config is empty, notes can't be filled."))
+	}
+	return section
+}
+
+func checkTemplate(name string, src string) *Check {
+	if _, err := anki.RenderRawTemplate(src, &anki.DefaultExampleLemma); err != nil {
+		return failed(name, "Template can't be rendered: %s", err)
+	}
+	return passed(name)
+}
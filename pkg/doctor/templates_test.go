@@ -0,0 +1,49 @@
+package doctor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Darkclainer/japwords/pkg/config"
+)
+
+func Test_CheckTemplates(t *testing.T) {
+	t.Run("default config", func(t *testing.T) {
+		section := CheckTemplates(&config.DefaultUserConfig().Anki)
+		assert.NotEmpty(t, section.Checks)
+		assert.Empty(t, section.Failed())
+	})
+	t.Run("invalid templates", func(t *testing.T) {
+		section := CheckTemplates(&config.Anki{
+			FieldMapping: map[string]string{
+				"Kanji":   "{{ .Slug.Word }}",
+				"Meaning": "{{ .Unknown }}",
+				"Broken":  "{{ .Slug.Word ",
+			},
+			Tags: config.AnkiTags{
+				Rules: []string{"{{ fail }}"},
+			},
+		})
+		var names []string
+		for _, check := range section.Checks {
+			names = append(names, check.Name)
+		}
+		assert.Equal(t, []string{
+			`Template of field "Broken"`,
+			`Template of field "Kanji"`,
+			`Template of field "Meaning"`,
+			"Tag rule #1",
+		}, names)
+		failedChecks := section.Failed()
+		require.Len(t, failedChecks, 3)
+		assert.Contains(t, failedChecks[0].Problem, "Template can't be rendered")
+		assert.Equal(t, `Template of field "Meaning"`, failedChecks[1].Name)
+		assert.Equal(t, "Tag rule #1", failedChecks[2].Name)
+	})
+	t.Run("empty mapping", func(t *testing.T) {
+		section := CheckTemplates(&config.Anki{})
+		require.Len(t, section.Failed(), 1)
+	})
+}
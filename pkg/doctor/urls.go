@@ -0,0 +1,51 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/Darkclainer/japwords/pkg/config"
+	"github.com/Darkclainer/japwords/pkg/jisho"
+	"github.com/Darkclainer/japwords/pkg/wadoku"
+)
+
+// Doer sends http requests, for example fetcher.Fetcher.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// CheckURLs checks that online dictionaries that are enabled in config are reachable.
+func CheckURLs(ctx context.Context, client Doer, conf *config.Dictionary) *Section {
+	section := &Section{
+		Title: "Online dictionaries",
+	}
+	if len(conf.LemmaDicts) == 0 || slices.Contains(conf.LemmaDicts, "jisho") {
+		section.Checks = append(section.Checks, checkURL(ctx, client, "jisho", conf.Jisho.URL, jisho.DefaultBaseURL))
+	}
+	if len(conf.PitchDicts) == 0 || slices.Contains(conf.PitchDicts, "wadoku") {
+		section.Checks = append(section.Checks, checkURL(ctx, client, "wadoku", conf.Wadoku.URL, wadoku.DefaultBaseURL))
+	}
+	return section
+}
+
+func checkURL(ctx context.Context, client Doer, name string, url string, defaultURL string) *Check {
+	if url == "" {
+		url = defaultURL
+	}
+	checkName := fmt.Sprintf("%s is reachable at %s", name, url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return failed(checkName, "URL is invalid (dictionary.%s.url in config): %s", name, err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return failed(checkName, "Request failed, check network connection and dictionary.%s.url in config: %s", name, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return failed(checkName, "Server responded with status %q, lookups in %s will fail.", resp.Status, name)
+	}
+	return passed(checkName)
+}
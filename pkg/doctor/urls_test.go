@@ -0,0 +1,94 @@
+package doctor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Darkclainer/japwords/pkg/config"
+)
+
+func Test_CheckURLs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken/" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		Name     string
+		Config   *config.Dictionary
+		Expected map[string]bool
+	}{
+		{
+			Name: "reachable",
+			Config: &config.Dictionary{
+				Jisho:  config.Jisho{URL: server.URL + "/jisho/"},
+				Wadoku: config.Wadoku{URL: server.URL + "/wadoku/"},
+			},
+			Expected: map[string]bool{
+				"jisho is reachable at " + server.URL + "/jisho/":   true,
+				"wadoku is reachable at " + server.URL + "/wadoku/": true,
+			},
+		},
+		{
+			Name: "bad status",
+			Config: &config.Dictionary{
+				LemmaDicts: []string{"jisho"},
+				PitchDicts: []string{"accents"},
+				Jisho:      config.Jisho{URL: server.URL + "/broken/"},
+			},
+			Expected: map[string]bool{
+				"jisho is reachable at " + server.URL + "/broken/": false,
+			},
+		},
+		{
+			Name: "invalid url",
+			Config: &config.Dictionary{
+				LemmaDicts: []string{"jmdict"},
+				PitchDicts: []string{"wadoku"},
+				Wadoku:     config.Wadoku{URL: "http://[::1"},
+			},
+			Expected: map[string]bool{
+				"wadoku is reachable at http://[::1": false,
+			},
+		},
+		{
+			Name: "disabled",
+			Config: &config.Dictionary{
+				LemmaDicts: []string{"jmdict"},
+				PitchDicts: []string{"accents"},
+			},
+			Expected: map[string]bool{},
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			section := CheckURLs(context.Background(), server.Client(), tc.Config)
+			actual := map[string]bool{}
+			for _, check := range section.Checks {
+				actual[check.Name] = check.OK()
+			}
+			require.Equal(t, tc.Expected, actual)
+		})
+	}
+}
+
+func Test_CheckURLs_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL + "/"
+	server.Close()
+	section := CheckURLs(context.Background(), http.DefaultClient, &config.Dictionary{
+		PitchDicts: []string{"accents"},
+		Jisho:      config.Jisho{URL: url},
+	})
+	require.Len(t, section.Checks, 1)
+	assert.False(t, section.Checks[0].OK())
+	assert.Contains(t, section.Checks[0].Problem, "Request failed")
+}
@@ -0,0 +1,103 @@
+package examples
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/Darkclainer/japwords/pkg/kana"
+)
+
+// word is single word of B line of Tanaka corpus.
+type word struct {
+	// Headword is dictionary form of word
+	Headword string
+	// Reading is reading of headword, it's specified in corpus only sometimes
+	Reading string
+	// Surface is form of word in sentence, empty if it's the same as headword
+	Surface string
+	// Checked is true if sentence is good example for headword
+	Checked bool
+}
+
+var bWordRegex = regexp.MustCompile(`^([^(\[{~]+)(?:\(([^)]*)\))?(?:\[\d+\])?(?:\{([^}]*)\})?(~)?$`)
+
+func parseBLine(line string) []word {
+	var words []word
+	for _, field := range strings.Fields(strings.TrimPrefix(line, "B: ")) {
+		match := bWordRegex.FindStringSubmatch(field)
+		if match == nil {
+			continue
+		}
+		words = append(words, word{
+			Headword: match[1],
+			Reading:  kana.ToHiragana(match[2]),
+			Surface:  match[3],
+			Checked:  match[4] != "",
+		})
+	}
+	return words
+}
+
+// buildReading adds readings of words to japanese sentence in Anki furigana format.
+// It returns empty string if no word has reading.
+func buildReading(japanese string, words []word) string {
+	var (
+		result    strings.Builder
+		annotated bool
+	)
+	rest := japanese
+	for _, w := range words {
+		if w.Reading == "" {
+			continue
+		}
+		surface := w.Surface
+		if surface == "" {
+			surface = w.Headword
+		}
+		index := strings.Index(rest, surface)
+		if index < 0 {
+			continue
+		}
+		surfaceReading, ok := annotate(surface, w.Headword, w.Reading)
+		if !ok {
+			continue
+		}
+		result.WriteString(rest[:index])
+		// space delimits annotated word from previous text, Anki doesn't show it
+		if result.Len() != 0 {
+			result.WriteByte(' ')
+		}
+		result.WriteString(surfaceReading)
+		rest = rest[index+len(surface):]
+		annotated = true
+	}
+	if !annotated {
+		return ""
+	}
+	result.WriteString(rest)
+	return result.String()
+}
+
+// annotate returns surface with reading in Anki furigana format. Reading is specified for
+// headword, so kana suffix that headword and reading share (okurigana) is removed from both
+// and only stem is annotated, for example 飼っている with headword 飼う(かう) becomes 飼[か]っている.
+func annotate(surface, headword, reading string) (string, bool) {
+	stem, stemReading := []rune(headword), []rune(reading)
+	for len(stem) > 0 && len(stemReading) > 0 && kana.ToHiragana(string(stem[len(stem)-1])) == string(stemReading[len(stemReading)-1]) {
+		stem, stemReading = stem[:len(stem)-1], stemReading[:len(stemReading)-1]
+	}
+	if len(stem) == 0 || len(stemReading) == 0 || !hasKanji(string(stem)) || !strings.HasPrefix(surface, string(stem)) {
+		return "", false
+	}
+	return string(stem) + "[" + string(stemReading) + "]" + strings.TrimPrefix(surface, string(stem)), true
+}
+
+func hasKanji(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Han, r) {
+			return true
+		}
+	}
+	return false
+}
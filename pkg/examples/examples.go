@@ -0,0 +1,180 @@
+// examples is offline index of example sentences. It reads sentences in format
+// of Tanaka corpus (examples.utf distributed by EDRDG and Tatoeba project):
+//
+//	A: 彼は犬を飼っている。<TAB>He keeps a dog.#ID=1_2
+//	B: 彼(かれ)[01] は 犬 を 飼う(かう){飼っている}~
+//
+// where B line lists dictionary forms of words of sentence above. Also simple
+// tab separated format is supported:
+//
+//	japanese<TAB>english<TAB>space separated words[<TAB>reading]
+//
+// where reading is japanese sentence in Anki furigana format (彼[かれ]は 犬[いぬ]).
+package examples
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+// Index is in-memory index of example sentences.
+type Index struct {
+	examples []lemma.Example
+	// byWord maps word to indexes of examples that contain it,
+	// checked examples go first.
+	byWord map[string][]int
+}
+
+// Open reads examples file located at path.
+func Open(path string) (*Index, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("examples file is not available: %w", err)
+	}
+	defer file.Close()
+	return New(file)
+}
+
+// New reads examples from src.
+func New(src io.Reader) (*Index, error) {
+	builder := newIndexBuilder()
+	scanner := bufio.NewScanner(src)
+	scanner.Buffer(nil, 1024*1024)
+	lineNumber := 0
+	// pending is A line of Tanaka corpus, that waits its B line
+	var pending *lemma.Example
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "A: "):
+			example, err := parseALine(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			pending = example
+		case strings.HasPrefix(line, "B: "):
+			if pending == nil {
+				return nil, fmt.Errorf("line %d: B line without A line", lineNumber)
+			}
+			words := parseBLine(line)
+			pending.Reading = buildReading(pending.Japanese, words)
+			builder.add(pending, words)
+			pending = nil
+		default:
+			example, words, err := parseTSVLine(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			builder.add(example, words)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("examples file reading failed: %w", err)
+	}
+	return builder.build(), nil
+}
+
+// Query returns at most limit examples that contain word. Non positive limit means no limit.
+func (i *Index) Query(word string, limit int) []lemma.Example {
+	indexes := i.byWord[word]
+	if limit > 0 && len(indexes) > limit {
+		indexes = indexes[:limit]
+	}
+	if len(indexes) == 0 {
+		return nil
+	}
+	result := make([]lemma.Example, len(indexes))
+	for j, index := range indexes {
+		result[j] = i.examples[index]
+	}
+	return result
+}
+
+// Len returns number of examples in index.
+func (i *Index) Len() int {
+	return len(i.examples)
+}
+
+type indexBuilder struct {
+	examples []lemma.Example
+	checked  map[string][]int
+	other    map[string][]int
+}
+
+func newIndexBuilder() *indexBuilder {
+	return &indexBuilder{
+		checked: map[string][]int{},
+		other:   map[string][]int{},
+	}
+}
+
+func (b *indexBuilder) add(example *lemma.Example, words []word) {
+	index := len(b.examples)
+	b.examples = append(b.examples, *example)
+	seen := map[string]bool{}
+	for _, w := range words {
+		if seen[w.Headword] {
+			continue
+		}
+		seen[w.Headword] = true
+		if w.Checked {
+			b.checked[w.Headword] = append(b.checked[w.Headword], index)
+		} else {
+			b.other[w.Headword] = append(b.other[w.Headword], index)
+		}
+	}
+}
+
+func (b *indexBuilder) build() *Index {
+	byWord := make(map[string][]int, len(b.checked)+len(b.other))
+	for headword, indexes := range b.checked {
+		byWord[headword] = indexes
+	}
+	for headword, indexes := range b.other {
+		byWord[headword] = append(byWord[headword], indexes...)
+	}
+	return &Index{
+		examples: b.examples,
+		byWord:   byWord,
+	}
+}
+
+func parseALine(line string) (*lemma.Example, error) {
+	japanese, english, ok := strings.Cut(strings.TrimPrefix(line, "A: "), "\t")
+	if !ok {
+		return nil, fmt.Errorf("A line should contain japanese and english sentences separated by tab")
+	}
+	// english sentence is followed by ids of sentences in tatoeba
+	english, _, _ = strings.Cut(english, "#ID=")
+	return &lemma.Example{
+		Japanese: strings.TrimSpace(japanese),
+		English:  strings.TrimSpace(english),
+	}, nil
+}
+
+func parseTSVLine(line string) (*lemma.Example, []word, error) {
+	fields := strings.Split(line, "\t")
+	if len(fields) < 3 || len(fields) > 4 {
+		return nil, nil, fmt.Errorf("line should contain 3 or 4 tab separated fields, but it has %d", len(fields))
+	}
+	example := &lemma.Example{
+		Japanese: strings.TrimSpace(fields[0]),
+		English:  strings.TrimSpace(fields[1]),
+	}
+	if len(fields) == 4 {
+		example.Reading = strings.TrimSpace(fields[3])
+	}
+	var words []word
+	for _, headword := range strings.Fields(fields[2]) {
+		words = append(words, word{Headword: headword})
+	}
+	return example, words, nil
+}
@@ -0,0 +1,159 @@
+package examples
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+func Test_Index_Query(t *testing.T) {
+	index, err := Open("testdata/examples.utf")
+	require.NoError(t, err)
+	require.Equal(t, 4, index.Len())
+	testCases := []struct {
+		Name     string
+		Word     string
+		Limit    int
+		Expected []lemma.Example
+	}{
+		{
+			Name: "checked first",
+			Word: "犬",
+			Expected: []lemma.Example{
+				{
+					Japanese: "彼は犬を飼っている。",
+					English:  "He keeps a dog.",
+					Reading:  "彼[かれ]は 犬[いぬ]を 飼[か]っている。",
+				},
+				{
+					Japanese: "その犬は大きい。",
+					English:  "That dog is big.",
+					Reading:  "その 犬[いぬ]は 大[おお]きい。",
+				},
+				{
+					Japanese: "犬が好きです。",
+					English:  "I like dogs.",
+				},
+			},
+		},
+		{
+			Name:  "limit",
+			Word:  "犬",
+			Limit: 1,
+			Expected: []lemma.Example{
+				{
+					Japanese: "彼は犬を飼っている。",
+					English:  "He keeps a dog.",
+					Reading:  "彼[かれ]は 犬[いぬ]を 飼[か]っている。",
+				},
+			},
+		},
+		{
+			Name: "simple format",
+			Word: "寝る",
+			Expected: []lemma.Example{
+				{
+					Japanese: "猫が寝ている。",
+					English:  "The cat is sleeping.",
+					Reading:  "猫[ねこ]が 寝[ね]ている。",
+				},
+			},
+		},
+		{
+			Name: "not found",
+			Word: "鳥",
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, index.Query(tc.Word, tc.Limit))
+		})
+	}
+}
+
+func Test_New_Error(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Src  string
+	}{
+		{
+			Name: "B line without A line",
+			Src:  "B: 犬",
+		},
+		{
+			Name: "A line without english",
+			Src:  "A: 犬",
+		},
+		{
+			Name: "wrong number of fields",
+			Src:  "犬\tdog",
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			_, err := New(strings.NewReader(tc.Src))
+			assert.ErrorContains(t, err, "line 1")
+		})
+	}
+}
+
+func Test_Open_NotExists(t *testing.T) {
+	_, err := Open("testdata/notexists")
+	assert.ErrorContains(t, err, "examples file is not available")
+}
+
+func Test_annotate(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Surface  string
+		Headword string
+		Reading  string
+		Expected string
+		OK       bool
+	}{
+		{
+			Name:     "kanji",
+			Surface:  "犬",
+			Headword: "犬",
+			Reading:  "いぬ",
+			Expected: "犬[いぬ]",
+			OK:       true,
+		},
+		{
+			Name:     "okurigana",
+			Surface:  "飼っている",
+			Headword: "飼う",
+			Reading:  "かう",
+			Expected: "飼[か]っている",
+			OK:       true,
+		},
+		{
+			Name:     "kana",
+			Surface:  "その",
+			Headword: "其の",
+			Reading:  "その",
+			OK:       false,
+		},
+		{
+			Name:     "surface doesn't match headword",
+			Surface:  "喰べた",
+			Headword: "食べる",
+			Reading:  "たべる",
+			OK:       false,
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			actual, ok := annotate(tc.Surface, tc.Headword, tc.Reading)
+			assert.Equal(t, tc.OK, ok)
+			assert.Equal(t, tc.Expected, actual)
+		})
+	}
+}
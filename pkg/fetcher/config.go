@@ -1,9 +1,29 @@
 package fetcher
 
-import "maps"
+import (
+	"maps"
+	"time"
+)
 
 type Config struct {
 	Headers map[string]string
+	// Timeout is time limit of single request attempt, default timeout is used if it's zero.
+	Timeout time.Duration
+	// Retries is how many times failed request is retried. Request is failed if
+	// it returned network error or status 429, 502, 503 or 504.
+	Retries int
+	// RetryBaseDelay is delay before the first retry, every next delay is doubled.
+	// Actual delay is randomly chosen between half of delay and delay.
+	RetryBaseDelay time.Duration
+	// RetryMaxDelay limits delay between retries. Request is not retried if
+	// server asks (by Retry-After) to wait longer.
+	RetryMaxDelay time.Duration
+	// RateLimit is number of requests per second to single host, zero means no limit.
+	RateLimit float64
+	// RateBurst is number of requests that can be made at once before rate limit is applied.
+	RateBurst int
+	// MaxConcurrent is number of simultaneous requests to single host, zero means no limit.
+	MaxConcurrent int
 }
 
 func (c *Config) Equal(other any) bool {
@@ -11,5 +31,12 @@ func (c *Config) Equal(other any) bool {
 	if !ok {
 		return false
 	}
-	return maps.Equal(c.Headers, otherConfig.Headers)
+	return maps.Equal(c.Headers, otherConfig.Headers) &&
+		c.Timeout == otherConfig.Timeout &&
+		c.Retries == otherConfig.Retries &&
+		c.RetryBaseDelay == otherConfig.RetryBaseDelay &&
+		c.RetryMaxDelay == otherConfig.RetryMaxDelay &&
+		c.RateLimit == otherConfig.RateLimit &&
+		c.RateBurst == otherConfig.RateBurst &&
+		c.MaxConcurrent == otherConfig.MaxConcurrent
 }
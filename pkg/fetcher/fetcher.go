@@ -1,31 +1,151 @@
 package fetcher
 
 import (
+	"fmt"
+	"io"
 	"net/http"
+	"sync"
 	"time"
 )
 
+const (
+	// defaultTimeout is used if timeout is not specified in config
+	defaultTimeout = 30 * time.Second
+	// drainLimit is how much of body of failed response is read before retry,
+	// so connection can be reused
+	drainLimit = 64 * 1024
+)
+
 // Fetcher is a wrapper for http.Client that provides means
 // for configuration all http requests, for example providing
-// custom user agent.
+// custom user agent. It also retries failed requests and
+// limits rate and concurrency of requests to every host.
 type Fetcher struct {
-	client       http.Client
-	extraHeaders map[string]string
+	now    func() time.Time
+	sleep  sleepFunc
+	random func() float64
+
+	stateLock sync.RWMutex
+	state     *fetcherState
+}
+
+// fetcherState is everything that depends on config. It's replaced as a whole on reload,
+// so requests in flight finish with the config they started with.
+type fetcherState struct {
+	conf   *Config
+	client *http.Client
+
+	hostsLock sync.Mutex
+	hosts     map[string]*hostLimiter
 }
 
 func New(conf *Config) (*Fetcher, error) {
-	fetcher := Fetcher{
-		client: http.Client{
-			Timeout: time.Second * 30,
+	fetcher := &Fetcher{
+		now:    time.Now,
+		sleep:  sleepContext,
+		random: defaultRandom,
+	}
+	if err := fetcher.ReloadConfig(conf); err != nil {
+		return nil, err
+	}
+	return fetcher, nil
+}
+
+// ReloadConfig replaces config of fetcher, it's safe to call it while requests are in flight.
+// Limits of new config are counted from scratch.
+func (f *Fetcher) ReloadConfig(conf *Config) error {
+	if err := conf.validate(); err != nil {
+		return err
+	}
+	timeout := conf.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+	state := &fetcherState{
+		conf: conf,
+		client: &http.Client{
+			Timeout: timeout,
 		},
-		extraHeaders: conf.Headers,
+		hosts: map[string]*hostLimiter{},
 	}
-	return &fetcher, nil
+	f.stateLock.Lock()
+	defer f.stateLock.Unlock()
+	f.state = state
+	return nil
 }
 
 func (f *Fetcher) Do(req *http.Request) (*http.Response, error) {
-	for k, v := range f.extraHeaders {
+	f.stateLock.RLock()
+	state := f.state
+	f.stateLock.RUnlock()
+	for k, v := range state.conf.Headers {
 		req.Header.Set(k, v)
 	}
-	return f.client.Do(req)
+	ctx := req.Context()
+	limiter := state.hostLimiter(req.URL.Host, f.now)
+	retries := state.conf.Retries
+	if !isIdempotent(req) {
+		retries = 0
+	}
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+		release, err := limiter.acquire(ctx, f.sleep)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := state.client.Do(req)
+		if err != nil {
+			release()
+		} else {
+			resp.Body = &releaseBody{
+				ReadCloser: resp.Body,
+				release:    release,
+			}
+		}
+		if attempt >= retries || !isRetryable(resp, err) {
+			return resp, err
+		}
+		delay := backoff(state.conf.RetryBaseDelay, state.conf.RetryMaxDelay, attempt, f.random)
+		if retryAfter, ok := parseRetryAfter(resp, f.now()); ok {
+			// server asks to wait too long, it's better to fail now
+			if state.conf.RetryMaxDelay > 0 && retryAfter > state.conf.RetryMaxDelay {
+				return resp, nil
+			}
+			delay = retryAfter
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, drainLimit))
+			resp.Body.Close()
+		}
+		if err := f.sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (s *fetcherState) hostLimiter(host string, now func() time.Time) *hostLimiter {
+	s.hostsLock.Lock()
+	defer s.hostsLock.Unlock()
+	limiter, ok := s.hosts[host]
+	if !ok {
+		limiter = newHostLimiter(s.conf, now)
+		s.hosts[host] = limiter
+	}
+	return limiter
+}
+
+func (c *Config) validate() error {
+	if c.Timeout < 0 || c.Retries < 0 || c.RetryBaseDelay < 0 || c.RetryMaxDelay < 0 {
+		return fmt.Errorf("timeout, retries and retry delays should not be negative")
+	}
+	if c.RateLimit < 0 || c.RateBurst < 0 || c.MaxConcurrent < 0 {
+		return fmt.Errorf("rate limit, rate burst and max concurrent should not be negative")
+	}
+	return nil
 }
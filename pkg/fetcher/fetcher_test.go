@@ -1,10 +1,16 @@
 package fetcher
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"net"
 	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -71,3 +77,289 @@ func newTestListener(t *testing.T) net.Listener {
 	}
 	return l
 }
+
+// fakeClock records sleeps instead of sleeping and advances time by them.
+type fakeClock struct {
+	lock   sync.Mutex
+	time   time.Time
+	sleeps []time.Duration
+}
+
+func (c *fakeClock) now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.time
+}
+
+func (c *fakeClock) sleep(ctx context.Context, d time.Duration) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.sleeps = append(c.sleeps, d)
+	c.time = c.time.Add(d)
+	return ctx.Err()
+}
+
+func newTestFetcher(t *testing.T, conf *Config) (*Fetcher, *fakeClock) {
+	fetcher, err := New(conf)
+	require.NoError(t, err)
+	clock := &fakeClock{
+		time: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	fetcher.now = clock.now
+	fetcher.sleep = clock.sleep
+	// jitter is always maximal
+	fetcher.random = func() float64 { return 1 }
+	return fetcher, clock
+}
+
+// statusSequenceHandler responds with statuses in order, the last status is repeated.
+func statusSequenceHandler(calls *atomic.Int32, headers http.Header, statuses ...int) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		call := int(calls.Add(1)) - 1
+		status := statuses[min(call, len(statuses)-1)]
+		for k, v := range headers {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(status)
+		fmt.Fprintf(w, "status %d", status)
+	}
+}
+
+func Test_Fetcher_Retry(t *testing.T) {
+	testCases := []struct {
+		Name           string
+		Method         string
+		Statuses       []int
+		Headers        http.Header
+		ExpectedStatus int
+		ExpectedCalls  int
+		ExpectedSleeps []time.Duration
+	}{
+		{
+			Name:           "success",
+			Statuses:       []int{http.StatusOK},
+			ExpectedStatus: http.StatusOK,
+			ExpectedCalls:  1,
+		},
+		{
+			Name:           "retry until success",
+			Statuses:       []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			ExpectedStatus: http.StatusOK,
+			ExpectedCalls:  3,
+			ExpectedSleeps: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+		},
+		{
+			Name:           "retries exhausted",
+			Statuses:       []int{http.StatusServiceUnavailable},
+			ExpectedStatus: http.StatusServiceUnavailable,
+			ExpectedCalls:  4,
+			ExpectedSleeps: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond},
+		},
+		{
+			Name:           "not retryable status",
+			Statuses:       []int{http.StatusNotFound},
+			ExpectedStatus: http.StatusNotFound,
+			ExpectedCalls:  1,
+		},
+		{
+			Name:           "retry after seconds",
+			Statuses:       []int{http.StatusTooManyRequests, http.StatusOK},
+			Headers:        http.Header{"Retry-After": []string{"2"}},
+			ExpectedStatus: http.StatusOK,
+			ExpectedCalls:  2,
+			ExpectedSleeps: []time.Duration{2 * time.Second},
+		},
+		{
+			Name:           "retry after date",
+			Statuses:       []int{http.StatusServiceUnavailable, http.StatusOK},
+			Headers:        http.Header{"Retry-After": []string{"Sun, 01 Jan 2023 00:00:01 GMT"}},
+			ExpectedStatus: http.StatusOK,
+			ExpectedCalls:  2,
+			ExpectedSleeps: []time.Duration{time.Second},
+		},
+		{
+			Name:           "retry after too long",
+			Statuses:       []int{http.StatusTooManyRequests, http.StatusOK},
+			Headers:        http.Header{"Retry-After": []string{"3600"}},
+			ExpectedStatus: http.StatusTooManyRequests,
+			ExpectedCalls:  1,
+		},
+		{
+			Name:           "post is not retried",
+			Method:         http.MethodPost,
+			Statuses:       []int{http.StatusServiceUnavailable, http.StatusOK},
+			ExpectedStatus: http.StatusServiceUnavailable,
+			ExpectedCalls:  1,
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(statusSequenceHandler(&calls, tc.Headers, tc.Statuses...))
+			defer server.Close()
+			fetcher, clock := newTestFetcher(t, &Config{
+				Retries:        3,
+				RetryBaseDelay: 100 * time.Millisecond,
+				RetryMaxDelay:  5 * time.Second,
+			})
+			method := tc.Method
+			if method == "" {
+				method = http.MethodGet
+			}
+			var body io.Reader
+			if method == http.MethodPost {
+				body = &bytes.Buffer{}
+			}
+			req, err := http.NewRequest(method, server.URL, body)
+			require.NoError(t, err)
+			// request without GetBody can't be repeated
+			req.GetBody = nil
+
+			resp, err := fetcher.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			content, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tc.ExpectedStatus, resp.StatusCode)
+			assert.Equal(t, fmt.Sprintf("status %d", tc.ExpectedStatus), string(content))
+			assert.Equal(t, tc.ExpectedCalls, int(calls.Load()))
+			assert.Equal(t, tc.ExpectedSleeps, clock.sleeps)
+		})
+	}
+}
+
+func Test_Fetcher_RetryNetworkError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	// closed server refuses connections
+	server.Close()
+	fetcher, clock := newTestFetcher(t, &Config{
+		Retries:        2,
+		RetryBaseDelay: 100 * time.Millisecond,
+	})
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	_, err = fetcher.Do(req)
+	require.Error(t, err)
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, clock.sleeps)
+}
+
+func Test_Fetcher_RetryCanceled(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(statusSequenceHandler(&calls, nil, http.StatusServiceUnavailable))
+	defer server.Close()
+	fetcher, err := New(&Config{
+		Retries:        3,
+		RetryBaseDelay: time.Hour,
+	})
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	_, err = fetcher.Do(req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, int(calls.Load()))
+}
+
+func Test_Fetcher_RateLimit(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(statusSequenceHandler(&calls, nil, http.StatusOK))
+	defer server.Close()
+	fetcher, clock := newTestFetcher(t, &Config{
+		RateLimit: 2,
+		RateBurst: 2,
+	})
+	for i := 0; i < 4; i++ {
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		resp, err := fetcher.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	// burst is spent by the first two requests, then every request waits for new token
+	assert.Equal(t, []time.Duration{500 * time.Millisecond, 500 * time.Millisecond}, clock.sleeps)
+	assert.Equal(t, 4, int(calls.Load()))
+}
+
+func Test_Fetcher_MaxConcurrent(t *testing.T) {
+	const (
+		maxConcurrent = 2
+		requests      = 6
+	)
+	var (
+		current    atomic.Int32
+		maxCurrent atomic.Int32
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		value := current.Add(1)
+		defer current.Add(-1)
+		for {
+			old := maxCurrent.Load()
+			if value <= old || maxCurrent.CompareAndSwap(old, value) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer server.Close()
+	fetcher, err := New(&Config{
+		MaxConcurrent: maxConcurrent,
+	})
+	require.NoError(t, err)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+			resp, err := fetcher.Do(req)
+			if !assert.NoError(t, err) {
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(maxConcurrent), maxCurrent.Load())
+}
+
+func Test_New_Invalid(t *testing.T) {
+	_, err := New(&Config{Retries: -1})
+	assert.Error(t, err)
+	_, err = New(&Config{MaxConcurrent: -1})
+	assert.Error(t, err)
+}
+
+func Test_Fetcher_ReloadConfig(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Header.Get("User-Agent"))
+	}))
+	defer server.Close()
+	fetcher, err := New(&Config{
+		Headers: map[string]string{"User-Agent": "old"},
+	})
+	require.NoError(t, err)
+	get := func() string {
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		resp, err := fetcher.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		content, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(content)
+	}
+	assert.Equal(t, "old", get())
+
+	require.NoError(t, fetcher.ReloadConfig(&Config{
+		Headers: map[string]string{"User-Agent": "new"},
+	}))
+	assert.Equal(t, "new", get())
+
+	assert.Error(t, fetcher.ReloadConfig(&Config{Timeout: -1}))
+	assert.Equal(t, "new", get())
+}
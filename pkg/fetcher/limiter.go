@@ -0,0 +1,122 @@
+package fetcher
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// hostLimiter limits rate and concurrency of requests to single host.
+type hostLimiter struct {
+	bucket *tokenBucket
+	// slots is semaphore for concurrent requests, nil if concurrency is not limited
+	slots chan struct{}
+}
+
+func newHostLimiter(conf *Config, now func() time.Time) *hostLimiter {
+	limiter := &hostLimiter{}
+	if conf.RateLimit > 0 {
+		limiter.bucket = newTokenBucket(conf.RateLimit, conf.RateBurst, now)
+	}
+	if conf.MaxConcurrent > 0 {
+		limiter.slots = make(chan struct{}, conf.MaxConcurrent)
+	}
+	return limiter
+}
+
+// acquire waits until request can be made. Returned function should be called
+// when request is finished.
+func (l *hostLimiter) acquire(ctx context.Context, sleep sleepFunc) (func(), error) {
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	release := func() {
+		if l.slots != nil {
+			<-l.slots
+		}
+	}
+	if l.bucket != nil {
+		if err := l.bucket.wait(ctx, sleep); err != nil {
+			release()
+			return nil, err
+		}
+	}
+	return release, nil
+}
+
+// tokenBucket is classic token bucket: tokens are added with constant rate
+// up to burst and every request takes one token.
+type tokenBucket struct {
+	lock   sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now func() time.Time) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   now(),
+		now:    now,
+	}
+}
+
+// reserve takes token and returns how long caller should wait before it can use it.
+// Tokens can go negative, this way waiting callers are served in order.
+func (b *tokenBucket) reserve() time.Duration {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	now := b.now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel returns token taken by reserve.
+func (b *tokenBucket) cancel() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.tokens = min(b.burst, b.tokens+1)
+}
+
+func (b *tokenBucket) wait(ctx context.Context, sleep sleepFunc) error {
+	delay := b.reserve()
+	if delay == 0 {
+		return nil
+	}
+	if err := sleep(ctx, delay); err != nil {
+		b.cancel()
+		return err
+	}
+	return nil
+}
+
+// releaseBody calls release when body is closed, so requests are counted as
+// concurrent until their body is read.
+type releaseBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releaseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
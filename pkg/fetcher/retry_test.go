@@ -0,0 +1,176 @@
+package fetcher
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_backoff(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Attempt  int
+		MaxDelay time.Duration
+		Random   float64
+		Expected time.Duration
+	}{
+		{
+			Name:     "first attempt",
+			Attempt:  0,
+			Random:   1,
+			Expected: time.Second,
+		},
+		{
+			Name:     "exponential",
+			Attempt:  3,
+			Random:   1,
+			Expected: 8 * time.Second,
+		},
+		{
+			Name:     "minimal jitter",
+			Attempt:  1,
+			Random:   0,
+			Expected: time.Second,
+		},
+		{
+			Name:     "capped",
+			Attempt:  10,
+			MaxDelay: 5 * time.Second,
+			Random:   1,
+			Expected: 5 * time.Second,
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			actual := backoff(time.Second, tc.MaxDelay, tc.Attempt, func() float64 { return tc.Random })
+			assert.Equal(t, tc.Expected, actual)
+		})
+	}
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		Name       string
+		Value      string
+		Expected   time.Duration
+		ExpectedOK bool
+	}{
+		{
+			Name: "empty",
+		},
+		{
+			Name:       "seconds",
+			Value:      "120",
+			Expected:   2 * time.Minute,
+			ExpectedOK: true,
+		},
+		{
+			Name:       "date",
+			Value:      "Sun, 01 Jan 2023 00:01:00 GMT",
+			Expected:   time.Minute,
+			ExpectedOK: true,
+		},
+		{
+			Name:       "date in past",
+			Value:      "Sat, 31 Dec 2022 00:00:00 GMT",
+			Expected:   0,
+			ExpectedOK: true,
+		},
+		{
+			Name:  "negative",
+			Value: "-1",
+		},
+		{
+			Name:  "invalid",
+			Value: "soon",
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			resp := &http.Response{
+				Header: http.Header{},
+			}
+			if tc.Value != "" {
+				resp.Header.Set("Retry-After", tc.Value)
+			}
+			actual, ok := parseRetryAfter(resp, now)
+			assert.Equal(t, tc.ExpectedOK, ok)
+			assert.Equal(t, tc.Expected, actual)
+		})
+	}
+}
+
+func Test_isIdempotent(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		Method     string
+		Body       bool
+		NoGetBody  bool
+		Idempotent bool
+	}{
+		{
+			Name:       "get",
+			Method:     http.MethodGet,
+			Idempotent: true,
+		},
+		{
+			Name:       "head",
+			Method:     http.MethodHead,
+			Idempotent: true,
+		},
+		{
+			Name:       "delete",
+			Method:     http.MethodDelete,
+			Idempotent: true,
+		},
+		{
+			Name:       "put with body",
+			Method:     http.MethodPut,
+			Body:       true,
+			Idempotent: true,
+		},
+		{
+			Name:      "put without GetBody",
+			Method:    http.MethodPut,
+			Body:      true,
+			NoGetBody: true,
+		},
+		{
+			Name:   "post with body",
+			Method: http.MethodPost,
+			Body:   true,
+		},
+		{
+			Name:   "post without body",
+			Method: http.MethodPost,
+		},
+		{
+			Name:   "patch with body",
+			Method: http.MethodPatch,
+			Body:   true,
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			var body io.Reader
+			if tc.Body {
+				body = strings.NewReader("body")
+			}
+			req, err := http.NewRequest(tc.Method, "http://example.com", body)
+			require.NoError(t, err)
+			if tc.NoGetBody {
+				req.GetBody = nil
+			}
+			assert.Equal(t, tc.Idempotent, isIdempotent(req))
+		})
+	}
+}
@@ -0,0 +1,332 @@
+// Package history keeps queries that were looked up by user.
+package history
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	entriesBucket = []byte("entries")
+	// metaBucket keeps number of entries, so it's not counted on every added entry
+	metaBucket = []byte("meta")
+	countKey   = []byte("count")
+)
+
+// ErrNotFound is returned if entry with specified id doesn't exist.
+var ErrNotFound = errors.New("history entry not found")
+
+// Options specifies how long entries are kept.
+type Options struct {
+	// Retention is time after which entry is removed. Zero means that entries are kept forever.
+	Retention time.Duration
+	// MaxEntries is maximum number of entries, the oldest entries are removed when it's exceeded.
+	// Zero means no limit.
+	MaxEntries int
+}
+
+// Entry is a single looked up query.
+type Entry struct {
+	ID    uint64
+	Query string
+	Time  time.Time
+	// ResultCount is number of found lemmas (every sense is counted separately)
+	ResultCount int
+	// NoteAdded is true if note was added to Anki from result of this query
+	NoteAdded bool
+}
+
+// Filter specifies entries that are returned by List.
+type Filter struct {
+	// Query is substring of query, case insensitive. Empty string matches all queries.
+	Query string
+	// NoteAdded if not nil matches only entries with the same NoteAdded.
+	NoteAdded *bool
+}
+
+func (f *Filter) match(entry *Entry) bool {
+	if f == nil {
+		return true
+	}
+	if f.NoteAdded != nil && *f.NoteAdded != entry.NoteAdded {
+		return false
+	}
+	return strings.Contains(strings.ToLower(entry.Query), strings.ToLower(f.Query))
+}
+
+// record is how entry is stored, key of record is id
+type record struct {
+	Query       string `json:"q"`
+	Time        int64  `json:"t"`
+	ResultCount int    `json:"c"`
+	NoteAdded   bool   `json:"n,omitempty"`
+}
+
+// Store is persistent history of queries based on embedded key-value database.
+// Entries are ordered by time when they were added.
+type Store struct {
+	db   *bolt.DB
+	opts Options
+	now  func() time.Time
+}
+
+// Open opens (or creates) history located at path and removes expired entries.
+func Open(path string, opts Options) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("history directory creation failed: %w", err)
+	}
+	db, err := bolt.Open(path, 0o644, &bolt.Options{
+		Timeout: time.Second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("history open failed: %w", err)
+	}
+	s := &Store{
+		db:   db,
+		opts: opts,
+		now:  time.Now,
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(entriesBucket); err != nil {
+			return err
+		}
+		// count of entries is zero until it's set by the first Add
+		if _, err := tx.CreateBucketIfNotExists(metaBucket); err != nil {
+			return err
+		}
+		return s.prune(tx)
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("history prune failed: %w", err)
+	}
+	return s, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Add records query and returns new entry.
+func (s *Store) Add(query string, resultCount int) (*Entry, error) {
+	entry := &Entry{
+		Query:       query,
+		Time:        s.now(),
+		ResultCount: resultCount,
+	}
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(entriesBucket)
+		id, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		entry.ID = id
+		if err := putEntry(bucket, entry); err != nil {
+			return err
+		}
+		if err := setCount(tx, getCount(tx)+1); err != nil {
+			return err
+		}
+		return s.prune(tx)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("history add failed: %w", err)
+	}
+	return entry, nil
+}
+
+// MarkNoteAdded marks that note was added from result of query with specified id.
+func (s *Store) MarkNoteAdded(id uint64) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(entriesBucket)
+		entry, err := getEntry(bucket, id)
+		if err != nil {
+			return err
+		}
+		entry.NoteAdded = true
+		return putEntry(bucket, entry)
+	})
+	if err != nil {
+		return fmt.Errorf("history update failed: %w", err)
+	}
+	return nil
+}
+
+// List returns entries that match filter starting from the newest one and total number of matched entries.
+// Offset entries are skipped and at most limit entries are returned, zero limit means no limit.
+func (s *Store) List(limit, offset int, filter *Filter) ([]*Entry, int, error) {
+	var (
+		entries []*Entry
+		total   int
+	)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(entriesBucket).Cursor()
+		for k, v := cursor.Last(); k != nil; k, v = cursor.Prev() {
+			entry, err := decodeEntry(k, v)
+			if err != nil {
+				return err
+			}
+			if !filter.match(entry) {
+				continue
+			}
+			if total >= offset && (limit == 0 || len(entries) < limit) {
+				entries = append(entries, entry)
+			}
+			total++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("history list failed: %w", err)
+	}
+	return entries, total, nil
+}
+
+// Delete removes entry with specified id and returns removed entry.
+func (s *Store) Delete(id uint64) (*Entry, error) {
+	var entry *Entry
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(entriesBucket)
+		var err error
+		entry, err = getEntry(bucket, id)
+		if err != nil {
+			return err
+		}
+		if err := bucket.Delete(encodeID(id)); err != nil {
+			return err
+		}
+		return setCount(tx, getCount(tx)-1)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("history delete failed: %w", err)
+	}
+	return entry, nil
+}
+
+// Clear removes all entries.
+func (s *Store) Clear() error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(entriesBucket)
+		// sequence is preserved, so ids of removed entries are not reused
+		sequence := bucket.Sequence()
+		if err := tx.DeleteBucket(entriesBucket); err != nil {
+			return err
+		}
+		bucket, err := tx.CreateBucket(entriesBucket)
+		if err != nil {
+			return err
+		}
+		if err := bucket.SetSequence(sequence); err != nil {
+			return err
+		}
+		return setCount(tx, 0)
+	})
+	if err != nil {
+		return fmt.Errorf("history clear failed: %w", err)
+	}
+	return nil
+}
+
+// prune removes entries that are older than retention and the oldest entries above limit.
+func (s *Store) prune(tx *bolt.Tx) error {
+	if s.opts.Retention <= 0 && s.opts.MaxEntries <= 0 {
+		return nil
+	}
+	bucket := tx.Bucket(entriesBucket)
+	count := getCount(tx)
+	deadline := s.now().Add(-s.opts.Retention).UnixNano()
+	var expired [][]byte
+	// ids increase with time, so the oldest entries are at the beginning
+	cursor := bucket.Cursor()
+	for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+		if s.opts.MaxEntries <= 0 || count-len(expired) <= s.opts.MaxEntries {
+			if s.opts.Retention <= 0 {
+				break
+			}
+			var r record
+			if err := json.Unmarshal(v, &r); err != nil {
+				return err
+			}
+			if r.Time >= deadline {
+				break
+			}
+		}
+		expired = append(expired, append([]byte{}, k...))
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+	// bucket can't be modified during iteration
+	for _, key := range expired {
+		if err := bucket.Delete(key); err != nil {
+			return err
+		}
+	}
+	return setCount(tx, count-len(expired))
+}
+
+// getCount returns number of entries.
+func getCount(tx *bolt.Tx) int {
+	value := tx.Bucket(metaBucket).Get(countKey)
+	if len(value) != 8 {
+		return 0
+	}
+	return int(binary.BigEndian.Uint64(value))
+}
+
+func setCount(tx *bolt.Tx, count int) error {
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, uint64(count))
+	return tx.Bucket(metaBucket).Put(countKey, value)
+}
+
+func getEntry(bucket *bolt.Bucket, id uint64) (*Entry, error) {
+	key := encodeID(id)
+	value := bucket.Get(key)
+	if value == nil {
+		return nil, ErrNotFound
+	}
+	return decodeEntry(key, value)
+}
+
+func putEntry(bucket *bolt.Bucket, entry *Entry) error {
+	value, err := json.Marshal(&record{
+		Query:       entry.Query,
+		Time:        entry.Time.UnixNano(),
+		ResultCount: entry.ResultCount,
+		NoteAdded:   entry.NoteAdded,
+	})
+	if err != nil {
+		return err
+	}
+	return bucket.Put(encodeID(entry.ID), value)
+}
+
+func decodeEntry(key, value []byte) (*Entry, error) {
+	var r record
+	if err := json.Unmarshal(value, &r); err != nil {
+		return nil, fmt.Errorf("entry decoding failed: %w", err)
+	}
+	return &Entry{
+		ID:          binary.BigEndian.Uint64(key),
+		Query:       r.Query,
+		Time:        time.Unix(0, r.Time),
+		ResultCount: r.ResultCount,
+		NoteAdded:   r.NoteAdded,
+	}, nil
+}
+
+// encodeID encodes id in big endian, so keys are ordered by id.
+func encodeID(id uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, id)
+	return key
+}
@@ -0,0 +1,198 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStore(t *testing.T, opts Options) (*Store, *time.Time) {
+	store, err := Open(filepath.Join(t.TempDir(), "history", "history.db"), opts)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		store.Close()
+	})
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time {
+		return now
+	}
+	return store, &now
+}
+
+func addQueries(t *testing.T, store *Store, now *time.Time, queries ...string) {
+	for i, query := range queries {
+		_, err := store.Add(query, i)
+		require.NoError(t, err)
+		*now = now.Add(time.Minute)
+	}
+}
+
+func listQueries(t *testing.T, store *Store, limit, offset int, filter *Filter) ([]string, int) {
+	entries, total, err := store.List(limit, offset, filter)
+	require.NoError(t, err)
+	var queries []string
+	for _, entry := range entries {
+		queries = append(queries, entry.Query)
+	}
+	return queries, total
+}
+
+func Test_Store_AddList(t *testing.T) {
+	store, now := newTestStore(t, Options{})
+	start := *now
+
+	entry, err := store.Add("犬", 3)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), entry.ID)
+
+	entries, total, err := store.List(0, 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, entries, 1)
+	assert.Equal(t, uint64(1), entries[0].ID)
+	assert.Equal(t, "犬", entries[0].Query)
+	assert.Equal(t, 3, entries[0].ResultCount)
+	assert.False(t, entries[0].NoteAdded)
+	assert.True(t, start.Equal(entries[0].Time))
+}
+
+func Test_Store_List(t *testing.T) {
+	store, now := newTestStore(t, Options{})
+	addQueries(t, store, now, "inu", "neko", "犬", "inuneko", "tori")
+	require.NoError(t, store.MarkNoteAdded(3))
+	noteAdded := true
+	notAdded := false
+
+	testCases := []struct {
+		Name     string
+		Limit    int
+		Offset   int
+		Filter   *Filter
+		Expected []string
+		Total    int
+	}{
+		{
+			Name:     "all",
+			Expected: []string{"tori", "inuneko", "犬", "neko", "inu"},
+			Total:    5,
+		},
+		{
+			Name:     "limit and offset",
+			Limit:    2,
+			Offset:   1,
+			Expected: []string{"inuneko", "犬"},
+			Total:    5,
+		},
+		{
+			Name:   "offset out of range",
+			Offset: 10,
+			Total:  5,
+		},
+		{
+			Name:     "query",
+			Filter:   &Filter{Query: "INU"},
+			Expected: []string{"inuneko", "inu"},
+			Total:    2,
+		},
+		{
+			Name:     "note added",
+			Filter:   &Filter{NoteAdded: &noteAdded},
+			Expected: []string{"犬"},
+			Total:    1,
+		},
+		{
+			Name:     "note not added",
+			Limit:    1,
+			Filter:   &Filter{Query: "neko", NoteAdded: &notAdded},
+			Expected: []string{"inuneko"},
+			Total:    2,
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			queries, total := listQueries(t, store, tc.Limit, tc.Offset, tc.Filter)
+			assert.Equal(t, tc.Expected, queries)
+			assert.Equal(t, tc.Total, total)
+		})
+	}
+}
+
+func Test_Store_DeleteClear(t *testing.T) {
+	store, now := newTestStore(t, Options{})
+	addQueries(t, store, now, "first", "second", "third")
+
+	entry, err := store.Delete(2)
+	require.NoError(t, err)
+	assert.Equal(t, "second", entry.Query)
+	_, err = store.Delete(2)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, store.MarkNoteAdded(2), ErrNotFound)
+	queries, _ := listQueries(t, store, 0, 0, nil)
+	assert.Equal(t, []string{"third", "first"}, queries)
+
+	require.NoError(t, store.Clear())
+	queries, total := listQueries(t, store, 0, 0, nil)
+	assert.Empty(t, queries)
+	assert.Zero(t, total)
+
+	// ids are not reused after clear
+	entry, err = store.Add("fourth", 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), entry.ID)
+}
+
+func Test_Store_Retention(t *testing.T) {
+	store, now := newTestStore(t, Options{Retention: 3 * time.Minute})
+	addQueries(t, store, now, "first", "second", "third", "fourth")
+
+	// entries are pruned only when new one is added
+	queries, _ := listQueries(t, store, 0, 0, nil)
+	assert.Equal(t, []string{"fourth", "third", "second", "first"}, queries)
+	// the first entry is added 4 minutes ago and the second 3 minutes ago
+	addQueries(t, store, now, "fifth")
+	queries, _ = listQueries(t, store, 0, 0, nil)
+	assert.Equal(t, []string{"fifth", "fourth", "third", "second"}, queries)
+}
+
+func Test_Store_MaxEntries(t *testing.T) {
+	store, now := newTestStore(t, Options{MaxEntries: 2})
+	addQueries(t, store, now, "first", "second", "third")
+
+	queries, total := listQueries(t, store, 0, 0, nil)
+	assert.Equal(t, []string{"third", "second"}, queries)
+	assert.Equal(t, 2, total)
+}
+
+func Test_Store_MaxEntries_DeleteClear(t *testing.T) {
+	store, now := newTestStore(t, Options{MaxEntries: 2})
+	addQueries(t, store, now, "first", "second")
+	// deleted entry frees place, so nothing is pruned
+	_, err := store.Delete(1)
+	require.NoError(t, err)
+	addQueries(t, store, now, "third")
+	queries, _ := listQueries(t, store, 0, 0, nil)
+	assert.Equal(t, []string{"third", "second"}, queries)
+
+	require.NoError(t, store.Clear())
+	addQueries(t, store, now, "fourth", "fifth")
+	queries, _ = listQueries(t, store, 0, 0, nil)
+	assert.Equal(t, []string{"fifth", "fourth"}, queries)
+}
+
+func Test_Open_Prune(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	store, err := Open(path, Options{})
+	require.NoError(t, err)
+	addQueries(t, store, new(time.Time), "first", "second", "third")
+	require.NoError(t, store.Close())
+
+	store, err = Open(path, Options{MaxEntries: 1})
+	require.NoError(t, err)
+	defer store.Close()
+	queries, _ := listQueries(t, store, 0, 0, nil)
+	assert.Equal(t, []string{"third"}, queries)
+}
@@ -0,0 +1,209 @@
+// httpfixture records http responses to fixture directory and replays them back.
+// It's used to run application offline and to build test corpora for dictionaries.
+package httpfixture
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Mode specifies how requests are handled.
+type Mode string
+
+const (
+	// ModeOff disables recording and replaying.
+	ModeOff Mode = "off"
+	// ModeRecord makes requests and saves responses to fixture directory.
+	ModeRecord Mode = "record"
+	// ModeReplay serves responses from fixture directory only.
+	ModeReplay Mode = "replay"
+)
+
+func ParseMode(src string) (Mode, error) {
+	switch mode := Mode(src); mode {
+	case "":
+		return ModeOff, nil
+	case ModeOff, ModeRecord, ModeReplay:
+		return mode, nil
+	default:
+		return ModeOff, fmt.Errorf("unknown http mode %q, should be one of: off, record, replay", src)
+	}
+}
+
+// ErrNotRecorded returned in replay mode if there is no fixture for request.
+var ErrNotRecorded = errors.New("request is not recorded")
+
+// Fetcher is the same interface as basicdict.Fetcher
+type Fetcher interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// fixture is metadata of recorded response, body is stored in separate file
+// to keep it readable and byte exact.
+type fixture struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+}
+
+const (
+	metaExt = ".json"
+	bodyExt = ".body"
+)
+
+// Recorder makes requests with underlying fetcher and saves every response to directory.
+// Existing fixtures for the same request are overwritten.
+type Recorder struct {
+	fetcher Fetcher
+	dir     string
+}
+
+func NewRecorder(f Fetcher, dir string) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+	return &Recorder{
+		fetcher: f,
+		dir:     dir,
+	}, nil
+}
+
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	resp, err := r.fetcher.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body for recording: %w", err)
+	}
+	meta := fixture{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Status: resp.StatusCode,
+		Header: resp.Header,
+	}
+	if err := r.save(&meta, body); err != nil {
+		return nil, fmt.Errorf("failed to record %s %s: %w", req.Method, req.URL, err)
+	}
+	return newResponse(req, &meta, body), nil
+}
+
+func (r *Recorder) save(meta *fixture, body []byte) error {
+	metaData, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	base := filepath.Join(r.dir, fixtureName(meta.Method, meta.URL))
+	// body is written first, so fixture without body is never visible
+	if err := writeFileAtomic(base+bodyExt, body); err != nil {
+		return err
+	}
+	return writeFileAtomic(base+metaExt, metaData)
+}
+
+// Replayer serves responses recorded by Recorder and never makes real requests.
+type Replayer struct {
+	dir string
+}
+
+func NewReplayer(dir string) (*Replayer, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("fixture directory is not available: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("fixture path %q is not a directory", dir)
+	}
+	return &Replayer{
+		dir: dir,
+	}, nil
+}
+
+func (r *Replayer) Do(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	url := req.URL.String()
+	base := filepath.Join(r.dir, fixtureName(req.Method, url))
+	metaData, err := os.ReadFile(base + metaExt)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s %s (fixture directory %s)", ErrNotRecorded, req.Method, url, r.dir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+	var meta fixture
+	if err := json.Unmarshal(metaData, &meta); err != nil {
+		return nil, fmt.Errorf("fixture %s is malformed: %w", base+metaExt, err)
+	}
+	if meta.Method != req.Method || meta.URL != url {
+		return nil, fmt.Errorf("fixture %s is recorded for %s %s", base+metaExt, meta.Method, meta.URL)
+	}
+	body, err := os.ReadFile(base + bodyExt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture body: %w", err)
+	}
+	return newResponse(req, &meta, body), nil
+}
+
+func newResponse(req *http.Request, meta *fixture, body []byte) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", meta.Status, http.StatusText(meta.Status)),
+		StatusCode:    meta.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        meta.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// fixtureName returns file name without extension for request.
+// It starts with host to make directory easier to browse.
+func fixtureName(method string, url string) string {
+	hash := sha256.Sum256([]byte(method + " " + url))
+	host := url
+	if _, rest, ok := strings.Cut(url, "://"); ok {
+		host = rest
+	}
+	host, _, _ = strings.Cut(host, "/")
+	host = strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' {
+			return r
+		}
+		return '_'
+	}, host)
+	return host + "-" + hex.EncodeToString(hash[:8])
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), path)
+	}
+	if err != nil {
+		os.Remove(file.Name())
+	}
+	return err
+}
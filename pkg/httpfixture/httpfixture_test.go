@@ -0,0 +1,128 @@
+package httpfixture
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseMode(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Src      string
+		Expected Mode
+		Error    bool
+	}{
+		{
+			Name:     "empty",
+			Expected: ModeOff,
+		},
+		{
+			Name:     "off",
+			Src:      "off",
+			Expected: ModeOff,
+		},
+		{
+			Name:     "record",
+			Src:      "record",
+			Expected: ModeRecord,
+		},
+		{
+			Name:     "replay",
+			Src:      "replay",
+			Expected: ModeReplay,
+		},
+		{
+			Name:  "unknown",
+			Src:   "play",
+			Error: true,
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			mode, err := ParseMode(tc.Src)
+			if tc.Error {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.Expected, mode)
+			}
+		})
+	}
+}
+
+func readResponse(t *testing.T, f Fetcher, url string) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	require.NoError(t, err)
+	resp, err := f.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+func Test_RecordReplay(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		_, _ = io.WriteString(w, "<p>"+r.URL.RawQuery+"</p>")
+	}))
+	defer server.Close()
+	dir := filepath.Join(t.TempDir(), "fixtures")
+
+	recorder, err := NewRecorder(http.DefaultClient, dir)
+	require.NoError(t, err)
+	resp, body := readResponse(t, recorder, server.URL+"/search?q=犬")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "<p>q=犬</p>", body)
+	resp, body = readResponse(t, recorder, server.URL+"/missing")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "<p></p>", body)
+	require.Equal(t, 2, requests)
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 4)
+
+	replayer, err := NewReplayer(dir)
+	require.NoError(t, err)
+	resp, body = readResponse(t, replayer, server.URL+"/search?q=犬")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/html; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "<p>q=犬</p>", body)
+	resp, body = readResponse(t, replayer, server.URL+"/missing")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "<p></p>", body)
+	assert.Equal(t, 2, requests, "replayer should not make requests")
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL+"/search?q=猫", nil)
+	require.NoError(t, err)
+	_, err = replayer.Do(req)
+	assert.ErrorIs(t, err, ErrNotRecorded)
+	assert.ErrorContains(t, err, "q=猫")
+	assert.Equal(t, 2, requests)
+}
+
+func Test_NewReplayer_NoDir(t *testing.T) {
+	_, err := NewReplayer(filepath.Join(t.TempDir(), "notexists"))
+	assert.ErrorContains(t, err, "fixture directory is not available")
+}
+
+func Test_fixtureName(t *testing.T) {
+	name := fixtureName("GET", "https://jisho.org/search/%E7%8A%AC")
+	assert.Regexp(t, `^jisho\.org-[0-9a-f]{16}$`, name)
+	assert.NotEqual(t, name, fixtureName("HEAD", "https://jisho.org/search/%E7%8A%AC"))
+}
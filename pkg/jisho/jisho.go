@@ -3,15 +3,19 @@ package jisho
 import (
 	"context"
 	"net/url"
+	"sync"
 
 	"github.com/Darkclainer/japwords/pkg/lemma"
 )
 
-const defaultBaseURL = "https://jisho.org/search/"
+// DefaultBaseURL is used if base url is not configured.
+const DefaultBaseURL = "https://jisho.org/search/"
 
 type Jisho struct {
-	client  BasicDict
-	baseURL string
+	client BasicDict
+
+	baseURLLock sync.RWMutex
+	baseURL     string
 }
 
 type BasicDict interface {
@@ -19,13 +23,21 @@ type BasicDict interface {
 }
 
 func New(client BasicDict, baseURL string) *Jisho {
-	if baseURL == "" {
-		baseURL = defaultBaseURL
+	dict := &Jisho{
+		client: client,
 	}
-	return &Jisho{
-		client:  client,
-		baseURL: baseURL,
+	dict.SetBaseURL(baseURL)
+	return dict
+}
+
+// SetBaseURL changes url that is used for subsequent queries, empty url means default one.
+func (j *Jisho) SetBaseURL(baseURL string) {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
 	}
+	j.baseURLLock.Lock()
+	defer j.baseURLLock.Unlock()
+	j.baseURL = baseURL
 }
 
 func (j *Jisho) Query(ctx context.Context, query string) ([]*lemma.Lemma, error) {
@@ -38,5 +50,7 @@ func (j *Jisho) Query(ctx context.Context, query string) ([]*lemma.Lemma, error)
 }
 
 func (j *Jisho) queryURL(query string) string {
+	j.baseURLLock.RLock()
+	defer j.baseURLLock.RUnlock()
 	return j.baseURL + url.PathEscape(query)
 }
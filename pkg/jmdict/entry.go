@@ -0,0 +1,201 @@
+package jmdict
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Darkclainer/japwords/pkg/kana"
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+// Entry is format independent representation of JMdict entry.
+// Both XML and JSON parsers produce this structure.
+type Entry struct {
+	ID       string
+	Kanji    []KanjiElement
+	Readings []ReadingElement
+	Senses   []Sense
+}
+
+type KanjiElement struct {
+	Text   string
+	Common bool
+	Info   []string
+}
+
+type ReadingElement struct {
+	Text   string
+	Common bool
+	// NoKanji indicates that reading is not true reading of kanji.
+	NoKanji bool
+	// Restrictions is list of kanji elements this reading is applied to.
+	// Empty list means that reading is applied to all kanji elements.
+	Restrictions []string
+	Info         []string
+}
+
+type Sense struct {
+	PartOfSpeech []string
+	Glosses      []string
+	// Tags contains misc, field, dialect and sense info
+	Tags []string
+}
+
+const commonWordTag = "Common word"
+
+// Lemma converts entry to lemma. Kanji and reading elements are expected to be
+// sorted by priority (as they are in JMdict), so first suitable pair becomes slug.
+func (e *Entry) Lemma() *lemma.Lemma {
+	var result lemma.Lemma
+	if len(e.Kanji) == 0 {
+		if len(e.Readings) == 0 {
+			return nil
+		}
+		// kana only words are represented like in jisho: without hiragana and furigana
+		result.Slug = lemma.Word{
+			Word: e.Readings[0].Text,
+		}
+		for _, reading := range e.Readings[1:] {
+			result.Forms = append(result.Forms, lemma.Word{
+				Word: reading.Text,
+			})
+		}
+	} else {
+		for i, kanji := range e.Kanji {
+			word := lemma.Word{
+				Word: kanji.Text,
+			}
+			if reading := e.readingFor(kanji.Text); reading != "" {
+				word.Hiragana = reading
+				word.Furigana = alignFurigana(kanji.Text, reading)
+			}
+			if i == 0 {
+				result.Slug = word
+			} else {
+				result.Forms = append(result.Forms, word)
+			}
+		}
+		// readings that are not readings of kanji are valid forms on its own
+		for _, reading := range e.Readings {
+			if reading.NoKanji {
+				result.Forms = append(result.Forms, lemma.Word{
+					Word: reading.Text,
+				})
+			}
+		}
+	}
+	if e.isCommon() {
+		result.Tags = append(result.Tags, commonWordTag)
+	}
+	for _, sense := range e.Senses {
+		if len(sense.Glosses) == 0 {
+			continue
+		}
+		result.Senses = append(result.Senses, lemma.WordSense{
+			Definition:   slices.Clone(sense.Glosses),
+			PartOfSpeech: slices.Clone(sense.PartOfSpeech),
+			Tags:         slices.Clone(sense.Tags),
+		})
+	}
+	return &result
+}
+
+// Keys returns all strings by which entry can be found.
+func (e *Entry) Keys() []string {
+	var keys []string
+	for _, kanji := range e.Kanji {
+		keys = append(keys, kanji.Text)
+	}
+	for _, reading := range e.Readings {
+		keys = append(keys, reading.Text)
+	}
+	slices.Sort(keys)
+	return slices.Compact(keys)
+}
+
+func (e *Entry) readingFor(kanji string) string {
+	for _, reading := range e.Readings {
+		if reading.NoKanji {
+			continue
+		}
+		if len(reading.Restrictions) == 0 || slices.Contains(reading.Restrictions, kanji) {
+			return reading.Text
+		}
+	}
+	return ""
+}
+
+func (e *Entry) isCommon() bool {
+	for _, kanji := range e.Kanji {
+		if kanji.Common {
+			return true
+		}
+	}
+	for _, reading := range e.Readings {
+		if reading.Common {
+			return true
+		}
+	}
+	return false
+}
+
+// commonPriorities are priority codes that jisho (and edict) consider as common word.
+var commonPriorities = []string{"news1", "ichi1", "spec1", "spec2", "gai1"}
+
+func isCommonPriority(priority string) bool {
+	return slices.Contains(commonPriorities, priority)
+}
+
+// alignFurigana splits word to parts in such way that every kanji run get its part of
+// reading. If alignment is not possible, the whole word gets the whole reading.
+func alignFurigana(word, reading string) lemma.Furigana {
+	type run struct {
+		Text string
+		Kana bool
+	}
+	var runs []run
+	for _, r := range word {
+		isKana := kana.IsKana(r)
+		if len(runs) != 0 && runs[len(runs)-1].Kana == isKana && !isKana {
+			runs[len(runs)-1].Text += string(r)
+			continue
+		}
+		runs = append(runs, run{Text: string(r), Kana: isKana})
+	}
+	var pattern strings.Builder
+	pattern.WriteByte('^')
+	for _, r := range runs {
+		if r.Kana {
+			pattern.WriteString(regexp.QuoteMeta(kana.ToHiragana(r.Text)))
+		} else {
+			pattern.WriteString("(.+?)")
+		}
+	}
+	pattern.WriteByte('$')
+	matches := regexp.MustCompile(pattern.String()).FindStringSubmatch(kana.ToHiragana(reading))
+	if matches == nil {
+		return lemma.Furigana{
+			{
+				Kanji:    word,
+				Hiragana: reading,
+			},
+		}
+	}
+	furigana := make(lemma.Furigana, 0, len(runs))
+	group := 1
+	for _, r := range runs {
+		if r.Kana {
+			furigana = append(furigana, lemma.FuriganaChar{
+				Hiragana: r.Text,
+			})
+			continue
+		}
+		furigana = append(furigana, lemma.FuriganaChar{
+			Kanji:    r.Text,
+			Hiragana: matches[group],
+		})
+		group++
+	}
+	return furigana
+}
@@ -0,0 +1,247 @@
+// jmdict is offline lemma dictionary based on JMdict project
+// (https://www.edrdg.org/jmdict/j_jmdict.html). Dictionary file
+// should be imported first with Import and then can be opened with Open.
+package jmdict
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+const (
+	// formatVersion is incremented every time when index layout is changed
+	formatVersion = "1"
+	// keySeparator separates key (word) and entry id in keys bucket
+	keySeparator = 0
+	// importBatchSize is how many entries written in single transaction
+	importBatchSize = 1000
+)
+
+var (
+	metaBucket    = []byte("meta")
+	entriesBucket = []byte("entries")
+	keysBucket    = []byte("keys")
+
+	versionKey = []byte("version")
+)
+
+var ErrIncompatibleIndex = errors.New("jmdict index has incompatible format, it should be imported again")
+
+// JMdict is implementation of multidict.LemmaDict.
+type JMdict struct {
+	db *bolt.DB
+}
+
+// Open opens index previously created by Import.
+func Open(path string) (*JMdict, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("jmdict index is not available: %w", err)
+	}
+	db, err := bolt.Open(path, 0o644, &bolt.Options{
+		ReadOnly: true,
+		Timeout:  time.Second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("jmdict index open failed: %w", err)
+	}
+	err = db.View(func(tx *bolt.Tx) error {
+		meta := tx.Bucket(metaBucket)
+		if meta == nil || string(meta.Get(versionKey)) != formatVersion {
+			return ErrIncompatibleIndex
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &JMdict{
+		db: db,
+	}, nil
+}
+
+func (j *JMdict) Close() error {
+	return j.db.Close()
+}
+
+// Query returns lemmas which kanji or reading is exactly query.
+// Common words goes first.
+func (j *JMdict) Query(ctx context.Context, query string) ([]*lemma.Lemma, error) {
+	if query == "" {
+		return nil, nil
+	}
+	var common, other []*lemma.Lemma
+	err := j.db.View(func(tx *bolt.Tx) error {
+		entries := tx.Bucket(entriesBucket)
+		prefix := append([]byte(query), keySeparator)
+		cursor := tx.Bucket(keysBucket).Cursor()
+		for k, _ := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = cursor.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			var l lemma.Lemma
+			if err := json.Unmarshal(entries.Get(k[len(prefix):]), &l); err != nil {
+				return fmt.Errorf("entry decoding failed: %w", err)
+			}
+			if isCommonLemma(&l) {
+				common = append(common, &l)
+			} else {
+				other = append(other, &l)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append(common, other...), nil
+}
+
+// ForEachLemma calls fn for every lemma in dictionary. Iteration stops if fn returns error
+// or ctx is done.
+func (j *JMdict) ForEachLemma(ctx context.Context, fn func(*lemma.Lemma) error) error {
+	return j.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(entriesBucket).ForEach(func(_, v []byte) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			var l lemma.Lemma
+			if err := json.Unmarshal(v, &l); err != nil {
+				return fmt.Errorf("entry decoding failed: %w", err)
+			}
+			return fn(&l)
+		})
+	})
+}
+
+func isCommonLemma(l *lemma.Lemma) bool {
+	for _, tag := range l.Tags {
+		if tag == commonWordTag {
+			return true
+		}
+	}
+	return false
+}
+
+// Parser is function that parses dictionary source, for example ParseXML or ParseJSON.
+type Parser func(EntryHandler) error
+
+// Import creates new index at path from entries provided by parser.
+// Index is written to temporary file and replaces file at path only on success.
+// It returns number of imported entries.
+func Import(path string, parse Parser) (int, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return 0, err
+	}
+	tmpPath := path + ".tmp"
+	if err := os.Remove(tmpPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, err
+	}
+	db, err := bolt.Open(tmpPath, 0o644, &bolt.Options{
+		Timeout: time.Second,
+		// we write into temporary file anyway
+		NoSync: true,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("index creation failed: %w", err)
+	}
+	imported, err := importEntries(db, parse)
+	if err != nil {
+		db.Close()
+		os.Remove(tmpPath)
+		return 0, err
+	}
+	if err := db.Sync(); err != nil {
+		db.Close()
+		os.Remove(tmpPath)
+		return 0, err
+	}
+	if err := db.Close(); err != nil {
+		os.Remove(tmpPath)
+		return 0, err
+	}
+	return imported, os.Rename(tmpPath, path)
+}
+
+func importEntries(db *bolt.DB, parse Parser) (int, error) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		meta, err := tx.CreateBucket(metaBucket)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.CreateBucket(entriesBucket); err != nil {
+			return err
+		}
+		if _, err := tx.CreateBucket(keysBucket); err != nil {
+			return err
+		}
+		return meta.Put(versionKey, []byte(formatVersion))
+	})
+	if err != nil {
+		return 0, err
+	}
+	var (
+		imported int
+		batch    []*Entry
+	)
+	flush := func() error {
+		err := db.Update(func(tx *bolt.Tx) error {
+			entries := tx.Bucket(entriesBucket)
+			keys := tx.Bucket(keysBucket)
+			for _, entry := range batch {
+				l := entry.Lemma()
+				if l == nil {
+					continue
+				}
+				value, err := json.Marshal(l)
+				if err != nil {
+					return err
+				}
+				// we use our own sequence, because entry id is not guaranteed to be present in every format
+				seq, err := entries.NextSequence()
+				if err != nil {
+					return err
+				}
+				id := binary.BigEndian.AppendUint64(nil, seq)
+				if err := entries.Put(id, value); err != nil {
+					return err
+				}
+				for _, key := range entry.Keys() {
+					indexKey := append(append([]byte(key), keySeparator), id...)
+					if err := keys.Put(indexKey, nil); err != nil {
+						return err
+					}
+				}
+				imported++
+			}
+			return nil
+		})
+		batch = batch[:0]
+		return err
+	}
+	err = parse(func(entry *Entry) error {
+		batch = append(batch, entry)
+		if len(batch) < importBatchSize {
+			return nil
+		}
+		return flush()
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := flush(); err != nil {
+		return 0, err
+	}
+	return imported, nil
+}
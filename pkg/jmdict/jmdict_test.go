@@ -0,0 +1,143 @@
+package jmdict
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+func newTestJMdict(t *testing.T) *JMdict {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "jmdict.db")
+	imported, err := Import(path, func(h EntryHandler) error {
+		file, err := os.Open("testdata/JMdict_excerpt.xml")
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		return ParseXML(file, h)
+	})
+	require.NoError(t, err)
+	require.Equal(t, 5, imported)
+	dict, err := Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { dict.Close() })
+	return dict
+}
+
+func Test_JMdict_Query(t *testing.T) {
+	dict := newTestJMdict(t)
+	testCases := []struct {
+		Name          string
+		Query         string
+		ExpectedSlugs []string
+	}{
+		{
+			Name:          "kanji",
+			Query:         "犬",
+			ExpectedSlugs: []string{"犬"},
+		},
+		{
+			Name:          "reading",
+			Query:         "たべる",
+			ExpectedSlugs: []string{"食べる"},
+		},
+		{
+			Name:          "other form",
+			Query:         "狗",
+			ExpectedSlugs: []string{"犬"},
+		},
+		{
+			Name:          "second reading",
+			Query:         "にっぽん",
+			ExpectedSlugs: []string{"日本"},
+		},
+		{
+			Name:          "kana only",
+			Query:         "ペラペラ",
+			ExpectedSlugs: []string{"ぺらぺら"},
+		},
+		{
+			Name:  "prefix is not match",
+			Query: "日",
+		},
+		{
+			Name:  "empty",
+			Query: "",
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			lemmas, err := dict.Query(context.Background(), tc.Query)
+			require.NoError(t, err)
+			var slugs []string
+			for _, l := range lemmas {
+				slugs = append(slugs, l.Slug.Word)
+			}
+			assert.Equal(t, tc.ExpectedSlugs, slugs)
+		})
+	}
+}
+
+func Test_Import_Failed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jmdict.db")
+	_, err := Import(path, func(h EntryHandler) error {
+		return errors.New("parse error")
+	})
+	require.ErrorContains(t, err, "parse error")
+	// neither index nor temporary file should be left
+	_, err = os.Stat(path)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	_, err = os.Stat(path + ".tmp")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func Test_Open(t *testing.T) {
+	t.Run("NotExists", func(t *testing.T) {
+		_, err := Open(filepath.Join(t.TempDir(), "notexists"))
+		assert.ErrorContains(t, err, "jmdict index is not available")
+	})
+	t.Run("Incompatible", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "jmdict.db")
+		db, err := bolt.Open(path, 0o644, nil)
+		require.NoError(t, err)
+		require.NoError(t, db.Close())
+		_, err = Open(path)
+		assert.ErrorIs(t, err, ErrIncompatibleIndex)
+	})
+}
+
+func Test_JMdict_ForEachLemma(t *testing.T) {
+	dict := newTestJMdict(t)
+	var (
+		slugs  []string
+		common []string
+	)
+	err := dict.ForEachLemma(context.Background(), func(l *lemma.Lemma) error {
+		slugs = append(slugs, l.Slug.Word)
+		if isCommonLemma(l) {
+			common = append(common, l.Slug.Word)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, slugs, 5)
+	assert.Contains(t, slugs, "犬")
+	assert.Contains(t, common, "犬")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = dict.ForEachLemma(ctx, func(l *lemma.Lemma) error {
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+}
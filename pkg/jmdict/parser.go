@@ -0,0 +1,279 @@
+package jmdict
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// EntryHandler is called for every parsed entry.
+// If handler returns error, parsing stops and error is returned.
+type EntryHandler func(*Entry) error
+
+var entityRegex = regexp.MustCompile(`<!ENTITY\s+(\S+)\s+"([^"]*)"\s*>`)
+
+// ParseXML parses original JMdict XML file. Entities declared in DOCTYPE
+// are expanded to their descriptions.
+func ParseXML(src io.Reader, handler EntryHandler) error {
+	decoder := xml.NewDecoder(src)
+	decoder.Entity = map[string]string{}
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("xml parsing failed: %w", err)
+		}
+		switch t := token.(type) {
+		case xml.Directive:
+			for _, match := range entityRegex.FindAllSubmatch(t, -1) {
+				decoder.Entity[string(match[1])] = string(match[2])
+			}
+		case xml.StartElement:
+			if t.Name.Local != "entry" {
+				continue
+			}
+			var raw xmlEntry
+			if err := decoder.DecodeElement(&raw, &t); err != nil {
+				return fmt.Errorf("entry decoding failed: %w", err)
+			}
+			if err := handler(raw.entry()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+type xmlEntry struct {
+	Sequence string `xml:"ent_seq"`
+	Kanji    []struct {
+		Text     string   `xml:"keb"`
+		Info     []string `xml:"ke_inf"`
+		Priority []string `xml:"ke_pri"`
+	} `xml:"k_ele"`
+	Readings []struct {
+		Text         string    `xml:"reb"`
+		NoKanji      *struct{} `xml:"re_nokanji"`
+		Restrictions []string  `xml:"re_restr"`
+		Info         []string  `xml:"re_inf"`
+		Priority     []string  `xml:"re_pri"`
+	} `xml:"r_ele"`
+	Senses []struct {
+		PartOfSpeech []string `xml:"pos"`
+		Field        []string `xml:"field"`
+		Misc         []string `xml:"misc"`
+		Dialect      []string `xml:"dial"`
+		Info         []string `xml:"s_inf"`
+		Glosses      []struct {
+			Lang string `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+			Text string `xml:",chardata"`
+		} `xml:"gloss"`
+	} `xml:"sense"`
+}
+
+func (x *xmlEntry) entry() *Entry {
+	entry := &Entry{
+		ID: strings.TrimSpace(x.Sequence),
+	}
+	for _, kanji := range x.Kanji {
+		entry.Kanji = append(entry.Kanji, KanjiElement{
+			Text:   kanji.Text,
+			Common: hasCommonPriority(kanji.Priority),
+			Info:   kanji.Info,
+		})
+	}
+	for _, reading := range x.Readings {
+		entry.Readings = append(entry.Readings, ReadingElement{
+			Text:         reading.Text,
+			Common:       hasCommonPriority(reading.Priority),
+			NoKanji:      reading.NoKanji != nil,
+			Restrictions: reading.Restrictions,
+			Info:         reading.Info,
+		})
+	}
+	// part of speech is inherited by following senses, unless they specify their own
+	var partOfSpeech []string
+	for _, sense := range x.Senses {
+		if len(sense.PartOfSpeech) != 0 {
+			partOfSpeech = sense.PartOfSpeech
+		}
+		var glosses []string
+		for _, gloss := range sense.Glosses {
+			if gloss.Lang != "" && gloss.Lang != "eng" {
+				continue
+			}
+			glosses = append(glosses, gloss.Text)
+		}
+		var tags []string
+		tags = append(tags, sense.Misc...)
+		tags = append(tags, sense.Field...)
+		tags = append(tags, sense.Dialect...)
+		tags = append(tags, sense.Info...)
+		entry.Senses = append(entry.Senses, Sense{
+			PartOfSpeech: partOfSpeech,
+			Glosses:      glosses,
+			Tags:         tags,
+		})
+	}
+	return entry
+}
+
+func hasCommonPriority(priorities []string) bool {
+	for _, priority := range priorities {
+		if isCommonPriority(priority) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseJSON parses JMdict in format of jmdict-simplified project
+// (https://github.com/scriptin/jmdict-simplified).
+// Tags are expanded to their description.
+func ParseJSON(src io.Reader, handler EntryHandler) error {
+	decoder := json.NewDecoder(src)
+	if err := expectDelim(decoder, '{'); err != nil {
+		return err
+	}
+	tags := map[string]string{}
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return fmt.Errorf("json parsing failed: %w", err)
+		}
+		switch key {
+		case "tags":
+			if err := decoder.Decode(&tags); err != nil {
+				return fmt.Errorf("tags decoding failed: %w", err)
+			}
+		case "words":
+			if err := expectDelim(decoder, '['); err != nil {
+				return err
+			}
+			for decoder.More() {
+				var word jsonWord
+				if err := decoder.Decode(&word); err != nil {
+					return fmt.Errorf("word decoding failed: %w", err)
+				}
+				if err := handler(word.entry(tags)); err != nil {
+					return err
+				}
+			}
+			if err := expectDelim(decoder, ']'); err != nil {
+				return err
+			}
+		default:
+			// skip value of unknown key
+			var skip json.RawMessage
+			if err := decoder.Decode(&skip); err != nil {
+				return fmt.Errorf("json parsing failed: %w", err)
+			}
+		}
+	}
+	return expectDelim(decoder, '}')
+}
+
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return fmt.Errorf("json parsing failed: %w", err)
+	}
+	if token != delim {
+		return fmt.Errorf("json parsing failed: expected %q, got %v", delim, token)
+	}
+	return nil
+}
+
+type jsonWord struct {
+	ID    string `json:"id"`
+	Kanji []struct {
+		Common bool     `json:"common"`
+		Text   string   `json:"text"`
+		Tags   []string `json:"tags"`
+	} `json:"kanji"`
+	Kana []struct {
+		Common         bool     `json:"common"`
+		Text           string   `json:"text"`
+		Tags           []string `json:"tags"`
+		AppliesToKanji []string `json:"appliesToKanji"`
+	} `json:"kana"`
+	Sense []struct {
+		PartOfSpeech []string `json:"partOfSpeech"`
+		Field        []string `json:"field"`
+		Dialect      []string `json:"dialect"`
+		Misc         []string `json:"misc"`
+		Info         []string `json:"info"`
+		Gloss        []struct {
+			Lang string `json:"lang"`
+			Text string `json:"text"`
+		} `json:"gloss"`
+	} `json:"sense"`
+}
+
+func (w *jsonWord) entry(tags map[string]string) *Entry {
+	expand := func(codes []string) []string {
+		if len(codes) == 0 {
+			return nil
+		}
+		result := make([]string, len(codes))
+		for i, code := range codes {
+			if description, ok := tags[code]; ok {
+				result[i] = description
+			} else {
+				result[i] = code
+			}
+		}
+		return result
+	}
+	entry := &Entry{
+		ID: w.ID,
+	}
+	for _, kanji := range w.Kanji {
+		entry.Kanji = append(entry.Kanji, KanjiElement{
+			Text:   kanji.Text,
+			Common: kanji.Common,
+			Info:   expand(kanji.Tags),
+		})
+	}
+	for _, kana := range w.Kana {
+		reading := ReadingElement{
+			Text:   kana.Text,
+			Common: kana.Common,
+			Info:   expand(kana.Tags),
+		}
+		switch {
+		case len(kana.AppliesToKanji) == 0:
+			// jmdict-simplified represents re_nokanji as empty list
+			reading.NoKanji = len(w.Kanji) != 0
+		case len(kana.AppliesToKanji) == 1 && kana.AppliesToKanji[0] == "*":
+		default:
+			reading.Restrictions = kana.AppliesToKanji
+		}
+		entry.Readings = append(entry.Readings, reading)
+	}
+	for _, sense := range w.Sense {
+		var glosses []string
+		for _, gloss := range sense.Gloss {
+			if gloss.Lang != "" && gloss.Lang != "eng" {
+				continue
+			}
+			glosses = append(glosses, gloss.Text)
+		}
+		var senseTags []string
+		senseTags = append(senseTags, expand(sense.Misc)...)
+		senseTags = append(senseTags, expand(sense.Field)...)
+		senseTags = append(senseTags, expand(sense.Dialect)...)
+		senseTags = append(senseTags, sense.Info...)
+		entry.Senses = append(entry.Senses, Sense{
+			PartOfSpeech: expand(sense.PartOfSpeech),
+			Glosses:      glosses,
+			Tags:         senseTags,
+		})
+	}
+	return entry
+}
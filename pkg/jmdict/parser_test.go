@@ -0,0 +1,218 @@
+package jmdict
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+func parseTestFile(t *testing.T, path string, parse func(*os.File, EntryHandler) error) map[string]*Entry {
+	t.Helper()
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	entries := map[string]*Entry{}
+	err = parse(file, func(e *Entry) error {
+		entries[e.ID] = e
+		return nil
+	})
+	require.NoError(t, err)
+	return entries
+}
+
+func Test_ParseXML(t *testing.T) {
+	entries := parseTestFile(t, "testdata/JMdict_excerpt.xml", func(f *os.File, h EntryHandler) error {
+		return ParseXML(f, h)
+	})
+	require.Len(t, entries, 5)
+	assert.Equal(t, &Entry{
+		ID: "1601720",
+		Kanji: []KanjiElement{
+			{Text: "犬", Common: true},
+			{Text: "狗", Info: []string{"rarely used kanji form"}},
+		},
+		Readings: []ReadingElement{
+			{Text: "いぬ", Common: true},
+			{Text: "イヌ", NoKanji: true},
+		},
+		Senses: []Sense{
+			{
+				PartOfSpeech: []string{"noun (common) (futsuumeishi)"},
+				Glosses:      []string{"dog (Canis (lupus) familiaris)"},
+				Tags:         []string{"zoology"},
+			},
+			{
+				// part of speech is inherited
+				PartOfSpeech: []string{"noun (common) (futsuumeishi)"},
+				Glosses:      []string{"squealer", "rat", "snitch"},
+				Tags:         []string{"derogatory"},
+			},
+		},
+	}, entries["1601720"])
+	assert.Equal(t, []Sense{
+		{
+			PartOfSpeech: []string{"adverb (fukushi)", "adverb taking the `to' particle", "onomatopoeic or mimetic word"},
+			Glosses:      []string{"fluently (speaking a foreign language)"},
+		},
+		{
+			PartOfSpeech: []string{"noun or participle which takes the aux. verb suru"},
+			Glosses:      []string{"flimsy"},
+			Tags:         []string{"word usually written using kana alone", "of paper"},
+		},
+	}, entries["1010020"].Senses)
+}
+
+func Test_ParseJSON(t *testing.T) {
+	jsonEntries := parseTestFile(t, "testdata/jmdict-eng-excerpt.json", func(f *os.File, h EntryHandler) error {
+		return ParseJSON(f, h)
+	})
+	require.Len(t, jsonEntries, 2)
+	xmlEntries := parseTestFile(t, "testdata/JMdict_excerpt.xml", func(f *os.File, h EntryHandler) error {
+		return ParseXML(f, h)
+	})
+	// both formats should result in the same lemma
+	assert.Equal(t, xmlEntries["1601720"].Lemma(), jsonEntries["1601720"].Lemma())
+	assert.Equal(t, []string{"Ichidan verb", "transitive verb"}, jsonEntries["1358280"].Senses[0].PartOfSpeech)
+}
+
+func Test_Entry_Lemma(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Entry    *Entry
+		Expected *lemma.Lemma
+	}{
+		{
+			Name:     "empty",
+			Entry:    &Entry{},
+			Expected: nil,
+		},
+		{
+			Name: "kana only",
+			Entry: &Entry{
+				Readings: []ReadingElement{
+					{Text: "ぺらぺら", Common: true},
+					{Text: "ペラペラ"},
+				},
+				Senses: []Sense{
+					{Glosses: []string{"fluently"}, PartOfSpeech: []string{"adverb"}},
+					// senses without english glosses are skipped
+					{},
+				},
+			},
+			Expected: &lemma.Lemma{
+				Slug:  lemma.Word{Word: "ぺらぺら"},
+				Tags:  []string{"Common word"},
+				Forms: []lemma.Word{{Word: "ペラペラ"}},
+				Senses: []lemma.WordSense{
+					{Definition: []string{"fluently"}, PartOfSpeech: []string{"adverb"}},
+				},
+			},
+		},
+		{
+			Name: "restricted readings",
+			Entry: &Entry{
+				Kanji: []KanjiElement{
+					{Text: "日本"},
+					{Text: "日ノ本"},
+				},
+				Readings: []ReadingElement{
+					{Text: "ひのもと", Restrictions: []string{"日ノ本"}},
+					{Text: "にほん"},
+				},
+			},
+			Expected: &lemma.Lemma{
+				Slug: lemma.Word{
+					Word:     "日本",
+					Hiragana: "にほん",
+					Furigana: lemma.Furigana{{Kanji: "日本", Hiragana: "にほん"}},
+				},
+				Forms: []lemma.Word{
+					{
+						Word:     "日ノ本",
+						Hiragana: "ひのもと",
+						Furigana: lemma.Furigana{
+							{Kanji: "日", Hiragana: "ひ"},
+							{Hiragana: "ノ"},
+							{Kanji: "本", Hiragana: "もと"},
+						},
+					},
+				},
+			},
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, tc.Entry.Lemma())
+		})
+	}
+}
+
+func Test_alignFurigana(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Word     string
+		Reading  string
+		Expected lemma.Furigana
+	}{
+		{
+			Name:    "single kanji",
+			Word:    "犬",
+			Reading: "いぬ",
+			Expected: lemma.Furigana{
+				{Kanji: "犬", Hiragana: "いぬ"},
+			},
+		},
+		{
+			Name:    "okurigana",
+			Word:    "食べる",
+			Reading: "たべる",
+			Expected: lemma.Furigana{
+				{Kanji: "食", Hiragana: "た"},
+				{Hiragana: "べ"},
+				{Hiragana: "る"},
+			},
+		},
+		{
+			Name:    "kana in the middle",
+			Word:    "犬も食わない",
+			Reading: "いぬもくわない",
+			Expected: lemma.Furigana{
+				{Kanji: "犬", Hiragana: "いぬ"},
+				{Hiragana: "も"},
+				{Kanji: "食", Hiragana: "く"},
+				{Hiragana: "わ"},
+				{Hiragana: "な"},
+				{Hiragana: "い"},
+			},
+		},
+		{
+			Name:    "katakana matches hiragana reading",
+			Word:    "日ノ本",
+			Reading: "ひのもと",
+			Expected: lemma.Furigana{
+				{Kanji: "日", Hiragana: "ひ"},
+				{Hiragana: "ノ"},
+				{Kanji: "本", Hiragana: "もと"},
+			},
+		},
+		{
+			Name:    "not aligned",
+			Word:    "一ヶ月",
+			Reading: "いっかげつ",
+			Expected: lemma.Furigana{
+				{Kanji: "一ヶ月", Hiragana: "いっかげつ"},
+			},
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, alignFurigana(tc.Word, tc.Reading))
+		})
+	}
+}
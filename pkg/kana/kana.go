@@ -0,0 +1,107 @@
+// kana contains conversions between hiragana, katakana and romaji
+// that are needed to normalize user input.
+package kana
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	// katakanaOffset is distance between the same kana in katakana and hiragana blocks
+	katakanaOffset = 'ァ' - 'ぁ'
+	// LongVowelMark is used mostly in katakana to prolong previous vowel
+	LongVowelMark = 'ー'
+)
+
+// IsKana reports whether r is hiragana, katakana or long vowel mark.
+func IsKana(r rune) bool {
+	return unicode.In(r, unicode.Hiragana, unicode.Katakana) || r == LongVowelMark
+}
+
+// IsHiragana reports whether every rune of src is hiragana or long vowel mark.
+func IsHiragana(src string) bool {
+	for _, r := range src {
+		if !unicode.Is(unicode.Hiragana, r) && r != LongVowelMark {
+			return false
+		}
+	}
+	return src != ""
+}
+
+// ToHiragana converts katakana to hiragana, other symbols are left as is.
+func ToHiragana(src string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'ァ' && r <= 'ヶ' {
+			return r - katakanaOffset
+		}
+		return r
+	}, src)
+}
+
+// ToKatakana converts hiragana to katakana, other symbols are left as is.
+func ToKatakana(src string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'ぁ' && r <= 'ゖ' {
+			return r + katakanaOffset
+		}
+		return r
+	}, src)
+}
+
+// vowelRows maps every vowel to kana which ends with it.
+var vowelRows = map[rune]string{
+	'あ': "あかさたなはまやらわがざだばぱぁゃゎ",
+	'い': "いきしちにひみりゐぎじぢびぴぃ",
+	'う': "うくすつぬふむゆるぐずづぶぷゔぅゅっ",
+	'え': "えけせてねへめれゑげぜでべぺぇ",
+	'お': "おこそとのほもよろをごぞどぼぽぉょ",
+}
+
+var kanaVowels = func() map[rune]rune {
+	vowels := map[rune]rune{}
+	for vowel, row := range vowelRows {
+		for _, r := range row {
+			vowels[r] = vowel
+		}
+	}
+	return vowels
+}()
+
+// ExpandLongVowels replaces long vowel marks by vowels of previous kana,
+// for example らーめん becomes らあめん and ラーメン becomes ラアメン.
+// Long vowel mark that can't be expanded is left as is.
+func ExpandLongVowels(src string) string {
+	if !strings.ContainsRune(src, LongVowelMark) {
+		return src
+	}
+	var (
+		result   strings.Builder
+		previous rune
+	)
+	for _, r := range src {
+		if r == LongVowelMark {
+			r = expandLongVowel(previous)
+		}
+		result.WriteRune(r)
+		previous = r
+	}
+	return result.String()
+}
+
+// expandLongVowel returns vowel of kana (in the same script), or long vowel mark
+// if vowel is unknown.
+func expandLongVowel(previous rune) rune {
+	if unicode.Is(unicode.Katakana, previous) {
+		vowel, ok := kanaVowels[previous-katakanaOffset]
+		if !ok {
+			return LongVowelMark
+		}
+		return vowel + katakanaOffset
+	}
+	vowel, ok := kanaVowels[previous]
+	if !ok {
+		return LongVowelMark
+	}
+	return vowel
+}
@@ -0,0 +1,66 @@
+package kana
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ToHiragana(t *testing.T) {
+	assert.Equal(t, "ぺらぺら", ToHiragana("ペラペラ"))
+	assert.Equal(t, "らーめん", ToHiragana("ラーメン"))
+	assert.Equal(t, "日の本 abc", ToHiragana("日ノ本 abc"))
+}
+
+func Test_ToKatakana(t *testing.T) {
+	assert.Equal(t, "ペラペラ", ToKatakana("ぺらぺら"))
+	assert.Equal(t, "ラーメン", ToKatakana("らーめん"))
+	assert.Equal(t, "食ベル abc", ToKatakana("食べる abc"))
+}
+
+func Test_IsHiragana(t *testing.T) {
+	assert.True(t, IsHiragana("らーめん"))
+	assert.False(t, IsHiragana("ラーメン"))
+	assert.False(t, IsHiragana("食べる"))
+	assert.False(t, IsHiragana(""))
+}
+
+func Test_ExpandLongVowels(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Src      string
+		Expected string
+	}{
+		{
+			Name:     "hiragana",
+			Src:      "らーめん",
+			Expected: "らあめん",
+		},
+		{
+			Name:     "katakana",
+			Src:      "コーヒー",
+			Expected: "コオヒイ",
+		},
+		{
+			Name:     "small kana",
+			Src:      "ちょーし",
+			Expected: "ちょおし",
+		},
+		{
+			Name:     "nothing to expand",
+			Src:      "ーん",
+			Expected: "ーん",
+		},
+		{
+			Name:     "without long vowels",
+			Src:      "食べる",
+			Expected: "食べる",
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, ExpandLongVowels(tc.Src))
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package kana
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NormalizeQuery prepares user input for dictionary lookup:
+// surrounding spaces are trimmed, full width latin letters and digits are converted
+// to ASCII and romaji is converted to hiragana. Romaji is converted only if the whole
+// query can be converted, so english words like "dog" are left as is.
+func NormalizeQuery(query string) string {
+	query = strings.TrimSpace(foldWidth(query))
+	if !IsRomaji(query) {
+		return query
+	}
+	converted := RomajiToHiragana(query)
+	for _, r := range converted {
+		if r < unicode.MaxASCII && unicode.IsLetter(r) {
+			return query
+		}
+	}
+	return strings.Join(strings.Fields(converted), "")
+}
+
+// NormalizePrefix prepares beginning of user input for prefix search. Unlike NormalizeQuery
+// it returns several variants: input itself (lower case, with katakana converted to hiragana)
+// and, if it's romaji, its hiragana conversion without incomplete last syllable,
+// so "inuk" and "in" (that can become いな) are searched as いぬ and い.
+func NormalizePrefix(prefix string) []string {
+	prefix = strings.ToLower(strings.TrimSpace(foldWidth(prefix)))
+	if prefix == "" {
+		return nil
+	}
+	prefixes := []string{ToHiragana(prefix)}
+	if !IsRomaji(prefix) {
+		return prefixes
+	}
+	converted := strings.Join(strings.Fields(RomajiToHiragana(prefix)), "")
+	converted = strings.TrimRightFunc(converted, func(r rune) bool {
+		return r < unicode.MaxASCII && unicode.IsLetter(r)
+	})
+	// trailing n is converted to ん, but it's probably beginning of na, ni, ...
+	if strings.HasSuffix(prefix, "n") && !strings.HasSuffix(prefix, "nn") {
+		converted = strings.TrimSuffix(converted, "ん")
+	}
+	if converted != "" && converted != prefixes[0] {
+		prefixes = append(prefixes, converted)
+	}
+	return prefixes
+}
+
+// foldWidth converts full width ASCII variants (Ａ, ｂ, １) to ASCII.
+func foldWidth(src string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '！' && r <= '～' {
+			return r - ('！' - '!')
+		}
+		if r == '　' {
+			return ' '
+		}
+		return r
+	}, src)
+}
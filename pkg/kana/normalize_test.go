@@ -0,0 +1,106 @@
+package kana
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NormalizeQuery(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Query    string
+		Expected string
+	}{
+		{
+			Name:     "romaji",
+			Query:    "taberu",
+			Expected: "たべる",
+		},
+		{
+			Name:     "romaji with spaces",
+			Query:    " tabe masu ",
+			Expected: "たべます",
+		},
+		{
+			Name:     "full width romaji",
+			Query:    "ｎｅｋｏ",
+			Expected: "ねこ",
+		},
+		{
+			Name:     "english",
+			Query:    "dog",
+			Expected: "dog",
+		},
+		{
+			Name:     "japanese",
+			Query:    "　食べる　",
+			Expected: "食べる",
+		},
+		{
+			Name:     "katakana is kept",
+			Query:    "ラーメン",
+			Expected: "ラーメン",
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, NormalizeQuery(tc.Query))
+		})
+	}
+}
+
+func Test_NormalizePrefix(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Prefix   string
+		Expected []string
+	}{
+		{
+			Name:   "empty",
+			Prefix: "  ",
+		},
+		{
+			Name:     "kanji",
+			Prefix:   "食べ",
+			Expected: []string{"食べ"},
+		},
+		{
+			Name:     "katakana",
+			Prefix:   "ラー",
+			Expected: []string{"らー"},
+		},
+		{
+			Name:     "complete romaji",
+			Prefix:   "Inu",
+			Expected: []string{"inu", "いぬ"},
+		},
+		{
+			Name:     "incomplete syllable",
+			Prefix:   "ｉｎｕｋ",
+			Expected: []string{"inuk", "いぬ"},
+		},
+		{
+			Name:     "trailing n",
+			Prefix:   "in",
+			Expected: []string{"in", "い"},
+		},
+		{
+			Name:     "trailing nn",
+			Prefix:   "honn",
+			Expected: []string{"honn", "ほん"},
+		},
+		{
+			Name:     "nothing converted",
+			Prefix:   "sh",
+			Expected: []string{"sh"},
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, NormalizePrefix(tc.Prefix))
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package kana
+
+import (
+	"strings"
+	"unicode"
+)
+
+// romajiSyllables maps romaji (Hepburn, Kunrei-shiki and common IME input) to hiragana.
+var romajiSyllables = map[string]string{
+	"a": "あ", "i": "い", "u": "う", "e": "え", "o": "お",
+
+	"ka": "か", "ki": "き", "ku": "く", "ke": "け", "ko": "こ",
+	"kya": "きゃ", "kyu": "きゅ", "kyo": "きょ",
+	"ga": "が", "gi": "ぎ", "gu": "ぐ", "ge": "げ", "go": "ご",
+	"gya": "ぎゃ", "gyu": "ぎゅ", "gyo": "ぎょ",
+
+	"sa": "さ", "si": "し", "shi": "し", "su": "す", "se": "せ", "so": "そ",
+	"sha": "しゃ", "shu": "しゅ", "she": "しぇ", "sho": "しょ",
+	"sya": "しゃ", "syu": "しゅ", "syo": "しょ",
+	"za": "ざ", "zi": "じ", "ji": "じ", "zu": "ず", "ze": "ぜ", "zo": "ぞ",
+	"ja": "じゃ", "ju": "じゅ", "je": "じぇ", "jo": "じょ",
+	"jya": "じゃ", "jyu": "じゅ", "jyo": "じょ",
+	"zya": "じゃ", "zyu": "じゅ", "zyo": "じょ",
+
+	"ta": "た", "ti": "ち", "chi": "ち", "tu": "つ", "tsu": "つ", "te": "て", "to": "と",
+	"cha": "ちゃ", "chu": "ちゅ", "che": "ちぇ", "cho": "ちょ",
+	"tya": "ちゃ", "tyu": "ちゅ", "tyo": "ちょ",
+	"cya": "ちゃ", "cyu": "ちゅ", "cyo": "ちょ",
+	"thi": "てぃ", "dhi": "でぃ",
+	"da": "だ", "di": "ぢ", "du": "づ", "de": "で", "do": "ど",
+	"dya": "ぢゃ", "dyu": "ぢゅ", "dyo": "ぢょ",
+
+	"na": "な", "ni": "に", "nu": "ぬ", "ne": "ね", "no": "の",
+	"nya": "にゃ", "nyu": "にゅ", "nyo": "にょ",
+
+	"ha": "は", "hi": "ひ", "hu": "ふ", "fu": "ふ", "he": "へ", "ho": "ほ",
+	"hya": "ひゃ", "hyu": "ひゅ", "hyo": "ひょ",
+	"fa": "ふぁ", "fi": "ふぃ", "fe": "ふぇ", "fo": "ふぉ",
+	"ba": "ば", "bi": "び", "bu": "ぶ", "be": "べ", "bo": "ぼ",
+	"bya": "びゃ", "byu": "びゅ", "byo": "びょ",
+	"pa": "ぱ", "pi": "ぴ", "pu": "ぷ", "pe": "ぺ", "po": "ぽ",
+	"pya": "ぴゃ", "pyu": "ぴゅ", "pyo": "ぴょ",
+
+	"ma": "ま", "mi": "み", "mu": "む", "me": "め", "mo": "も",
+	"mya": "みゃ", "myu": "みゅ", "myo": "みょ",
+
+	"ya": "や", "yu": "ゆ", "yo": "よ",
+
+	"ra": "ら", "ri": "り", "ru": "る", "re": "れ", "ro": "ろ",
+	"rya": "りゃ", "ryu": "りゅ", "ryo": "りょ",
+
+	"wa": "わ", "wi": "うぃ", "we": "うぇ", "wo": "を",
+
+	"va": "ゔぁ", "vi": "ゔぃ", "vu": "ゔ", "ve": "ゔぇ", "vo": "ゔぉ",
+
+	// small kana as they typed in IME
+	"xa": "ぁ", "xi": "ぃ", "xu": "ぅ", "xe": "ぇ", "xo": "ぉ",
+	"la": "ぁ", "li": "ぃ", "lu": "ぅ", "le": "ぇ", "lo": "ぉ",
+	"xya": "ゃ", "xyu": "ゅ", "xyo": "ょ",
+	"lya": "ゃ", "lyu": "ゅ", "lyo": "ょ",
+	"xtu": "っ", "xtsu": "っ", "ltu": "っ", "ltsu": "っ",
+	"xwa": "ゎ", "lwa": "ゎ",
+}
+
+// maxSyllableLength is length of the longest key in romajiSyllables
+const maxSyllableLength = 4
+
+// longVowels maps vowels with macron or circumflex to plain romaji.
+// Long o is usually written as おう.
+var longVowels = map[rune]string{
+	'ā': "aa", 'â': "aa",
+	'ī': "ii", 'î': "ii",
+	'ū': "uu", 'û': "uu",
+	'ē': "ee", 'ê': "ee",
+	'ō': "ou", 'ô': "ou",
+}
+
+// RomajiToHiragana converts romaji to hiragana. Characters that can not
+// be converted are left as is. Hyphen is converted to long vowel mark.
+func RomajiToHiragana(src string) string {
+	runes := []rune(expandLongRomaji(strings.ToLower(src)))
+	var result strings.Builder
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		if r == '-' {
+			result.WriteRune(LongVowelMark)
+			i++
+			continue
+		}
+		if r == 'n' {
+			converted, consumed := convertN(runes[i:])
+			if consumed != 0 {
+				result.WriteString(converted)
+				i += consumed
+				continue
+			}
+		}
+		// double consonant (kk, tt, tch) is written with small tsu
+		if i+1 < len(runes) && isRomajiConsonant(r) && (runes[i+1] == r || r == 't' && runes[i+1] == 'c') {
+			result.WriteRune('っ')
+			i++
+			continue
+		}
+		converted, consumed := convertSyllable(runes[i:])
+		if consumed == 0 {
+			result.WriteRune(r)
+			i++
+			continue
+		}
+		result.WriteString(converted)
+		i += consumed
+	}
+	return result.String()
+}
+
+func expandLongRomaji(src string) string {
+	var result strings.Builder
+	for _, r := range src {
+		if long, ok := longVowels[r]; ok {
+			result.WriteString(long)
+		} else {
+			result.WriteRune(r)
+		}
+	}
+	return result.String()
+}
+
+// convertN handles n that is not followed by vowel and so is ん.
+// It returns zero consumed if n starts syllable.
+func convertN(runes []rune) (string, int) {
+	if len(runes) == 1 {
+		return "ん", 1
+	}
+	next := runes[1]
+	switch {
+	case next == '\'':
+		return "ん", 2
+	case isRomajiVowel(next) || next == 'y':
+		return "", 0
+	case next == 'n' && (len(runes) == 2 || !isRomajiVowel(runes[2]) && runes[2] != 'y'):
+		// nn typed in IME
+		return "ん", 2
+	default:
+		return "ん", 1
+	}
+}
+
+func convertSyllable(runes []rune) (string, int) {
+	for length := min(maxSyllableLength, len(runes)); length > 0; length-- {
+		if converted, ok := romajiSyllables[string(runes[:length])]; ok {
+			return converted, length
+		}
+	}
+	return "", 0
+}
+
+func isRomajiVowel(r rune) bool {
+	return strings.ContainsRune("aiueo", r)
+}
+
+func isRomajiConsonant(r rune) bool {
+	return r >= 'a' && r <= 'z' && !isRomajiVowel(r) && r != 'n'
+}
+
+// IsRomaji reports whether src looks like romaji: it contains only latin letters
+// (with macrons or circumflexes), apostrophes, hyphens and spaces.
+func IsRomaji(src string) bool {
+	hasLetter := false
+	for _, r := range strings.ToLower(src) {
+		_, long := longVowels[r]
+		switch {
+		case r >= 'a' && r <= 'z' || long:
+			hasLetter = true
+		case r == '\'' || r == '-' || unicode.IsSpace(r):
+		default:
+			return false
+		}
+	}
+	return hasLetter
+}
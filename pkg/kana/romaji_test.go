@@ -0,0 +1,106 @@
+package kana
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RomajiToHiragana(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Src      string
+		Expected string
+	}{
+		{
+			Name:     "simple",
+			Src:      "taberu",
+			Expected: "たべる",
+		},
+		{
+			Name:     "hepburn",
+			Src:      "shinjitsu",
+			Expected: "しんじつ",
+		},
+		{
+			Name:     "kunrei",
+			Src:      "sinzitu",
+			Expected: "しんじつ",
+		},
+		{
+			Name:     "upper case",
+			Src:      "Neko",
+			Expected: "ねこ",
+		},
+		{
+			Name:     "double consonant",
+			Src:      "kitte",
+			Expected: "きって",
+		},
+		{
+			Name:     "tch",
+			Src:      "matcha",
+			Expected: "まっちゃ",
+		},
+		{
+			Name:     "n before n",
+			Src:      "konnichiwa",
+			Expected: "こんにちわ",
+		},
+		{
+			Name:     "n at the end",
+			Src:      "sennen",
+			Expected: "せんねん",
+		},
+		{
+			Name:     "n with apostrophe",
+			Src:      "kin'en",
+			Expected: "きんえん",
+		},
+		{
+			Name:     "ime nn",
+			Src:      "konnbanwa",
+			Expected: "こんばんわ",
+		},
+		{
+			Name:     "n before y",
+			Src:      "hon'ya",
+			Expected: "ほんや",
+		},
+		{
+			Name:     "youon",
+			Src:      "kyoushitsu",
+			Expected: "きょうしつ",
+		},
+		{
+			Name:     "macron",
+			Src:      "tōkyō",
+			Expected: "とうきょう",
+		},
+		{
+			Name:     "hyphen",
+			Src:      "ra-men",
+			Expected: "らーめん",
+		},
+		{
+			Name:     "not convertible",
+			Src:      "dog",
+			Expected: "どg",
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, RomajiToHiragana(tc.Src))
+		})
+	}
+}
+
+func Test_IsRomaji(t *testing.T) {
+	assert.True(t, IsRomaji("taberu"))
+	assert.True(t, IsRomaji("Tōkyō"))
+	assert.True(t, IsRomaji("kin'en"))
+	assert.False(t, IsRomaji("食べる"))
+	assert.False(t, IsRomaji("123"))
+	assert.False(t, IsRomaji(""))
+}
@@ -0,0 +1,92 @@
+// kanji is offline dictionary of kanji based on KANJIDIC2 project
+// (https://www.edrdg.org/wiki/index.php/KANJIDIC_Project). It reads
+// kanjidic2.xml file (can be gzipped) and keeps all characters in memory.
+package kanji
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+// Dict is in-memory index of kanji.
+type Dict struct {
+	characters map[string]*lemma.Kanji
+}
+
+// Open reads KANJIDIC2 file located at path. File with .gz extension is decompressed.
+func Open(path string) (*Dict, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("kanjidic file is not available: %w", err)
+	}
+	defer file.Close()
+	var src io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("kanjidic file decompression failed: %w", err)
+		}
+		defer gzipReader.Close()
+		src = gzipReader
+	}
+	return New(src)
+}
+
+// New reads KANJIDIC2 XML from src.
+func New(src io.Reader) (*Dict, error) {
+	dict := &Dict{
+		characters: map[string]*lemma.Kanji{},
+	}
+	err := ParseXML(src, func(kanji *lemma.Kanji) error {
+		dict.characters[kanji.Character] = kanji
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dict, nil
+}
+
+// Query returns information about every distinct kanji of characters in order
+// of their appearance. Kana, latin letters and unknown kanji are skipped.
+func (d *Dict) Query(characters string) []lemma.Kanji {
+	var result []lemma.Kanji
+	for _, character := range Characters(characters) {
+		kanji, ok := d.characters[character]
+		if !ok {
+			continue
+		}
+		result = append(result, *kanji)
+	}
+	return result
+}
+
+// Len returns number of kanji in dictionary.
+func (d *Dict) Len() int {
+	return len(d.characters)
+}
+
+// IsKanji reports whether r is kanji (CJK ideograph or iteration mark 々).
+func IsKanji(r rune) bool {
+	return unicode.Is(unicode.Han, r)
+}
+
+// Characters returns distinct kanji of src in order of their appearance.
+func Characters(src string) []string {
+	var result []string
+	seen := map[rune]bool{}
+	for _, r := range src {
+		if seen[r] || !IsKanji(r) {
+			continue
+		}
+		seen[r] = true
+		result = append(result, string(r))
+	}
+	return result
+}
@@ -0,0 +1,157 @@
+package kanji
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+var (
+	testKanjiA = lemma.Kanji{
+		Character:   "亜",
+		StrokeCount: 7,
+		Grade:       8,
+		JLPT:        1,
+		Meanings:    []string{"Asia", "rank next", "come after", "-ous"},
+		OnReadings:  []string{"ア"},
+		KunReadings: []string{"つ.ぐ"},
+		Radicals: []lemma.KanjiRadical{
+			{Number: 7, Character: "⼆", Type: "classical"},
+			{Number: 1, Character: "⼀", Type: "nelson_c"},
+		},
+	}
+	testKanjiDog = lemma.Kanji{
+		Character:   "犬",
+		StrokeCount: 4,
+		Grade:       1,
+		JLPT:        3,
+		Meanings:    []string{"dog"},
+		OnReadings:  []string{"ケン"},
+		KunReadings: []string{"いぬ", "いぬ-"},
+		Radicals: []lemma.KanjiRadical{
+			{Number: 94, Character: "⽝", Type: "classical"},
+		},
+	}
+	testKanjiRepeat = lemma.Kanji{
+		Character:   "々",
+		StrokeCount: 3,
+		Meanings:    []string{"repetition of kanji (sometimes voiced)"},
+		Radicals: []lemma.KanjiRadical{
+			{Number: 3, Character: "⼂", Type: "classical"},
+		},
+	}
+	testKanjiPerson = lemma.Kanji{
+		Character:   "人",
+		StrokeCount: 2,
+		Grade:       1,
+		JLPT:        4,
+		Meanings:    []string{"person"},
+		OnReadings:  []string{"ジン", "ニン"},
+		KunReadings: []string{"ひと", "-り", "-と"},
+		Radicals: []lemma.KanjiRadical{
+			{Number: 9, Character: "⼈", Type: "classical"},
+		},
+	}
+)
+
+func Test_Dict_Query(t *testing.T) {
+	dict, err := Open(filepath.Join("testdata", "kanjidic2_excerpt.xml"))
+	require.NoError(t, err)
+	require.Equal(t, 4, dict.Len())
+	testCases := []struct {
+		Name       string
+		Characters string
+		Expected   []lemma.Kanji
+	}{
+		{
+			Name:       "single",
+			Characters: "亜",
+			Expected:   []lemma.Kanji{testKanjiA},
+		},
+		{
+			Name:       "word with kana",
+			Characters: "人々が犬を",
+			Expected:   []lemma.Kanji{testKanjiPerson, testKanjiRepeat, testKanjiDog},
+		},
+		{
+			Name:       "duplicates",
+			Characters: "犬人犬",
+			Expected:   []lemma.Kanji{testKanjiDog, testKanjiPerson},
+		},
+		{
+			Name:       "unknown kanji",
+			Characters: "猫",
+		},
+		{
+			Name:       "kana only",
+			Characters: "いぬ",
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, dict.Query(tc.Characters))
+		})
+	}
+}
+
+func Test_Open_Gzip(t *testing.T) {
+	src, err := os.ReadFile(filepath.Join("testdata", "kanjidic2_excerpt.xml"))
+	require.NoError(t, err)
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	_, err = writer.Write(src)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	path := filepath.Join(t.TempDir(), "kanjidic2.xml.gz")
+	require.NoError(t, os.WriteFile(path, buffer.Bytes(), 0o644))
+
+	dict, err := Open(path)
+	require.NoError(t, err)
+	assert.Equal(t, 4, dict.Len())
+}
+
+func Test_Open_Errors(t *testing.T) {
+	_, err := Open(filepath.Join(t.TempDir(), "missing.xml"))
+	assert.ErrorContains(t, err, "kanjidic file is not available")
+
+	_, err = New(strings.NewReader(`<kanjidic2><character><literal>犬</literal><misc><grade>first</grade></misc></character></kanjidic2>`))
+	assert.ErrorContains(t, err, `character "犬": grade`)
+}
+
+func Test_Characters(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Src      string
+		Expected []string
+	}{
+		{
+			Name:     "mixed",
+			Src:      "人々が犬を飼う",
+			Expected: []string{"人", "々", "犬", "飼"},
+		},
+		{
+			Name:     "duplicates",
+			Src:      "一二一",
+			Expected: []string{"一", "二"},
+		},
+		{
+			Name: "no kanji",
+			Src:  "いぬ dog",
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, Characters(tc.Src))
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package kanji
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+const (
+	// kangxiRadicalsStart is code point of the first kangxi radical,
+	// radicals in unicode block are ordered by their numbers
+	kangxiRadicalsStart = '⼀'
+	kangxiRadicalsCount = 214
+)
+
+// CharacterHandler is called for every parsed character.
+// If handler returns error, parsing stops and error is returned.
+type CharacterHandler func(*lemma.Kanji) error
+
+// ParseXML parses KANJIDIC2 XML file.
+func ParseXML(src io.Reader, handler CharacterHandler) error {
+	decoder := xml.NewDecoder(src)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("xml parsing failed: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "character" {
+			continue
+		}
+		var raw xmlCharacter
+		if err := decoder.DecodeElement(&raw, &start); err != nil {
+			return fmt.Errorf("character decoding failed: %w", err)
+		}
+		kanji, err := raw.kanji()
+		if err != nil {
+			return fmt.Errorf("character %q: %w", raw.Literal, err)
+		}
+		if err := handler(kanji); err != nil {
+			return err
+		}
+	}
+}
+
+type xmlCharacter struct {
+	Literal  string `xml:"literal"`
+	Radicals []struct {
+		Type  string `xml:"rad_type,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"radical>rad_value"`
+	Misc struct {
+		Grade       string   `xml:"grade"`
+		StrokeCount []string `xml:"stroke_count"`
+		JLPT        string   `xml:"jlpt"`
+	} `xml:"misc"`
+	Groups []struct {
+		Readings []struct {
+			Type  string `xml:"r_type,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"reading"`
+		Meanings []struct {
+			Lang  string `xml:"m_lang,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"meaning"`
+	} `xml:"reading_meaning>rmgroup"`
+}
+
+func (c *xmlCharacter) kanji() (*lemma.Kanji, error) {
+	kanji := &lemma.Kanji{
+		Character: strings.TrimSpace(c.Literal),
+	}
+	var err error
+	// the first stroke count is accurate, the rest are common miscounts
+	if len(c.Misc.StrokeCount) > 0 {
+		if kanji.StrokeCount, err = parseOptionalInt(c.Misc.StrokeCount[0]); err != nil {
+			return nil, fmt.Errorf("stroke count: %w", err)
+		}
+	}
+	if kanji.Grade, err = parseOptionalInt(c.Misc.Grade); err != nil {
+		return nil, fmt.Errorf("grade: %w", err)
+	}
+	if kanji.JLPT, err = parseOptionalInt(c.Misc.JLPT); err != nil {
+		return nil, fmt.Errorf("jlpt: %w", err)
+	}
+	for _, radical := range c.Radicals {
+		number, err := strconv.Atoi(strings.TrimSpace(radical.Value))
+		if err != nil {
+			return nil, fmt.Errorf("radical: %w", err)
+		}
+		kanji.Radicals = append(kanji.Radicals, lemma.KanjiRadical{
+			Number:    number,
+			Character: radicalCharacter(number),
+			Type:      radical.Type,
+		})
+	}
+	for _, group := range c.Groups {
+		for _, reading := range group.Readings {
+			switch reading.Type {
+			case "ja_on":
+				kanji.OnReadings = append(kanji.OnReadings, reading.Value)
+			case "ja_kun":
+				kanji.KunReadings = append(kanji.KunReadings, reading.Value)
+			}
+		}
+		for _, meaning := range group.Meanings {
+			// meaning without language is english
+			if meaning.Lang == "" || meaning.Lang == "en" {
+				kanji.Meanings = append(kanji.Meanings, meaning.Value)
+			}
+		}
+	}
+	return kanji, nil
+}
+
+func parseOptionalInt(src string) (int, error) {
+	src = strings.TrimSpace(src)
+	if src == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(src)
+}
+
+// radicalCharacter returns character from Kangxi Radicals unicode block
+// or empty string if number is out of range.
+func radicalCharacter(number int) string {
+	if number < 1 || number > kangxiRadicalsCount {
+		return ""
+	}
+	return string(rune(kangxiRadicalsStart + number - 1))
+}
@@ -8,6 +8,8 @@ type Lemma struct {
 	Forms  []Word      `json:"Forms,omitempty"`
 	Senses []WordSense `json:"Senses,omitempty"`
 	Audio  []Audio     `json:"Audio,omitempty"`
+	// Sources is names of dictionaries where lemma was found
+	Sources []string `json:"Sources,omitempty"`
 }
 
 type Word struct {
@@ -61,11 +63,47 @@ type WordSense struct {
 // ProjectedLemma is more specific variant of Lemma.
 // This structure include only one meaning.
 type ProjectedLemma struct {
-	Slug          Word     `json:"Slug,omitempty"`
-	Tags          []string `json:"Tags,omitempty"`
-	Forms         []Word   `json:"Forms,omitempty"`
-	Definitions   []string `json:"Definitions,omitempty"`
-	PartsOfSpeech []string `json:"PartsOfSpeech,omitempty"`
-	SenseTags     []string `json:"SenseTags,omitempty"`
-	Audio         []Audio  `json:"Audio,omitempty"`
+	Slug          Word      `json:"Slug,omitempty"`
+	Tags          []string  `json:"Tags,omitempty"`
+	Forms         []Word    `json:"Forms,omitempty"`
+	Definitions   []string  `json:"Definitions,omitempty"`
+	PartsOfSpeech []string  `json:"PartsOfSpeech,omitempty"`
+	SenseTags     []string  `json:"SenseTags,omitempty"`
+	Audio         []Audio   `json:"Audio,omitempty"`
+	Sources       []string  `json:"Sources,omitempty"`
+	Examples      []Example `json:"Examples,omitempty"`
+	Kanji         []Kanji   `json:"Kanji,omitempty"`
+}
+
+// Example is example sentence that uses lemma.
+type Example struct {
+	Japanese string `json:"Japanese,omitempty"`
+	English  string `json:"English,omitempty"`
+	// Reading is japanese sentence with readings in Anki furigana format: 彼[かれ]は 犬[いぬ]を 飼[か]っている.
+	// It's optional.
+	Reading string `json:"Reading,omitempty"`
+}
+
+// Kanji is information about single kanji of lemma.
+type Kanji struct {
+	Character   string `json:"Character,omitempty"`
+	StrokeCount int    `json:"StrokeCount,omitempty"`
+	// Grade is school grade in which kanji is taught (1-6 for kyouiku kanji, 8 for rest of jouyou kanji,
+	// 9 and 10 for jinmeiyou kanji), zero if unknown.
+	Grade int `json:"Grade,omitempty"`
+	// JLPT is level of old JLPT (1-4), zero if unknown.
+	JLPT        int            `json:"JLPT,omitempty"`
+	Meanings    []string       `json:"Meanings,omitempty"`
+	OnReadings  []string       `json:"OnReadings,omitempty"`
+	KunReadings []string       `json:"KunReadings,omitempty"`
+	Radicals    []KanjiRadical `json:"Radicals,omitempty"`
+}
+
+// KanjiRadical is radical under which kanji is classified.
+type KanjiRadical struct {
+	// Number is number of radical among 214 kangxi radicals
+	Number    int    `json:"Number,omitempty"`
+	Character string `json:"Character,omitempty"`
+	// Type is classification that assigns radical, "classical" or "nelson_c"
+	Type string `json:"Type,omitempty"`
 }
@@ -0,0 +1,106 @@
+// Package lemmalookup looks up queries and projects found lemmas, so server and
+// command line commands return the same lemmas for the same query.
+package lemmalookup
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Darkclainer/japwords/pkg/deinflect"
+	"github.com/Darkclainer/japwords/pkg/kana"
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+// DefaultExamplesLimit is number of examples that is added to every lemma.
+const DefaultExamplesLimit = 5
+
+// Lookup normalizes query and looks up its dictionary form in dict, romaji is tried as english
+// word if nothing was found. It returns query that was actually used for lookup (it's returned
+// with error too) and deinflection if lemmas were found for deinflected query.
+func Lookup(ctx context.Context, dict deinflect.Dict, query string) ([]*lemma.Lemma, string, *deinflect.Candidate, error) {
+	normalizedQuery := kana.NormalizeQuery(query)
+	lemmas, deinflection, err := deinflect.Lookup(ctx, dict, normalizedQuery)
+	if err != nil {
+		return nil, normalizedQuery, nil, err
+	}
+	// romaji can be english word as well, so try it if nothing was found
+	if len(lemmas) == 0 && kana.IsRomaji(query) && normalizedQuery != query {
+		normalizedQuery = strings.TrimSpace(query)
+		lemmas, deinflection, err = deinflect.Lookup(ctx, dict, normalizedQuery)
+		if err != nil {
+			return nil, normalizedQuery, nil, err
+		}
+	}
+	return lemmas, normalizedQuery, deinflection, nil
+}
+
+// Term returns term that lemmas were actually found for.
+func Term(normalizedQuery string, deinflection *deinflect.Candidate) string {
+	if deinflection != nil {
+		return deinflection.Term
+	}
+	return normalizedQuery
+}
+
+// Expand returns lemma for every sense.
+func Expand(lemmas []*lemma.Lemma) []*lemma.ProjectedLemma {
+	var projectedLemmas []*lemma.ProjectedLemma
+	for _, l := range lemmas {
+		projectedLemmas = append(projectedLemmas, ExpandSenses(l, l.Senses)...)
+	}
+	return projectedLemmas
+}
+
+// ExpandSenses returns lemma for every specified sense of l.
+func ExpandSenses(l *lemma.Lemma, senses []lemma.WordSense) []*lemma.ProjectedLemma {
+	projectedLemmas := make([]*lemma.ProjectedLemma, len(senses))
+	for i, wordSense := range senses {
+		projectedLemmas[i] = &lemma.ProjectedLemma{
+			Slug:          l.Slug,
+			Tags:          l.Tags,
+			Forms:         l.Forms,
+			Definitions:   wordSense.Definition,
+			PartsOfSpeech: wordSense.PartOfSpeech,
+			SenseTags:     wordSense.Tags,
+			Audio:         l.Audio,
+			Sources:       l.Sources,
+		}
+	}
+	return projectedLemmas
+}
+
+// ExamplesSource is index of example sentences, for example examples.Index.
+type ExamplesSource interface {
+	Query(word string, limit int) []lemma.Example
+}
+
+// KanjiSource is kanji dictionary, for example kanji.Dict.
+type KanjiSource interface {
+	Query(characters string) []lemma.Kanji
+}
+
+// Projector projects lemmas and adds information from optional sources to them.
+// Nil sources are disabled (don't set them to typed nil pointers).
+type Projector struct {
+	Examples ExamplesSource
+	Kanji    KanjiSource
+}
+
+// Project expands lemmas by senses and enriches them.
+func (p *Projector) Project(lemmas []*lemma.Lemma) []*lemma.ProjectedLemma {
+	projectedLemmas := Expand(lemmas)
+	p.Enrich(projectedLemmas)
+	return projectedLemmas
+}
+
+// Enrich adds example sentences and information about kanji to lemmas if they are enabled.
+func (p *Projector) Enrich(projectedLemmas []*lemma.ProjectedLemma) {
+	for _, projectedLemma := range projectedLemmas {
+		if p.Examples != nil {
+			projectedLemma.Examples = p.Examples.Query(projectedLemma.Slug.Word, DefaultExamplesLimit)
+		}
+		if p.Kanji != nil {
+			projectedLemma.Kanji = p.Kanji.Query(projectedLemma.Slug.Word)
+		}
+	}
+}
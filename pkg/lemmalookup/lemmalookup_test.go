@@ -0,0 +1,211 @@
+package lemmalookup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+func Test_Expand(t *testing.T) {
+	word1 := lemma.Word{
+		Word:     "foo",
+		Hiragana: "foo",
+		Furigana: []lemma.FuriganaChar{
+			{},
+		},
+		PitchShapes: []lemma.PitchShape{
+			{},
+		},
+	}
+	word2 := lemma.Word{
+		Word:     "bar",
+		Hiragana: "bar",
+		Furigana: []lemma.FuriganaChar{
+			{}, {},
+		},
+		PitchShapes: []lemma.PitchShape{
+			{}, {},
+		},
+	}
+	lemmas := []*lemma.Lemma{
+		{
+			Slug:  word1,
+			Tags:  []string{"first"},
+			Forms: []lemma.Word{word1},
+			Senses: []lemma.WordSense{
+				{
+					Definition:   []string{"a", "b"},
+					PartOfSpeech: []string{"pos"},
+					Tags:         []string{"sensetag"},
+				},
+				{
+					Definition:   []string{"c", "d"},
+					PartOfSpeech: []string{"sop"},
+				},
+			},
+			Audio: []lemma.Audio{
+				{
+					MediaType: "hello",
+					Source:    "world",
+				},
+			},
+		},
+		{
+			Slug:  word2,
+			Tags:  []string{"second"},
+			Forms: []lemma.Word{word1, word2},
+			Senses: []lemma.WordSense{
+				{
+					Definition:   []string{"second"},
+					PartOfSpeech: []string{"pos2"},
+				},
+			},
+		},
+	}
+	expected := []*lemma.ProjectedLemma{
+		{
+			Slug:          word1,
+			Tags:          []string{"first"},
+			Forms:         []lemma.Word{word1},
+			Definitions:   []string{"a", "b"},
+			PartsOfSpeech: []string{"pos"},
+			SenseTags:     []string{"sensetag"},
+			Audio: []lemma.Audio{
+				{
+					MediaType: "hello",
+					Source:    "world",
+				},
+			},
+		},
+		{
+			Slug:          word1,
+			Tags:          []string{"first"},
+			Forms:         []lemma.Word{word1},
+			Definitions:   []string{"c", "d"},
+			PartsOfSpeech: []string{"sop"},
+			Audio: []lemma.Audio{
+				{
+					MediaType: "hello",
+					Source:    "world",
+				},
+			},
+		},
+		{
+			Slug:          word2,
+			Tags:          []string{"second"},
+			Forms:         []lemma.Word{word1, word2},
+			Definitions:   []string{"second"},
+			PartsOfSpeech: []string{"pos2"},
+		},
+	}
+	actual := Expand(lemmas)
+	assert.Equal(t, expected, actual)
+}
+
+type testDict map[string][]*lemma.Lemma
+
+func (d testDict) Query(_ context.Context, query string) ([]*lemma.Lemma, error) {
+	return d[query], nil
+}
+
+func Test_Lookup(t *testing.T) {
+	dog := &lemma.Lemma{
+		Slug:   lemma.Word{Word: "犬", Hiragana: "いぬ"},
+		Senses: []lemma.WordSense{{Definition: []string{"dog"}}},
+	}
+	english := &lemma.Lemma{
+		Slug:   lemma.Word{Word: "ドッグ", Hiragana: "どっぐ"},
+		Senses: []lemma.WordSense{{Definition: []string{"dog"}}},
+	}
+	dict := testDict{
+		"いぬ":  {dog},
+		"dog": {english},
+		"食べる": {{Slug: lemma.Word{Word: "食べる", Hiragana: "たべる"}, Senses: []lemma.WordSense{{PartOfSpeech: []string{"Ichidan verb"}}}}},
+	}
+	testCases := []struct {
+		Name                string
+		Query               string
+		ExpectedLemmas      []*lemma.Lemma
+		ExpectedQuery       string
+		ExpectedDeinflected bool
+		ExpectedTerm        string
+	}{
+		{
+			Name:           "romaji",
+			Query:          "inu",
+			ExpectedLemmas: []*lemma.Lemma{dog},
+			ExpectedQuery:  "いぬ",
+			ExpectedTerm:   "いぬ",
+		},
+		{
+			Name:           "english",
+			Query:          " dog ",
+			ExpectedLemmas: []*lemma.Lemma{english},
+			ExpectedQuery:  "dog",
+			ExpectedTerm:   "dog",
+		},
+		{
+			Name:                "deinflected",
+			Query:               "食べた",
+			ExpectedLemmas:      dict["食べる"],
+			ExpectedQuery:       "食べた",
+			ExpectedDeinflected: true,
+			ExpectedTerm:        "食べる",
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			lemmas, normalizedQuery, deinflection, err := Lookup(context.Background(), dict, tc.Query)
+			require.NoError(t, err)
+			assert.Equal(t, tc.ExpectedLemmas, lemmas)
+			assert.Equal(t, tc.ExpectedQuery, normalizedQuery)
+			assert.Equal(t, tc.ExpectedDeinflected, deinflection != nil)
+			assert.Equal(t, tc.ExpectedTerm, Term(normalizedQuery, deinflection))
+		})
+	}
+}
+
+type testExamples struct{}
+
+func (testExamples) Query(word string, limit int) []lemma.Example {
+	examples := make([]lemma.Example, limit)
+	for i := range examples {
+		examples[i].Japanese = word
+	}
+	return examples
+}
+
+type testKanji struct{}
+
+func (testKanji) Query(characters string) []lemma.Kanji {
+	return []lemma.Kanji{{Character: characters}}
+}
+
+func Test_Projector(t *testing.T) {
+	lemmas := []*lemma.Lemma{
+		{
+			Slug:   lemma.Word{Word: "犬"},
+			Senses: []lemma.WordSense{{Definition: []string{"dog"}}, {Definition: []string{"spy"}}},
+		},
+	}
+	projectedLemmas := (&Projector{}).Project(lemmas)
+	require.Len(t, projectedLemmas, 2)
+	assert.Nil(t, projectedLemmas[0].Examples)
+	assert.Nil(t, projectedLemmas[0].Kanji)
+
+	projector := &Projector{
+		Examples: testExamples{},
+		Kanji:    testKanji{},
+	}
+	projectedLemmas = projector.Project(lemmas)
+	require.Len(t, projectedLemmas, 2)
+	for _, projectedLemma := range projectedLemmas {
+		assert.Len(t, projectedLemma.Examples, DefaultExamplesLimit)
+		assert.Equal(t, []lemma.Kanji{{Character: "犬"}}, projectedLemma.Kanji)
+	}
+}
@@ -0,0 +1,207 @@
+// Package lemmaprint renders lemmas for terminal.
+package lemmaprint
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Darkclainer/japwords/pkg/anki"
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+// Format is output format.
+type Format string
+
+const (
+	// FormatTable is human readable format with one block for every lemma.
+	FormatTable Format = "table"
+	FormatJSON  Format = "json"
+	FormatYAML  Format = "yaml"
+)
+
+func ParseFormat(src string) (Format, error) {
+	switch format := Format(src); format {
+	case FormatTable, FormatJSON, FormatYAML:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown format %q, expected table, json or yaml", src)
+	}
+}
+
+// NoteStatus tells whether lemma has note in Anki.
+type NoteStatus string
+
+const (
+	NoteStatusExists  NoteStatus = "exists"
+	NoteStatusMissing NoteStatus = "missing"
+	// NoteStatusUnknown means that Anki couldn't be asked.
+	NoteStatusUnknown NoteStatus = "unknown"
+)
+
+// Entry is single projected lemma prepared for output.
+type Entry struct {
+	Word          string     `json:"word" yaml:"word"`
+	Reading       string     `json:"reading,omitempty" yaml:"reading,omitempty"`
+	Furigana      string     `json:"furigana" yaml:"furigana"`
+	Pitch         []string   `json:"pitch,omitempty" yaml:"pitch,omitempty"`
+	PartsOfSpeech []string   `json:"partsOfSpeech,omitempty" yaml:"partsOfSpeech,omitempty"`
+	Definitions   []string   `json:"definitions,omitempty" yaml:"definitions,omitempty"`
+	Tags          []string   `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Forms         []string   `json:"forms,omitempty" yaml:"forms,omitempty"`
+	Examples      []Example  `json:"examples,omitempty" yaml:"examples,omitempty"`
+	Kanji         []Kanji    `json:"kanji,omitempty" yaml:"kanji,omitempty"`
+	NoteStatus    NoteStatus `json:"noteStatus" yaml:"noteStatus"`
+	NoteID        string     `json:"noteID,omitempty" yaml:"noteID,omitempty"`
+}
+
+// Example is example sentence of lemma.
+type Example struct {
+	Japanese string `json:"japanese" yaml:"japanese"`
+	English  string `json:"english,omitempty" yaml:"english,omitempty"`
+}
+
+// Kanji is short information about single kanji of lemma.
+type Kanji struct {
+	Character   string   `json:"character" yaml:"character"`
+	Meanings    []string `json:"meanings,omitempty" yaml:"meanings,omitempty"`
+	OnReadings  []string `json:"onReadings,omitempty" yaml:"onReadings,omitempty"`
+	KunReadings []string `json:"kunReadings,omitempty" yaml:"kunReadings,omitempty"`
+}
+
+// NewEntries converts lemmas to entries, noteIDs are results of anki.Anki.SearchProjectedLemmas,
+// nil means that note status is unknown.
+func NewEntries(projectedLemmas []*lemma.ProjectedLemma, noteIDs []anki.NoteID) []*Entry {
+	entries := make([]*Entry, len(projectedLemmas))
+	for i, projectedLemma := range projectedLemmas {
+		entry := &Entry{
+			Word:          projectedLemma.Slug.Word,
+			Furigana:      FormatFurigana(&projectedLemma.Slug),
+			Pitch:         PitchGraph(projectedLemma.Slug.PitchShapes),
+			PartsOfSpeech: projectedLemma.PartsOfSpeech,
+			Definitions:   projectedLemma.Definitions,
+			Tags:          append(append([]string(nil), projectedLemma.Tags...), projectedLemma.SenseTags...),
+			NoteStatus:    NoteStatusUnknown,
+		}
+		if projectedLemma.Slug.Hiragana != projectedLemma.Slug.Word {
+			entry.Reading = projectedLemma.Slug.Hiragana
+		}
+		for j := range projectedLemma.Forms {
+			entry.Forms = append(entry.Forms, FormatFurigana(&projectedLemma.Forms[j]))
+		}
+		for _, example := range projectedLemma.Examples {
+			entry.Examples = append(entry.Examples, Example{
+				Japanese: example.Japanese,
+				English:  example.English,
+			})
+		}
+		for _, kanji := range projectedLemma.Kanji {
+			entry.Kanji = append(entry.Kanji, Kanji{
+				Character:   kanji.Character,
+				Meanings:    kanji.Meanings,
+				OnReadings:  kanji.OnReadings,
+				KunReadings: kanji.KunReadings,
+			})
+		}
+		if noteIDs != nil {
+			entry.NoteStatus = NoteStatusMissing
+			if noteIDs[i] != 0 {
+				entry.NoteStatus = NoteStatusExists
+				entry.NoteID = noteIDs[i].String()
+			}
+		}
+		entries[i] = entry
+	}
+	return entries
+}
+
+// Write writes entries in specified format.
+func Write(w io.Writer, format Format, entries []*Entry) error {
+	switch format {
+	case FormatTable:
+		return writeTable(w, entries)
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(entries)
+	case FormatYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(entries); err != nil {
+			return err
+		}
+		return encoder.Close()
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+func writeTable(w io.Writer, entries []*Entry) error {
+	buffer := bufio.NewWriter(w)
+	if len(entries) == 0 {
+		fmt.Fprintln(buffer, "Nothing found")
+	}
+	row := func(name string, lines ...string) {
+		for i, line := range lines {
+			if i != 0 {
+				name = ""
+			}
+			fmt.Fprintf(buffer, "  %-11s %s\n", name, line)
+		}
+	}
+	for i, entry := range entries {
+		if i != 0 {
+			_ = buffer.WriteByte('\n')
+		}
+		fmt.Fprintf(buffer, "%d. %s", i+1, entry.Word)
+		if entry.Reading != "" {
+			fmt.Fprintf(buffer, " (%s)", entry.Reading)
+		}
+		_ = buffer.WriteByte('\n')
+		row("furigana", entry.Furigana)
+		row("pitch", entry.Pitch...)
+		if len(entry.PartsOfSpeech) != 0 {
+			row("pos", strings.Join(entry.PartsOfSpeech, ", "))
+		}
+		row("definition", entry.Definitions...)
+		if len(entry.Tags) != 0 {
+			row("tags", strings.Join(entry.Tags, ", "))
+		}
+		if len(entry.Forms) != 0 {
+			row("forms", strings.Join(entry.Forms, ", "))
+		}
+		if len(entry.Examples) != 0 {
+			var lines []string
+			for _, example := range entry.Examples {
+				lines = append(lines, example.Japanese)
+				if example.English != "" {
+					lines = append(lines, "  "+example.English)
+				}
+			}
+			row("examples", lines...)
+		}
+		if len(entry.Kanji) != 0 {
+			var lines []string
+			for _, kanji := range entry.Kanji {
+				line := kanji.Character + " " + strings.Join(kanji.Meanings, ", ")
+				readings := append(kanji.OnReadings[:len(kanji.OnReadings):len(kanji.OnReadings)], kanji.KunReadings...)
+				if len(readings) != 0 {
+					line += " (" + strings.Join(readings, ", ") + ")"
+				}
+				lines = append(lines, line)
+			}
+			row("kanji", lines...)
+		}
+		note := string(entry.NoteStatus)
+		if entry.NoteID != "" {
+			note += " (" + entry.NoteID + ")"
+		}
+		row("anki", note)
+	}
+	return buffer.Flush()
+}
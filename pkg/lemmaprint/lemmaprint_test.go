@@ -0,0 +1,194 @@
+package lemmaprint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Darkclainer/japwords/pkg/accentdict"
+	"github.com/Darkclainer/japwords/pkg/anki"
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+func testLemmas() []*lemma.ProjectedLemma {
+	inu := lemma.Word{
+		Word:        "犬",
+		Hiragana:    "いぬ",
+		Furigana:    lemma.Furigana{{Kanji: "犬", Hiragana: "いぬ"}},
+		PitchShapes: accentdict.PitchShapes("いぬ", 2),
+	}
+	return []*lemma.ProjectedLemma{
+		{
+			Slug:          inu,
+			Tags:          []string{"Common word"},
+			Definitions:   []string{"dog"},
+			PartsOfSpeech: []string{"Noun"},
+		},
+		{
+			Slug:          inu,
+			Tags:          []string{"Common word"},
+			Definitions:   []string{"spy", "snoop"},
+			PartsOfSpeech: []string{"Noun"},
+			SenseTags:     []string{"Derogatory"},
+			Forms: []lemma.Word{
+				{Word: "狗", Furigana: lemma.Furigana{{Kanji: "狗", Hiragana: "いぬ"}}},
+			},
+			Examples: []lemma.Example{
+				{Japanese: "彼は警察の犬だ。", English: "He is a police spy."},
+			},
+			Kanji: []lemma.Kanji{
+				{Character: "犬", Meanings: []string{"dog"}, OnReadings: []string{"ケン"}, KunReadings: []string{"いぬ"}},
+			},
+		},
+	}
+}
+
+func Test_NewEntries(t *testing.T) {
+	t.Run("with note ids", func(t *testing.T) {
+		entries := NewEntries(testLemmas(), []anki.NoteID{123, 0})
+		require.Len(t, entries, 2)
+		assert.Equal(t, &Entry{
+			Word:          "犬",
+			Reading:       "いぬ",
+			Furigana:      "犬[いぬ]",
+			Pitch:         []string{"  |--|", "い|ぬ|", "__|  |"},
+			PartsOfSpeech: []string{"Noun"},
+			Definitions:   []string{"dog"},
+			Tags:          []string{"Common word"},
+			NoteStatus:    NoteStatusExists,
+			NoteID:        "123",
+		}, entries[0])
+		assert.Equal(t, NoteStatusMissing, entries[1].NoteStatus)
+		assert.Empty(t, entries[1].NoteID)
+		assert.Equal(t, []string{"Common word", "Derogatory"}, entries[1].Tags)
+		assert.Equal(t, []string{"狗[いぬ]"}, entries[1].Forms)
+		assert.Equal(t, []Example{{Japanese: "彼は警察の犬だ。", English: "He is a police spy."}}, entries[1].Examples)
+		assert.Equal(t, []Kanji{{Character: "犬", Meanings: []string{"dog"}, OnReadings: []string{"ケン"}, KunReadings: []string{"いぬ"}}}, entries[1].Kanji)
+	})
+	t.Run("without note ids", func(t *testing.T) {
+		entries := NewEntries(testLemmas(), nil)
+		require.Len(t, entries, 2)
+		assert.Equal(t, NoteStatusUnknown, entries[0].NoteStatus)
+		assert.Equal(t, NoteStatusUnknown, entries[1].NoteStatus)
+	})
+	t.Run("reading same as word", func(t *testing.T) {
+		entries := NewEntries([]*lemma.ProjectedLemma{
+			{Slug: lemma.Word{Word: "いぬ", Hiragana: "いぬ"}},
+		}, nil)
+		require.Len(t, entries, 1)
+		assert.Empty(t, entries[0].Reading)
+	})
+}
+
+func Test_Write(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Format   Format
+		Entries  []*Entry
+		Expected string
+	}{
+		{
+			Name:    "table",
+			Format:  FormatTable,
+			Entries: NewEntries(testLemmas(), []anki.NoteID{123, 0}),
+			Expected: `1. 犬 (いぬ)
+  furigana    犬[いぬ]
+  pitch         |--|
+              い|ぬ|
+              __|  |
+  pos         Noun
+  definition  dog
+  tags        Common word
+  anki        exists (123)
+
+2. 犬 (いぬ)
+  furigana    犬[いぬ]
+  pitch         |--|
+              い|ぬ|
+              __|  |
+  pos         Noun
+  definition  spy
+              snoop
+  tags        Common word, Derogatory
+  forms       狗[いぬ]
+  examples    彼は警察の犬だ。
+                He is a police spy.
+  kanji       犬 dog (ケン, いぬ)
+  anki        missing
+`,
+		},
+		{
+			Name:     "empty table",
+			Format:   FormatTable,
+			Expected: "Nothing found\n",
+		},
+		{
+			Name:    "json",
+			Format:  FormatJSON,
+			Entries: NewEntries(testLemmas()[:1], nil),
+			Expected: `[
+  {
+    "word": "犬",
+    "reading": "いぬ",
+    "furigana": "犬[いぬ]",
+    "pitch": [
+      "  |--|",
+      "い|ぬ|",
+      "__|  |"
+    ],
+    "partsOfSpeech": [
+      "Noun"
+    ],
+    "definitions": [
+      "dog"
+    ],
+    "tags": [
+      "Common word"
+    ],
+    "noteStatus": "unknown"
+  }
+]
+`,
+		},
+		{
+			Name:    "yaml",
+			Format:  FormatYAML,
+			Entries: NewEntries(testLemmas()[:1], []anki.NoteID{123}),
+			Expected: `- word: 犬
+  reading: いぬ
+  furigana: 犬[いぬ]
+  pitch:
+    - '  |--|'
+    - い|ぬ|
+    - __|  |
+  partsOfSpeech:
+    - Noun
+  definitions:
+    - dog
+  tags:
+    - Common word
+  noteStatus: exists
+  noteID: "123"
+`,
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			var buffer strings.Builder
+			err := Write(&buffer, tc.Format, tc.Entries)
+			require.NoError(t, err)
+			assert.Equal(t, tc.Expected, buffer.String())
+		})
+	}
+}
+
+func Test_ParseFormat(t *testing.T) {
+	format, err := ParseFormat("yaml")
+	require.NoError(t, err)
+	assert.Equal(t, FormatYAML, format)
+	_, err = ParseFormat("xml")
+	assert.Error(t, err)
+}
@@ -0,0 +1,88 @@
+package lemmaprint
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+// FormatFurigana returns word with readings of kanji in brackets: 食[た]べる.
+func FormatFurigana(word *lemma.Word) string {
+	if len(word.Furigana) == 0 {
+		return word.Word
+	}
+	var buffer strings.Builder
+	for _, char := range word.Furigana {
+		if char.Kanji == "" {
+			buffer.WriteString(char.Hiragana)
+			continue
+		}
+		buffer.WriteString(char.Kanji)
+		if char.Hiragana != "" {
+			buffer.WriteString("[" + char.Hiragana + "]")
+		}
+	}
+	return buffer.String()
+}
+
+// PitchGraph draws pitch shapes with ASCII characters in three lines: high moras are
+// marked with - above them, low moras with _ below them and changes of pitch with |.
+//
+//	  |--|
+//	た|べ|る
+//	__|  |__
+//
+// It returns nil if word doesn't have pitch.
+func PitchGraph(shapes []lemma.PitchShape) []string {
+	if len(shapes) == 0 {
+		return nil
+	}
+	var top, middle, bottom strings.Builder
+	boundary := func() {
+		top.WriteByte('|')
+		middle.WriteByte('|')
+		bottom.WriteByte('|')
+	}
+	for i, shape := range shapes {
+		if slices.Contains(shape.Directions, lemma.AccentDirectionLeft) ||
+			(i != 0 && slices.Contains(shapes[i-1].Directions, lemma.AccentDirectionRight)) {
+			boundary()
+		}
+		width := displayWidth(shape.Hiragana)
+		high := slices.Contains(shape.Directions, lemma.AccentDirectionUp)
+		low := slices.Contains(shape.Directions, lemma.AccentDirectionDown)
+		top.WriteString(strings.Repeat(pick(high, "-", " "), width))
+		middle.WriteString(shape.Hiragana)
+		bottom.WriteString(strings.Repeat(pick(low, "_", " "), width))
+	}
+	if slices.Contains(shapes[len(shapes)-1].Directions, lemma.AccentDirectionRight) {
+		boundary()
+	}
+	return []string{
+		strings.TrimRight(top.String(), " "),
+		middle.String(),
+		strings.TrimRight(bottom.String(), " "),
+	}
+}
+
+// displayWidth returns number of terminal columns that text occupies, kana and kanji are
+// considered to be twice as wide as latin characters.
+func displayWidth(text string) int {
+	width := 0
+	for _, r := range text {
+		if r >= 0x2e80 {
+			width += 2
+		} else {
+			width++
+		}
+	}
+	return width
+}
+
+func pick(cond bool, ifTrue, ifFalse string) string {
+	if cond {
+		return ifTrue
+	}
+	return ifFalse
+}
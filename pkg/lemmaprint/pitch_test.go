@@ -0,0 +1,128 @@
+package lemmaprint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Darkclainer/japwords/pkg/accentdict"
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+func Test_FormatFurigana(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Word     lemma.Word
+		Expected string
+	}{
+		{
+			Name:     "no furigana",
+			Word:     lemma.Word{Word: "いぬ"},
+			Expected: "いぬ",
+		},
+		{
+			Name: "kanji and kana",
+			Word: lemma.Word{
+				Word: "食べる",
+				Furigana: lemma.Furigana{
+					{Kanji: "食", Hiragana: "た"},
+					{Hiragana: "べ"},
+					{Hiragana: "る"},
+				},
+			},
+			Expected: "食[た]べる",
+		},
+		{
+			Name: "kanji without reading",
+			Word: lemma.Word{
+				Word: "々",
+				Furigana: lemma.Furigana{
+					{Kanji: "々"},
+				},
+			},
+			Expected: "々",
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, FormatFurigana(&tc.Word))
+		})
+	}
+}
+
+func Test_PitchGraph(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Shapes   []lemma.PitchShape
+		Expected []string
+	}{
+		{
+			Name:     "no pitch",
+			Expected: nil,
+		},
+		{
+			Name:   "heiban",
+			Shapes: accentdict.PitchShapes("さかな", 0),
+			Expected: []string{
+				"  |----",
+				"さ|かな",
+				"__|",
+			},
+		},
+		{
+			Name:   "atamadaka",
+			Shapes: accentdict.PitchShapes("ねこ", 1),
+			Expected: []string{
+				"--|",
+				"ね|こ",
+				"  |__",
+			},
+		},
+		{
+			Name:   "nakadaka",
+			Shapes: accentdict.PitchShapes("たべる", 2),
+			Expected: []string{
+				"  |--|",
+				"た|べ|る",
+				"__|  |__",
+			},
+		},
+		{
+			Name:   "odaka",
+			Shapes: accentdict.PitchShapes("いぬ", 2),
+			Expected: []string{
+				"  |--|",
+				"い|ぬ|",
+				"__|  |",
+			},
+		},
+		{
+			Name:   "small kana",
+			Shapes: accentdict.PitchShapes("きょう", 1),
+			Expected: []string{
+				"----|",
+				"きょ|う",
+				"    |__",
+			},
+		},
+		{
+			Name: "drop marked on previous shape",
+			Shapes: []lemma.PitchShape{
+				{Hiragana: "は", Directions: []lemma.AccentDirection{lemma.AccentDirectionUp, lemma.AccentDirectionRight}},
+				{Hiragana: "し", Directions: []lemma.AccentDirection{lemma.AccentDirectionDown}},
+			},
+			Expected: []string{
+				"--|",
+				"は|し",
+				"  |__",
+			},
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, PitchGraph(tc.Shapes))
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"path"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// URL returns path of file with specified source relative to Handler prefix.
+func (s *Store) URL(prefix string, source string) string {
+	return path.Join(prefix, s.Name(source))
+}
+
+// Handler serves files by name. Files that are not cached yet are downloaded,
+// but only if their name was returned by Name before.
+func (s *Store) Handler(prefix string) http.Handler {
+	return http.StripPrefix(prefix, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		name := r.URL.Path
+		if !isValidName(name) {
+			http.NotFound(w, r)
+			return
+		}
+		file, err := s.getByName(r.Context(), name)
+		switch {
+		case errors.Is(err, ErrUnknownSource):
+			http.NotFound(w, r)
+			return
+		case err != nil:
+			// error can contain source url and paths, so client gets only status
+			s.logger.Warn("media request failed", zap.String("name", name), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", file.MediaType)
+		// name is hash of source, so file is never changed
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(file.Data))
+	}))
+}
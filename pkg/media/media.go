@@ -0,0 +1,360 @@
+// media downloads audio files of lemmas, checks them and caches them on disk.
+// Cached files are served to UI and sent to Anki, so Anki itself never
+// downloads anything.
+package media
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultMaxSize is maximum size of single file if other is not specified.
+	DefaultMaxSize = 10 << 20
+	// sourcesSize is number of sources that are remembered to be served by name.
+	sourcesSize = 4096
+
+	typeExt = ".type"
+)
+
+var (
+	ErrNotAudio      = errors.New("media is not audio")
+	ErrTooLarge      = errors.New("media is too large")
+	ErrUnknownSource = errors.New("media source is unknown")
+)
+
+// Fetcher is the same interface as basicdict.Fetcher
+type Fetcher interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+type Options struct {
+	// Dir is directory where files are cached, it's created if not exists.
+	Dir string
+	// MaxSize is maximum size of single file in bytes, zero means DefaultMaxSize.
+	MaxSize int64
+	// MaxTotalSize is maximum size of all files in bytes, the oldest files are removed
+	// when it's exceeded. Zero means no limit.
+	MaxTotalSize int64
+	// Logger is used for errors that are not returned to clients, it's optional.
+	Logger *zap.Logger
+}
+
+// File is downloaded and checked media file.
+type File struct {
+	Name      string
+	MediaType string
+	Data      []byte
+}
+
+type Store struct {
+	fetcher      Fetcher
+	dir          string
+	maxSize      int64
+	maxTotalSize int64
+	logger       *zap.Logger
+
+	// sources maps names to source urls, so handler knows what to download
+	sources *lru.Cache[string, string]
+
+	callsLock sync.Mutex
+	calls     map[string]*call
+
+	// evictLock serializes eviction, so concurrent downloads don't remove the same files
+	evictLock sync.Mutex
+}
+
+type call struct {
+	done chan struct{}
+	file *File
+	err  error
+}
+
+func New(f Fetcher, opts *Options) (*Store, error) {
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create media directory: %w", err)
+	}
+	maxSize := opts.MaxSize
+	if maxSize == 0 {
+		maxSize = DefaultMaxSize
+	}
+	sources, err := lru.New[string, string](sourcesSize)
+	if err != nil {
+		return nil, err
+	}
+	logger := opts.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &Store{
+		fetcher:      f,
+		dir:          opts.Dir,
+		maxSize:      maxSize,
+		maxTotalSize: opts.MaxTotalSize,
+		logger:       logger,
+		sources:      sources,
+		calls:        make(map[string]*call),
+	}, nil
+}
+
+// Name returns name of file with specified source and remembers source,
+// so it can be requested later by name.
+func (s *Store) Name(source string) string {
+	name := sourceName(source)
+	s.sources.Add(name, source)
+	return name
+}
+
+// Get returns cached file or downloads it.
+func (s *Store) Get(ctx context.Context, source string) (*File, error) {
+	name := s.Name(source)
+	file, err := s.load(name)
+	if err == nil || !errors.Is(err, os.ErrNotExist) {
+		return file, err
+	}
+	return s.download(ctx, name, source)
+}
+
+// FetchAudio returns checked audio data of specified source.
+func (s *Store) FetchAudio(ctx context.Context, source string) ([]byte, error) {
+	file, err := s.Get(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	return file.Data, nil
+}
+
+// getByName returns file by its name, source of file should be known.
+func (s *Store) getByName(ctx context.Context, name string) (*File, error) {
+	file, err := s.load(name)
+	if err == nil || !errors.Is(err, os.ErrNotExist) {
+		return file, err
+	}
+	source, ok := s.sources.Get(name)
+	if !ok {
+		return nil, ErrUnknownSource
+	}
+	return s.download(ctx, name, source)
+}
+
+func (s *Store) load(name string) (*File, error) {
+	path := filepath.Join(s.dir, name)
+	mediaType, err := os.ReadFile(path + typeExt)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &File{
+		Name:      name,
+		MediaType: string(mediaType),
+		Data:      data,
+	}, nil
+}
+
+// download downloads file, concurrent downloads of the same file are coalesced.
+func (s *Store) download(ctx context.Context, name string, source string) (*File, error) {
+	s.callsLock.Lock()
+	if c, ok := s.calls[name]; ok {
+		s.callsLock.Unlock()
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		// download is cancelled by context of other caller, but we can still try
+		if isContextError(c.err) && ctx.Err() == nil {
+			return s.download(ctx, name, source)
+		}
+		return c.file, c.err
+	}
+	c := &call{
+		done: make(chan struct{}),
+	}
+	s.calls[name] = c
+	s.callsLock.Unlock()
+
+	c.file, c.err = s.fetch(ctx, name, source)
+	if c.err == nil {
+		c.err = s.save(c.file)
+	}
+	if c.err == nil {
+		s.evict(name)
+	}
+
+	s.callsLock.Lock()
+	delete(s.calls, name)
+	s.callsLock.Unlock()
+	close(c.done)
+	return c.file, c.err
+}
+
+func (s *Store) fetch(ctx context.Context, name string, source string) (*File, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, fmt.Errorf("media request construction failed: %w", err)
+	}
+	resp, err := s.fetcher.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("media request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("media response status %d != 200", resp.StatusCode)
+	}
+	if resp.ContentLength > s.maxSize {
+		return nil, fmt.Errorf("%w: %d bytes", ErrTooLarge, resp.ContentLength)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, s.maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read media: %w", err)
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, fmt.Errorf("%w: more than %d bytes", ErrTooLarge, s.maxSize)
+	}
+	mediaType, err := audioMediaType(resp.Header.Get("Content-Type"), data)
+	if err != nil {
+		return nil, err
+	}
+	return &File{
+		Name:      name,
+		MediaType: mediaType,
+		Data:      data,
+	}, nil
+}
+
+// audioMediaType returns media type of audio from Content-Type header.
+// If server didn't specify type, it's detected from content.
+func audioMediaType(contentType string, data []byte) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType == "application/octet-stream" {
+		mediaType, _, _ = mime.ParseMediaType(http.DetectContentType(data))
+	}
+	if !strings.HasPrefix(mediaType, "audio/") {
+		return "", fmt.Errorf("%w: content type is %q", ErrNotAudio, mediaType)
+	}
+	return mediaType, nil
+}
+
+func (s *Store) save(file *File) error {
+	path := filepath.Join(s.dir, file.Name)
+	// data is written first, so file without data is never visible
+	if err := writeFileAtomic(path, file.Data); err != nil {
+		return fmt.Errorf("failed to save media: %w", err)
+	}
+	if err := writeFileAtomic(path+typeExt, []byte(file.MediaType)); err != nil {
+		return fmt.Errorf("failed to save media: %w", err)
+	}
+	return nil
+}
+
+// evict removes the oldest files until total size of files is not greater than maxTotalSize.
+// File with name keep is never removed, so just downloaded file is always available.
+func (s *Store) evict(keep string) {
+	if s.maxTotalSize <= 0 {
+		return
+	}
+	s.evictLock.Lock()
+	defer s.evictLock.Unlock()
+	dirEntries, err := os.ReadDir(s.dir)
+	if err != nil {
+		s.logger.Warn("media eviction failed", zap.Error(err))
+		return
+	}
+	type cachedFile struct {
+		name    string
+		size    int64
+		modTime time.Time
+	}
+	var files []cachedFile
+	var total int64
+	for _, dirEntry := range dirEntries {
+		if !isValidName(dirEntry.Name()) {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, cachedFile{
+			name:    dirEntry.Name(),
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+		total += info.Size()
+	}
+	slices.SortFunc(files, func(a, b cachedFile) int {
+		return a.modTime.Compare(b.modTime)
+	})
+	for _, file := range files {
+		if total <= s.maxTotalSize {
+			break
+		}
+		if file.name == keep {
+			continue
+		}
+		path := filepath.Join(s.dir, file.name)
+		// type is removed first, so file without type is never loaded
+		if err := os.Remove(path + typeExt); err != nil && !errors.Is(err, os.ErrNotExist) {
+			s.logger.Warn("media eviction failed", zap.Error(err))
+			continue
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			s.logger.Warn("media eviction failed", zap.Error(err))
+			continue
+		}
+		total -= file.size
+	}
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func sourceName(source string) string {
+	hash := sha256.Sum256([]byte(source))
+	return hex.EncodeToString(hash[:])
+}
+
+func isValidName(name string) bool {
+	if len(name) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(name)
+	return err == nil
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), path)
+	}
+	if err != nil {
+		os.Remove(file.Name())
+	}
+	return err
+}
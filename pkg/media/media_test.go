@@ -0,0 +1,206 @@
+package media
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// id3Header is enough for http.DetectContentType to recognize mp3
+const id3Header = "ID3\x03\x00\x00\x00\x00\x00\x00"
+
+func newTestServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/audio.mp3":
+			w.Header().Set("Content-Type", "audio/mpeg")
+			_, _ = io.WriteString(w, "mp3 data")
+		case "/untyped.mp3":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			_, _ = io.WriteString(w, id3Header+"mp3 data")
+		case "/page.html":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = io.WriteString(w, "<html></html>")
+		case "/large.mp3":
+			w.Header().Set("Content-Type", "audio/mpeg")
+			_, _ = io.WriteString(w, strings.Repeat("a", 100))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func newTestStore(t *testing.T) *Store {
+	store, err := New(http.DefaultClient, &Options{
+		Dir:     t.TempDir(),
+		MaxSize: 50,
+	})
+	require.NoError(t, err)
+	return store
+}
+
+func Test_Store_Get(t *testing.T) {
+	server, _ := newTestServer(t)
+	testCases := []struct {
+		Name      string
+		Path      string
+		MediaType string
+		Data      string
+		Error     error
+		ErrorMsg  string
+	}{
+		{
+			Name:      "audio",
+			Path:      "/audio.mp3",
+			MediaType: "audio/mpeg",
+			Data:      "mp3 data",
+		},
+		{
+			Name:      "detected type",
+			Path:      "/untyped.mp3",
+			MediaType: "audio/mpeg",
+			Data:      id3Header + "mp3 data",
+		},
+		{
+			Name:  "not audio",
+			Path:  "/page.html",
+			Error: ErrNotAudio,
+		},
+		{
+			Name:  "too large",
+			Path:  "/large.mp3",
+			Error: ErrTooLarge,
+		},
+		{
+			Name:     "not found",
+			Path:     "/notexists.mp3",
+			ErrorMsg: "status 404",
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			store := newTestStore(t)
+			file, err := store.Get(context.Background(), server.URL+tc.Path)
+			switch {
+			case tc.Error != nil:
+				assert.ErrorIs(t, err, tc.Error)
+			case tc.ErrorMsg != "":
+				assert.ErrorContains(t, err, tc.ErrorMsg)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, tc.MediaType, file.MediaType)
+				assert.Equal(t, tc.Data, string(file.Data))
+			}
+		})
+	}
+}
+
+func Test_Store_Cache(t *testing.T) {
+	server, requests := newTestServer(t)
+	dir := t.TempDir()
+	store, err := New(http.DefaultClient, &Options{Dir: dir})
+	require.NoError(t, err)
+	source := server.URL + "/audio.mp3"
+	_, err = store.Get(context.Background(), source)
+	require.NoError(t, err)
+	data, err := store.FetchAudio(context.Background(), source)
+	require.NoError(t, err)
+	assert.Equal(t, "mp3 data", string(data))
+	assert.Equal(t, int32(1), requests.Load())
+
+	// files should survive restart
+	store, err = New(http.DefaultClient, &Options{Dir: dir})
+	require.NoError(t, err)
+	file, err := store.Get(context.Background(), source)
+	require.NoError(t, err)
+	assert.Equal(t, "audio/mpeg", file.MediaType)
+	assert.Equal(t, int32(1), requests.Load())
+
+	// failures are not cached
+	_, err = store.Get(context.Background(), server.URL+"/page.html")
+	require.Error(t, err)
+	_, err = store.Get(context.Background(), server.URL+"/page.html")
+	require.Error(t, err)
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func Test_Store_Evict(t *testing.T) {
+	server, requests := newTestServer(t)
+	dir := t.TempDir()
+	store, err := New(http.DefaultClient, &Options{
+		Dir:          dir,
+		MaxTotalSize: 20,
+	})
+	require.NoError(t, err)
+	audio := server.URL + "/audio.mp3"
+	untyped := server.URL + "/untyped.mp3"
+	_, err = store.Get(context.Background(), audio)
+	require.NoError(t, err)
+	// older file is removed, because both of them don't fit
+	_, err = store.Get(context.Background(), untyped)
+	require.NoError(t, err)
+	assert.NoFileExists(t, filepath.Join(dir, sourceName(audio)))
+	assert.NoFileExists(t, filepath.Join(dir, sourceName(audio)+typeExt))
+	assert.FileExists(t, filepath.Join(dir, sourceName(untyped)))
+	assert.Equal(t, int32(2), requests.Load())
+
+	// removed file is downloaded again
+	data, err := store.FetchAudio(context.Background(), audio)
+	require.NoError(t, err)
+	assert.Equal(t, "mp3 data", string(data))
+	assert.Equal(t, int32(3), requests.Load())
+	assert.NoFileExists(t, filepath.Join(dir, sourceName(untyped)))
+}
+
+func Test_Store_Handler(t *testing.T) {
+	server, requests := newTestServer(t)
+	store := newTestStore(t)
+	handler := httptest.NewServer(store.Handler("/media/"))
+	defer handler.Close()
+
+	get := func(t *testing.T, path string) (*http.Response, string) {
+		resp, err := http.Get(handler.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, string(body)
+	}
+
+	t.Run("unknown source", func(t *testing.T) {
+		resp, _ := get(t, "/media/"+sourceName(server.URL+"/audio.mp3"))
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, int32(0), requests.Load())
+	})
+	t.Run("invalid name", func(t *testing.T) {
+		resp, _ := get(t, "/media/..%2fsecret")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+	t.Run("known source", func(t *testing.T) {
+		url := store.URL("/media/", server.URL+"/audio.mp3")
+		resp, body := get(t, url)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "audio/mpeg", resp.Header.Get("Content-Type"))
+		assert.Equal(t, "mp3 data", body)
+	})
+	t.Run("download failed", func(t *testing.T) {
+		url := store.URL("/media/", server.URL+"/page.html")
+		resp, body := get(t, url)
+		assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+		// details of error are only logged
+		assert.Equal(t, http.StatusText(http.StatusBadGateway)+"\n", body)
+	})
+}
@@ -1,8 +1,49 @@
 package multidict
 
+import "time"
+
 type Options struct {
 	Workers int
 
-	LemmaDict LemmaDict
-	PitchDict PitchDict
+	// LemmaDicts are queried concurrently, order specifies priority of dictionary
+	// when results are merged.
+	LemmaDicts []LemmaSource
+	// PitchDicts are queried concurrently, if several dictionaries have pitch for the
+	// same word, the first one (in order of PitchDicts) is used.
+	PitchDicts  []PitchSource
+	MergePolicy MergePolicy
+	Timeouts    Timeouts
+}
+
+// Timeouts specifies how long dictionaries are waited.
+type Timeouts struct {
+	// Overall is deadline of whole query, zero means default timeout.
+	Overall time.Duration
+	// Source is deadline of request to single dictionary, zero means that only
+	// overall deadline is used.
+	Source time.Duration
+	// SoftPitch makes pitch dictionaries optional: if they fail or miss deadline,
+	// lemmas are returned without their pitches and with warning instead of error.
+	SoftPitch bool
+}
+
+func (t *Timeouts) overall() time.Duration {
+	if t.Overall == 0 {
+		return defaultTimeout
+	}
+	return t.Overall
+}
+
+// LemmaSource is lemma dictionary with its name.
+type LemmaSource struct {
+	// Name is added to sources of every lemma found in dictionary
+	Name string
+	Dict LemmaDict
+}
+
+// PitchSource is pitch dictionary with its name.
+type PitchSource struct {
+	// Name is used in error messages
+	Name string
+	Dict PitchDict
 }
@@ -0,0 +1,150 @@
+package multidict
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+// MergePolicy specifies how results of several lemma dictionaries are combined.
+type MergePolicy int
+
+const (
+	// MergePolicyFirstWins returns results from all dictionaries, but if the same
+	// lemma (same slug and reading) is found in several dictionaries, only lemma from
+	// the first one (in order of Options.LemmaDicts) is returned.
+	MergePolicyFirstWins MergePolicy = iota
+	// MergePolicyUnion returns results from all dictionaries, the same lemmas
+	// are merged into one: their senses, forms, tags and audio are united.
+	MergePolicyUnion
+	// MergePolicyPriority returns results only from the first dictionary that
+	// found anything.
+	MergePolicyPriority
+)
+
+var mergePolicyNames = map[string]MergePolicy{
+	"first-wins": MergePolicyFirstWins,
+	"union":      MergePolicyUnion,
+	"priority":   MergePolicyPriority,
+}
+
+// ParseMergePolicy converts name of policy (as it's written in config) to MergePolicy.
+// Empty name is MergePolicyFirstWins.
+func ParseMergePolicy(name string) (MergePolicy, error) {
+	if name == "" {
+		return MergePolicyFirstWins, nil
+	}
+	policy, ok := mergePolicyNames[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown merge policy %q", name)
+	}
+	return policy, nil
+}
+
+type lemmaKey struct {
+	Word     string
+	Hiragana string
+}
+
+func newLemmaKey(l *lemma.Lemma) lemmaKey {
+	return lemmaKey{
+		Word:     l.Slug.Word,
+		Hiragana: l.Slug.Hiragana,
+	}
+}
+
+// mergeLemmas merges results from several dictionaries according to policy.
+// Results must be in the same order as sources. Returned lemmas are copies
+// and can be modified without affecting results.
+func mergeLemmas(policy MergePolicy, sources []string, results [][]*lemma.Lemma) []*lemma.Lemma {
+	if policy == MergePolicyPriority {
+		for i, lemmas := range results {
+			if len(lemmas) == 0 {
+				continue
+			}
+			merged := make([]*lemma.Lemma, len(lemmas))
+			for j, l := range lemmas {
+				merged[j] = copyLemma(l, sources[i])
+			}
+			return merged
+		}
+		return nil
+	}
+	type seenLemma struct {
+		Source int
+		Lemma  *lemma.Lemma
+	}
+	var merged []*lemma.Lemma
+	seen := map[lemmaKey]seenLemma{}
+	for i, lemmas := range results {
+		for _, l := range lemmas {
+			key := newLemmaKey(l)
+			previous, ok := seen[key]
+			// duplicates inside one dictionary are left as is, they are probably different words
+			if ok && previous.Source != i {
+				if policy == MergePolicyUnion {
+					uniteLemma(previous.Lemma, l, sources[i])
+				}
+				continue
+			}
+			lemmaCopy := copyLemma(l, sources[i])
+			if !ok {
+				seen[key] = seenLemma{
+					Source: i,
+					Lemma:  lemmaCopy,
+				}
+			}
+			merged = append(merged, lemmaCopy)
+		}
+	}
+	return merged
+}
+
+// copyLemma makes copy of lemma with specified source. Lemma returned by dictionary can
+// be cached, so we need copy slices that we can later modify (for example by lemma.Enrich).
+func copyLemma(l *lemma.Lemma, source string) *lemma.Lemma {
+	lemmaCopy := *l
+	lemmaCopy.Tags = slices.Clone(l.Tags)
+	lemmaCopy.Forms = slices.Clone(l.Forms)
+	lemmaCopy.Senses = slices.Clone(l.Senses)
+	lemmaCopy.Audio = slices.Clone(l.Audio)
+	lemmaCopy.Sources = append(slices.Clone(l.Sources), source)
+	return &lemmaCopy
+}
+
+// uniteLemma adds to dst everything from src that dst doesn't have.
+func uniteLemma(dst *lemma.Lemma, src *lemma.Lemma, source string) {
+	if len(dst.Slug.Furigana) == 0 {
+		dst.Slug.Furigana = src.Slug.Furigana
+	}
+	for _, tag := range src.Tags {
+		if !slices.Contains(dst.Tags, tag) {
+			dst.Tags = append(dst.Tags, tag)
+		}
+	}
+	for _, form := range src.Forms {
+		exists := slices.ContainsFunc(dst.Forms, func(w lemma.Word) bool {
+			return w.Word == form.Word && w.Hiragana == form.Hiragana
+		})
+		if !exists {
+			dst.Forms = append(dst.Forms, form)
+		}
+	}
+	for _, sense := range src.Senses {
+		exists := slices.ContainsFunc(dst.Senses, func(s lemma.WordSense) bool {
+			return slices.Equal(s.Definition, sense.Definition)
+		})
+		if !exists {
+			dst.Senses = append(dst.Senses, sense)
+		}
+	}
+	for _, audio := range src.Audio {
+		if !slices.Contains(dst.Audio, audio) {
+			dst.Audio = append(dst.Audio, audio)
+		}
+	}
+	if !slices.Contains(dst.Sources, source) {
+		dst.Sources = append(dst.Sources, source)
+	}
+}
@@ -0,0 +1,241 @@
+package multidict
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+func Test_ParseMergePolicy(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		Policy      string
+		Expected    MergePolicy
+		ErrorAssert require.ErrorAssertionFunc
+	}{
+		{
+			Name:        "empty",
+			Policy:      "",
+			Expected:    MergePolicyFirstWins,
+			ErrorAssert: require.NoError,
+		},
+		{
+			Name:        "first-wins",
+			Policy:      "first-wins",
+			Expected:    MergePolicyFirstWins,
+			ErrorAssert: require.NoError,
+		},
+		{
+			Name:        "union",
+			Policy:      "union",
+			Expected:    MergePolicyUnion,
+			ErrorAssert: require.NoError,
+		},
+		{
+			Name:        "priority",
+			Policy:      "priority",
+			Expected:    MergePolicyPriority,
+			ErrorAssert: require.NoError,
+		},
+		{
+			Name:        "unknown",
+			Policy:      "random",
+			ErrorAssert: require.Error,
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			policy, err := ParseMergePolicy(tc.Policy)
+			tc.ErrorAssert(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.Expected, policy)
+		})
+	}
+}
+
+func Test_mergeLemmas(t *testing.T) {
+	sources := []string{"first", "second"}
+	newResults := func() [][]*lemma.Lemma {
+		return [][]*lemma.Lemma{
+			{
+				{
+					Slug:   lemma.Word{Word: "犬", Hiragana: "いぬ"},
+					Tags:   []string{"Common word"},
+					Senses: []lemma.WordSense{{Definition: []string{"dog"}}},
+				},
+				{
+					Slug:   lemma.Word{Word: "猫", Hiragana: "ねこ"},
+					Senses: []lemma.WordSense{{Definition: []string{"cat"}}},
+				},
+			},
+			{
+				{
+					Slug: lemma.Word{
+						Word:     "犬",
+						Hiragana: "いぬ",
+						Furigana: lemma.Furigana{{Kanji: "犬", Hiragana: "いぬ"}},
+					},
+					Tags:  []string{"Common word", "JLPT N5"},
+					Forms: []lemma.Word{{Word: "狗", Hiragana: "いぬ"}},
+					Senses: []lemma.WordSense{
+						{Definition: []string{"dog"}},
+						{Definition: []string{"snitch"}},
+					},
+				},
+				{
+					Slug:   lemma.Word{Word: "鳥", Hiragana: "とり"},
+					Senses: []lemma.WordSense{{Definition: []string{"bird"}}},
+				},
+			},
+		}
+	}
+	testCases := []struct {
+		Name     string
+		Policy   MergePolicy
+		Results  func() [][]*lemma.Lemma
+		Expected []*lemma.Lemma
+	}{
+		{
+			Name:    "first-wins",
+			Policy:  MergePolicyFirstWins,
+			Results: newResults,
+			Expected: []*lemma.Lemma{
+				{
+					Slug:    lemma.Word{Word: "犬", Hiragana: "いぬ"},
+					Tags:    []string{"Common word"},
+					Senses:  []lemma.WordSense{{Definition: []string{"dog"}}},
+					Sources: []string{"first"},
+				},
+				{
+					Slug:    lemma.Word{Word: "猫", Hiragana: "ねこ"},
+					Senses:  []lemma.WordSense{{Definition: []string{"cat"}}},
+					Sources: []string{"first"},
+				},
+				{
+					Slug:    lemma.Word{Word: "鳥", Hiragana: "とり"},
+					Senses:  []lemma.WordSense{{Definition: []string{"bird"}}},
+					Sources: []string{"second"},
+				},
+			},
+		},
+		{
+			Name:    "union",
+			Policy:  MergePolicyUnion,
+			Results: newResults,
+			Expected: []*lemma.Lemma{
+				{
+					Slug: lemma.Word{
+						Word:     "犬",
+						Hiragana: "いぬ",
+						Furigana: lemma.Furigana{{Kanji: "犬", Hiragana: "いぬ"}},
+					},
+					Tags:  []string{"Common word", "JLPT N5"},
+					Forms: []lemma.Word{{Word: "狗", Hiragana: "いぬ"}},
+					Senses: []lemma.WordSense{
+						{Definition: []string{"dog"}},
+						{Definition: []string{"snitch"}},
+					},
+					Sources: []string{"first", "second"},
+				},
+				{
+					Slug:    lemma.Word{Word: "猫", Hiragana: "ねこ"},
+					Senses:  []lemma.WordSense{{Definition: []string{"cat"}}},
+					Sources: []string{"first"},
+				},
+				{
+					Slug:    lemma.Word{Word: "鳥", Hiragana: "とり"},
+					Senses:  []lemma.WordSense{{Definition: []string{"bird"}}},
+					Sources: []string{"second"},
+				},
+			},
+		},
+		{
+			Name:    "priority",
+			Policy:  MergePolicyPriority,
+			Results: newResults,
+			Expected: []*lemma.Lemma{
+				{
+					Slug:    lemma.Word{Word: "犬", Hiragana: "いぬ"},
+					Tags:    []string{"Common word"},
+					Senses:  []lemma.WordSense{{Definition: []string{"dog"}}},
+					Sources: []string{"first"},
+				},
+				{
+					Slug:    lemma.Word{Word: "猫", Hiragana: "ねこ"},
+					Senses:  []lemma.WordSense{{Definition: []string{"cat"}}},
+					Sources: []string{"first"},
+				},
+			},
+		},
+		{
+			Name:   "priority skips empty",
+			Policy: MergePolicyPriority,
+			Results: func() [][]*lemma.Lemma {
+				results := newResults()
+				results[0] = nil
+				return results
+			},
+			Expected: []*lemma.Lemma{
+				{
+					Slug: lemma.Word{
+						Word:     "犬",
+						Hiragana: "いぬ",
+						Furigana: lemma.Furigana{{Kanji: "犬", Hiragana: "いぬ"}},
+					},
+					Tags:  []string{"Common word", "JLPT N5"},
+					Forms: []lemma.Word{{Word: "狗", Hiragana: "いぬ"}},
+					Senses: []lemma.WordSense{
+						{Definition: []string{"dog"}},
+						{Definition: []string{"snitch"}},
+					},
+					Sources: []string{"second"},
+				},
+				{
+					Slug:    lemma.Word{Word: "鳥", Hiragana: "とり"},
+					Senses:  []lemma.WordSense{{Definition: []string{"bird"}}},
+					Sources: []string{"second"},
+				},
+			},
+		},
+		{
+			Name:   "duplicates in one source are kept",
+			Policy: MergePolicyUnion,
+			Results: func() [][]*lemma.Lemma {
+				return [][]*lemma.Lemma{
+					{
+						{Slug: lemma.Word{Word: "はし"}, Senses: []lemma.WordSense{{Definition: []string{"bridge"}}}},
+						{Slug: lemma.Word{Word: "はし"}, Senses: []lemma.WordSense{{Definition: []string{"chopsticks"}}}},
+					},
+				}
+			},
+			Expected: []*lemma.Lemma{
+				{
+					Slug:    lemma.Word{Word: "はし"},
+					Senses:  []lemma.WordSense{{Definition: []string{"bridge"}}},
+					Sources: []string{"first"},
+				},
+				{
+					Slug:    lemma.Word{Word: "はし"},
+					Senses:  []lemma.WordSense{{Definition: []string{"chopsticks"}}},
+					Sources: []string{"first"},
+				},
+			},
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			results := tc.Results()
+			actual := mergeLemmas(tc.Policy, sources, results)
+			assert.Equal(t, tc.Expected, actual)
+			// original results should not be modified
+			assert.Equal(t, tc.Results(), results)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package multidict
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"go.uber.org/multierr"
@@ -11,10 +12,21 @@ import (
 	"github.com/Darkclainer/japwords/pkg/workerpool"
 )
 
+const (
+	// defaultWorkers is number of workers if it's not specified
+	defaultWorkers = 4
+	// defaultTimeout is overall deadline of query if it's not specified
+	defaultTimeout = 45 * time.Second
+)
+
 // MultiDict
 type MultiDict struct {
-	lemmaDict LemmaDict
-	pitchDict PitchDict
+	lemmaDicts []LemmaSource
+	pitchDicts []PitchSource
+
+	settingsLock sync.RWMutex
+	mergePolicy  MergePolicy
+	timeouts     Timeouts
 
 	workerPool *workerpool.WorkerPool
 }
@@ -30,7 +42,7 @@ type PitchDict interface {
 func New(opts *Options) (*MultiDict, error) {
 	workers := opts.Workers
 	if workers == 0 {
-		workers = 4
+		workers = defaultWorkers
 	}
 	wp, err := workerpool.New(workers)
 	if err != nil {
@@ -38,9 +50,11 @@ func New(opts *Options) (*MultiDict, error) {
 	}
 
 	return &MultiDict{
-		lemmaDict:  opts.LemmaDict,
-		pitchDict:  opts.PitchDict,
-		workerPool: wp,
+		lemmaDicts:  opts.LemmaDicts,
+		pitchDicts:  opts.PitchDicts,
+		mergePolicy: opts.MergePolicy,
+		timeouts:    opts.Timeouts,
+		workerPool:  wp,
 	}, nil
 }
 
@@ -52,122 +66,321 @@ func (m *MultiDict) Close() {
 	m.workerPool.Stop()
 }
 
+// SetWorkers changes number of workers, zero means default number.
+// It's safe to call it while queries are in flight.
+func (m *MultiDict) SetWorkers(workers int) error {
+	if workers == 0 {
+		workers = defaultWorkers
+	}
+	return m.workerPool.Resize(workers)
+}
+
+// SetMergePolicy changes merge policy for subsequent queries.
+func (m *MultiDict) SetMergePolicy(policy MergePolicy) {
+	m.settingsLock.Lock()
+	defer m.settingsLock.Unlock()
+	m.mergePolicy = policy
+}
+
+// SetTimeouts changes timeouts for subsequent queries.
+func (m *MultiDict) SetTimeouts(timeouts Timeouts) {
+	m.settingsLock.Lock()
+	defer m.settingsLock.Unlock()
+	m.timeouts = timeouts
+}
+
+func (m *MultiDict) currentMergePolicy() MergePolicy {
+	m.settingsLock.RLock()
+	defer m.settingsLock.RUnlock()
+	return m.mergePolicy
+}
+
+func (m *MultiDict) currentTimeouts() Timeouts {
+	m.settingsLock.RLock()
+	defer m.settingsLock.RUnlock()
+	return m.timeouts
+}
+
+// Query requests all lemma and pitch dictionaries concurrently, merges lemmas according
+// to merge policy and enriches them with pitches. Errors from separate dictionaries are
+// combined, so lemmas can be returned together with error. Warnings are dropped.
 func (m *MultiDict) Query(ctx context.Context, query string) ([]*lemma.Lemma, error) {
-	ctx, cancel := m.defaultContext(ctx)
+	lemmas, _, err := m.QueryWithWarnings(ctx, query)
+	return lemmas, err
+}
+
+// QueryWithWarnings is the same as Query, but if pitch dictionaries are optional (see Timeouts.SoftPitch)
+// their failures are returned as warnings instead of error.
+func (m *MultiDict) QueryWithWarnings(ctx context.Context, query string) ([]*lemma.Lemma, []*SourceError, error) {
+	ctx, cancel, timeouts := m.queryContext(ctx)
 	defer cancel()
+	mergePolicy := m.currentMergePolicy()
 
-	lemmasChan, err := m.queryAsync(ctx, query)
+	lemmasChan, err := m.queryAsync(ctx, query, timeouts.Source)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	pitchChan, err := m.queryPitchAsync(ctx, query)
+	pitchChan, err := m.queryPitchAsync(ctx, query, timeouts.Source)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	var (
-		lemmas      []*lemma.Lemma
-		pitches     []*lemma.PitchedLemma
-		combinedErr error
+		lemmaResults = make([][]*lemma.Lemma, len(m.lemmaDicts))
+		pitchResults = make([][]*lemma.PitchedLemma, len(m.pitchDicts))
+		pitchesDone  = make([]bool, len(m.pitchDicts))
+		lemmasLeft   = len(m.lemmaDicts)
+		pitchesLeft  = len(m.pitchDicts)
+		foundLemmas  bool
+		combinedErr  error
+		warnings     []*SourceError
+		// pitchDeadline is set only if pitches are optional, after it lemmas are not waiting pitches
+		pitchDeadline <-chan time.Time
+		pitchExpired  error
 	)
-	// we want collect result from both dictionary if possible,
-	// but pitch dictionary more or less optional.
-	for lemmasChan != nil || pitchChan != nil {
+	if timeouts.SoftPitch && timeouts.Source > 0 {
+		timer := time.NewTimer(timeouts.Source)
+		defer timer.Stop()
+		pitchDeadline = timer.C
+	}
+	// we want collect result from all dictionaries if possible,
+	// but pitch dictionaries more or less optional.
+	for lemmasLeft > 0 || (pitchesLeft > 0 && pitchExpired == nil) {
 		select {
 		case r := <-lemmasChan:
 			if r.Err != nil {
 				combinedErr = multierr.Append(
 					combinedErr,
-					fmt.Errorf("lemma dict request failed: %w", r.Err),
+					newLemmaError(m.lemmaDicts[r.Index].Name, r.Err),
 				)
 			}
-			lemmasChan = nil
-			lemmas = r.Value
+			lemmasLeft--
+			lemmaResults[r.Index] = r.Value
+			foundLemmas = foundLemmas || len(r.Value) != 0
 			// if we didn't get any lemmas then no need to wait pitches
-			if len(lemmas) == 0 {
-				return nil, combinedErr
+			if lemmasLeft == 0 && !foundLemmas {
+				return nil, nil, combinedErr
 			}
 		case r := <-pitchChan:
+			if r.Err != nil {
+				if timeouts.SoftPitch {
+					warnings = append(warnings, newPitchError(m.pitchDicts[r.Index].Name, r.Err))
+				} else {
+					combinedErr = multierr.Append(
+						combinedErr,
+						newPitchError(m.pitchDicts[r.Index].Name, r.Err),
+					)
+				}
+			}
+			pitchesLeft--
+			pitchResults[r.Index] = r.Value
+			pitchesDone[r.Index] = true
+		case <-pitchDeadline:
+			pitchDeadline = nil
+			pitchExpired = context.DeadlineExceeded
+		case <-ctx.Done():
+			if !timeouts.SoftPitch || lemmasLeft > 0 {
+				return mergeLemmas(mergePolicy, m.lemmaSourceNames(), lemmaResults), nil, ctx.Err()
+			}
+			pitchExpired = ctx.Err()
+		}
+	}
+	for i, done := range pitchesDone {
+		if !done {
+			warnings = append(warnings, newPitchError(m.pitchDicts[i].Name, pitchExpired))
+		}
+	}
+	pitches := concatPitches(pitchResults)
+	lemmas := mergeLemmas(mergePolicy, m.lemmaSourceNames(), lemmaResults)
+	// lemma.Enrich uses the first suitable pitch, so pitch dictionaries order is preserved
+	lemma.Enrich(lemmas, pitches)
+	return lemmas, warnings, combinedErr
+}
+
+// QueryLemmas requests only lemma dictionaries and merges lemmas according to merge policy.
+// Lemmas are not enriched with pitches, use QueryPitches for them.
+func (m *MultiDict) QueryLemmas(ctx context.Context, query string) ([]*lemma.Lemma, error) {
+	ctx, cancel, timeouts := m.queryContext(ctx)
+	defer cancel()
+	mergePolicy := m.currentMergePolicy()
+	lemmasChan, err := m.queryAsync(ctx, query, timeouts.Source)
+	if err != nil {
+		return nil, err
+	}
+	lemmaResults := make([][]*lemma.Lemma, len(m.lemmaDicts))
+	var combinedErr error
+	for range m.lemmaDicts {
+		select {
+		case r := <-lemmasChan:
 			if r.Err != nil {
 				combinedErr = multierr.Append(
 					combinedErr,
-					fmt.Errorf("pitch dict request failed: %w", r.Err),
+					newLemmaError(m.lemmaDicts[r.Index].Name, r.Err),
 				)
 			}
-			pitchChan = nil
-			pitches = r.Value
+			lemmaResults[r.Index] = r.Value
 		case <-ctx.Done():
-			return lemmas, ctx.Err()
+			return mergeLemmas(mergePolicy, m.lemmaSourceNames(), lemmaResults), ctx.Err()
 		}
 	}
-	lemma.Enrich(lemmas, pitches)
-	return lemmas, combinedErr
+	return mergeLemmas(mergePolicy, m.lemmaSourceNames(), lemmaResults), combinedErr
+}
+
+// QueryPitches requests only pitch dictionaries. Pitches are returned in order of
+// dictionaries, so they can be passed to lemma.Enrich. Pitches are optional, so
+// failures of dictionaries are returned as warnings.
+func (m *MultiDict) QueryPitches(ctx context.Context, query string) ([]*lemma.PitchedLemma, []*SourceError) {
+	ctx, cancel, timeouts := m.queryContext(ctx)
+	defer cancel()
+	pitchChan, err := m.queryPitchAsync(ctx, query, timeouts.Source)
+	if err != nil {
+		warnings := make([]*SourceError, len(m.pitchDicts))
+		for i := range m.pitchDicts {
+			warnings[i] = newPitchError(m.pitchDicts[i].Name, err)
+		}
+		return nil, warnings
+	}
+	pitchResults := make([][]*lemma.PitchedLemma, len(m.pitchDicts))
+	pitchesDone := make([]bool, len(m.pitchDicts))
+	var warnings []*SourceError
+	for range m.pitchDicts {
+		select {
+		case r := <-pitchChan:
+			if r.Err != nil {
+				warnings = append(warnings, newPitchError(m.pitchDicts[r.Index].Name, r.Err))
+			}
+			pitchResults[r.Index] = r.Value
+			pitchesDone[r.Index] = true
+			continue
+		case <-ctx.Done():
+		}
+		for i, done := range pitchesDone {
+			if !done {
+				warnings = append(warnings, newPitchError(m.pitchDicts[i].Name, ctx.Err()))
+			}
+		}
+		break
+	}
+	return concatPitches(pitchResults), warnings
+}
+
+func concatPitches(pitchResults [][]*lemma.PitchedLemma) []*lemma.PitchedLemma {
+	var pitches []*lemma.PitchedLemma
+	for _, pitchResult := range pitchResults {
+		pitches = append(pitches, pitchResult...)
+	}
+	return pitches
 }
 
+// QueryPitch returns pitch for specified slug and reading. If several pitch dictionaries
+// have pitch for it, the first one is returned.
 func (m *MultiDict) QueryPitch(ctx context.Context, slug string, hiragana string) ([]lemma.PitchShape, error) {
-	ctx, cancel := m.defaultContext(ctx)
+	ctx, cancel, timeouts := m.queryContext(ctx)
 	defer cancel()
-	pitchedLemmasChan, err := m.queryPitchAsync(ctx, slug)
+	pitchedLemmasChan, err := m.queryPitchAsync(ctx, slug, timeouts.Source)
 	if err != nil {
 		return nil, err
 	}
-	var pitchedLemmas []*lemma.PitchedLemma
+	pitchResults := make([][]*lemma.PitchedLemma, len(m.pitchDicts))
 	var pitchedErr error
-	select {
-	case r := <-pitchedLemmasChan:
-		pitchedErr = r.Err
-		pitchedLemmas = r.Value
-	case <-ctx.Done():
-		return nil, ctx.Err()
-	}
-	for _, reading := range pitchedLemmas {
-		if reading.Slug == slug && reading.Hiragana == hiragana {
-			return reading.PitchShapes, pitchedErr
+	for range m.pitchDicts {
+		select {
+		case r := <-pitchedLemmasChan:
+			if r.Err != nil {
+				pitchedErr = multierr.Append(
+					pitchedErr,
+					newPitchError(m.pitchDicts[r.Index].Name, r.Err),
+				)
+			}
+			pitchResults[r.Index] = r.Value
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	for _, pitchedLemmas := range pitchResults {
+		for _, reading := range pitchedLemmas {
+			if reading.Slug == slug && reading.Hiragana == hiragana {
+				return reading.PitchShapes, pitchedErr
+			}
 		}
 	}
 	return nil, pitchedErr
 }
 
-func (m *MultiDict) queryAsync(ctx context.Context, query string) (<-chan result[[]*lemma.Lemma], error) {
-	// we need boffered channel to not block workerpool
-	lemmaResult := make(chan result[[]*lemma.Lemma], 1)
-	fn := func(fctx context.Context) {
-		lemmas, err := m.lemmaDict.Query(fctx, query)
-		lemmaResult <- newResult(lemmas, err)
+// LemmaSourceCount returns number of lemma dictionaries.
+func (m *MultiDict) LemmaSourceCount() int {
+	return len(m.lemmaDicts)
+}
+
+func (m *MultiDict) lemmaSourceNames() []string {
+	names := make([]string, len(m.lemmaDicts))
+	for i, source := range m.lemmaDicts {
+		names[i] = source.Name
 	}
-	err := m.workerPool.Add(ctx, fn)
-	if err != nil {
-		return nil, err
+	return names
+}
+
+func (m *MultiDict) queryAsync(ctx context.Context, query string, timeout time.Duration) (<-chan result[[]*lemma.Lemma], error) {
+	// we need buffered channel to not block workerpool
+	lemmaResult := make(chan result[[]*lemma.Lemma], len(m.lemmaDicts))
+	for i, source := range m.lemmaDicts {
+		index, dict := i, source.Dict
+		fn := func(fctx context.Context) {
+			fctx, cancel := withOptionalTimeout(fctx, timeout)
+			defer cancel()
+			lemmas, err := dict.Query(fctx, query)
+			lemmaResult <- newResult(index, lemmas, err)
+		}
+		err := m.workerPool.Add(ctx, fn)
+		if err != nil {
+			return nil, err
+		}
 	}
 	return lemmaResult, nil
 }
 
-func (m *MultiDict) queryPitchAsync(ctx context.Context, query string) (<-chan result[[]*lemma.PitchedLemma], error) {
-	// we need boffered channel to not block workerpool
-	pitchResult := make(chan result[[]*lemma.PitchedLemma], 1)
-	fn := func(fctx context.Context) {
-		pitches, err := m.pitchDict.Query(fctx, query)
-		pitchResult <- newResult(pitches, err)
-	}
-	err := m.workerPool.Add(ctx, fn)
-	if err != nil {
-		return nil, err
+func (m *MultiDict) queryPitchAsync(ctx context.Context, query string, timeout time.Duration) (<-chan result[[]*lemma.PitchedLemma], error) {
+	// we need buffered channel to not block workerpool
+	pitchResult := make(chan result[[]*lemma.PitchedLemma], len(m.pitchDicts))
+	for i, source := range m.pitchDicts {
+		index, dict := i, source.Dict
+		fn := func(fctx context.Context) {
+			fctx, cancel := withOptionalTimeout(fctx, timeout)
+			defer cancel()
+			pitches, err := dict.Query(fctx, query)
+			pitchResult <- newResult(index, pitches, err)
+		}
+		err := m.workerPool.Add(ctx, fn)
+		if err != nil {
+			return nil, err
+		}
 	}
 	return pitchResult, nil
 }
 
-func (m *MultiDict) defaultContext(ctx context.Context) (context.Context, context.CancelFunc) {
-	// some default timeout for safety
-	ctx, cancel := context.WithTimeout(ctx, time.Second*45)
-	return ctx, cancel
+// queryContext returns context with overall deadline and timeouts for query.
+func (m *MultiDict) queryContext(ctx context.Context) (context.Context, context.CancelFunc, Timeouts) {
+	timeouts := m.currentTimeouts()
+	ctx, cancel := context.WithTimeout(ctx, timeouts.overall())
+	return ctx, cancel, timeouts
+}
+
+func withOptionalTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout == 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
 }
 
 type result[T any] struct {
+	// Index is index of dictionary in options
+	Index int
 	Value T
 	Err   error
 }
 
-func newResult[T any](v T, err error) result[T] {
+func newResult[T any](index int, v T, err error) result[T] {
 	return result[T]{
+		Index: index,
 		Value: v,
 		Err:   err,
 	}
@@ -13,6 +13,11 @@ import (
 	"github.com/Darkclainer/japwords/pkg/lemma"
 )
 
+const (
+	testLemmaSource = "lemma"
+	testPitchSource = "pitch"
+)
+
 type LemmaDictTest func(ctx context.Context, query string) ([]*lemma.Lemma, error)
 
 func (f LemmaDictTest) Query(ctx context.Context, query string) ([]*lemma.Lemma, error) {
@@ -30,12 +35,22 @@ func Test_Multidict_New(t *testing.T) {
 	defer cancel()
 	multidict, err := New(&Options{
 		Workers: 0,
-		LemmaDict: LemmaDictTest(func(ctx context.Context, query string) ([]*lemma.Lemma, error) {
-			return getLemmasTest("query"), nil
-		}),
-		PitchDict: PitchDictTest(func(ctx context.Context, query string) ([]*lemma.PitchedLemma, error) {
-			return getPitchedLemmasTest("query"), nil
-		}),
+		LemmaDicts: []LemmaSource{
+			{
+				Name: testLemmaSource,
+				Dict: LemmaDictTest(func(ctx context.Context, query string) ([]*lemma.Lemma, error) {
+					return getLemmasTest("query"), nil
+				}),
+			},
+		},
+		PitchDicts: []PitchSource{
+			{
+				Name: testPitchSource,
+				Dict: PitchDictTest(func(ctx context.Context, query string) ([]*lemma.PitchedLemma, error) {
+					return getPitchedLemmasTest("query"), nil
+				}),
+			},
+		},
 	})
 	require.NoError(t, err)
 	multidict.Init()
@@ -173,7 +188,7 @@ func Test_Multidict_Query(t *testing.T) {
 				return assert.ErrorContains(tt, err, "pitch error")
 			},
 			Expected: func(t *testing.T, result []*lemma.Lemma) {
-				assert.Equal(t, getLemmasTest(query), result)
+				assert.Equal(t, getSourcedLemmasTest(query), result)
 			},
 		},
 		{
@@ -195,7 +210,7 @@ func Test_Multidict_Query(t *testing.T) {
 				return assert.ErrorContains(tt, err, "pitch error")
 			},
 			Expected: func(t *testing.T, result []*lemma.Lemma) {
-				assert.Equal(t, getLemmasTest(query), result)
+				assert.Equal(t, getSourcedLemmasTest(query), result)
 			},
 		},
 		{
@@ -245,7 +260,7 @@ func Test_Multidict_Query(t *testing.T) {
 				if len(result) == 0 {
 					return
 				}
-				assert.Equal(t, getLemmasTest(query), result)
+				assert.Equal(t, getSourcedLemmasTest(query), result)
 			},
 		},
 	}
@@ -262,9 +277,9 @@ func Test_Multidict_Query(t *testing.T) {
 				defer cancel()
 				lemmaDict, pitchDict := tc.InitHandlers(t, ctx, cancel)
 				multidict, err := New(&Options{
-					Workers:   workers,
-					LemmaDict: lemmaDict,
-					PitchDict: pitchDict,
+					Workers:    workers,
+					LemmaDicts: []LemmaSource{{Name: testLemmaSource, Dict: lemmaDict}},
+					PitchDicts: []PitchSource{{Name: testPitchSource, Dict: pitchDict}},
 				})
 				require.NoError(t, err)
 				multidict.Init()
@@ -277,13 +292,299 @@ func Test_Multidict_Query(t *testing.T) {
 	}
 }
 
+func Test_Multidict_Query_MultipleSources(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	pitchShape := func(direction lemma.AccentDirection) []lemma.PitchShape {
+		return []lemma.PitchShape{
+			{
+				Hiragana:   "query",
+				Directions: []lemma.AccentDirection{direction},
+			},
+		}
+	}
+	multidict, err := New(&Options{
+		Workers: 2,
+		LemmaDicts: []LemmaSource{
+			{
+				Name: "broken",
+				Dict: LemmaDictTest(func(ctx context.Context, query string) ([]*lemma.Lemma, error) {
+					return nil, errors.New("lemma error")
+				}),
+			},
+			{
+				Name: "first",
+				Dict: LemmaDictTest(func(ctx context.Context, query string) ([]*lemma.Lemma, error) {
+					return getLemmasTest(query), nil
+				}),
+			},
+			{
+				Name: "second",
+				Dict: LemmaDictTest(func(ctx context.Context, query string) ([]*lemma.Lemma, error) {
+					return getLemmasTest(query), nil
+				}),
+			},
+		},
+		PitchDicts: []PitchSource{
+			{
+				Name: "first",
+				Dict: PitchDictTest(func(ctx context.Context, query string) ([]*lemma.PitchedLemma, error) {
+					return []*lemma.PitchedLemma{
+						{Slug: query, Hiragana: query, PitchShapes: pitchShape(lemma.AccentDirectionDown)},
+					}, nil
+				}),
+			},
+			{
+				Name: "second",
+				Dict: PitchDictTest(func(ctx context.Context, query string) ([]*lemma.PitchedLemma, error) {
+					return []*lemma.PitchedLemma{
+						{Slug: query, Hiragana: query, PitchShapes: pitchShape(lemma.AccentDirectionUp)},
+					}, errors.New("pitch error")
+				}),
+			},
+		},
+		MergePolicy: MergePolicyUnion,
+	})
+	require.NoError(t, err)
+	multidict.Init()
+	defer multidict.Close()
+	lemmas, err := multidict.Query(ctx, "query")
+	assert.ErrorContains(t, err, `lemma dict "broken" request failed: lemma error`)
+	assert.ErrorContains(t, err, `pitch dict "second" request failed: pitch error`)
+	var sourceErr *SourceError
+	require.ErrorAs(t, err, &sourceErr)
+	assert.Equal(t, SourceTypeLemma, sourceErr.SourceType)
+	assert.Equal(t, "broken", sourceErr.Source)
+	assert.Equal(t, []*lemma.Lemma{
+		{
+			Slug: lemma.Word{
+				Word:        "query",
+				Hiragana:    "query",
+				PitchShapes: pitchShape(lemma.AccentDirectionDown),
+			},
+			Tags:    []string{"just test"},
+			Sources: []string{"first", "second"},
+		},
+	}, lemmas)
+}
+
+func Test_Multidict_QueryWithWarnings(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	slowPitchDict := PitchDictTest(func(ctx context.Context, query string) ([]*lemma.PitchedLemma, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	multidict, err := New(&Options{
+		Workers: 4,
+		LemmaDicts: []LemmaSource{
+			{
+				Name: testLemmaSource,
+				Dict: LemmaDictTest(func(ctx context.Context, query string) ([]*lemma.Lemma, error) {
+					return getLemmasTest(query), nil
+				}),
+			},
+		},
+		PitchDicts: []PitchSource{
+			{Name: "slow", Dict: slowPitchDict},
+			{
+				Name: "broken",
+				Dict: PitchDictTest(func(ctx context.Context, query string) ([]*lemma.PitchedLemma, error) {
+					return nil, errors.New("pitch error")
+				}),
+			},
+			{
+				Name: testPitchSource,
+				Dict: PitchDictTest(func(ctx context.Context, query string) ([]*lemma.PitchedLemma, error) {
+					return getPitchedLemmasTest(query), nil
+				}),
+			},
+		},
+		MergePolicy: MergePolicyFirstWins,
+		Timeouts: Timeouts{
+			Source:    50 * time.Millisecond,
+			SoftPitch: true,
+		},
+	})
+	require.NoError(t, err)
+	multidict.Init()
+	defer multidict.Close()
+
+	lemmas, warnings, err := multidict.QueryWithWarnings(ctx, "query")
+	require.NoError(t, err)
+	assert.Equal(t, getResultLemmasTest("query"), lemmas)
+	require.Len(t, warnings, 2)
+	assert.Equal(t, "broken", warnings[0].Source)
+	assert.Equal(t, SourceTypePitch, warnings[0].SourceType)
+	assert.EqualError(t, warnings[0], `pitch dict "broken" request failed: pitch error`)
+	assert.Equal(t, "slow", warnings[1].Source)
+	assert.True(t, warnings[1].DeadlineMissed())
+	assert.EqualError(t, warnings[1], `pitch dict "slow" missed deadline`)
+
+	// without soft pitch the same failures are errors
+	multidict.SetTimeouts(Timeouts{Source: 50 * time.Millisecond})
+	lemmas, warnings, err = multidict.QueryWithWarnings(ctx, "query")
+	assert.ErrorContains(t, err, `pitch dict "broken" request failed: pitch error`)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Empty(t, warnings)
+	assert.Equal(t, getResultLemmasTest("query"), lemmas)
+
+	// overall deadline is not fatal with soft pitch if lemmas are found
+	multidict.SetTimeouts(Timeouts{Overall: 50 * time.Millisecond, SoftPitch: true})
+	lemmas, warnings, err = multidict.QueryWithWarnings(ctx, "query")
+	require.NoError(t, err)
+	assert.Equal(t, getResultLemmasTest("query"), lemmas)
+	require.Len(t, warnings, 2)
+	assert.True(t, warnings[1].DeadlineMissed())
+}
+
+func Test_Multidict_Query_SourceTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	multidict, err := New(&Options{
+		Workers: 2,
+		LemmaDicts: []LemmaSource{
+			{
+				Name: "slow",
+				Dict: LemmaDictTest(func(ctx context.Context, query string) ([]*lemma.Lemma, error) {
+					<-ctx.Done()
+					return nil, ctx.Err()
+				}),
+			},
+			{
+				Name: testLemmaSource,
+				Dict: LemmaDictTest(func(ctx context.Context, query string) ([]*lemma.Lemma, error) {
+					return getLemmasTest(query), nil
+				}),
+			},
+		},
+		MergePolicy: MergePolicyUnion,
+		Timeouts: Timeouts{
+			Source:    50 * time.Millisecond,
+			SoftPitch: true,
+		},
+	})
+	require.NoError(t, err)
+	multidict.Init()
+	defer multidict.Close()
+
+	lemmas, err := multidict.Query(ctx, "query")
+	assert.ErrorContains(t, err, `lemma dict "slow" missed deadline`)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, getSourcedLemmasTest("query"), lemmas)
+}
+
+func Test_Multidict_QueryLemmas_QueryPitches(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	// pitch dictionary is slow and answers only after lemmas are received
+	lemmasReceived := make(chan struct{})
+	pitchShapes := []lemma.PitchShape{
+		{
+			Hiragana:   "query",
+			Directions: []lemma.AccentDirection{lemma.AccentDirectionDown},
+		},
+	}
+	multidict, err := New(&Options{
+		Workers: 2,
+		LemmaDicts: []LemmaSource{
+			{
+				Name: testLemmaSource,
+				Dict: LemmaDictTest(func(ctx context.Context, query string) ([]*lemma.Lemma, error) {
+					return getLemmasTest(query), nil
+				}),
+			},
+		},
+		PitchDicts: []PitchSource{
+			{
+				Name: testPitchSource,
+				Dict: PitchDictTest(func(ctx context.Context, query string) ([]*lemma.PitchedLemma, error) {
+					select {
+					case <-lemmasReceived:
+					case <-ctx.Done():
+						return nil, ctx.Err()
+					}
+					return []*lemma.PitchedLemma{
+						{Slug: query, Hiragana: query, PitchShapes: pitchShapes},
+					}, nil
+				}),
+			},
+			{
+				Name: "broken",
+				Dict: PitchDictTest(func(ctx context.Context, query string) ([]*lemma.PitchedLemma, error) {
+					return nil, errors.New("pitch error")
+				}),
+			},
+		},
+		MergePolicy: MergePolicyFirstWins,
+	})
+	require.NoError(t, err)
+	multidict.Init()
+	defer multidict.Close()
+
+	type pitchResult struct {
+		Pitches  []*lemma.PitchedLemma
+		Warnings []*SourceError
+	}
+	pitchChan := make(chan pitchResult, 1)
+	go func() {
+		pitches, warnings := multidict.QueryPitches(ctx, "query")
+		pitchChan <- pitchResult{Pitches: pitches, Warnings: warnings}
+	}()
+	lemmas, err := multidict.QueryLemmas(ctx, "query")
+	require.NoError(t, err)
+	require.Len(t, lemmas, 1)
+	assert.Empty(t, lemmas[0].Slug.PitchShapes)
+	close(lemmasReceived)
+
+	result := <-pitchChan
+	require.Len(t, result.Warnings, 1)
+	assert.ErrorContains(t, result.Warnings[0], `pitch dict "broken" request failed: pitch error`)
+	assert.Equal(t, []*lemma.PitchedLemma{
+		{Slug: "query", Hiragana: "query", PitchShapes: pitchShapes},
+	}, result.Pitches)
+}
+
+func Test_Multidict_Reload(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	lemmaDict := LemmaDictTest(func(ctx context.Context, query string) ([]*lemma.Lemma, error) {
+		return getLemmasTest(query), nil
+	})
+	multidict, err := New(&Options{
+		Workers: 1,
+		LemmaDicts: []LemmaSource{
+			{Name: "first", Dict: lemmaDict},
+			{Name: "second", Dict: lemmaDict},
+		},
+		MergePolicy: MergePolicyFirstWins,
+	})
+	require.NoError(t, err)
+	multidict.Init()
+	defer multidict.Close()
+
+	lemmas, err := multidict.Query(ctx, "query")
+	require.NoError(t, err)
+	require.Len(t, lemmas, 1)
+	assert.Equal(t, []string{"first"}, lemmas[0].Sources)
+
+	require.NoError(t, multidict.SetWorkers(3))
+	multidict.SetMergePolicy(MergePolicyUnion)
+	lemmas, err = multidict.Query(ctx, "query")
+	require.NoError(t, err)
+	require.Len(t, lemmas, 1)
+	assert.Equal(t, []string{"first", "second"}, lemmas[0].Sources)
+
+	assert.Error(t, multidict.SetWorkers(-1))
+}
+
 func Test_Multidict_Stop_Query(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
 	defer cancel()
 	multidict, err := New(&Options{
-		Workers:   1,
-		LemmaDict: nil,
-		PitchDict: nil,
+		Workers:    1,
+		LemmaDicts: []LemmaSource{{Name: testLemmaSource}},
+		PitchDicts: []PitchSource{{Name: testPitchSource}},
 	})
 	require.NoError(t, err)
 	multidict.Init()
@@ -297,17 +598,17 @@ func Test_Multidict_Query_Stop(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
 	defer cancel()
 	multidict, err := New(&Options{
-		Workers:   1,
-		LemmaDict: nil,
-		PitchDict: nil,
+		Workers:    1,
+		LemmaDicts: []LemmaSource{{Name: testLemmaSource}},
+		PitchDicts: []PitchSource{{Name: testPitchSource}},
 	})
 	require.NoError(t, err)
-	multidict.lemmaDict = LemmaDictTest(func(ctx context.Context, query string) ([]*lemma.Lemma, error) {
+	multidict.lemmaDicts[0].Dict = LemmaDictTest(func(ctx context.Context, query string) ([]*lemma.Lemma, error) {
 		multidict.Close()
 		<-ctx.Done()
 		return nil, ctx.Err()
 	})
-	multidict.pitchDict = PitchDictTest(func(ctx context.Context, query string) ([]*lemma.PitchedLemma, error) {
+	multidict.pitchDicts[0].Dict = PitchDictTest(func(ctx context.Context, query string) ([]*lemma.PitchedLemma, error) {
 		return nil, nil
 	})
 	multidict.Init()
@@ -498,8 +799,8 @@ func Test_Multidict_QueryPitch(t *testing.T) {
 				defer cancel()
 				pitchDict := tc.InitHandlers(t, ctx, cancel)
 				multidict, err := New(&Options{
-					Workers:   workers,
-					PitchDict: pitchDict,
+					Workers:    workers,
+					PitchDicts: []PitchSource{{Name: testPitchSource, Dict: pitchDict}},
 				})
 				require.NoError(t, err)
 				multidict.Init()
@@ -516,8 +817,8 @@ func Test_Multidict_Stop_QueryPitch(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
 	defer cancel()
 	multidict, err := New(&Options{
-		Workers:   1,
-		PitchDict: nil,
+		Workers:    1,
+		PitchDicts: []PitchSource{{Name: testPitchSource}},
 	})
 	require.NoError(t, err)
 	multidict.Init()
@@ -531,11 +832,12 @@ func Test_Multidict_QueryPitch_Stop(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
 	defer cancel()
 	multidict, err := New(&Options{
-		Workers: 1,
+		Workers:    1,
+		PitchDicts: []PitchSource{{Name: testPitchSource}},
 	})
 	require.NoError(t, err)
 	wait := make(chan struct{})
-	multidict.pitchDict = PitchDictTest(func(ctx context.Context, query string) ([]*lemma.PitchedLemma, error) {
+	multidict.pitchDicts[0].Dict = PitchDictTest(func(ctx context.Context, query string) ([]*lemma.PitchedLemma, error) {
 		close(wait)
 		<-ctx.Done()
 		return nil, ctx.Err()
@@ -562,6 +864,15 @@ func getLemmasTest(query string) []*lemma.Lemma {
 	}
 }
 
+// getSourcedLemmasTest returns lemmas from getLemmasTest as they returned by multidict
+func getSourcedLemmasTest(query string) []*lemma.Lemma {
+	lemmas := getLemmasTest(query)
+	for _, l := range lemmas {
+		l.Sources = []string{testLemmaSource}
+	}
+	return lemmas
+}
+
 func getPitchedLemmasTest(query string) []*lemma.PitchedLemma {
 	return []*lemma.PitchedLemma{
 		{
@@ -594,7 +905,8 @@ func getResultLemmasTest(query string) []*lemma.Lemma {
 					},
 				},
 			},
-			Tags: []string{"just test"},
+			Tags:    []string{"just test"},
+			Sources: []string{testLemmaSource},
 		},
 	}
 }
@@ -0,0 +1,67 @@
+package multidict
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// SourceType is type of dictionary.
+type SourceType int
+
+const (
+	SourceTypeLemma SourceType = iota
+	SourceTypePitch
+)
+
+func (t SourceType) String() string {
+	switch t {
+	case SourceTypeLemma:
+		return "lemma"
+	case SourceTypePitch:
+		return "pitch"
+	default:
+		return fmt.Sprintf("SourceType(%d)", int(t))
+	}
+}
+
+// SourceError is failure of single dictionary. It's combined with errors of other dictionaries
+// or returned as warning if dictionary is optional, in both cases result is returned without data from it.
+type SourceError struct {
+	SourceType SourceType
+	// Source is name of dictionary
+	Source string
+	Err    error
+}
+
+func newLemmaError(source string, err error) *SourceError {
+	return &SourceError{
+		SourceType: SourceTypeLemma,
+		Source:     source,
+		Err:        err,
+	}
+}
+
+func newPitchError(source string, err error) *SourceError {
+	return &SourceError{
+		SourceType: SourceTypePitch,
+		Source:     source,
+		Err:        err,
+	}
+}
+
+// DeadlineMissed reports whether dictionary didn't answer in time.
+func (e *SourceError) DeadlineMissed() bool {
+	return errors.Is(e.Err, context.DeadlineExceeded)
+}
+
+func (e *SourceError) Error() string {
+	if e.DeadlineMissed() {
+		return fmt.Sprintf("%s dict %q missed deadline", e.SourceType, e.Source)
+	}
+	return fmt.Sprintf("%s dict %q request failed: %s", e.SourceType, e.Source, e.Err)
+}
+
+func (e *SourceError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,122 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Darkclainer/japwords/pkg/anki"
+)
+
+// FlushResult is summary of Flush.
+type FlushResult struct {
+	// Added is number of notes that were added to Anki
+	Added int
+	// Duplicates is number of notes that were removed, because the same notes already exist in Anki
+	Duplicates int
+	// Failed is number of notes that failed and are left in outbox
+	Failed int
+}
+
+// IsUnreachable returns true if err means that AnkiConnect can't be reached,
+// notes that failed with such error should be queued.
+func IsUnreachable(err error) bool {
+	var connectionError *anki.ConnectionError
+	return errors.As(err, &connectionError)
+}
+
+// isAnkiUnavailable returns true if err is not related to specific note, so
+// there is no point to try other notes.
+func isAnkiUnavailable(err error) bool {
+	return IsUnreachable(err) ||
+		errors.Is(err, anki.ErrCollectionUnavailable) ||
+		errors.Is(err, anki.ErrInvalidAPIKey) ||
+		errors.Is(err, anki.ErrForbiddenOrigin) ||
+		errors.Is(err, anki.ErrIncompleteConfiguration)
+}
+
+// Flush tries to add all queued notes in order. Added notes are removed from outbox, as well as notes
+// that Anki reports as duplicates. Notes that Anki rejects for other reasons (for example, with empty
+// first field) are left with their errors.
+// Flush stops and returns error if Anki becomes unavailable.
+func (o *Outbox) Flush(ctx context.Context) (*FlushResult, error) {
+	o.flushLock.Lock()
+	defer o.flushLock.Unlock()
+	result := &FlushResult{}
+	if o.closed {
+		return result, nil
+	}
+	entries, err := o.List()
+	if err != nil {
+		return result, err
+	}
+	for _, entry := range entries {
+		// entry could be edited or discarded while previous notes were added
+		entry, err := o.Get(entry.ID)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return result, err
+		}
+		_, err = o.add(ctx, entry)
+		switch {
+		case err == nil:
+			result.Added++
+		case errors.Is(err, anki.ErrDuplicatedNoteFound):
+			result.Duplicates++
+		case isAnkiUnavailable(err) || ctx.Err() != nil:
+			return result, err
+		default:
+			result.Failed++
+		}
+	}
+	return result, nil
+}
+
+// Retry tries to add single queued note. Note is removed from outbox if it was added or
+// if Anki reports it as duplicate (anki.ErrDuplicatedNoteFound is returned in this case).
+func (o *Outbox) Retry(ctx context.Context, id uint64) (anki.NoteID, error) {
+	o.flushLock.Lock()
+	defer o.flushLock.Unlock()
+	if o.closed {
+		return 0, ErrNotFound
+	}
+	entry, err := o.Get(id)
+	if err != nil {
+		return 0, err
+	}
+	return o.add(ctx, entry)
+}
+
+// add adds note of entry to Anki and updates outbox according to result.
+func (o *Outbox) add(ctx context.Context, entry *Entry) (anki.NoteID, error) {
+	noteID, err := o.opts.Anki.AddNote(ctx, entry.Request)
+	switch {
+	case err == nil:
+		if discardErr := o.Discard(entry.ID); discardErr != nil && !errors.Is(discardErr, ErrNotFound) {
+			return noteID, fmt.Errorf("note %s is added, but it's not removed from outbox: %w", noteID, discardErr)
+		}
+		if o.opts.OnAdded != nil {
+			o.opts.OnAdded(entry, noteID)
+		}
+		return noteID, nil
+	case errors.Is(err, anki.ErrDuplicatedNoteFound):
+		if discardErr := o.Discard(entry.ID); discardErr != nil && !errors.Is(discardErr, ErrNotFound) {
+			return 0, errors.Join(err, discardErr)
+		}
+		return 0, err
+	case isAnkiUnavailable(err) || ctx.Err() != nil:
+		// it's not fault of note, so attempt is not counted
+		return 0, err
+	default:
+		_, updateErr := o.update(entry.ID, func(entry *Entry) {
+			entry.Attempts++
+			entry.LastError = err.Error()
+		})
+		if updateErr != nil && !errors.Is(updateErr, ErrNotFound) {
+			return 0, errors.Join(err, updateErr)
+		}
+		return 0, err
+	}
+}
@@ -0,0 +1,190 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Darkclainer/japwords/pkg/anki"
+)
+
+// testAnki adds notes by the first field value, words from errs fail with specified error
+type testAnki struct {
+	errs  map[string]error
+	added []string
+}
+
+func (a *testAnki) AddNote(ctx context.Context, note *anki.AddNoteRequest) (anki.NoteID, error) {
+	word := note.Fields[0].Value
+	if err := a.errs[word]; err != nil {
+		return 0, err
+	}
+	a.added = append(a.added, word)
+	return anki.NoteID(100 + len(a.added)), nil
+}
+
+func Test_Outbox_Flush(t *testing.T) {
+	testCases := []struct {
+		Name           string
+		Errs           map[string]error
+		ExpectedResult *FlushResult
+		ExpectedErr    bool
+		ExpectedAdded  []string
+		ExpectedLeft   []string
+		ExpectedErrors map[string]string
+	}{
+		{
+			Name:           "all added",
+			ExpectedResult: &FlushResult{Added: 3},
+			ExpectedAdded:  []string{"犬", "猫", "鳥"},
+		},
+		{
+			Name: "duplicate is removed",
+			Errs: map[string]error{
+				"猫": anki.ErrDuplicatedNoteFound,
+			},
+			ExpectedResult: &FlushResult{Added: 2, Duplicates: 1},
+			ExpectedAdded:  []string{"犬", "鳥"},
+		},
+		{
+			Name: "failed note is left",
+			Errs: map[string]error{
+				"猫": anki.ErrAudioUnavailable,
+			},
+			ExpectedResult: &FlushResult{Added: 2, Failed: 1},
+			ExpectedAdded:  []string{"犬", "鳥"},
+			ExpectedLeft:   []string{"猫"},
+			ExpectedErrors: map[string]string{
+				"猫": anki.ErrAudioUnavailable.Error(),
+			},
+		},
+		{
+			Name: "note that can't be added is left",
+			Errs: map[string]error{
+				"猫": anki.ErrEmptyOrderField,
+			},
+			ExpectedResult: &FlushResult{Added: 2, Failed: 1},
+			ExpectedAdded:  []string{"犬", "鳥"},
+			ExpectedLeft:   []string{"猫"},
+			ExpectedErrors: map[string]string{
+				"猫": anki.ErrEmptyOrderField.Error(),
+			},
+		},
+		{
+			Name: "stopped when anki is unreachable",
+			Errs: map[string]error{
+				"猫": &anki.ConnectionError{Msg: "refused"},
+			},
+			ExpectedResult: &FlushResult{Added: 1},
+			ExpectedErr:    true,
+			ExpectedAdded:  []string{"犬"},
+			ExpectedLeft:   []string{"猫", "鳥"},
+		},
+		{
+			Name: "stopped when configuration is incomplete",
+			Errs: map[string]error{
+				"犬": fmt.Errorf("wrapped: %w", anki.ErrIncompleteConfiguration),
+			},
+			ExpectedResult: &FlushResult{},
+			ExpectedErr:    true,
+			ExpectedLeft:   []string{"犬", "猫", "鳥"},
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			ankiClient := &testAnki{errs: tc.Errs}
+			var onAdded []string
+			outbox := newTestOutbox(t, Options{
+				Anki: ankiClient,
+				OnAdded: func(entry *Entry, noteID anki.NoteID) {
+					onAdded = append(onAdded, fmt.Sprintf("%s:%d:%s", entry.Request.Fields[0].Value, entry.HistoryID, noteID))
+				},
+			})
+			queueWords(t, outbox, "犬", "猫", "鳥")
+			result, err := outbox.Flush(context.Background())
+			if tc.ExpectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.ExpectedResult, result)
+			assert.Equal(t, tc.ExpectedAdded, ankiClient.added)
+			assert.Len(t, onAdded, len(tc.ExpectedAdded))
+			assert.Equal(t, tc.ExpectedLeft, listWords(t, outbox))
+			entries, err := outbox.List()
+			require.NoError(t, err)
+			for _, entry := range entries {
+				expectedError := tc.ExpectedErrors[entry.Request.Fields[0].Value]
+				assert.Equal(t, expectedError, entry.LastError)
+				if expectedError != "" {
+					assert.Equal(t, 1, entry.Attempts)
+				} else {
+					assert.Equal(t, 0, entry.Attempts)
+				}
+			}
+		})
+	}
+}
+
+func Test_Outbox_Flush_OnAdded(t *testing.T) {
+	var onAdded []string
+	outbox := newTestOutbox(t, Options{
+		Anki: &testAnki{},
+		OnAdded: func(entry *Entry, noteID anki.NoteID) {
+			onAdded = append(onAdded, fmt.Sprintf("%d:%s", entry.HistoryID, noteID))
+		},
+	})
+	_, err := outbox.Queue(testRequest("犬"), 5)
+	require.NoError(t, err)
+	_, err = outbox.Queue(testRequest("猫"), 0)
+	require.NoError(t, err)
+	_, err = outbox.Flush(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"5:101", "0:102"}, onAdded)
+}
+
+func Test_Outbox_Flush_Closed(t *testing.T) {
+	ankiClient := &testAnki{}
+	outbox := newTestOutbox(t, Options{Anki: ankiClient})
+	queueWords(t, outbox, "犬")
+	require.NoError(t, outbox.Close())
+	result, err := outbox.Flush(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &FlushResult{}, result)
+	assert.Empty(t, ankiClient.added)
+}
+
+func Test_Outbox_Retry(t *testing.T) {
+	ankiClient := &testAnki{
+		errs: map[string]error{
+			"猫": errors.New("unknown"),
+			"鳥": anki.ErrDuplicatedNoteFound,
+		},
+	}
+	outbox := newTestOutbox(t, Options{Anki: ankiClient})
+	queueWords(t, outbox, "犬", "猫", "鳥")
+
+	noteID, err := outbox.Retry(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, anki.NoteID(101), noteID)
+
+	_, err = outbox.Retry(context.Background(), 2)
+	assert.ErrorContains(t, err, "unknown")
+	entry, err := outbox.Get(2)
+	require.NoError(t, err)
+	assert.Equal(t, 1, entry.Attempts)
+	assert.Equal(t, "unknown", entry.LastError)
+
+	_, err = outbox.Retry(context.Background(), 3)
+	assert.ErrorIs(t, err, anki.ErrDuplicatedNoteFound)
+
+	_, err = outbox.Retry(context.Background(), 1)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.Equal(t, []string{"猫"}, listWords(t, outbox))
+}
@@ -0,0 +1,246 @@
+// Package outbox keeps notes that couldn't be added because Anki was unreachable,
+// so they can be added later.
+package outbox
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/Darkclainer/japwords/pkg/anki"
+)
+
+var entriesBucket = []byte("entries")
+
+// ErrNotFound is returned if entry with specified id doesn't exist.
+var ErrNotFound = errors.New("outbox entry not found")
+
+// Entry is a single queued note.
+type Entry struct {
+	ID      uint64
+	Request *anki.AddNoteRequest
+	// Time is when note was queued
+	Time time.Time
+	// HistoryID is id of history entry that should be marked when note is added, zero if there is none
+	HistoryID uint64
+	// Attempts is number of failed attempts to add note after it was queued
+	Attempts int
+	// LastError is error of the last failed attempt
+	LastError string
+}
+
+// record is how entry is stored, key of record is id
+type record struct {
+	Request   *anki.AddNoteRequest `json:"r"`
+	Time      int64                `json:"t"`
+	HistoryID uint64               `json:"h,omitempty"`
+	Attempts  int                  `json:"a,omitempty"`
+	LastError string               `json:"e,omitempty"`
+}
+
+// Anki is part of anki.Anki that is used to add queued notes.
+type Anki interface {
+	AddNote(ctx context.Context, note *anki.AddNoteRequest) (anki.NoteID, error)
+}
+
+type Options struct {
+	Anki Anki
+	// OnAdded is optional callback that is called for every queued note that was added
+	OnAdded func(entry *Entry, noteID anki.NoteID)
+}
+
+// Outbox is persistent queue of notes based on embedded key-value database.
+// Entries are ordered by time when they were queued.
+type Outbox struct {
+	db   *bolt.DB
+	opts Options
+	now  func() time.Time
+
+	// flushLock serializes attempts to add notes, so they are added in order
+	flushLock sync.Mutex
+	closed    bool
+}
+
+// Open opens (or creates) outbox located at path.
+func Open(path string, opts Options) (*Outbox, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("outbox directory creation failed: %w", err)
+	}
+	db, err := bolt.Open(path, 0o644, &bolt.Options{
+		Timeout: time.Second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("outbox open failed: %w", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(entriesBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("outbox bucket creation failed: %w", err)
+	}
+	return &Outbox{
+		db:   db,
+		opts: opts,
+		now:  time.Now,
+	}, nil
+}
+
+// Close waits until notes that are being added are processed and closes database.
+func (o *Outbox) Close() error {
+	o.flushLock.Lock()
+	defer o.flushLock.Unlock()
+	o.closed = true
+	return o.db.Close()
+}
+
+// Queue stores note, historyID is id of history entry that should be marked when note is added.
+func (o *Outbox) Queue(request *anki.AddNoteRequest, historyID uint64) (*Entry, error) {
+	entry := &Entry{
+		Request:   request,
+		Time:      o.now(),
+		HistoryID: historyID,
+	}
+	err := o.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(entriesBucket)
+		id, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		entry.ID = id
+		return putEntry(bucket, entry)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("outbox queue failed: %w", err)
+	}
+	return entry, nil
+}
+
+// List returns all entries starting from the oldest one.
+func (o *Outbox) List() ([]*Entry, error) {
+	var entries []*Entry
+	err := o.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(entriesBucket).ForEach(func(k, v []byte) error {
+			entry, err := decodeEntry(k, v)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("outbox list failed: %w", err)
+	}
+	return entries, nil
+}
+
+// Get returns entry with specified id.
+func (o *Outbox) Get(id uint64) (*Entry, error) {
+	var entry *Entry
+	err := o.db.View(func(tx *bolt.Tx) error {
+		var err error
+		entry, err = getEntry(tx.Bucket(entriesBucket), id)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("outbox get failed: %w", err)
+	}
+	return entry, nil
+}
+
+// Edit replaces request of entry, error of the last attempt is cleared.
+func (o *Outbox) Edit(id uint64, request *anki.AddNoteRequest) (*Entry, error) {
+	return o.update(id, func(entry *Entry) {
+		entry.Request = request
+		entry.LastError = ""
+	})
+}
+
+// Discard removes entry with specified id.
+func (o *Outbox) Discard(id uint64) error {
+	err := o.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(entriesBucket)
+		key := encodeID(id)
+		if bucket.Get(key) == nil {
+			return ErrNotFound
+		}
+		return bucket.Delete(key)
+	})
+	if err != nil {
+		return fmt.Errorf("outbox discard failed: %w", err)
+	}
+	return nil
+}
+
+func (o *Outbox) update(id uint64, fn func(entry *Entry)) (*Entry, error) {
+	var entry *Entry
+	err := o.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(entriesBucket)
+		var err error
+		entry, err = getEntry(bucket, id)
+		if err != nil {
+			return err
+		}
+		fn(entry)
+		return putEntry(bucket, entry)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("outbox update failed: %w", err)
+	}
+	return entry, nil
+}
+
+func getEntry(bucket *bolt.Bucket, id uint64) (*Entry, error) {
+	key := encodeID(id)
+	value := bucket.Get(key)
+	if value == nil {
+		return nil, ErrNotFound
+	}
+	return decodeEntry(key, value)
+}
+
+func putEntry(bucket *bolt.Bucket, entry *Entry) error {
+	value, err := json.Marshal(&record{
+		Request:   entry.Request,
+		Time:      entry.Time.UnixNano(),
+		HistoryID: entry.HistoryID,
+		Attempts:  entry.Attempts,
+		LastError: entry.LastError,
+	})
+	if err != nil {
+		return err
+	}
+	return bucket.Put(encodeID(entry.ID), value)
+}
+
+func decodeEntry(key, value []byte) (*Entry, error) {
+	var r record
+	if err := json.Unmarshal(value, &r); err != nil {
+		return nil, fmt.Errorf("entry decoding failed: %w", err)
+	}
+	return &Entry{
+		ID:        binary.BigEndian.Uint64(key),
+		Request:   r.Request,
+		Time:      time.Unix(0, r.Time),
+		HistoryID: r.HistoryID,
+		Attempts:  r.Attempts,
+		LastError: r.LastError,
+	}, nil
+}
+
+// encodeID encodes id in big endian, so keys are ordered by id.
+func encodeID(id uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, id)
+	return key
+}
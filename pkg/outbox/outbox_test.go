@@ -0,0 +1,137 @@
+package outbox
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Darkclainer/japwords/pkg/anki"
+)
+
+func newTestOutbox(t *testing.T, opts Options) *Outbox {
+	outbox, err := Open(filepath.Join(t.TempDir(), "outbox", "outbox.db"), opts)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		outbox.Close()
+	})
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	outbox.now = func() time.Time {
+		return now
+	}
+	return outbox
+}
+
+func testRequest(word string) *anki.AddNoteRequest {
+	return &anki.AddNoteRequest{
+		Fields: []anki.AddNoteField{
+			{Name: "Kanji", Value: word},
+		},
+		Tags: []string{"japwords"},
+		AudioAssets: []anki.AddNoteAudioAsset{
+			{Field: "Audio", Filename: word + ".mp3", URL: "https://example.com/" + word + ".mp3"},
+		},
+	}
+}
+
+func queueWords(t *testing.T, outbox *Outbox, words ...string) {
+	for _, word := range words {
+		_, err := outbox.Queue(testRequest(word), 0)
+		require.NoError(t, err)
+	}
+}
+
+func listWords(t *testing.T, outbox *Outbox) []string {
+	entries, err := outbox.List()
+	require.NoError(t, err)
+	var words []string
+	for _, entry := range entries {
+		words = append(words, entry.Request.Fields[0].Value)
+	}
+	return words
+}
+
+func Test_Outbox_QueueList(t *testing.T) {
+	outbox := newTestOutbox(t, Options{})
+	entry, err := outbox.Queue(testRequest("犬"), 7)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), entry.ID)
+
+	entries, err := outbox.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, &Entry{
+		ID:        1,
+		Request:   testRequest("犬"),
+		Time:      time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC).Local(),
+		HistoryID: 7,
+	}, entries[0])
+
+	queueWords(t, outbox, "猫", "鳥")
+	assert.Equal(t, []string{"犬", "猫", "鳥"}, listWords(t, outbox))
+}
+
+func Test_Outbox_Empty(t *testing.T) {
+	outbox := newTestOutbox(t, Options{})
+	entries, err := outbox.List()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func Test_Outbox_Get(t *testing.T) {
+	outbox := newTestOutbox(t, Options{})
+	queueWords(t, outbox, "犬", "猫")
+	entry, err := outbox.Get(2)
+	require.NoError(t, err)
+	assert.Equal(t, "猫", entry.Request.Fields[0].Value)
+	_, err = outbox.Get(3)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func Test_Outbox_Edit(t *testing.T) {
+	outbox := newTestOutbox(t, Options{})
+	queueWords(t, outbox, "犬", "猫")
+	_, err := outbox.update(1, func(entry *Entry) {
+		entry.Attempts = 2
+		entry.LastError = "failed"
+	})
+	require.NoError(t, err)
+
+	entry, err := outbox.Edit(1, testRequest("狗"))
+	require.NoError(t, err)
+	assert.Equal(t, "狗", entry.Request.Fields[0].Value)
+	assert.Equal(t, 2, entry.Attempts)
+	assert.Empty(t, entry.LastError)
+	assert.Equal(t, []string{"狗", "猫"}, listWords(t, outbox))
+
+	_, err = outbox.Edit(3, testRequest("鳥"))
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func Test_Outbox_Discard(t *testing.T) {
+	outbox := newTestOutbox(t, Options{})
+	queueWords(t, outbox, "犬", "猫", "鳥")
+	require.NoError(t, outbox.Discard(2))
+	assert.Equal(t, []string{"犬", "鳥"}, listWords(t, outbox))
+	assert.ErrorIs(t, outbox.Discard(2), ErrNotFound)
+
+	// ids are not reused
+	entry, err := outbox.Queue(testRequest("魚"), 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), entry.ID)
+}
+
+func Test_Outbox_Reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.db")
+	outbox, err := Open(path, Options{})
+	require.NoError(t, err)
+	queueWords(t, outbox, "犬", "猫")
+	require.NoError(t, outbox.Close())
+
+	outbox, err = Open(path, Options{})
+	require.NoError(t, err)
+	defer outbox.Close()
+	assert.Equal(t, []string{"犬", "猫"}, listWords(t, outbox))
+}
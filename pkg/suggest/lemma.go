@@ -0,0 +1,24 @@
+package suggest
+
+import (
+	"slices"
+
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+// commonWordTag is tag that both jisho and jmdict use for common words
+const commonWordTag = "Common word"
+
+// LemmaWords returns slug and other forms of lemma as candidates.
+func LemmaWords(l *lemma.Lemma) []Word {
+	common := slices.Contains(l.Tags, commonWordTag)
+	words := make([]Word, 0, len(l.Forms)+1)
+	for _, form := range append([]lemma.Word{l.Slug}, l.Forms...) {
+		words = append(words, Word{
+			Text:    form.Word,
+			Reading: form.Hiragana,
+			Common:  common,
+		})
+	}
+	return words
+}
@@ -0,0 +1,26 @@
+package suggest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Darkclainer/japwords/pkg/lemma"
+)
+
+func Test_LemmaWords(t *testing.T) {
+	words := LemmaWords(&lemma.Lemma{
+		Slug: lemma.Word{Word: "犬", Hiragana: "いぬ"},
+		Tags: []string{"Common word"},
+		Forms: []lemma.Word{
+			{Word: "狗", Hiragana: "いぬ"},
+		},
+	})
+	assert.Equal(t, []Word{
+		{Text: "犬", Reading: "いぬ", Common: true},
+		{Text: "狗", Reading: "いぬ", Common: true},
+	}, words)
+	assert.Equal(t, []Word{{Text: "ぺらぺら"}}, LemmaWords(&lemma.Lemma{
+		Slug: lemma.Word{Word: "ぺらぺら"},
+	}))
+}
@@ -0,0 +1,317 @@
+// Package suggest completes beginning of query with words that user looked up
+// before or that are known to dictionaries.
+package suggest
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Darkclainer/japwords/pkg/kana"
+)
+
+const (
+	// usageHalfLife is time after which weight of single usage is halved
+	usageHalfLife = 7 * 24 * time.Hour
+	// commonBonus is added to score of common words, it's less than score of single recent usage,
+	// so words that user looked up go first
+	commonBonus = 0.5
+	// maxPending is how many keys are added to small sorted slice before it's merged with the main one
+	maxPending = 1024
+)
+
+// Word is candidate for suggestion that is known to dictionary.
+type Word struct {
+	Text    string
+	Reading string
+	Common  bool
+}
+
+// Suggestion is word that starts with requested prefix.
+type Suggestion struct {
+	Text    string
+	Reading string
+}
+
+type candidate struct {
+	text    string
+	reading string
+	common  bool
+	// known is true if candidate was added by AddWords, not only by usage
+	known bool
+	// usage is number of usages exponentially decayed to time last
+	usage float64
+	last  time.Time
+	// keys are normalized text and readings that candidate is indexed by
+	keys []string
+}
+
+func (c *candidate) score(now time.Time) float64 {
+	score := c.usage * decay(now.Sub(c.last))
+	if c.common {
+		score += commonBonus
+	}
+	return score
+}
+
+func (c *candidate) addUsage(at time.Time) {
+	if at.After(c.last) {
+		c.usage = c.usage*decay(at.Sub(c.last)) + 1
+		c.last = at
+	} else {
+		c.usage += decay(c.last.Sub(at))
+	}
+}
+
+func (c *candidate) resetUsage() {
+	c.usage = 0
+	c.last = time.Time{}
+}
+
+func decay(age time.Duration) float64 {
+	if age <= 0 {
+		return 1
+	}
+	return math.Exp2(-float64(age) / float64(usageHalfLife))
+}
+
+type indexKey struct {
+	key       string
+	candidate *candidate
+}
+
+// Index is in-memory prefix index of candidates, that is fast enough to be queried on every keystroke.
+// Candidates are ranked by frequency and recency of their usage, common words are preferred.
+// Index is safe for concurrent use.
+type Index struct {
+	lock       sync.RWMutex
+	candidates map[string]*candidate
+	// keys are sorted, new keys are added to sorted pending first, because it's cheaper
+	keys    []indexKey
+	pending []indexKey
+	now     func() time.Time
+}
+
+func New() *Index {
+	return &Index{
+		candidates: map[string]*candidate{},
+		now:        time.Now,
+	}
+}
+
+// AddWords adds words to index, words that are already in index are updated.
+func (i *Index) AddWords(words ...Word) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	var newKeys []indexKey
+	for _, word := range words {
+		c, added := i.candidate(word.Text)
+		if c == nil {
+			continue
+		}
+		newKeys = append(newKeys, added...)
+		c.known = true
+		c.common = c.common || word.Common
+		if word.Reading == "" {
+			continue
+		}
+		if c.reading == "" {
+			c.reading = word.Reading
+		}
+		newKeys = append(newKeys, c.addKey(word.Reading)...)
+	}
+	i.insertKeys(newKeys)
+}
+
+// AddUsage records that text was used (looked up) at specified time.
+func (i *Index) AddUsage(text string, at time.Time) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	c, added := i.candidate(text)
+	if c == nil {
+		return
+	}
+	c.addUsage(at)
+	i.insertKeys(added)
+}
+
+// RemoveUsage forgets all usages of text. Text is still suggested if it was added by AddWords,
+// but it's ranked as if it has never been used.
+func (i *Index) RemoveUsage(text string) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	c, ok := i.candidates[strings.TrimSpace(text)]
+	if !ok {
+		return
+	}
+	if c.known {
+		c.resetUsage()
+		return
+	}
+	delete(i.candidates, c.text)
+	i.removeKeys()
+}
+
+// ClearUsage forgets usages of all candidates, candidates that were added by AddWords are kept.
+func (i *Index) ClearUsage() {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	for text, c := range i.candidates {
+		if c.known {
+			c.resetUsage()
+		} else {
+			delete(i.candidates, text)
+		}
+	}
+	i.removeKeys()
+}
+
+// Len returns number of candidates in index.
+func (i *Index) Len() int {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	return len(i.candidates)
+}
+
+// Suggest returns at most limit the best candidates that start with prefix.
+// Prefix is normalized with kana.NormalizePrefix, so it can be kana, kanji or romaji.
+func (i *Index) Suggest(prefix string, limit int) []Suggestion {
+	prefixes := kana.NormalizePrefix(prefix)
+	if len(prefixes) == 0 || limit <= 0 {
+		return nil
+	}
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	matched := map[*candidate]struct{}{}
+	for _, prefix := range prefixes {
+		for _, keys := range [][]indexKey{i.keys, i.pending} {
+			start := sort.Search(len(keys), func(j int) bool {
+				return keys[j].key >= prefix
+			})
+			for j := start; j < len(keys) && strings.HasPrefix(keys[j].key, prefix); j++ {
+				matched[keys[j].candidate] = struct{}{}
+			}
+		}
+	}
+	type scored struct {
+		candidate *candidate
+		score     float64
+	}
+	now := i.now()
+	candidates := make([]scored, 0, len(matched))
+	for c := range matched {
+		candidates = append(candidates, scored{
+			candidate: c,
+			score:     c.score(now),
+		})
+	}
+	sort.Slice(candidates, func(a, b int) bool {
+		ca, cb := candidates[a], candidates[b]
+		if ca.score != cb.score {
+			return ca.score > cb.score
+		}
+		// shorter words are closer to prefix
+		lenA, lenB := utf8.RuneCountInString(ca.candidate.text), utf8.RuneCountInString(cb.candidate.text)
+		if lenA != lenB {
+			return lenA < lenB
+		}
+		return ca.candidate.text < cb.candidate.text
+	})
+	suggestions := make([]Suggestion, 0, min(limit, len(candidates)))
+	for _, c := range candidates[:min(limit, len(candidates))] {
+		suggestions = append(suggestions, Suggestion{
+			Text:    c.candidate.text,
+			Reading: c.candidate.reading,
+		})
+	}
+	return suggestions
+}
+
+// candidate returns candidate for text creating it if needed and keys that should be added to index.
+func (i *Index) candidate(text string) (*candidate, []indexKey) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, nil
+	}
+	c, ok := i.candidates[text]
+	if ok {
+		return c, nil
+	}
+	c = &candidate{
+		text: text,
+	}
+	i.candidates[text] = c
+	return c, c.addKey(text)
+}
+
+// addKey returns new key if candidate wasn't indexed by it yet.
+func (c *candidate) addKey(text string) []indexKey {
+	key := normalizeKey(text)
+	for _, existing := range c.keys {
+		if existing == key {
+			return nil
+		}
+	}
+	c.keys = append(c.keys, key)
+	return []indexKey{{key: key, candidate: c}}
+}
+
+func (i *Index) insertKeys(keys []indexKey) {
+	if len(keys) == 0 {
+		return
+	}
+	sortKeys(keys)
+	if len(keys) > maxPending {
+		i.keys = mergeKeys(i.keys, keys)
+		return
+	}
+	i.pending = mergeKeys(i.pending, keys)
+	if len(i.pending) > maxPending {
+		i.keys = mergeKeys(i.keys, i.pending)
+		i.pending = nil
+	}
+}
+
+// removeKeys removes keys of candidates that are not in index anymore.
+func (i *Index) removeKeys() {
+	filter := func(keys []indexKey) []indexKey {
+		result := keys[:0]
+		for _, key := range keys {
+			if i.candidates[key.candidate.text] == key.candidate {
+				result = append(result, key)
+			}
+		}
+		return result
+	}
+	i.keys = filter(i.keys)
+	i.pending = filter(i.pending)
+}
+
+func normalizeKey(text string) string {
+	return kana.ToHiragana(strings.ToLower(text))
+}
+
+func sortKeys(keys []indexKey) {
+	sort.Slice(keys, func(a, b int) bool {
+		return keys[a].key < keys[b].key
+	})
+}
+
+// mergeKeys merges two sorted slices into new one.
+func mergeKeys(a, b []indexKey) []indexKey {
+	result := make([]indexKey, 0, len(a)+len(b))
+	for len(a) != 0 && len(b) != 0 {
+		if b[0].key < a[0].key {
+			result = append(result, b[0])
+			b = b[1:]
+		} else {
+			result = append(result, a[0])
+			a = a[1:]
+		}
+	}
+	result = append(result, a...)
+	return append(result, b...)
+}
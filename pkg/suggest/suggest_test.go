@@ -0,0 +1,178 @@
+package suggest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestIndex() (*Index, time.Time) {
+	index := New()
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	index.now = func() time.Time {
+		return now
+	}
+	return index, now
+}
+
+func texts(suggestions []Suggestion) []string {
+	var result []string
+	for _, suggestion := range suggestions {
+		result = append(result, suggestion.Text)
+	}
+	return result
+}
+
+func Test_Index_Suggest(t *testing.T) {
+	index, now := newTestIndex()
+	index.AddWords(
+		Word{Text: "犬", Reading: "いぬ", Common: true},
+		Word{Text: "犬小屋", Reading: "いぬごや"},
+		Word{Text: "稲", Reading: "いね", Common: true},
+		Word{Text: "イヌワシ", Reading: "イヌワシ"},
+		Word{Text: "猫", Reading: "ねこ", Common: true},
+	)
+	index.AddUsage("dog", now.Add(-time.Hour))
+	index.AddUsage("いぬごや", now.Add(-time.Hour))
+
+	testCases := []struct {
+		Name     string
+		Prefix   string
+		Limit    int
+		Expected []string
+	}{
+		{
+			Name:     "hiragana",
+			Prefix:   "いぬ",
+			Limit:    10,
+			Expected: []string{"いぬごや", "犬", "犬小屋", "イヌワシ"},
+		},
+		{
+			Name:     "katakana",
+			Prefix:   "イヌ",
+			Limit:    10,
+			Expected: []string{"いぬごや", "犬", "犬小屋", "イヌワシ"},
+		},
+		{
+			Name:     "romaji with incomplete syllable",
+			Prefix:   "inuw",
+			Limit:    10,
+			Expected: []string{"いぬごや", "犬", "犬小屋", "イヌワシ"},
+		},
+		{
+			Name:     "romaji with trailing n",
+			Prefix:   "in",
+			Limit:    3,
+			Expected: []string{"いぬごや", "犬", "稲"},
+		},
+		{
+			Name:     "kanji",
+			Prefix:   "犬",
+			Limit:    10,
+			Expected: []string{"犬", "犬小屋"},
+		},
+		{
+			Name:     "english",
+			Prefix:   "DO",
+			Limit:    10,
+			Expected: []string{"dog"},
+		},
+		{
+			Name:   "nothing",
+			Prefix: "とり",
+			Limit:  10,
+		},
+		{
+			Name:   "empty prefix",
+			Prefix: " ",
+			Limit:  10,
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, texts(index.Suggest(tc.Prefix, tc.Limit)))
+		})
+	}
+}
+
+func Test_Index_Ranking(t *testing.T) {
+	index, now := newTestIndex()
+	index.AddWords(
+		Word{Text: "common", Common: true},
+		Word{Text: "cold"},
+	)
+	// frequent, but old usage
+	for i := 0; i < 3; i++ {
+		index.AddUsage("cook", now.Add(-30*24*time.Hour))
+	}
+	index.AddUsage("cost", now.Add(-time.Hour))
+	index.AddUsage("coin", now.Add(-2*time.Hour))
+	index.AddUsage("coin", now.Add(-3*time.Hour))
+
+	assert.Equal(t, []string{"coin", "cost", "common", "cook", "cold"}, texts(index.Suggest("co", 10)))
+}
+
+func Test_Index_Reading(t *testing.T) {
+	index, _ := newTestIndex()
+	index.AddUsage("犬", time.Time{})
+	index.AddWords(
+		Word{Text: "犬", Reading: "いぬ"},
+		Word{Text: "犬", Reading: "けん"},
+	)
+	assert.Equal(t, []Suggestion{{Text: "犬", Reading: "いぬ"}}, index.Suggest("ken", 10))
+	assert.Equal(t, 1, index.Len())
+}
+
+func Test_Index_ManyKeys(t *testing.T) {
+	index, _ := newTestIndex()
+	var words []Word
+	for i := 0; i < 3*maxPending; i++ {
+		words = append(words, Word{Text: fmt.Sprintf("word%04d", i)})
+	}
+	// bulk and one by one
+	index.AddWords(words[:maxPending+1]...)
+	for _, word := range words[maxPending+1:] {
+		index.AddWords(word)
+	}
+	require.Equal(t, len(words), index.Len())
+	assert.Equal(t, []string{"word1000", "word1001"}, texts(index.Suggest("word100", 2)))
+	assert.Len(t, index.Suggest("word", len(words)), len(words))
+	assert.Len(t, index.Suggest("word2", len(words)), 1000)
+}
+
+func Test_Index_RemoveUsage(t *testing.T) {
+	index, now := newTestIndex()
+	index.AddWords(Word{Text: "common", Common: true}, Word{Text: "cold"})
+	index.AddUsage("cold", now)
+	index.AddUsage("cook", now)
+	index.AddUsage("cost", now)
+	require.Equal(t, []string{"cold", "cook", "cost", "common"}, texts(index.Suggest("co", 10)))
+
+	// word from dictionary is kept, but it's not ranked by usage anymore
+	index.RemoveUsage("cold")
+	index.RemoveUsage(" cook ")
+	index.RemoveUsage("unknown")
+	assert.Equal(t, []string{"cost", "common", "cold"}, texts(index.Suggest("co", 10)))
+	assert.Equal(t, 3, index.Len())
+
+	// removed word can be used again
+	index.AddUsage("cook", now)
+	assert.Equal(t, []string{"cook", "cost", "common", "cold"}, texts(index.Suggest("co", 10)))
+}
+
+func Test_Index_ClearUsage(t *testing.T) {
+	index, now := newTestIndex()
+	index.AddWords(Word{Text: "犬", Reading: "いぬ"}, Word{Text: "common", Common: true})
+	index.AddUsage("犬", now)
+	index.AddUsage("いぬごや", now)
+	index.AddUsage("cook", now)
+
+	index.ClearUsage()
+	assert.Equal(t, 2, index.Len())
+	assert.Equal(t, []Suggestion{{Text: "犬", Reading: "いぬ"}}, index.Suggest("inu", 10))
+	assert.Equal(t, []string{"common"}, texts(index.Suggest("co", 10)))
+}
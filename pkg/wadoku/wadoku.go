@@ -3,28 +3,40 @@ package wadoku
 import (
 	"context"
 	"net/url"
+	"sync"
 
 	"github.com/Darkclainer/japwords/pkg/lemma"
 )
 
-const defaultBaseURL = "https://www.wadoku.de/search/"
+// DefaultBaseURL is used if base url is not configured.
+const DefaultBaseURL = "https://www.wadoku.de/search/"
 
 type Wadoku struct {
-	client  BasicDict
-	baseURL string
+	client BasicDict
+
+	baseURLLock sync.RWMutex
+	baseURL     string
 }
 type BasicDict interface {
 	Query(context.Context, string) ([]byte, error)
 }
 
 func New(client BasicDict, baseURL string) *Wadoku {
-	if baseURL == "" {
-		baseURL = defaultBaseURL
+	dict := &Wadoku{
+		client: client,
 	}
-	return &Wadoku{
-		client:  client,
-		baseURL: baseURL,
+	dict.SetBaseURL(baseURL)
+	return dict
+}
+
+// SetBaseURL changes url that is used for subsequent queries, empty url means default one.
+func (w *Wadoku) SetBaseURL(baseURL string) {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
 	}
+	w.baseURLLock.Lock()
+	defer w.baseURLLock.Unlock()
+	w.baseURL = baseURL
 }
 
 func (w *Wadoku) Query(ctx context.Context, query string) ([]*lemma.PitchedLemma, error) {
@@ -37,5 +49,7 @@ func (w *Wadoku) Query(ctx context.Context, query string) ([]*lemma.PitchedLemma
 }
 
 func (j *Wadoku) queryURL(query string) string {
+	j.baseURLLock.RLock()
+	defer j.baseURLLock.RUnlock()
 	return j.baseURL + url.PathEscape(query)
 }
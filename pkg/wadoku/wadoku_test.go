@@ -48,3 +48,12 @@ func Test_queryURL(t *testing.T) {
 		})
 	}
 }
+
+func Test_SetBaseURL(t *testing.T) {
+	d := New(nil, "http://localhost:3890/")
+	assert.Equal(t, "http://localhost:3890/hello", d.queryURL("hello"))
+	d.SetBaseURL("http://localhost:3891/")
+	assert.Equal(t, "http://localhost:3891/hello", d.queryURL("hello"))
+	d.SetBaseURL("")
+	assert.Equal(t, DefaultBaseURL+"hello", d.queryURL("hello"))
+}
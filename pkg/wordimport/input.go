@@ -0,0 +1,94 @@
+package wordimport
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Format is format of word list.
+type Format string
+
+const (
+	// FormatText is plain text with word on every line, only text before the first tab is used.
+	FormatText Format = "text"
+	// FormatCSV is CSV file with word in the first column, other columns are ignored.
+	FormatCSV Format = "csv"
+)
+
+// DetectFormat returns format by file extension, files are considered to be plain text by default.
+func DetectFormat(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV
+	default:
+		return FormatText
+	}
+}
+
+func ParseFormat(src string) (Format, error) {
+	switch format := Format(src); format {
+	case FormatText, FormatCSV:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown format %q, expected text or csv", src)
+	}
+}
+
+// byteOrderMark is often added to CSV files by spreadsheet editors
+const byteOrderMark = "\ufeff"
+
+// ReadWords reads words from word list. Empty lines and lines starting with # are skipped,
+// repeated words are returned only once.
+func ReadWords(r io.Reader, format Format) ([]string, error) {
+	var words []string
+	seen := map[string]struct{}{}
+	addWord := func(word string) {
+		word = strings.TrimSpace(strings.TrimPrefix(word, byteOrderMark))
+		if word == "" {
+			return
+		}
+		if _, ok := seen[word]; ok {
+			return
+		}
+		seen[word] = struct{}{}
+		words = append(words, word)
+	}
+	switch format {
+	case FormatText:
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), byteOrderMark))
+			if strings.HasPrefix(line, "#") {
+				continue
+			}
+			word, _, _ := strings.Cut(line, "\t")
+			addWord(word)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	case FormatCSV:
+		reader := csv.NewReader(r)
+		reader.Comment = '#'
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		for {
+			record, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			addWord(record[0])
+		}
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+	return words, nil
+}
@@ -0,0 +1,76 @@
+package wordimport
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DetectFormat(t *testing.T) {
+	assert.Equal(t, FormatCSV, DetectFormat("words.CSV"))
+	assert.Equal(t, FormatText, DetectFormat("words.txt"))
+	assert.Equal(t, FormatText, DetectFormat("words"))
+}
+
+func Test_ParseFormat(t *testing.T) {
+	format, err := ParseFormat("csv")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatCSV, format)
+	_, err = ParseFormat("xml")
+	assert.Error(t, err)
+}
+
+func Test_ReadWords(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		Input       string
+		Format      Format
+		Expected    []string
+		ErrorAssert assert.ErrorAssertionFunc
+	}{
+		{
+			Name:        "text",
+			Input:       "犬\n\n  猫  \n# comment\n食べる\tto eat\n犬\n",
+			Format:      FormatText,
+			Expected:    []string{"犬", "猫", "食べる"},
+			ErrorAssert: assert.NoError,
+		},
+		{
+			Name:        "text with bom",
+			Input:       byteOrderMark + "犬\n猫",
+			Format:      FormatText,
+			Expected:    []string{"犬", "猫"},
+			ErrorAssert: assert.NoError,
+		},
+		{
+			Name:        "csv",
+			Input:       byteOrderMark + "犬,dog\n# comment\n\"猫\",\"cat, kitty\"\n,empty\n食べる\n犬,dog again\n",
+			Format:      FormatCSV,
+			Expected:    []string{"犬", "猫", "食べる"},
+			ErrorAssert: assert.NoError,
+		},
+		{
+			Name:        "invalid csv",
+			Input:       "\"犬,dog\n",
+			Format:      FormatCSV,
+			Expected:    nil,
+			ErrorAssert: assert.Error,
+		},
+		{
+			Name:        "empty",
+			Input:       "",
+			Format:      FormatText,
+			Expected:    nil,
+			ErrorAssert: assert.NoError,
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			words, err := ReadWords(strings.NewReader(tc.Input), tc.Format)
+			tc.ErrorAssert(t, err)
+			assert.Equal(t, tc.Expected, words)
+		})
+	}
+}
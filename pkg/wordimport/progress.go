@@ -0,0 +1,73 @@
+package wordimport
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+)
+
+// Progress keeps results of imported words in file (one JSON object per line),
+// so import can be resumed after interruption.
+type Progress struct {
+	file    *os.File
+	results map[string]*Result
+}
+
+// OpenProgress reads results from file and opens it for appending, file is created if it doesn't exist.
+// Lines that can't be decoded (for example the last line written partially) are ignored.
+func OpenProgress(path string) (*Progress, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	progress := &Progress{
+		file:    file,
+		results: map[string]*Result{},
+	}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		var result Result
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil || result.Word == "" {
+			continue
+		}
+		progress.results[result.Word] = &result
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Join(err, file.Close())
+	}
+	return progress, nil
+}
+
+// Result returns the last recorded result for word.
+func (p *Progress) Result(word string) (*Result, bool) {
+	result, ok := p.results[word]
+	return result, ok
+}
+
+// Record writes result to file.
+func (p *Progress) Record(result *Result) error {
+	line, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	if _, err := p.file.Write(line); err != nil {
+		return err
+	}
+	p.results[result.Word] = result
+	return nil
+}
+
+func (p *Progress) Close() error {
+	return p.file.Close()
+}
+
+// Remove closes and removes progress file.
+func (p *Progress) Remove() error {
+	if err := p.file.Close(); err != nil {
+		return err
+	}
+	return os.Remove(p.file.Name())
+}
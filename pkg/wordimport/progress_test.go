@@ -0,0 +1,46 @@
+package wordimport
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Darkclainer/japwords/pkg/anki"
+)
+
+func Test_Progress(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.progress")
+	progress, err := OpenProgress(path)
+	require.NoError(t, err)
+	_, ok := progress.Result("犬")
+	assert.False(t, ok)
+	require.NoError(t, progress.Record(&Result{Word: "犬", Status: StatusFailed, Error: "timeout"}))
+	require.NoError(t, progress.Record(&Result{Word: "犬", Status: StatusAdded, NoteIDs: []anki.NoteID{12}}))
+	require.NoError(t, progress.Record(&Result{Word: "はし", Status: StatusAmbiguous, Candidates: []string{"橋", "箸"}}))
+	require.NoError(t, progress.Close())
+
+	// simulate interruption in the middle of write
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"word":"猫","sta`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	progress, err = OpenProgress(path)
+	require.NoError(t, err)
+	result, ok := progress.Result("犬")
+	assert.True(t, ok)
+	assert.Equal(t, &Result{Word: "犬", Status: StatusAdded, NoteIDs: []anki.NoteID{12}}, result)
+	result, ok = progress.Result("はし")
+	assert.True(t, ok)
+	assert.Equal(t, &Result{Word: "はし", Status: StatusAmbiguous, Candidates: []string{"橋", "箸"}}, result)
+	_, ok = progress.Result("猫")
+	assert.False(t, ok)
+
+	require.NoError(t, progress.Remove())
+	_, err = os.Stat(path)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
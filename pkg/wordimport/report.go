@@ -0,0 +1,47 @@
+package wordimport
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteReport writes summary of import and lists words that need attention:
+// ambiguous, not found and failed.
+func WriteReport(w io.Writer, results []*Result) error {
+	byStatus := map[Status][]*Result{}
+	for _, result := range results {
+		byStatus[result.Status] = append(byStatus[result.Status], result)
+	}
+	buffer := bufio.NewWriter(w)
+	fmt.Fprintf(buffer, "Words: %d\n", len(results))
+	fmt.Fprintf(buffer, "Added: %d\n", len(byStatus[StatusAdded]))
+	fmt.Fprintf(buffer, "Already exist: %d\n", len(byStatus[StatusExists]))
+	sections := []struct {
+		Status Status
+		Title  string
+	}{
+		{StatusAmbiguous, "Ambiguous"},
+		{StatusNotFound, "Not found"},
+		{StatusFailed, "Failed"},
+	}
+	for _, section := range sections {
+		sectionResults := byStatus[section.Status]
+		if len(sectionResults) == 0 {
+			continue
+		}
+		fmt.Fprintf(buffer, "\n%s (%d):\n", section.Title, len(sectionResults))
+		for _, result := range sectionResults {
+			fmt.Fprintf(buffer, "  %s", result.Word)
+			switch {
+			case result.Error != "":
+				fmt.Fprintf(buffer, ": %s", result.Error)
+			case len(result.Candidates) != 0:
+				fmt.Fprintf(buffer, ": %s", strings.Join(result.Candidates, ", "))
+			}
+			_ = buffer.WriteByte('\n')
+		}
+	}
+	return buffer.Flush()
+}
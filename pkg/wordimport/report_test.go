@@ -0,0 +1,37 @@
+package wordimport
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WriteReport(t *testing.T) {
+	var buffer strings.Builder
+	err := WriteReport(&buffer, []*Result{
+		{Word: "犬", Status: StatusAdded},
+		{Word: "猫", Status: StatusExists},
+		{Word: "はし", Status: StatusAmbiguous, Candidates: []string{"橋 (はし)", "箸 (はし)"}},
+		{Word: "foo", Status: StatusNotFound},
+		{Word: "ほげ", Status: StatusNotFound, Candidates: []string{"ほげほげ"}},
+		{Word: "鳥", Status: StatusFailed, Error: "timeout"},
+		{Word: "魚", Status: StatusAdded},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `Words: 7
+Added: 2
+Already exist: 1
+
+Ambiguous (1):
+  はし: 橋 (はし), 箸 (はし)
+
+Not found (2):
+  foo
+  ほげ: ほげほげ
+
+Failed (1):
+  鳥: timeout
+`, buffer.String())
+}
@@ -0,0 +1,224 @@
+// Package wordimport adds notes to Anki for words from word lists.
+package wordimport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Darkclainer/japwords/pkg/anki"
+	"github.com/Darkclainer/japwords/pkg/deinflect"
+	"github.com/Darkclainer/japwords/pkg/lemma"
+	"github.com/Darkclainer/japwords/pkg/lemmalookup"
+)
+
+// SensePolicy specifies what senses of found word become notes.
+type SensePolicy string
+
+const (
+	// SensePolicyFirst adds note only for the first sense.
+	SensePolicyFirst SensePolicy = "first"
+	// SensePolicyAll adds note for every sense.
+	SensePolicyAll SensePolicy = "all"
+)
+
+func ParseSensePolicy(src string) (SensePolicy, error) {
+	switch policy := SensePolicy(src); policy {
+	case SensePolicyFirst, SensePolicyAll:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown sense policy %q, expected first or all", src)
+	}
+}
+
+// Status is outcome of import of single word.
+type Status string
+
+const (
+	StatusAdded     Status = "added"
+	StatusExists    Status = "exists"
+	StatusNotFound  Status = "not-found"
+	StatusAmbiguous Status = "ambiguous"
+	// StatusFailed means that word should be tried again.
+	StatusFailed Status = "failed"
+)
+
+// Result is result of import of single word.
+type Result struct {
+	Word   string `json:"word"`
+	Status Status `json:"status"`
+	// NoteIDs are ids of added and already existing notes
+	NoteIDs []anki.NoteID `json:"noteIDs,omitempty"`
+	// Candidates are lemmas that were found for ambiguous or not found word
+	Candidates []string `json:"candidates,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// Done returns true if word doesn't need to be imported again.
+func (r *Result) Done() bool {
+	return r.Status != StatusFailed
+}
+
+// Anki is part of anki.Anki that is used by Importer.
+type Anki interface {
+	SearchProjectedLemmas(ctx context.Context, lemmas []*lemma.ProjectedLemma) ([]anki.NoteID, error)
+	PrepareProjectedLemma(ctx context.Context, lemma *lemma.ProjectedLemma) (*anki.AddNoteRequest, error)
+	AddNotes(ctx context.Context, notes []*anki.AddNoteRequest) ([]anki.AddNoteResult, error)
+}
+
+type Options struct {
+	Dict        deinflect.Dict
+	Anki        Anki
+	SensePolicy SensePolicy
+	// Projector is optional, it adds examples and kanji to lemmas like in UI
+	Projector *lemmalookup.Projector
+}
+
+type Importer struct {
+	dict        deinflect.Dict
+	anki        Anki
+	sensePolicy SensePolicy
+	projector   *lemmalookup.Projector
+}
+
+func New(opts *Options) *Importer {
+	sensePolicy := opts.SensePolicy
+	if sensePolicy == "" {
+		sensePolicy = SensePolicyFirst
+	}
+	projector := opts.Projector
+	if projector == nil {
+		projector = &lemmalookup.Projector{}
+	}
+	return &Importer{
+		dict:        opts.Dict,
+		anki:        opts.Anki,
+		sensePolicy: sensePolicy,
+		projector:   projector,
+	}
+}
+
+// Import looks up word and adds notes for it. Only lemmas that are written exactly as word
+// (or its dictionary form) are considered, if there are several of them, word is ambiguous.
+// Failed lookup is reported in result, but returned error means that Anki failed and
+// import should be stopped.
+func (im *Importer) Import(ctx context.Context, word string) (*Result, error) {
+	result := &Result{
+		Word: word,
+	}
+	lemmas, normalizedQuery, candidate, err := lemmalookup.Lookup(ctx, im.dict, word)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		result.Status = StatusFailed
+		result.Error = err.Error()
+		return result, nil
+	}
+	if candidate == nil {
+		candidate = &deinflect.Candidate{Term: normalizedQuery}
+	}
+	var matched []*lemma.Lemma
+	for _, l := range lemmas {
+		if candidate.Match(l) {
+			matched = append(matched, l)
+		}
+	}
+	if len(matched) != 1 {
+		result.Status = StatusNotFound
+		if len(matched) > 1 {
+			result.Status = StatusAmbiguous
+			lemmas = matched
+		}
+		for _, l := range lemmas {
+			result.Candidates = append(result.Candidates, formatLemma(l))
+		}
+		return result, nil
+	}
+	projectedLemmas := im.project(matched[0])
+	noteIDs, err := im.anki.SearchProjectedLemmas(ctx, projectedLemmas)
+	if err != nil {
+		return nil, err
+	}
+	var requests []*anki.AddNoteRequest
+	for i, projectedLemma := range projectedLemmas {
+		if noteIDs[i] != 0 {
+			result.NoteIDs = append(result.NoteIDs, noteIDs[i])
+			continue
+		}
+		request, err := im.anki.PrepareProjectedLemma(ctx, projectedLemma)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+	result.Status = StatusExists
+	if len(requests) == 0 {
+		return result, nil
+	}
+	addResults, err := im.anki.AddNotes(ctx, requests)
+	if err != nil {
+		return nil, err
+	}
+	var errs []error
+	for _, addResult := range addResults {
+		switch {
+		case addResult.Err == nil:
+			result.NoteIDs = append(result.NoteIDs, addResult.NoteID)
+			result.Status = StatusAdded
+		case errors.Is(addResult.Err, anki.ErrDuplicatedNoteFound):
+			// SearchProjectedLemmas and duplicate check of Anki can disagree, it's fine
+		default:
+			errs = append(errs, addResult.Err)
+		}
+	}
+	if len(errs) != 0 {
+		result.Status = StatusFailed
+		result.Error = errors.Join(errs...).Error()
+	}
+	return result, nil
+}
+
+// project returns lemmas for senses selected by policy with examples and kanji.
+func (im *Importer) project(l *lemma.Lemma) []*lemma.ProjectedLemma {
+	senses := l.Senses
+	if im.sensePolicy == SensePolicyFirst && len(senses) > 1 {
+		senses = senses[:1]
+	}
+	projectedLemmas := lemmalookup.ExpandSenses(l, senses)
+	im.projector.Enrich(projectedLemmas)
+	return projectedLemmas
+}
+
+// Run imports words in order. Words that are done according to progress are skipped,
+// results of other words are recorded to progress. onResult is called for every word
+// that was imported in this run. Run returns results of all words including words done before,
+// they are returned even if error occurred.
+func (im *Importer) Run(ctx context.Context, words []string, progress *Progress, onResult func(*Result)) ([]*Result, error) {
+	results := make([]*Result, 0, len(words))
+	for _, word := range words {
+		if result, ok := progress.Result(word); ok && result.Done() {
+			results = append(results, result)
+			continue
+		}
+		result, err := im.Import(ctx, word)
+		if err != nil {
+			return results, err
+		}
+		if err := progress.Record(result); err != nil {
+			return results, err
+		}
+		results = append(results, result)
+		if onResult != nil {
+			onResult(result)
+		}
+	}
+	return results, nil
+}
+
+func formatLemma(l *lemma.Lemma) string {
+	if l.Slug.Hiragana == "" || l.Slug.Hiragana == l.Slug.Word {
+		return l.Slug.Word
+	}
+	return l.Slug.Word + " (" + l.Slug.Hiragana + ")"
+}
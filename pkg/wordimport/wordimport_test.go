@@ -0,0 +1,352 @@
+package wordimport
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Darkclainer/japwords/pkg/anki"
+	"github.com/Darkclainer/japwords/pkg/lemma"
+	"github.com/Darkclainer/japwords/pkg/lemmalookup"
+)
+
+type testDict map[string][]*lemma.Lemma
+
+func (d testDict) Query(_ context.Context, query string) ([]*lemma.Lemma, error) {
+	if query == "broken" {
+		return nil, errors.New("dictionary is unavailable")
+	}
+	return d[query], nil
+}
+
+// testAnki has notes with definitions as order field
+type testAnki struct {
+	notes    map[string]anki.NoteID
+	addErrs  map[string]error
+	added    []*anki.AddNoteRequest
+	failWith error
+}
+
+func (a *testAnki) SearchProjectedLemmas(_ context.Context, lemmas []*lemma.ProjectedLemma) ([]anki.NoteID, error) {
+	if a.failWith != nil {
+		return nil, a.failWith
+	}
+	ids := make([]anki.NoteID, len(lemmas))
+	for i, l := range lemmas {
+		ids[i] = a.notes[l.Definitions[0]]
+	}
+	return ids, nil
+}
+
+func (a *testAnki) PrepareProjectedLemma(_ context.Context, l *lemma.ProjectedLemma) (*anki.AddNoteRequest, error) {
+	fields := []anki.AddNoteField{
+		{Name: "Definition", Value: l.Definitions[0]},
+	}
+	for _, example := range l.Examples {
+		fields = append(fields, anki.AddNoteField{Name: "Example", Value: example.Japanese})
+	}
+	for _, kanji := range l.Kanji {
+		fields = append(fields, anki.AddNoteField{Name: "Kanji", Value: kanji.Character})
+	}
+	return &anki.AddNoteRequest{
+		Fields: fields,
+	}, nil
+}
+
+func (a *testAnki) AddNotes(_ context.Context, notes []*anki.AddNoteRequest) ([]anki.AddNoteResult, error) {
+	results := make([]anki.AddNoteResult, len(notes))
+	for i, note := range notes {
+		a.added = append(a.added, note)
+		definition := note.Fields[0].Value
+		if err := a.addErrs[definition]; err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].NoteID = anki.NoteID(100 + len(a.added))
+	}
+	return results, nil
+}
+
+type testExamples struct{}
+
+func (testExamples) Query(word string, _ int) []lemma.Example {
+	return []lemma.Example{{Japanese: word + "がいる"}}
+}
+
+type testKanji struct{}
+
+func (testKanji) Query(characters string) []lemma.Kanji {
+	return []lemma.Kanji{{Character: characters}}
+}
+
+func newTestDict() testDict {
+	taberu := &lemma.Lemma{
+		Slug: lemma.Word{Word: "食べる", Hiragana: "たべる"},
+		Senses: []lemma.WordSense{
+			{Definition: []string{"to eat"}, PartOfSpeech: []string{"Ichidan verb"}},
+		},
+	}
+	return testDict{
+		"いぬ": {
+			{
+				Slug:   lemma.Word{Word: "犬", Hiragana: "いぬ"},
+				Senses: []lemma.WordSense{{Definition: []string{"dog"}}, {Definition: []string{"spy"}}},
+			},
+			{
+				Slug:   lemma.Word{Word: "戌", Hiragana: "いぬ"},
+				Senses: []lemma.WordSense{{Definition: []string{"eleventh sign"}}},
+			},
+		},
+		"犬": {
+			{
+				Slug:   lemma.Word{Word: "犬", Hiragana: "いぬ"},
+				Senses: []lemma.WordSense{{Definition: []string{"dog"}}, {Definition: []string{"spy"}}},
+			},
+			{
+				Slug:   lemma.Word{Word: "犬小屋", Hiragana: "いぬごや"},
+				Senses: []lemma.WordSense{{Definition: []string{"kennel"}}},
+			},
+		},
+		"食べる": {taberu},
+		"たべる": {taberu},
+		"ねこ": {
+			{
+				Slug:   lemma.Word{Word: "猫舌", Hiragana: "ねこじた"},
+				Senses: []lemma.WordSense{{Definition: []string{"sensitive to heat"}}},
+			},
+		},
+	}
+}
+
+func Test_Importer_Import(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Word          string
+		SensePolicy   SensePolicy
+		Anki          *testAnki
+		Expected      *Result
+		ExpectedAdded []string
+	}{
+		{
+			Name:          "first sense",
+			Word:          "犬",
+			SensePolicy:   SensePolicyFirst,
+			Anki:          &testAnki{},
+			Expected:      &Result{Word: "犬", Status: StatusAdded, NoteIDs: []anki.NoteID{101}},
+			ExpectedAdded: []string{"dog"},
+		},
+		{
+			Name:          "all senses",
+			Word:          "犬",
+			SensePolicy:   SensePolicyAll,
+			Anki:          &testAnki{},
+			Expected:      &Result{Word: "犬", Status: StatusAdded, NoteIDs: []anki.NoteID{101, 102}},
+			ExpectedAdded: []string{"dog", "spy"},
+		},
+		{
+			Name:        "some senses exist",
+			Word:        "犬",
+			SensePolicy: SensePolicyAll,
+			Anki: &testAnki{
+				notes: map[string]anki.NoteID{"dog": 7},
+			},
+			Expected:      &Result{Word: "犬", Status: StatusAdded, NoteIDs: []anki.NoteID{7, 101}},
+			ExpectedAdded: []string{"spy"},
+		},
+		{
+			Name:        "exists",
+			Word:        "犬",
+			SensePolicy: SensePolicyFirst,
+			Anki: &testAnki{
+				notes: map[string]anki.NoteID{"dog": 7},
+			},
+			Expected: &Result{Word: "犬", Status: StatusExists, NoteIDs: []anki.NoteID{7}},
+		},
+		{
+			Name:        "duplicate",
+			Word:        "犬",
+			SensePolicy: SensePolicyFirst,
+			Anki: &testAnki{
+				addErrs: map[string]error{"dog": anki.ErrDuplicatedNoteFound},
+			},
+			Expected:      &Result{Word: "犬", Status: StatusExists},
+			ExpectedAdded: []string{"dog"},
+		},
+		{
+			Name:        "add failed",
+			Word:        "犬",
+			SensePolicy: SensePolicyAll,
+			Anki: &testAnki{
+				addErrs: map[string]error{"spy": anki.ErrAudioUnavailable},
+			},
+			Expected: &Result{
+				Word:    "犬",
+				Status:  StatusFailed,
+				NoteIDs: []anki.NoteID{101},
+				Error:   anki.ErrAudioUnavailable.Error(),
+			},
+			ExpectedAdded: []string{"dog", "spy"},
+		},
+		{
+			Name:          "romaji",
+			Word:          "tabemasita",
+			SensePolicy:   SensePolicyFirst,
+			Anki:          &testAnki{},
+			Expected:      &Result{Word: "tabemasita", Status: StatusAdded, NoteIDs: []anki.NoteID{101}},
+			ExpectedAdded: []string{"to eat"},
+		},
+		{
+			Name:        "ambiguous",
+			Word:        "いぬ",
+			SensePolicy: SensePolicyFirst,
+			Anki:        &testAnki{},
+			Expected: &Result{
+				Word:       "いぬ",
+				Status:     StatusAmbiguous,
+				Candidates: []string{"犬 (いぬ)", "戌 (いぬ)"},
+			},
+		},
+		{
+			Name:        "not found",
+			Word:        "ねこ",
+			SensePolicy: SensePolicyFirst,
+			Anki:        &testAnki{},
+			Expected: &Result{
+				Word:       "ねこ",
+				Status:     StatusNotFound,
+				Candidates: []string{"猫舌 (ねこじた)"},
+			},
+		},
+		{
+			Name:        "nothing found",
+			Word:        "鳥",
+			SensePolicy: SensePolicyFirst,
+			Anki:        &testAnki{},
+			Expected: &Result{
+				Word:   "鳥",
+				Status: StatusNotFound,
+			},
+		},
+		{
+			Name:        "lookup failed",
+			Word:        "broken",
+			SensePolicy: SensePolicyFirst,
+			Anki:        &testAnki{},
+			Expected: &Result{
+				Word:   "broken",
+				Status: StatusFailed,
+				Error:  "dictionary is unavailable",
+			},
+		},
+	}
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			importer := New(&Options{
+				Dict:        newTestDict(),
+				Anki:        tc.Anki,
+				SensePolicy: tc.SensePolicy,
+			})
+			result, err := importer.Import(context.Background(), tc.Word)
+			require.NoError(t, err)
+			assert.Equal(t, tc.Expected, result)
+			var added []string
+			for _, note := range tc.Anki.added {
+				added = append(added, note.Fields[0].Value)
+			}
+			assert.Equal(t, tc.ExpectedAdded, added)
+		})
+	}
+}
+
+func Test_Importer_Import_Enrich(t *testing.T) {
+	testAnki := &testAnki{}
+	importer := New(&Options{
+		Dict: newTestDict(),
+		Anki: testAnki,
+		Projector: &lemmalookup.Projector{
+			Examples: testExamples{},
+			Kanji:    testKanji{},
+		},
+	})
+	_, err := importer.Import(context.Background(), "犬")
+	require.NoError(t, err)
+	require.Len(t, testAnki.added, 1)
+	assert.Equal(t, []anki.AddNoteField{
+		{Name: "Definition", Value: "dog"},
+		{Name: "Example", Value: "犬がいる"},
+		{Name: "Kanji", Value: "犬"},
+	}, testAnki.added[0].Fields)
+}
+
+func Test_Importer_Import_AnkiError(t *testing.T) {
+	importer := New(&Options{
+		Dict: newTestDict(),
+		Anki: &testAnki{failWith: anki.ErrIncompleteConfiguration},
+	})
+	_, err := importer.Import(context.Background(), "犬")
+	assert.ErrorIs(t, err, anki.ErrIncompleteConfiguration)
+}
+
+func Test_Importer_Run(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress")
+	progress, err := OpenProgress(path)
+	require.NoError(t, err)
+	require.NoError(t, progress.Record(&Result{Word: "犬", Status: StatusAdded, NoteIDs: []anki.NoteID{7}}))
+	require.NoError(t, progress.Record(&Result{Word: "broken", Status: StatusFailed, Error: "old error"}))
+
+	testAnki := &testAnki{}
+	importer := New(&Options{
+		Dict: newTestDict(),
+		Anki: testAnki,
+	})
+	var reported []string
+	results, err := importer.Run(context.Background(), []string{"犬", "broken", "食べる"}, progress, func(r *Result) {
+		reported = append(reported, r.Word)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*Result{
+		{Word: "犬", Status: StatusAdded, NoteIDs: []anki.NoteID{7}},
+		{Word: "broken", Status: StatusFailed, Error: "dictionary is unavailable"},
+		{Word: "食べる", Status: StatusAdded, NoteIDs: []anki.NoteID{101}},
+	}, results)
+	// done words are not imported again
+	assert.Equal(t, []string{"broken", "食べる"}, reported)
+	require.NoError(t, progress.Close())
+
+	progress, err = OpenProgress(path)
+	require.NoError(t, err)
+	defer progress.Close()
+	result, ok := progress.Result("食べる")
+	assert.True(t, ok)
+	assert.Equal(t, StatusAdded, result.Status)
+}
+
+func Test_Importer_Run_Stopped(t *testing.T) {
+	progress, err := OpenProgress(filepath.Join(t.TempDir(), "progress"))
+	require.NoError(t, err)
+	defer progress.Close()
+	importer := New(&Options{
+		Dict: newTestDict(),
+		Anki: &testAnki{failWith: anki.ErrIncompleteConfiguration},
+	})
+	results, err := importer.Run(context.Background(), []string{"鳥", "犬", "食べる"}, progress, nil)
+	assert.ErrorIs(t, err, anki.ErrIncompleteConfiguration)
+	assert.Equal(t, []*Result{
+		{Word: "鳥", Status: StatusNotFound},
+	}, results)
+	_, ok := progress.Result("犬")
+	assert.False(t, ok)
+}
+
+func Test_ParseSensePolicy(t *testing.T) {
+	policy, err := ParseSensePolicy("all")
+	assert.NoError(t, err)
+	assert.Equal(t, SensePolicyAll, policy)
+	_, err = ParseSensePolicy("some")
+	assert.Error(t, err)
+}
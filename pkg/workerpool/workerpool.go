@@ -9,13 +9,16 @@ import (
 // WorkerPool is simple worker pool with preallocated goroutines for tasks
 // and tasks cancelation on workerpool close.
 type WorkerPool struct {
-	workersCount int
-	tasks        chan func()
+	tasks chan func()
+	// quit stops one worker, it's used to shrink worker pool
+	quit chan struct{}
 
 	stopOnce     sync.Once
 	stopSignal   chan struct{}
 	wg           sync.WaitGroup
 	mu           sync.Mutex
+	workersCount int
+	started      bool
 	stopped      bool
 	currentTasks map[context.Context]context.CancelFunc
 }
@@ -27,13 +30,58 @@ func New(workers int) (*WorkerPool, error) {
 	return &WorkerPool{
 		workersCount: workers,
 		tasks:        make(chan func()),
+		quit:         make(chan struct{}),
 		stopSignal:   make(chan struct{}),
 		currentTasks: map[context.Context]context.CancelFunc{},
 	}, nil
 }
 
 func (wp *WorkerPool) Init() {
-	for i := 0; i < wp.workersCount; i++ {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+	wp.started = true
+	wp.startWorkers(wp.workersCount)
+}
+
+// Resize changes number of workers, it's safe to call it while tasks are running.
+// Excess workers exit after they finish their current tasks.
+func (wp *WorkerPool) Resize(workers int) error {
+	if workers <= 0 {
+		return errors.New("number of workers should be greater than zero")
+	}
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+	if wp.stopped {
+		return errors.New("worker pool is closed")
+	}
+	diff := workers - wp.workersCount
+	wp.workersCount = workers
+	if !wp.started {
+		return nil
+	}
+	if diff > 0 {
+		wp.startWorkers(diff)
+	}
+	for ; diff < 0; diff++ {
+		go func() {
+			select {
+			case wp.quit <- struct{}{}:
+			case <-wp.stopSignal:
+			}
+		}()
+	}
+	return nil
+}
+
+// Workers returns number of workers.
+func (wp *WorkerPool) Workers() int {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+	return wp.workersCount
+}
+
+func (wp *WorkerPool) startWorkers(count int) {
+	for i := 0; i < count; i++ {
 		wp.wg.Add(1)
 		go wp.runTasks()
 	}
@@ -58,6 +106,8 @@ func (wp *WorkerPool) runTasks() {
 		select {
 		case <-wp.stopSignal:
 			return
+		case <-wp.quit:
+			return
 		case task := <-wp.tasks:
 			task()
 		}
@@ -151,3 +151,51 @@ func Test_AddAlsoRespectContext(t *testing.T) {
 	require.Error(t, err)
 	wp.Stop()
 }
+
+func Test_Resize(t *testing.T) {
+	wp, err := New(1)
+	require.NoError(t, err)
+	wp.Init()
+	defer wp.Stop()
+
+	// tryRunning reports whether count blocking tasks can run simultaneously
+	tryRunning := func(count int) bool {
+		release := make(chan struct{})
+		defer close(release)
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		for i := 0; i < count; i++ {
+			err := wp.Add(ctx, func(_ context.Context) {
+				<-release
+			})
+			if err != nil {
+				return false
+			}
+		}
+		return true
+	}
+	assert.False(t, tryRunning(2))
+
+	require.NoError(t, wp.Resize(3))
+	assert.Equal(t, 3, wp.Workers())
+	assert.True(t, tryRunning(3))
+
+	require.NoError(t, wp.Resize(1))
+	// excess workers stop when they are idle
+	require.Eventually(t, func() bool {
+		return !tryRunning(2)
+	}, time.Second, time.Millisecond)
+	assert.True(t, tryRunning(1))
+
+	assert.Error(t, wp.Resize(0))
+}
+
+func Test_ResizeBeforeInit(t *testing.T) {
+	wp, err := New(1)
+	require.NoError(t, err)
+	require.NoError(t, wp.Resize(2))
+	wp.Init()
+	assert.Equal(t, 2, wp.Workers())
+	wp.Stop()
+	assert.Error(t, wp.Resize(3))
+}